@@ -0,0 +1,36 @@
+// Command benchreport converts go test -bench -benchmem output into a
+// comparison report.
+//
+//	go test -run '^$' -bench . -benchmem ./... | go run ./cmd/benchreport -format csv
+package main
+
+import (
+	"flag"
+	"fmt"
+	"fun/pkg/bench"
+	"os"
+)
+
+func main() {
+	format := flag.String("format", "markdown", "report format: markdown or csv")
+	flag.Parse()
+
+	results, err := bench.Parse(os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "benchreport:", err)
+		os.Exit(1)
+	}
+
+	switch *format {
+	case "markdown", "md":
+		err = bench.WriteMarkdown(os.Stdout, results)
+	case "csv":
+		err = bench.WriteCSV(os.Stdout, results)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "benchreport:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,186 @@
+// Command dsrepl is an interactive interpreter for experimenting with the
+// data structures. Statements are separated by newlines or semicolons, and
+// the structure a statement touches is printed after it runs:
+//
+//	> l := list(3, 1); l.append(2); l.sort()
+//	l = Length: 2, Data: 3 1
+//	l = Length: 3, Data: 3 1 2
+//	l = Length: 3, Data: 1 2 3
+//
+// Type help for the supported statements.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"fun/pkg/data"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// usage describes the supported statements.
+const usage = `statements:
+	l := list(values...)   create a list
+	l.insert(v)            add v at the head
+	l.append(v)            add v at the tail
+	l.delete(v)            remove the first v
+	l.deletehead()         remove the head
+	l.deletetail()         remove the tail
+	l.sort()               sort the values in increasing order
+	l.reverse()            reverse the order of the values
+	l.find(v)              report whether v is in the list
+	l.length()             report the number of values
+	l                      print a structure
+	vars                   list the structures
+	help                   print this message`
+
+var (
+	assignPattern = regexp.MustCompile(`^([A-Za-z_]\w*)\s*:=\s*list\((.*)\)$`)
+	callPattern   = regexp.MustCompile(`^([A-Za-z_]\w*)\.(\w+)\((.*)\)$`)
+	namePattern   = regexp.MustCompile(`^[A-Za-z_]\w*$`)
+)
+
+// interpreter holds the structures created by statements.
+type interpreter struct {
+	lists map[string]*data.List[int] // Lists by variable name.
+}
+
+func main() {
+	repl := &interpreter{lists: map[string]*data.List[int]{}}
+	repl.run(os.Stdin, os.Stdout)
+}
+
+// run reads statements until the end of input, printing their results.
+func (repl *interpreter) run(r io.Reader, w io.Writer) {
+	scanner := bufio.NewScanner(r)
+	fmt.Fprint(w, "> ")
+	for scanner.Scan() {
+		for _, statement := range strings.Split(scanner.Text(), ";") {
+			statement = strings.TrimSpace(statement)
+			if statement == "" {
+				continue
+			}
+			output, err := repl.exec(statement)
+			if err != nil {
+				fmt.Fprintln(w, "error:", err)
+				break
+			}
+			fmt.Fprintln(w, output)
+		}
+		fmt.Fprint(w, "> ")
+	}
+	fmt.Fprintln(w)
+}
+
+// exec runs a statement.
+func (repl *interpreter) exec(statement string) (string, error) {
+	if statement == "help" {
+		return usage, nil
+	}
+	if statement == "vars" {
+		names := make([]string, 0, len(repl.lists))
+		for name := range repl.lists {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return strings.Join(names, " "), nil
+	}
+	if match := assignPattern.FindStringSubmatch(statement); match != nil {
+		values, err := parseValues(match[2])
+		if err != nil {
+			return "", err
+		}
+		list := data.NewList[int]()
+		list.AppendAll(values...)
+		repl.lists[match[1]] = list
+		return repl.show(match[1], list), nil
+	}
+	if match := callPattern.FindStringSubmatch(statement); match != nil {
+		list, ok := repl.lists[match[1]]
+		if !ok {
+			return "", fmt.Errorf("undefined: %s", match[1])
+		}
+		args, err := parseValues(match[3])
+		if err != nil {
+			return "", err
+		}
+		return repl.call(match[1], list, strings.ToLower(match[2]), args)
+	}
+	if namePattern.MatchString(statement) {
+		list, ok := repl.lists[statement]
+		if !ok {
+			return "", fmt.Errorf("undefined: %s", statement)
+		}
+		return repl.show(statement, list), nil
+	}
+	return "", fmt.Errorf("cannot parse %q, type help for usage", statement)
+}
+
+// call runs a method of a list.
+func (repl *interpreter) call(name string, list *data.List[int], method string, args []int) (string, error) {
+	arity := map[string]int{
+		"insert": 1, "append": 1, "delete": 1, "find": 1,
+		"deletehead": 0, "deletetail": 0, "length": 0, "sort": 0, "reverse": 0,
+	}
+	n, ok := arity[method]
+	if !ok {
+		return "", fmt.Errorf("%s has no method %s", name, method)
+	}
+	if len(args) != n {
+		return "", fmt.Errorf("%s takes %d arguments, got %d", method, n, len(args))
+	}
+	switch method {
+	case "insert":
+		list.Insert(args[0])
+	case "append":
+		list.Append(args[0])
+	case "delete":
+		if !list.Delete(args[0]) {
+			return "", fmt.Errorf("%d not found", args[0])
+		}
+	case "deletehead", "deletetail":
+		var ok bool
+		if method == "deletehead" {
+			_, ok = list.DeleteHead()
+		} else {
+			_, ok = list.DeleteTail()
+		}
+		if !ok {
+			return "", data.ErrEmpty
+		}
+	case "sort":
+		list.Sort(func(a, b int) bool { return a < b })
+	case "reverse":
+		list.Reverse()
+	case "find":
+		return strconv.FormatBool(list.Find(args[0]) != nil), nil
+	case "length":
+		return strconv.Itoa(list.Length()), nil
+	}
+	return repl.show(name, list), nil
+}
+
+// show formats a structure with its name.
+func (repl *interpreter) show(name string, list *data.List[int]) string {
+	return name + " = " + list.String()
+}
+
+// parseValues parses comma separated integers.
+func parseValues(s string) ([]int, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var values []int
+	for _, field := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q", strings.TrimSpace(field))
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
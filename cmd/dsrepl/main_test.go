@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fun/pkg/data"
+	"strings"
+	"testing"
+)
+
+func Test_Exec(t *testing.T) {
+	repl := &interpreter{lists: map[string]*data.List[int]{}}
+	statements := []struct{ statement, output string }{
+		{"l := list()", "l = Length: 0, Data:"},
+		{"l.append(3)", "l = Length: 1, Data: 3"},
+		{"l.insert(1)", "l = Length: 2, Data: 1 3"},
+		{"l.append(2)", "l = Length: 3, Data: 1 3 2"},
+		{"l.sort()", "l = Length: 3, Data: 1 2 3"},
+		{"l.reverse()", "l = Length: 3, Data: 3 2 1"},
+		{"l.find(2)", "true"},
+		{"l.Length()", "3"},
+		{"m := list(4, 5)", "m = Length: 2, Data: 4 5"},
+		{"vars", "l m"},
+		{"m", "m = Length: 2, Data: 4 5"},
+	}
+	for _, s := range statements {
+		output, err := repl.exec(s.statement)
+		if err != nil || output != s.output {
+			t.Errorf("%s: expected %q, got %q, %v", s.statement, s.output, output, err)
+		}
+	}
+}
+
+func Test_ExecErrors(t *testing.T) {
+	repl := &interpreter{lists: map[string]*data.List[int]{}}
+	repl.exec("l := list(1)")
+	statements := map[string]string{
+		"l +":             "cannot parse \"l +\", type help for usage",
+		"l := list(1, x)": "invalid value \"x\"",
+		"l.append(y)":     "invalid value \"y\"",
+		"k":               "undefined: k",
+		"k.append(1)":     "undefined: k",
+		"l.pop()":         "l has no method pop",
+		"l.append()":      "append takes 1 arguments, got 0",
+		"l.sort(1)":       "sort takes 0 arguments, got 1",
+		"l.delete(2)":     "2 not found",
+	}
+	for statement, want := range statements {
+		if _, err := repl.exec(statement); err == nil || err.Error() != want {
+			t.Errorf("%s: expected %q, got %v", statement, want, err)
+		}
+	}
+	repl.exec("l.deletehead()")
+	if _, err := repl.exec("l.deletetail()"); !errors.Is(err, data.ErrEmpty) {
+		t.Error("expected an empty error, got", err)
+	}
+}
+
+func Test_Run(t *testing.T) {
+	repl := &interpreter{lists: map[string]*data.List[int]{}}
+	var out strings.Builder
+	repl.run(strings.NewReader("l := list(2, 1); l.sort()\nl.pop(); l.reverse()\n"), &out)
+	want := "> l = Length: 2, Data: 2 1\nl = Length: 2, Data: 1 2\n> error: l has no method pop\n> \n"
+	if out.String() != want {
+		t.Errorf("expected %q, got %q", want, out.String())
+	}
+}
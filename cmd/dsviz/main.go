@@ -0,0 +1,136 @@
+// Command dsviz renders a list as a chain of nodes. The list is read from
+// standard input, either as a stream written by List.EncodeTo or, with
+// -script, as one list operation per line:
+//
+//	append 3
+//	insert 1
+//	delete 3
+//	deletehead
+//	deletetail
+//
+// Blank lines and lines starting with # are ignored. The svg format pipes
+// the dot format through Graphviz, which must be installed.
+//
+//	go run ./cmd/dsviz -script -format svg < ops.txt > list.svg
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"fun/pkg/codec"
+	"fun/pkg/data"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	script := flag.Bool("script", false, "read list operations instead of an encoded list")
+	codecName := flag.String("codec", "json", "codec of an encoded list: json, gob, or msgpack")
+	format := flag.String("format", "ascii", "output format: ascii, mermaid, dot, or svg")
+	flag.Parse()
+
+	list := data.NewList[int]()
+	var err error
+	if *script {
+		err = runScript(list, os.Stdin)
+	} else {
+		var c codec.Codec[int]
+		if c, err = codecByName(*codecName); err == nil {
+			err = list.DecodeFrom(os.Stdin, c)
+		}
+	}
+	if err == nil {
+		err = render(os.Stdout, list, *format)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dsviz:", err)
+		os.Exit(1)
+	}
+}
+
+// codecByName gets a codec by its flag name.
+func codecByName(name string) (codec.Codec[int], error) {
+	switch name {
+	case "json":
+		return codec.JSON[int](), nil
+	case "gob":
+		return codec.Gob[int](), nil
+	case "msgpack":
+		return codec.MsgPack[int](), nil
+	}
+	return nil, fmt.Errorf("unknown codec %q", name)
+}
+
+// runScript applies one list operation per line.
+func runScript(list *data.List[int], r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if err := apply(list, fields); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// apply runs a single list operation.
+func apply(list *data.List[int], fields []string) error {
+	op := strings.ToLower(fields[0])
+	switch op {
+	case "deletehead", "deletetail":
+		if len(fields) != 1 {
+			return fmt.Errorf("%s takes no argument", op)
+		}
+		if op == "deletehead" {
+			list.DeleteHead()
+		} else {
+			list.DeleteTail()
+		}
+		return nil
+	case "insert", "append", "delete":
+		if len(fields) != 2 {
+			return fmt.Errorf("%s takes one argument", op)
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return err
+		}
+		switch op {
+		case "insert":
+			return list.Insert(n)
+		case "append":
+			return list.Append(n)
+		}
+		list.Delete(n)
+		return nil
+	}
+	return fmt.Errorf("unknown operation %q", fields[0])
+}
+
+// render writes the list in a format.
+func render(w io.Writer, list *data.List[int], format string) error {
+	if format == "svg" {
+		var dot bytes.Buffer
+		if err := list.Render(&dot, data.RenderDOT); err != nil {
+			return err
+		}
+		cmd := exec.Command("dot", "-Tsvg")
+		cmd.Stdin = &dot
+		cmd.Stdout = w
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+	renderFormat, err := data.ParseRenderFormat(format)
+	if err != nil {
+		return err
+	}
+	return list.Render(w, renderFormat)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fun/pkg/data"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+func Test_RenderScript(t *testing.T) {
+	for _, format := range []string{"ascii", "mermaid", "dot"} {
+		t.Run(format, func(t *testing.T) {
+			script, err := os.Open(filepath.Join("testdata", "ops.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer script.Close()
+			list := data.NewList[int]()
+			if err := runScript(list, script); err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			var out strings.Builder
+			if err := render(&out, list, format); err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			golden := filepath.Join("testdata", "ops."+format+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(out.String()), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != string(want) {
+				t.Errorf("expected\n%s\ngot\n%s", want, out.String())
+			}
+		})
+	}
+}
+
+func Test_ScriptErrors(t *testing.T) {
+	scripts := map[string]string{
+		"append":                 "line 1: append takes one argument",
+		"insert x":               "line 1: strconv.Atoi: parsing \"x\": invalid syntax",
+		"\n# note\ndeletehead 1": "line 3: deletehead takes no argument",
+		"pop":                    "line 1: unknown operation \"pop\"",
+	}
+	for script, want := range scripts {
+		err := runScript(data.NewList[int](), strings.NewReader(script))
+		if err == nil || err.Error() != want {
+			t.Errorf("%q: expected %q, got %v", script, want, err)
+		}
+	}
+	if err := render(&strings.Builder{}, data.NewList[int](), "png"); err == nil {
+		t.Error("expected an unknown format error")
+	}
+}
@@ -0,0 +1,142 @@
+// Package bench runs benchmarks and compares implementations of the same
+// interface.
+//
+// Benchmarks are named Benchmark<Group>/<Operation>/<Implementation>, for
+// example BenchmarkList/Append/List, so results for the same group and
+// operation can be reported side by side.
+package bench
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Result of a single benchmark.
+type Result struct {
+	Group          string  // Interface being measured, e.g. List.
+	Operation      string  // Operation being measured, e.g. Append.
+	Implementation string  // Implementation being measured.
+	N              int     // Number of iterations run.
+	NsPerOp        float64 // Nanoseconds per operation.
+	BytesPerOp     int64   // Bytes allocated per operation.
+	AllocsPerOp    int64   // Allocations per operation.
+}
+
+// Case is a benchmark to run for an implementation.
+type Case struct {
+	Group          string           // Interface being measured.
+	Operation      string           // Operation being measured.
+	Implementation string           // Implementation being measured.
+	Bench          func(*testing.B) // Benchmark function.
+}
+
+// OpsPerSec reports the number of operations per second.
+func (result Result) OpsPerSec() float64 {
+	if result.NsPerOp <= 0 {
+		return 0
+	}
+	return 1e9 / result.NsPerOp
+}
+
+// Name is the benchmark name, without the Benchmark prefix.
+func (result Result) Name() string {
+	parts := []string{result.Group}
+	if result.Operation != "" {
+		parts = append(parts, result.Operation)
+	}
+	if result.Implementation != "" {
+		parts = append(parts, result.Implementation)
+	}
+	return strings.Join(parts, "/")
+}
+
+// Run benchmarks each case with memory statistics enabled.
+func Run(cases []Case) []Result {
+	results := make([]Result, 0, len(cases))
+	for _, c := range cases {
+		bench := c.Bench
+		r := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			bench(b)
+		})
+		results = append(results, Result{
+			Group:          c.Group,
+			Operation:      c.Operation,
+			Implementation: c.Implementation,
+			N:              r.N,
+			NsPerOp:        float64(r.NsPerOp()),
+			BytesPerOp:     r.AllocedBytesPerOp(),
+			AllocsPerOp:    r.AllocsPerOp(),
+		})
+	}
+	return results
+}
+
+// Parse reads the output of go test -bench -benchmem.
+func Parse(r io.Reader) ([]Result, error) {
+	var results []Result
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		result, ok, err := parseLine(scanner.Text())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			results = append(results, result)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// parseLine parses a single benchmark line, ignoring other output.
+func parseLine(line string) (Result, bool, error) {
+	var result Result
+	fields := strings.Fields(line)
+	if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+		return result, false, nil
+	}
+	n, err := strconv.Atoi(fields[1])
+	if err != nil {
+		// Not a result line, e.g. a benchmark that logged output.
+		return result, false, nil
+	}
+	result.N = n
+
+	name := strings.TrimPrefix(fields[0], "Benchmark")
+	if i := strings.LastIndex(name, "-"); i >= 0 {
+		if _, err := strconv.Atoi(name[i+1:]); err == nil {
+			name = name[:i]
+		}
+	}
+	parts := strings.SplitN(name, "/", 3)
+	result.Group = parts[0]
+	if len(parts) > 1 {
+		result.Operation = parts[1]
+	}
+	if len(parts) > 2 {
+		result.Implementation = parts[2]
+	}
+
+	for i := 2; i+1 < len(fields); i += 2 {
+		value, unit := fields[i], fields[i+1]
+		switch unit {
+		case "ns/op":
+			result.NsPerOp, err = strconv.ParseFloat(value, 64)
+		case "B/op":
+			result.BytesPerOp, err = strconv.ParseInt(value, 10, 64)
+		case "allocs/op":
+			result.AllocsPerOp, err = strconv.ParseInt(value, 10, 64)
+		}
+		if err != nil {
+			return result, false, errors.New("invalid " + unit + " in " + fields[0] + ": " + value)
+		}
+	}
+	return result, true, nil
+}
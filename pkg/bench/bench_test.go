@@ -0,0 +1,70 @@
+package bench_test
+
+import (
+	. "fun/pkg/bench"
+	"strings"
+	"testing"
+)
+
+const output = `goos: linux
+goarch: amd64
+pkg: fun/pkg/data
+BenchmarkList/Append/List-8         	 1000000	       100.0 ns/op	      32 B/op	       1 allocs/op
+BenchmarkList/Append/Other-8        	 2000000	        50.5 ns/op	       0 B/op	       0 allocs/op
+BenchmarkSnapshot-8                 	   10000	      2000 ns/op
+PASS
+ok  	fun/pkg/data	3.000s
+`
+
+func Test_Parse(t *testing.T) {
+	results, err := Parse(strings.NewReader(output))
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if len(results) != 3 {
+		t.Fatal("expected 3 results, got", len(results))
+	}
+	result := results[0]
+	if result.Group != "List" || result.Operation != "Append" || result.Implementation != "List" {
+		t.Error("unexpected name", result.Name())
+	}
+	if result.N != 1000000 || result.NsPerOp != 100 || result.BytesPerOp != 32 || result.AllocsPerOp != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if result.OpsPerSec() != 1e7 {
+		t.Error("expected 1e7 ops/sec, got", result.OpsPerSec())
+	}
+	if results[2].Name() != "Snapshot" {
+		t.Error("expected Snapshot, got", results[2].Name())
+	}
+}
+
+func Test_ParseInvalid(t *testing.T) {
+	_, err := Parse(strings.NewReader("BenchmarkList-8 10 fast ns/op\n"))
+	if err == nil {
+		t.Error("expected error parsing invalid ns/op")
+	}
+}
+
+func Test_Run(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping benchmark run in short mode")
+	}
+	results := Run([]Case{{
+		Group:          "Slice",
+		Operation:      "Append",
+		Implementation: "Builtin",
+		Bench: func(b *testing.B) {
+			var s []int
+			for i := 0; i < b.N; i++ {
+				s = append(s, i)
+			}
+		},
+	}})
+	if len(results) != 1 {
+		t.Fatal("expected 1 result, got", len(results))
+	}
+	if results[0].N == 0 || results[0].Name() != "Slice/Append/Builtin" {
+		t.Errorf("unexpected result %+v", results[0])
+	}
+}
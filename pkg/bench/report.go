@@ -0,0 +1,110 @@
+package bench
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// group of results for the same interface and operation.
+type group struct {
+	name    string   // Name of the group and operation.
+	results []Result // Results in implementation order.
+}
+
+// groupResults collects results by group and operation, sorted by name and
+// then by speed so the fastest implementation is listed first.
+func groupResults(results []Result) []group {
+	index := map[string]int{}
+	var groups []group
+	for _, result := range results {
+		name := result.Group
+		if result.Operation != "" {
+			name += "/" + result.Operation
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, group{name: name})
+		}
+		groups[i].results = append(groups[i].results, result)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].name < groups[j].name
+	})
+	for _, g := range groups {
+		sort.SliceStable(g.results, func(i, j int) bool {
+			return g.results[i].NsPerOp < g.results[j].NsPerOp
+		})
+	}
+	return groups
+}
+
+// WriteMarkdown writes a markdown comparison table for each operation.
+func WriteMarkdown(w io.Writer, results []Result) error {
+	for i, g := range groupResults(results) {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "### %s\n\n", g.name); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w, "| Implementation | ops/sec | ns/op | B/op | allocs/op | relative |"); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w, "|---|---:|---:|---:|---:|---:|"); err != nil {
+			return err
+		}
+		fastest := g.results[0].NsPerOp
+		for _, result := range g.results {
+			relative := 1.0
+			if fastest > 0 {
+				relative = result.NsPerOp / fastest
+			}
+			implementation := result.Implementation
+			if implementation == "" {
+				implementation = "-"
+			}
+			_, err := fmt.Fprintf(w, "| %s | %.0f | %.2f | %d | %d | %.2fx |\n",
+				implementation, result.OpsPerSec(), result.NsPerOp,
+				result.BytesPerOp, result.AllocsPerOp, relative)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes one row per result with a header row.
+func WriteCSV(w io.Writer, results []Result) error {
+	writer := csv.NewWriter(w)
+	header := []string{"group", "operation", "implementation", "n", "ops_per_sec", "ns_per_op", "bytes_per_op", "allocs_per_op"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, g := range groupResults(results) {
+		for _, result := range g.results {
+			record := []string{
+				result.Group,
+				result.Operation,
+				result.Implementation,
+				strconv.Itoa(result.N),
+				strconv.FormatFloat(result.OpsPerSec(), 'f', 0, 64),
+				strconv.FormatFloat(result.NsPerOp, 'f', 2, 64),
+				strconv.FormatInt(result.BytesPerOp, 10),
+				strconv.FormatInt(result.AllocsPerOp, 10),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,51 @@
+package bench_test
+
+import (
+	. "fun/pkg/bench"
+	"strings"
+	"testing"
+)
+
+var results = []Result{
+	{Group: "List", Operation: "Append", Implementation: "Slow", N: 10, NsPerOp: 200, BytesPerOp: 64, AllocsPerOp: 2},
+	{Group: "List", Operation: "Append", Implementation: "Fast", N: 20, NsPerOp: 100, BytesPerOp: 32, AllocsPerOp: 1},
+	{Group: "List", Operation: "Delete", Implementation: "Fast", N: 30, NsPerOp: 10},
+}
+
+func Test_WriteMarkdown(t *testing.T) {
+	var sb strings.Builder
+	if err := WriteMarkdown(&sb, results); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	expected := `### List/Append
+
+| Implementation | ops/sec | ns/op | B/op | allocs/op | relative |
+|---|---:|---:|---:|---:|---:|
+| Fast | 10000000 | 100.00 | 32 | 1 | 1.00x |
+| Slow | 5000000 | 200.00 | 64 | 2 | 2.00x |
+
+### List/Delete
+
+| Implementation | ops/sec | ns/op | B/op | allocs/op | relative |
+|---|---:|---:|---:|---:|---:|
+| Fast | 100000000 | 10.00 | 0 | 0 | 1.00x |
+`
+	if sb.String() != expected {
+		t.Errorf("unexpected markdown:\n%s", sb.String())
+	}
+}
+
+func Test_WriteCSV(t *testing.T) {
+	var sb strings.Builder
+	if err := WriteCSV(&sb, results); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	expected := `group,operation,implementation,n,ops_per_sec,ns_per_op,bytes_per_op,allocs_per_op
+List,Append,Fast,20,10000000,100.00,32,1
+List,Append,Slow,10,5000000,200.00,64,2
+List,Delete,Fast,30,100000000,10.00,0,0
+`
+	if sb.String() != expected {
+		t.Errorf("unexpected csv:\n%s", sb.String())
+	}
+}
@@ -0,0 +1,77 @@
+// Package codec encodes values for persistence and wire transfer, and frames
+// sequences of encoded values so containers can be written to and read from
+// streams uniformly.
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// Codec converts values to and from bytes.
+type Codec[T any] interface {
+	Encode(value T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// jsonCodec encodes values with encoding/json.
+type jsonCodec[T any] struct{}
+
+// JSON creates a codec using encoding/json.
+func JSON[T any]() Codec[T] {
+	return jsonCodec[T]{}
+}
+
+func (jsonCodec[T]) Encode(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// gobCodec encodes values with encoding/gob.
+type gobCodec[T any] struct{}
+
+// Gob creates a codec using encoding/gob. Every value carries its own type
+// description, so it can be decoded on its own.
+func Gob[T any]() Codec[T] {
+	return gobCodec[T]{}
+}
+
+func (gobCodec[T]) Encode(value T) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(value); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (gobCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value)
+	return value, err
+}
+
+// msgpackCodec encodes values as MessagePack.
+type msgpackCodec[T any] struct{}
+
+// MsgPack creates a codec using MessagePack. Structs are encoded as maps of
+// their exported field names, which can be renamed or skipped with a
+// `msgpack:"name"` or `msgpack:"-"` tag.
+func MsgPack[T any]() Codec[T] {
+	return msgpackCodec[T]{}
+}
+
+func (msgpackCodec[T]) Encode(value T) ([]byte, error) {
+	return MarshalMsgPack(value)
+}
+
+func (msgpackCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := UnmarshalMsgPack(data, &value)
+	return value, err
+}
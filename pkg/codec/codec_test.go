@@ -0,0 +1,85 @@
+package codec_test
+
+import (
+	"bytes"
+	"errors"
+	. "fun/pkg/codec"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// record is a struct encoded by the codecs.
+type record struct {
+	Name  string
+	Count int
+	Tags  []string
+}
+
+// codecs are the codecs under test.
+var codecs = map[string]Codec[record]{
+	"json":    JSON[record](),
+	"gob":     Gob[record](),
+	"msgpack": MsgPack[record](),
+}
+
+func Test_Codecs(t *testing.T) {
+	value := record{Name: "a", Count: -3, Tags: []string{"x", "y"}}
+	for name, codec := range codecs {
+		data, err := codec.Encode(value)
+		if err != nil {
+			t.Error(name, "unexpected error", err)
+			continue
+		}
+		decoded, err := codec.Decode(data)
+		if err != nil {
+			t.Error(name, "unexpected error", err)
+			continue
+		}
+		if !reflect.DeepEqual(decoded, value) {
+			t.Errorf("%s: expected %+v, got %+v", name, value, decoded)
+		}
+	}
+}
+
+func Test_Frames(t *testing.T) {
+	values := []record{{Name: "a"}, {Name: "b", Count: 1}, {Name: "c", Tags: []string{"z"}}}
+	for name, codec := range codecs {
+		var buffer bytes.Buffer
+		writer := NewWriter(&buffer, codec)
+		writer.WriteHeader(len(values))
+		for _, value := range values {
+			if err := writer.Write(value); err != nil {
+				t.Fatal(name, "unexpected error", err)
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			t.Fatal(name, "unexpected error", err)
+		}
+
+		data := buffer.Bytes()
+		decoded, err := NewReader(bytes.NewReader(data), codec).ReadAll()
+		if err != nil {
+			t.Fatal(name, "unexpected error", err)
+		}
+		if len(decoded) != len(values) || decoded[1].Name != "b" || decoded[2].Tags[0] != "z" {
+			t.Errorf("%s: expected %+v, got %+v", name, values, decoded)
+		}
+
+		_, err = NewReader(bytes.NewReader(data[:len(data)-1]), codec).ReadAll()
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Error(name, "expected truncated frames to fail, got", err)
+		}
+	}
+}
+
+func Test_FrameLimits(t *testing.T) {
+	var buffer bytes.Buffer
+	buffer.Write([]byte{1, 0xff, 0xff, 0xff, 0xff, 0x0f})
+	if _, err := NewReader(&buffer, JSON[int]()).ReadAll(); err == nil {
+		t.Error("expected oversized record to fail")
+	}
+	if _, err := NewReader(&bytes.Buffer{}, JSON[int]()).ReadAll(); err != io.EOF {
+		t.Error("expected empty stream to report io.EOF, got", err)
+	}
+}
@@ -0,0 +1,139 @@
+package codec
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxRecordSize is the largest encoded value a Reader accepts, to guard
+// against corrupt length prefixes.
+const MaxRecordSize = 1 << 30
+
+// Writer writes a count of values followed by each value as a
+// length-prefixed record.
+type Writer[T any] struct {
+	codec  Codec[T]      // Encodes each value.
+	writer *bufio.Writer // Buffered destination.
+	buffer []byte        // Scratch space for varints.
+}
+
+// Create a new writer of values encoded by a codec.
+func NewWriter[T any](w io.Writer, codec Codec[T]) *Writer[T] {
+	return &Writer[T]{codec: codec, writer: bufio.NewWriter(w), buffer: make([]byte, binary.MaxVarintLen64)}
+}
+
+// WriteHeader writes the number of values that follow.
+func (writer *Writer[T]) WriteHeader(count int) error {
+	return writer.writeUvarint(uint64(count))
+}
+
+// Write encodes a value as a length-prefixed record.
+func (writer *Writer[T]) Write(value T) error {
+	data, err := writer.codec.Encode(value)
+	if err != nil {
+		return err
+	}
+	if err := writer.writeUvarint(uint64(len(data))); err != nil {
+		return err
+	}
+	_, err = writer.writer.Write(data)
+	return err
+}
+
+// Flush writes any buffered data to the destination.
+func (writer *Writer[T]) Flush() error {
+	return writer.writer.Flush()
+}
+
+// writeUvarint writes an unsigned varint.
+func (writer *Writer[T]) writeUvarint(n uint64) error {
+	size := binary.PutUvarint(writer.buffer, n)
+	_, err := writer.writer.Write(writer.buffer[:size])
+	return err
+}
+
+// Reader reads values written by a Writer.
+type Reader[T any] struct {
+	codec  Codec[T]      // Decodes each value.
+	reader *bufio.Reader // Buffered source.
+}
+
+// Create a new reader of values decoded by a codec.
+func NewReader[T any](r io.Reader, codec Codec[T]) *Reader[T] {
+	return &Reader[T]{codec: codec, reader: bufio.NewReader(r)}
+}
+
+// ReadHeader reads the number of values that follow.
+func (reader *Reader[T]) ReadHeader() (int, error) {
+	count, err := binary.ReadUvarint(reader.reader)
+	if err != nil {
+		return 0, err
+	}
+	if count > uint64(int(^uint(0)>>1)) {
+		return 0, errors.New("codec: invalid count")
+	}
+	return int(count), nil
+}
+
+// Read decodes the next record.
+func (reader *Reader[T]) Read() (T, error) {
+	var unset T
+	data, err := readRecord(reader.reader)
+	if err != nil {
+		return unset, err
+	}
+	return reader.codec.Decode(data)
+}
+
+// byteReader reads varints and records.
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// readRecord reads a length-prefixed record.
+func readRecord(reader byteReader) ([]byte, error) {
+	size, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, err
+	}
+	if size > MaxRecordSize {
+		return nil, fmt.Errorf("codec: record of %d bytes exceeds maximum", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// ReadAll reads a header and that many values.
+func (reader *Reader[T]) ReadAll() ([]T, error) {
+	count, err := reader.ReadHeader()
+	if err != nil {
+		return nil, err
+	}
+	capacity := count
+	if capacity > 1024 {
+		// Don't trust a corrupt count with a huge allocation.
+		capacity = 1024
+	}
+	values := make([]T, 0, capacity)
+	for i := 0; i < count; i++ {
+		value, err := reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
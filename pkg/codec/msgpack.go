@@ -0,0 +1,607 @@
+package codec
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MessagePack format bytes.
+const (
+	msgpackNil     = 0xc0
+	msgpackFalse   = 0xc2
+	msgpackTrue    = 0xc3
+	msgpackBin8    = 0xc4
+	msgpackBin16   = 0xc5
+	msgpackBin32   = 0xc6
+	msgpackFloat32 = 0xca
+	msgpackFloat64 = 0xcb
+	msgpackUint8   = 0xcc
+	msgpackUint16  = 0xcd
+	msgpackUint32  = 0xce
+	msgpackUint64  = 0xcf
+	msgpackInt8    = 0xd0
+	msgpackInt16   = 0xd1
+	msgpackInt32   = 0xd2
+	msgpackInt64   = 0xd3
+	msgpackStr8    = 0xd9
+	msgpackStr16   = 0xda
+	msgpackStr32   = 0xdb
+	msgpackArray16 = 0xdc
+	msgpackArray32 = 0xdd
+	msgpackMap16   = 0xde
+	msgpackMap32   = 0xdf
+)
+
+// MarshalMsgPack encodes a value as MessagePack. Booleans, numbers, strings,
+// byte slices, slices, arrays, maps, structs, pointers, and interfaces are
+// supported.
+func MarshalMsgPack(value any) ([]byte, error) {
+	encoder := &msgpackEncoder{}
+	if err := encoder.encode(reflect.ValueOf(value)); err != nil {
+		return nil, err
+	}
+	return encoder.data, nil
+}
+
+// UnmarshalMsgPack decodes MessagePack into the value pointed to by target.
+// Decoding into an interface produces bool, int64, uint64, float64, string,
+// []byte, []any, and map[string]any (or map[any]any for non-string keys).
+func UnmarshalMsgPack(data []byte, target any) error {
+	pointer := reflect.ValueOf(target)
+	if pointer.Kind() != reflect.Pointer || pointer.IsNil() {
+		return errors.New("msgpack: target must be a non-nil pointer")
+	}
+	decoder := &msgpackDecoder{data: data}
+	if err := decoder.decode(pointer.Elem()); err != nil {
+		return err
+	}
+	if decoder.offset != len(data) {
+		return fmt.Errorf("msgpack: %d trailing bytes", len(data)-decoder.offset)
+	}
+	return nil
+}
+
+// msgpackEncoder appends encoded values.
+type msgpackEncoder struct {
+	data []byte // Encoded output.
+}
+
+func (encoder *msgpackEncoder) byte(b byte) {
+	encoder.data = append(encoder.data, b)
+}
+
+func (encoder *msgpackEncoder) uint16(prefix byte, n uint16) {
+	encoder.data = append(encoder.data, prefix, byte(n>>8), byte(n))
+}
+
+func (encoder *msgpackEncoder) uint32(prefix byte, n uint32) {
+	encoder.data = append(encoder.data, prefix, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(encoder.data[len(encoder.data)-4:], n)
+}
+
+func (encoder *msgpackEncoder) uint64(prefix byte, n uint64) {
+	encoder.data = append(encoder.data, prefix, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint64(encoder.data[len(encoder.data)-8:], n)
+}
+
+func (encoder *msgpackEncoder) encodeInt(n int64) {
+	switch {
+	case n >= 0:
+		encoder.encodeUint(uint64(n))
+	case n >= -32:
+		encoder.byte(byte(n))
+	case n >= math.MinInt8:
+		encoder.data = append(encoder.data, msgpackInt8, byte(n))
+	case n >= math.MinInt16:
+		encoder.uint16(msgpackInt16, uint16(n))
+	case n >= math.MinInt32:
+		encoder.uint32(msgpackInt32, uint32(n))
+	default:
+		encoder.uint64(msgpackInt64, uint64(n))
+	}
+}
+
+func (encoder *msgpackEncoder) encodeUint(n uint64) {
+	switch {
+	case n <= 0x7f:
+		encoder.byte(byte(n))
+	case n <= math.MaxUint8:
+		encoder.data = append(encoder.data, msgpackUint8, byte(n))
+	case n <= math.MaxUint16:
+		encoder.uint16(msgpackUint16, uint16(n))
+	case n <= math.MaxUint32:
+		encoder.uint32(msgpackUint32, uint32(n))
+	default:
+		encoder.uint64(msgpackUint64, n)
+	}
+}
+
+// length writes the header of a string, binary, array, or map.
+func (encoder *msgpackEncoder) length(n int, fix byte, fixMax int, prefix8, prefix16, prefix32 byte) {
+	switch {
+	case fix != 0 && n <= fixMax:
+		encoder.byte(fix | byte(n))
+	case prefix8 != 0 && n <= math.MaxUint8:
+		encoder.data = append(encoder.data, prefix8, byte(n))
+	case n <= math.MaxUint16:
+		encoder.uint16(prefix16, uint16(n))
+	default:
+		encoder.uint32(prefix32, uint32(n))
+	}
+}
+
+func (encoder *msgpackEncoder) encodeString(s string) {
+	encoder.length(len(s), 0xa0, 31, msgpackStr8, msgpackStr16, msgpackStr32)
+	encoder.data = append(encoder.data, s...)
+}
+
+func (encoder *msgpackEncoder) encode(value reflect.Value) error {
+	if !value.IsValid() {
+		encoder.byte(msgpackNil)
+		return nil
+	}
+	switch value.Kind() {
+	case reflect.Bool:
+		if value.Bool() {
+			encoder.byte(msgpackTrue)
+		} else {
+			encoder.byte(msgpackFalse)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		encoder.encodeInt(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		encoder.encodeUint(value.Uint())
+	case reflect.Float32:
+		encoder.uint32(msgpackFloat32, math.Float32bits(float32(value.Float())))
+	case reflect.Float64:
+		encoder.uint64(msgpackFloat64, math.Float64bits(value.Float()))
+	case reflect.String:
+		encoder.encodeString(value.String())
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			encoder.byte(msgpackNil)
+			return nil
+		}
+		return encoder.encode(value.Elem())
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			encoder.byte(msgpackNil)
+			return nil
+		}
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			encoder.length(value.Len(), 0, 0, msgpackBin8, msgpackBin16, msgpackBin32)
+			for i := 0; i < value.Len(); i++ {
+				encoder.byte(byte(value.Index(i).Uint()))
+			}
+			return nil
+		}
+		encoder.length(value.Len(), 0x90, 15, 0, msgpackArray16, msgpackArray32)
+		for i := 0; i < value.Len(); i++ {
+			if err := encoder.encode(value.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if value.IsNil() {
+			encoder.byte(msgpackNil)
+			return nil
+		}
+		keys := value.MapKeys()
+		// Sort keys so equal maps encode identically.
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		encoder.length(len(keys), 0x80, 15, 0, msgpackMap16, msgpackMap32)
+		for _, key := range keys {
+			if err := encoder.encode(key); err != nil {
+				return err
+			}
+			if err := encoder.encode(value.MapIndex(key)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		fields := msgpackFields(value.Type())
+		encoder.length(len(fields), 0x80, 15, 0, msgpackMap16, msgpackMap32)
+		for _, field := range fields {
+			encoder.encodeString(field.name)
+			if err := encoder.encode(value.Field(field.index)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("msgpack: unsupported type %s", value.Type())
+	}
+	return nil
+}
+
+// msgpackField is an encoded struct field.
+type msgpackField struct {
+	name  string // Encoded name.
+	index int    // Index in the struct.
+}
+
+// msgpackFields lists the exported fields of a struct that are encoded.
+func msgpackFields(t reflect.Type) []msgpackField {
+	var fields []msgpackField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("msgpack"); ok {
+			tag, _, _ = strings.Cut(tag, ",")
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		fields = append(fields, msgpackField{name, i})
+	}
+	return fields
+}
+
+// msgpackDecoder reads encoded values.
+type msgpackDecoder struct {
+	data   []byte // Encoded input.
+	offset int    // Position of the next byte.
+}
+
+var errMsgpackShort = errors.New("msgpack: unexpected end of data")
+
+func (decoder *msgpackDecoder) read(n int) ([]byte, error) {
+	if n < 0 || len(decoder.data)-decoder.offset < n {
+		return nil, errMsgpackShort
+	}
+	b := decoder.data[decoder.offset : decoder.offset+n]
+	decoder.offset += n
+	return b, nil
+}
+
+func (decoder *msgpackDecoder) readUint(size int) (uint64, error) {
+	b, err := decoder.read(size)
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return binary.BigEndian.Uint64(b), nil
+	}
+}
+
+// msgpackItem is a decoded header: the kind of value and its immediate
+// content or length.
+type msgpackItem struct {
+	kind   reflect.Kind // Bool, Int64, Uint64, Float64, String, Slice (binary), Array, Map, or Invalid for nil.
+	b      bool         // Boolean value.
+	i      int64        // Signed integer value.
+	u      uint64       // Unsigned integer value.
+	f      float64      // Float value.
+	length int          // Length of a string, binary, array, or map.
+}
+
+func (decoder *msgpackDecoder) header() (msgpackItem, error) {
+	b, err := decoder.read(1)
+	if err != nil {
+		return msgpackItem{}, err
+	}
+	c := b[0]
+	sized := func(kind reflect.Kind, size int) (msgpackItem, error) {
+		n, err := decoder.readUint(size)
+		return msgpackItem{kind: kind, length: int(n)}, err
+	}
+	switch {
+	case c <= 0x7f:
+		return msgpackItem{kind: reflect.Uint64, u: uint64(c)}, nil
+	case c >= 0xe0:
+		return msgpackItem{kind: reflect.Int64, i: int64(int8(c))}, nil
+	case c&0xf0 == 0x80:
+		return msgpackItem{kind: reflect.Map, length: int(c & 0x0f)}, nil
+	case c&0xf0 == 0x90:
+		return msgpackItem{kind: reflect.Array, length: int(c & 0x0f)}, nil
+	case c&0xe0 == 0xa0:
+		return msgpackItem{kind: reflect.String, length: int(c & 0x1f)}, nil
+	}
+	switch c {
+	case msgpackNil:
+		return msgpackItem{kind: reflect.Invalid}, nil
+	case msgpackFalse, msgpackTrue:
+		return msgpackItem{kind: reflect.Bool, b: c == msgpackTrue}, nil
+	case msgpackBin8:
+		return sized(reflect.Slice, 1)
+	case msgpackBin16:
+		return sized(reflect.Slice, 2)
+	case msgpackBin32:
+		return sized(reflect.Slice, 4)
+	case msgpackStr8:
+		return sized(reflect.String, 1)
+	case msgpackStr16:
+		return sized(reflect.String, 2)
+	case msgpackStr32:
+		return sized(reflect.String, 4)
+	case msgpackArray16:
+		return sized(reflect.Array, 2)
+	case msgpackArray32:
+		return sized(reflect.Array, 4)
+	case msgpackMap16:
+		return sized(reflect.Map, 2)
+	case msgpackMap32:
+		return sized(reflect.Map, 4)
+	case msgpackFloat32:
+		n, err := decoder.readUint(4)
+		return msgpackItem{kind: reflect.Float64, f: float64(math.Float32frombits(uint32(n)))}, err
+	case msgpackFloat64:
+		n, err := decoder.readUint(8)
+		return msgpackItem{kind: reflect.Float64, f: math.Float64frombits(n)}, err
+	case msgpackUint8, msgpackUint16, msgpackUint32, msgpackUint64:
+		n, err := decoder.readUint(1 << (c - msgpackUint8))
+		return msgpackItem{kind: reflect.Uint64, u: n}, err
+	case msgpackInt8:
+		n, err := decoder.readUint(1)
+		return msgpackItem{kind: reflect.Int64, i: int64(int8(n))}, err
+	case msgpackInt16:
+		n, err := decoder.readUint(2)
+		return msgpackItem{kind: reflect.Int64, i: int64(int16(n))}, err
+	case msgpackInt32:
+		n, err := decoder.readUint(4)
+		return msgpackItem{kind: reflect.Int64, i: int64(int32(n))}, err
+	case msgpackInt64:
+		n, err := decoder.readUint(8)
+		return msgpackItem{kind: reflect.Int64, i: int64(n)}, err
+	}
+	return msgpackItem{}, fmt.Errorf("msgpack: unsupported format byte 0x%02x", c)
+}
+
+// decode reads a value into target, which must be settable.
+func (decoder *msgpackDecoder) decode(target reflect.Value) error {
+	item, err := decoder.header()
+	if err != nil {
+		return err
+	}
+	return decoder.decodeItem(item, target)
+}
+
+func (decoder *msgpackDecoder) decodeItem(item msgpackItem, target reflect.Value) error {
+	if item.kind == reflect.Invalid {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+	switch target.Kind() {
+	case reflect.Pointer:
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return decoder.decodeItem(item, target.Elem())
+	case reflect.Interface:
+		if target.NumMethod() != 0 {
+			return fmt.Errorf("msgpack: cannot decode into %s", target.Type())
+		}
+		value, err := decoder.decodeAny(item)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			target.Set(reflect.Zero(target.Type()))
+		} else {
+			target.Set(reflect.ValueOf(value))
+		}
+		return nil
+	}
+
+	mismatch := func() error {
+		return fmt.Errorf("msgpack: cannot decode %s into %s", item.kind, target.Type())
+	}
+	switch item.kind {
+	case reflect.Bool:
+		if target.Kind() != reflect.Bool {
+			return mismatch()
+		}
+		target.SetBool(item.b)
+	case reflect.Int64, reflect.Uint64, reflect.Float64:
+		return decoder.setNumber(item, target, mismatch)
+	case reflect.String:
+		b, err := decoder.read(item.length)
+		if err != nil {
+			return err
+		}
+		switch {
+		case target.Kind() == reflect.String:
+			target.SetString(string(b))
+		case target.Kind() == reflect.Slice && target.Type().Elem().Kind() == reflect.Uint8:
+			target.SetBytes(append([]byte(nil), b...))
+		default:
+			return mismatch()
+		}
+	case reflect.Slice:
+		b, err := decoder.read(item.length)
+		if err != nil {
+			return err
+		}
+		switch {
+		case target.Kind() == reflect.Slice && target.Type().Elem().Kind() == reflect.Uint8:
+			target.SetBytes(append([]byte(nil), b...))
+		case target.Kind() == reflect.Array && target.Type().Elem().Kind() == reflect.Uint8:
+			if item.length != target.Len() {
+				return mismatch()
+			}
+			reflect.Copy(target, reflect.ValueOf(b))
+		case target.Kind() == reflect.String:
+			target.SetString(string(b))
+		default:
+			return mismatch()
+		}
+	case reflect.Array:
+		switch target.Kind() {
+		case reflect.Slice:
+			if item.length > len(decoder.data)-decoder.offset {
+				return errMsgpackShort
+			}
+			slice := reflect.MakeSlice(target.Type(), item.length, item.length)
+			for i := 0; i < item.length; i++ {
+				if err := decoder.decode(slice.Index(i)); err != nil {
+					return err
+				}
+			}
+			target.Set(slice)
+		case reflect.Array:
+			if item.length != target.Len() {
+				return mismatch()
+			}
+			for i := 0; i < item.length; i++ {
+				if err := decoder.decode(target.Index(i)); err != nil {
+					return err
+				}
+			}
+		default:
+			return mismatch()
+		}
+	case reflect.Map:
+		switch target.Kind() {
+		case reflect.Map:
+			if target.IsNil() {
+				target.Set(reflect.MakeMap(target.Type()))
+			}
+			for i := 0; i < item.length; i++ {
+				key := reflect.New(target.Type().Key()).Elem()
+				if err := decoder.decode(key); err != nil {
+					return err
+				}
+				if key.Kind() == reflect.Interface && !key.IsNil() && !key.Elem().Type().Comparable() {
+					return fmt.Errorf("msgpack: unhashable map key %s", key.Elem().Type())
+				}
+				value := reflect.New(target.Type().Elem()).Elem()
+				if err := decoder.decode(value); err != nil {
+					return err
+				}
+				target.SetMapIndex(key, value)
+			}
+		case reflect.Struct:
+			fields := map[string]int{}
+			for _, field := range msgpackFields(target.Type()) {
+				fields[field.name] = field.index
+			}
+			for i := 0; i < item.length; i++ {
+				var name string
+				if err := decoder.decode(reflect.ValueOf(&name).Elem()); err != nil {
+					return err
+				}
+				index, ok := fields[name]
+				if !ok {
+					// Skip unknown fields.
+					var skipped any
+					if err := decoder.decode(reflect.ValueOf(&skipped).Elem()); err != nil {
+						return err
+					}
+					continue
+				}
+				if err := decoder.decode(target.Field(index)); err != nil {
+					return err
+				}
+			}
+		default:
+			return mismatch()
+		}
+	}
+	return nil
+}
+
+// setNumber stores a decoded number, checking it fits the target.
+func (decoder *msgpackDecoder) setNumber(item msgpackItem, target reflect.Value, mismatch func() error) error {
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := item.i
+		switch item.kind {
+		case reflect.Uint64:
+			if item.u > math.MaxInt64 {
+				return fmt.Errorf("msgpack: %d overflows %s", item.u, target.Type())
+			}
+			n = int64(item.u)
+		case reflect.Float64:
+			return mismatch()
+		}
+		if target.OverflowInt(n) {
+			return fmt.Errorf("msgpack: %d overflows %s", n, target.Type())
+		}
+		target.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := item.u
+		switch item.kind {
+		case reflect.Int64:
+			if item.i < 0 {
+				return fmt.Errorf("msgpack: %d overflows %s", item.i, target.Type())
+			}
+			n = uint64(item.i)
+		case reflect.Float64:
+			return mismatch()
+		}
+		if target.OverflowUint(n) {
+			return fmt.Errorf("msgpack: %d overflows %s", n, target.Type())
+		}
+		target.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f := item.f
+		switch item.kind {
+		case reflect.Int64:
+			f = float64(item.i)
+		case reflect.Uint64:
+			f = float64(item.u)
+		}
+		target.SetFloat(f)
+	default:
+		return mismatch()
+	}
+	return nil
+}
+
+// decodeAny decodes an item into a generic value.
+func (decoder *msgpackDecoder) decodeAny(item msgpackItem) (any, error) {
+	switch item.kind {
+	case reflect.Invalid:
+		return nil, nil
+	case reflect.Bool:
+		return item.b, nil
+	case reflect.Int64:
+		return item.i, nil
+	case reflect.Uint64:
+		return item.u, nil
+	case reflect.Float64:
+		return item.f, nil
+	case reflect.String:
+		b, err := decoder.read(item.length)
+		return string(b), err
+	case reflect.Slice:
+		b, err := decoder.read(item.length)
+		return append([]byte(nil), b...), err
+	case reflect.Array:
+		var values []any
+		err := decoder.decodeItem(item, reflect.ValueOf(&values).Elem())
+		return values, err
+	}
+	var values map[any]any
+	if err := decoder.decodeItem(item, reflect.ValueOf(&values).Elem()); err != nil {
+		return nil, err
+	}
+	byName := make(map[string]any, len(values))
+	for key, value := range values {
+		name, ok := key.(string)
+		if !ok {
+			return values, nil
+		}
+		byName[name] = value
+	}
+	return byName, nil
+}
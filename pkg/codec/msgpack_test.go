@@ -0,0 +1,127 @@
+package codec_test
+
+import (
+	"bytes"
+	. "fun/pkg/codec"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_MsgPackFormat(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected []byte
+	}{
+		{nil, []byte{0xc0}},
+		{true, []byte{0xc3}},
+		{false, []byte{0xc2}},
+		{1, []byte{0x01}},
+		{-1, []byte{0xff}},
+		{200, []byte{0xcc, 0xc8}},
+		{-100, []byte{0xd0, 0x9c}},
+		{70000, []byte{0xce, 0x00, 0x01, 0x11, 0x70}},
+		{"hi", []byte{0xa2, 'h', 'i'}},
+		{[]byte{1, 2}, []byte{0xc4, 0x02, 0x01, 0x02}},
+		{[]int{1, 2}, []byte{0x92, 0x01, 0x02}},
+		{map[string]int{"a": 1}, []byte{0x81, 0xa1, 'a', 0x01}},
+		{1.5, []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+	}
+	for _, test := range tests {
+		data, err := MarshalMsgPack(test.value)
+		if err != nil {
+			t.Error("unexpected error encoding", test.value, err)
+			continue
+		}
+		if !bytes.Equal(data, test.expected) {
+			t.Errorf("encoding %v: expected % x, got % x", test.value, test.expected, data)
+		}
+	}
+}
+
+func Test_MsgPackRoundTrip(t *testing.T) {
+	type nested struct {
+		Values map[string][]int64
+		Ratio  float32
+		Next   *nested
+		Fixed  [2]uint16
+		Raw    []byte `msgpack:"raw"`
+	}
+	value := nested{
+		Values: map[string][]int64{"a": {math.MinInt64, math.MaxInt64}, "b": nil},
+		Ratio:  0.25,
+		Next:   &nested{Fixed: [2]uint16{1, math.MaxUint16}},
+		Fixed:  [2]uint16{3, 4},
+		Raw:    []byte(strings.Repeat("x", 300)),
+	}
+	data, err := MarshalMsgPack(value)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	var decoded nested
+	if err := UnmarshalMsgPack(data, &decoded); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if !reflect.DeepEqual(decoded, value) {
+		t.Errorf("expected %+v, got %+v", value, decoded)
+	}
+
+	var generic any
+	if err := UnmarshalMsgPack(data, &generic); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	fields, ok := generic.(map[string]any)
+	if !ok || fields["Ratio"] != 0.25 || len(fields["raw"].([]byte)) != 300 {
+		t.Errorf("unexpected generic decoding %v", generic)
+	}
+}
+
+func Test_MsgPackTags(t *testing.T) {
+	type tagged struct {
+		Name    string `msgpack:"n"`
+		Skipped string `msgpack:"-"`
+		hidden  string
+	}
+	data, _ := MarshalMsgPack(tagged{"a", "b", "c"})
+	if !bytes.Equal(data, []byte{0x81, 0xa1, 'n', 0xa1, 'a'}) {
+		t.Errorf("expected only the renamed field, got % x", data)
+	}
+	var decoded tagged
+	if err := UnmarshalMsgPack([]byte{0x82, 0xa1, 'n', 0xa1, 'a', 0xa1, 'x', 0x01}, &decoded); err != nil || decoded.Name != "a" {
+		t.Error("expected unknown fields to be skipped, got", decoded, err)
+	}
+}
+
+func Test_MsgPackErrors(t *testing.T) {
+	var small int8
+	data, _ := MarshalMsgPack(1000)
+	if err := UnmarshalMsgPack(data, &small); err == nil {
+		t.Error("expected overflow error")
+	}
+	var u uint
+	data, _ = MarshalMsgPack(-1)
+	if err := UnmarshalMsgPack(data, &u); err == nil {
+		t.Error("expected negative to unsigned error")
+	}
+	var s string
+	if err := UnmarshalMsgPack([]byte{0xa5, 'a'}, &s); err == nil {
+		t.Error("expected short data error")
+	}
+	if err := UnmarshalMsgPack([]byte{0x01, 0x02}, &u); err == nil {
+		t.Error("expected trailing data error")
+	}
+	if err := UnmarshalMsgPack([]byte{0xc1}, &u); err == nil {
+		t.Error("expected unsupported format error")
+	}
+	if err := UnmarshalMsgPack([]byte{0x01}, u); err == nil {
+		t.Error("expected non-pointer target error")
+	}
+	var generic any
+	if err := UnmarshalMsgPack([]byte{0x81, 0x90, 0x01}, &generic); err == nil {
+		t.Error("expected unhashable key error")
+	}
+	if _, err := MarshalMsgPack(make(chan int)); err == nil {
+		t.Error("expected unsupported type error")
+	}
+}
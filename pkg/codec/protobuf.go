@@ -0,0 +1,73 @@
+package codec
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Protocol buffer wire types.
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+// AppendProtoBytes appends a length-delimited field of a protocol buffer
+// message, which holds a bytes, string, or embedded message field, numbered
+// field, which must be positive.
+func AppendProtoBytes(data []byte, field int, value []byte) []byte {
+	data = binary.AppendUvarint(data, uint64(field)<<3|protoBytes)
+	data = binary.AppendUvarint(data, uint64(len(value)))
+	return append(data, value...)
+}
+
+// ReadProtoBytes calls fn with the number and contents of each
+// length-delimited field of a protocol buffer message, in order. Fields of
+// other wire types are skipped, as a reader of an older schema skips fields
+// it does not know.
+func ReadProtoBytes(data []byte, fn func(field int, value []byte) error) error {
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errors.New("protobuf: malformed tag")
+		}
+		data = data[n:]
+		field := tag >> 3
+		if field == 0 || field > 1<<29-1 {
+			return fmt.Errorf("protobuf: invalid field number %d", field)
+		}
+		var size uint64
+		switch tag & 7 {
+		case protoVarint:
+			if _, n = binary.Uvarint(data); n <= 0 {
+				return fmt.Errorf("protobuf: malformed varint in field %d", field)
+			}
+			size = uint64(n)
+		case protoFixed64:
+			size = 8
+		case protoFixed32:
+			size = 4
+		case protoBytes:
+			if size, n = binary.Uvarint(data); n <= 0 {
+				return fmt.Errorf("protobuf: malformed length in field %d", field)
+			}
+			data = data[n:]
+		default:
+			return fmt.Errorf("protobuf: unsupported wire type %d in field %d", tag&7, field)
+		}
+		if size > uint64(len(data)) {
+			return fmt.Errorf("protobuf: field %d is truncated", field)
+		}
+		value := data[:size]
+		data = data[size:]
+		if tag&7 != protoBytes {
+			continue
+		}
+		if err := fn(int(field), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
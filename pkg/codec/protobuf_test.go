@@ -0,0 +1,39 @@
+package codec_test
+
+import (
+	"bytes"
+	"errors"
+	. "fun/pkg/codec"
+	"testing"
+)
+
+func Test_ProtoBytes(t *testing.T) {
+	data := AppendProtoBytes(nil, 1, []byte("hi"))
+	data = AppendProtoBytes(data, 20, nil)
+	if expected := []byte{0x0a, 0x02, 'h', 'i', 0xa2, 0x01, 0x00}; !bytes.Equal(data, expected) {
+		t.Errorf("expected % x, got % x", expected, data)
+	}
+
+	// A varint, fixed64, and fixed32 field are skipped.
+	data = append([]byte{0x08, 0x96, 0x01, 0x11, 1, 2, 3, 4, 5, 6, 7, 8, 0x1d, 1, 2, 3, 4}, data...)
+	var fields []int
+	var values []string
+	err := ReadProtoBytes(data, func(field int, value []byte) error {
+		fields = append(fields, field)
+		values = append(values, string(value))
+		return nil
+	})
+	if err != nil || len(fields) != 2 || fields[0] != 1 || fields[1] != 20 || values[0] != "hi" || values[1] != "" {
+		t.Error("unexpected fields", fields, values, err)
+	}
+
+	stop := errors.New("stop")
+	if err := ReadProtoBytes(data, func(int, []byte) error { return stop }); err != stop {
+		t.Error("expected the error of fn, got", err)
+	}
+	for _, malformed := range [][]byte{{0x0a, 0x05, 'h'}, {0x0a}, {0x00}, {0x0b}, {0x80}, {0x09, 1, 2}} {
+		if err := ReadProtoBytes(malformed, func(int, []byte) error { return nil }); err == nil {
+			t.Errorf("expected an error reading % x", malformed)
+		}
+	}
+}
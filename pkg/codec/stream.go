@@ -0,0 +1,186 @@
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// DefaultChunkSize is the number of values per chunk when a StreamWriter is
+// created without a positive chunk size.
+const DefaultChunkSize = 1024
+
+// StreamWriter writes values in chunks, each a count followed by that many
+// length-prefixed records, and ends the stream with an empty chunk. Only one
+// chunk is held in memory at a time, so structures of any size can be
+// written without encoding them into a single byte slice.
+type StreamWriter[T any] struct {
+	codec     Codec[T]     // Encodes each value.
+	writer    io.Writer    // Destination of chunks.
+	chunkSize int          // Values per chunk.
+	chunk     bytes.Buffer // Records of the pending chunk.
+	count     int          // Values in the pending chunk.
+	buffer    []byte       // Scratch space for varints.
+	closed    bool         // Whether the end of the stream was written.
+}
+
+// Create a new stream writer of values encoded by a codec.
+func NewStreamWriter[T any](w io.Writer, codec Codec[T], chunkSize int) *StreamWriter[T] {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	return &StreamWriter[T]{codec: codec, writer: w, chunkSize: chunkSize, buffer: make([]byte, binary.MaxVarintLen64)}
+}
+
+// Write encodes a value into the pending chunk, writing the chunk once it is
+// full.
+func (writer *StreamWriter[T]) Write(value T) error {
+	if writer.closed {
+		return errors.New("codec: stream is closed")
+	}
+	data, err := writer.codec.Encode(value)
+	if err != nil {
+		return err
+	}
+	size := binary.PutUvarint(writer.buffer, uint64(len(data)))
+	writer.chunk.Write(writer.buffer[:size])
+	writer.chunk.Write(data)
+	writer.count++
+	if writer.count >= writer.chunkSize {
+		return writer.Flush()
+	}
+	return nil
+}
+
+// Flush writes the pending chunk, if it has any values.
+func (writer *StreamWriter[T]) Flush() error {
+	if writer.count == 0 {
+		return nil
+	}
+	size := binary.PutUvarint(writer.buffer, uint64(writer.count))
+	if _, err := writer.writer.Write(writer.buffer[:size]); err != nil {
+		return err
+	}
+	if _, err := writer.writer.Write(writer.chunk.Bytes()); err != nil {
+		return err
+	}
+	writer.chunk.Reset()
+	writer.count = 0
+	return nil
+}
+
+// Close flushes the pending chunk and marks the end of the stream. It does
+// not close the destination.
+func (writer *StreamWriter[T]) Close() error {
+	if writer.closed {
+		return nil
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	writer.closed = true
+	_, err := writer.writer.Write([]byte{0})
+	return err
+}
+
+// Checkpoint is the position of a StreamReader, from which decoding can be
+// resumed after an error.
+type Checkpoint struct {
+	Offset    int64 // Bytes consumed from the start of the stream.
+	Values    int64 // Values decoded.
+	Remaining int   // Records left in the current chunk.
+}
+
+// StreamReader reads values written by a StreamWriter one at a time.
+type StreamReader[T any] struct {
+	codec      Codec[T]     // Decodes each value.
+	reader     *countReader // Counted source.
+	checkpoint Checkpoint   // Position after the last complete read.
+	done       bool         // Whether the end of the stream was read.
+}
+
+// Create a new stream reader of values decoded by a codec.
+func NewStreamReader[T any](r io.Reader, codec Codec[T]) *StreamReader[T] {
+	return ResumeStreamReader(r, codec, Checkpoint{})
+}
+
+// ResumeStreamReader continues reading a stream from a checkpoint. The
+// source must be positioned at checkpoint.Offset bytes into the stream.
+func ResumeStreamReader[T any](r io.Reader, codec Codec[T], checkpoint Checkpoint) *StreamReader[T] {
+	return &StreamReader[T]{codec: codec, reader: &countReader{bufio.NewReader(r), 0}, checkpoint: checkpoint}
+}
+
+// Checkpoint reports the position after the last complete read. After an
+// error, reading can be resumed from it with ResumeStreamReader.
+func (reader *StreamReader[T]) Checkpoint() Checkpoint {
+	return reader.checkpoint
+}
+
+// Read decodes the next value, or returns io.EOF at the end of the stream.
+func (reader *StreamReader[T]) Read() (T, error) {
+	var unset T
+	if reader.done {
+		return unset, io.EOF
+	}
+	if reader.checkpoint.Remaining == 0 {
+		count, err := binary.ReadUvarint(reader.reader)
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return unset, err
+		}
+		if count > uint64(int(^uint(0)>>1)) {
+			return unset, errors.New("codec: invalid count")
+		}
+		reader.advance(0, int(count))
+		if count == 0 {
+			reader.done = true
+			return unset, io.EOF
+		}
+	}
+	data, err := readRecord(reader.reader)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return unset, err
+	}
+	value, err := reader.codec.Decode(data)
+	if err != nil {
+		return unset, err
+	}
+	reader.advance(1, reader.checkpoint.Remaining-1)
+	return value, nil
+}
+
+// advance moves the checkpoint past the bytes consumed since it was last
+// moved.
+func (reader *StreamReader[T]) advance(values int64, remaining int) {
+	reader.checkpoint.Offset += reader.reader.count
+	reader.checkpoint.Values += values
+	reader.checkpoint.Remaining = remaining
+	reader.reader.count = 0
+}
+
+// countReader counts the bytes read through it.
+type countReader struct {
+	reader *bufio.Reader // Buffered source.
+	count  int64         // Bytes read since the count was reset.
+}
+
+func (reader *countReader) Read(p []byte) (int, error) {
+	n, err := reader.reader.Read(p)
+	reader.count += int64(n)
+	return n, err
+}
+
+func (reader *countReader) ReadByte() (byte, error) {
+	b, err := reader.reader.ReadByte()
+	if err == nil {
+		reader.count++
+	}
+	return b, err
+}
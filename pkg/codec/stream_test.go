@@ -0,0 +1,82 @@
+package codec_test
+
+import (
+	"bytes"
+	"errors"
+	. "fun/pkg/codec"
+	"io"
+	"testing"
+)
+
+func Test_Stream(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewStreamWriter(&buffer, JSON[int](), 3)
+	for i := 0; i < 10; i++ {
+		if err := writer.Write(i); err != nil {
+			t.Fatal("unexpected error", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if err := writer.Write(10); err == nil {
+		t.Error("expected error writing to closed stream")
+	}
+
+	reader := NewStreamReader(bytes.NewReader(buffer.Bytes()), JSON[int]())
+	for i := 0; i < 10; i++ {
+		value, err := reader.Read()
+		if err != nil || value != i {
+			t.Fatalf("expected %d, got %d %v", i, value, err)
+		}
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		t.Error("expected io.EOF, got", err)
+	}
+	if checkpoint := reader.Checkpoint(); checkpoint.Values != 10 || checkpoint.Offset != int64(buffer.Len()) {
+		t.Error("unexpected checkpoint", checkpoint)
+	}
+}
+
+func Test_StreamResume(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewStreamWriter(&buffer, MsgPack[string](), 2)
+	for _, value := range []string{"a", "b", "c", "d", "e"} {
+		writer.Write(value)
+	}
+	writer.Close()
+	data := buffer.Bytes()
+
+	// Cut the stream in the middle of the second chunk.
+	reader := NewStreamReader(bytes.NewReader(data[:8]), MsgPack[string]())
+	var values []string
+	var err error
+	for err == nil {
+		var value string
+		if value, err = reader.Read(); err == nil {
+			values = append(values, value)
+		}
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Error("expected io.ErrUnexpectedEOF, got", err)
+	}
+
+	checkpoint := reader.Checkpoint()
+	reader = ResumeStreamReader(bytes.NewReader(data[checkpoint.Offset:]), MsgPack[string](), checkpoint)
+	for {
+		value, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal("unexpected error", err)
+		}
+		values = append(values, value)
+	}
+	if len(values) != 5 || values[2] != "c" || values[4] != "e" {
+		t.Error("expected all values after resuming, got", values)
+	}
+	if reader.Checkpoint().Values != 5 {
+		t.Error("unexpected checkpoint", reader.Checkpoint())
+	}
+}
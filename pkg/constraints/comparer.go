@@ -0,0 +1,167 @@
+package constraints
+
+import (
+	"bytes"
+	"hash/maphash"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Comparer orders values. Compare returns a negative number if a is before
+// b, a positive number if a is after b, and 0 if they are equivalent.
+type Comparer[T any] interface {
+	Compare(a, b T) int
+}
+
+// Hasher hashes values for hashed structures. Equivalent values must have
+// equal hashes.
+type Hasher[T any] interface {
+	Hash(value T) uint64
+	Equal(a, b T) bool
+}
+
+// ComparerFunc adapts a comparison function to a Comparer.
+type ComparerFunc[T any] func(a, b T) int
+
+// Compare calls f.
+func (f ComparerFunc[T]) Compare(a, b T) int {
+	return f(a, b)
+}
+
+// orderedComparer compares values with the built-in operators.
+type orderedComparer[T Ordered] struct{}
+
+// OrderedComparer creates a comparer using < on ordered values. NaN is
+// ordered before every other float.
+func OrderedComparer[T Ordered]() Comparer[T] {
+	return orderedComparer[T]{}
+}
+
+func (orderedComparer[T]) Compare(a, b T) int {
+	aNaN, bNaN := a != a, b != b
+	switch {
+	case aNaN && bNaN:
+		return 0
+	case aNaN || a < b:
+		return -1
+	case bNaN || a > b:
+		return 1
+	}
+	return 0
+}
+
+// Reverse creates a comparer in the opposite order.
+func Reverse[T any](comparer Comparer[T]) Comparer[T] {
+	return ComparerFunc[T](func(a, b T) int {
+		return comparer.Compare(b, a)
+	})
+}
+
+// comparableHasher hashes comparable values with hash/maphash.
+type comparableHasher[T Hashable] struct {
+	seed maphash.Seed // Randomizes hashes per hasher.
+}
+
+// ComparableHasher creates a hasher of comparable values using ==.
+func ComparableHasher[T Hashable]() Hasher[T] {
+	return comparableHasher[T]{maphash.MakeSeed()}
+}
+
+func (hasher comparableHasher[T]) Hash(value T) uint64 {
+	return maphash.Comparable(hasher.seed, value)
+}
+
+func (comparableHasher[T]) Equal(a, b T) bool {
+	return a == b
+}
+
+// bytesKeys compares and hashes byte slices by content.
+type bytesKeys struct {
+	seed maphash.Seed // Randomizes hashes per hasher.
+}
+
+// BytesComparer compares byte slices lexicographically.
+func BytesComparer() Comparer[[]byte] {
+	return bytesKeys{}
+}
+
+// BytesHasher hashes byte slices by content.
+func BytesHasher() Hasher[[]byte] {
+	return bytesKeys{maphash.MakeSeed()}
+}
+
+func (bytesKeys) Compare(a, b []byte) int {
+	return bytes.Compare(a, b)
+}
+
+func (keys bytesKeys) Hash(value []byte) uint64 {
+	return maphash.Bytes(keys.seed, value)
+}
+
+func (bytesKeys) Equal(a, b []byte) bool {
+	return bytes.Equal(a, b)
+}
+
+// foldKeys compares and hashes strings ignoring case.
+type foldKeys struct {
+	seed maphash.Seed // Randomizes hashes per hasher.
+}
+
+// FoldComparer compares strings ignoring case, by their simple Unicode case
+// folding.
+func FoldComparer() Comparer[string] {
+	return foldKeys{}
+}
+
+// FoldHasher hashes strings ignoring case, consistent with strings.EqualFold.
+func FoldHasher() Hasher[string] {
+	return foldKeys{maphash.MakeSeed()}
+}
+
+func (foldKeys) Compare(a, b string) int {
+	for a != "" && b != "" {
+		ra, sizeA := utf8.DecodeRuneInString(a)
+		rb, sizeB := utf8.DecodeRuneInString(b)
+		if fa, fb := foldRune(ra), foldRune(rb); fa != fb {
+			if fa < fb {
+				return -1
+			}
+			return 1
+		}
+		a, b = a[sizeA:], b[sizeB:]
+	}
+	switch {
+	case a != "":
+		return 1
+	case b != "":
+		return -1
+	}
+	return 0
+}
+
+func (keys foldKeys) Hash(value string) uint64 {
+	var h maphash.Hash
+	h.SetSeed(keys.seed)
+	buffer := make([]byte, 0, utf8.UTFMax)
+	for _, r := range value {
+		h.Write(utf8.AppendRune(buffer, foldRune(r)))
+	}
+	return h.Sum64()
+}
+
+func (foldKeys) Equal(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
+
+// foldRune maps a rune to the smallest rune equivalent under simple case
+// folding, so equal-folding strings fold to the same runes.
+func foldRune(r rune) rune {
+	smallest := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < smallest {
+			smallest = f
+		}
+	}
+	return smallest
+}
@@ -0,0 +1,41 @@
+// Package constraints defines type constraints for generic structures, and
+// the Comparer and Hasher interfaces keyed structures use to order and hash
+// keys that cannot be compared with the built-in operators, such as
+// byte slices or case-insensitive strings.
+package constraints
+
+// Signed is any signed integer type.
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Unsigned is any unsigned integer type.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Integer is any integer type.
+type Integer interface {
+	Signed | Unsigned
+}
+
+// Float is any floating-point type.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Number is any integer or floating-point type.
+type Number interface {
+	Integer | Float
+}
+
+// Ordered is any type supporting the < operator.
+type Ordered interface {
+	Integer | Float | ~string
+}
+
+// Hashable is any type usable as a built-in map key. Keys that are not
+// comparable use a Hasher instead.
+type Hashable interface {
+	comparable
+}
@@ -0,0 +1,91 @@
+package constraints_test
+
+import (
+	. "fun/pkg/constraints"
+	"math"
+	"sort"
+	"testing"
+)
+
+// sum adds numbers of any type.
+func sum[T Number](values ...T) T {
+	var total T
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
+
+// maximum gets the largest ordered value.
+func maximum[T Ordered](values ...T) T {
+	largest := values[0]
+	for _, value := range values[1:] {
+		if value > largest {
+			largest = value
+		}
+	}
+	return largest
+}
+
+func Test_Constraints(t *testing.T) {
+	if sum(1, 2, 3) != 6 || sum(0.5, 0.25) != 0.75 || sum[uint8](200, 100) != 44 {
+		t.Error("unexpected sums")
+	}
+	if maximum("b", "c", "a") != "c" || maximum(-1, -3) != -1 {
+		t.Error("unexpected maximums")
+	}
+}
+
+func Test_OrderedComparer(t *testing.T) {
+	values := []float64{3, math.NaN(), -1, 2}
+	comparer := OrderedComparer[float64]()
+	sort.Slice(values, func(i, j int) bool { return comparer.Compare(values[i], values[j]) < 0 })
+	if !math.IsNaN(values[0]) || values[1] != -1 || values[3] != 3 {
+		t.Error("unexpected order", values)
+	}
+	if comparer.Compare(math.NaN(), math.NaN()) != 0 || comparer.Compare(1, 1) != 0 {
+		t.Error("expected equal values to compare as 0")
+	}
+	if Reverse(comparer).Compare(1, 2) <= 0 {
+		t.Error("expected reversed order")
+	}
+	byLength := ComparerFunc[string](func(a, b string) int { return len(a) - len(b) })
+	if byLength.Compare("aa", "b") <= 0 {
+		t.Error("expected longer string after shorter")
+	}
+}
+
+func Test_Hashers(t *testing.T) {
+	type point struct{ X, Y int }
+	points := ComparableHasher[point]()
+	if points.Hash(point{1, 2}) != points.Hash(point{1, 2}) || !points.Equal(point{1, 2}, point{1, 2}) {
+		t.Error("expected equal struct keys to hash equally")
+	}
+	if points.Equal(point{1, 2}, point{2, 1}) {
+		t.Error("expected different struct keys to differ")
+	}
+
+	keys := BytesHasher()
+	if keys.Hash([]byte("key")) != keys.Hash([]byte("key")) || !keys.Equal([]byte("key"), []byte("key")) {
+		t.Error("expected equal byte keys to hash equally")
+	}
+	if BytesComparer().Compare([]byte("a"), []byte("b")) >= 0 {
+		t.Error("expected lexicographic byte order")
+	}
+}
+
+func Test_Fold(t *testing.T) {
+	hasher := FoldHasher()
+	comparer := FoldComparer()
+	for _, pair := range [][2]string{{"Go", "gO"}, {"STRASSE", "strasse"}, {"K", "k"}, {"", ""}} {
+		if !hasher.Equal(pair[0], pair[1]) || hasher.Hash(pair[0]) != hasher.Hash(pair[1]) {
+			t.Errorf("expected %q and %q to hash equally", pair[0], pair[1])
+		}
+		if comparer.Compare(pair[0], pair[1]) != 0 {
+			t.Errorf("expected %q and %q to compare equal", pair[0], pair[1])
+		}
+	}
+	if comparer.Compare("apple", "Banana") >= 0 || comparer.Compare("ab", "A") <= 0 {
+		t.Error("unexpected case-insensitive order")
+	}
+}
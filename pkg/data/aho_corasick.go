@@ -0,0 +1,168 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// ahoState is a state of an AhoCorasick automaton, the node of a pattern
+// prefix in a trie of the patterns.
+type ahoState struct {
+	labels   []byte // Byte leading to each child, in increasing order.
+	children []int  // Child state for each label.
+	fail     int    // State of the longest proper suffix that is a prefix.
+	output   int    // Nearest state along fail links that ends a pattern, or -1.
+	patterns []int  // Equal patterns ending at the state.
+}
+
+// AhoCorasickMatch is an occurrence of a pattern in a text.
+type AhoCorasickMatch struct {
+	Pattern int // Index of the pattern.
+	Start   int // Offset of the first byte of the occurrence.
+	End     int // Offset after the last byte of the occurrence.
+}
+
+// AhoCorasick is an automaton that finds every occurrence of a set of
+// patterns in one pass over a text, in O(n + m + z) time for a text of
+// length n, patterns of total length m, and z occurrences. It is a trie of
+// the patterns whose states link to the state of their longest suffix in the
+// trie, to follow on a mismatch. An automaton is immutable and safe for
+// concurrent use.
+type AhoCorasick struct {
+	states  []ahoState // States by index, the root first.
+	lengths []int      // Length of each pattern.
+}
+
+// Create a new Aho-Corasick automaton of patterns, numbered in order. Empty
+// patterns never match, and duplicates match together.
+func NewAhoCorasick(patterns ...[]byte) *AhoCorasick {
+	automaton := &AhoCorasick{states: []ahoState{{output: -1}}}
+	for i, pattern := range patterns {
+		automaton.lengths = append(automaton.lengths, len(pattern))
+		if len(pattern) == 0 {
+			continue
+		}
+		state := 0
+		for _, b := range pattern {
+			j, found := slices.BinarySearch(automaton.states[state].labels, b)
+			if !found {
+				automaton.states = append(automaton.states, ahoState{output: -1})
+				parent := &automaton.states[state]
+				parent.labels = slices.Insert(parent.labels, j, b)
+				parent.children = slices.Insert(parent.children, j, len(automaton.states)-1)
+			}
+			state = automaton.states[state].children[j]
+		}
+		automaton.states[state].patterns = append(automaton.states[state].patterns, i)
+	}
+	// Link states breadth first, so the suffixes of each state, which are
+	// shorter, are linked before it.
+	queue := NewQueue[int](WithLocking(false))
+	for _, child := range automaton.states[0].children {
+		queue.Enqueue(child)
+	}
+	for state, ok := queue.Dequeue(); ok; state, ok = queue.Dequeue() {
+		for i, child := range automaton.states[state].children {
+			fail := automaton.next(automaton.states[state].fail, automaton.states[state].labels[i])
+			automaton.states[child].fail = fail
+			if len(automaton.states[fail].patterns) > 0 {
+				automaton.states[child].output = fail
+			} else {
+				automaton.states[child].output = automaton.states[fail].output
+			}
+			queue.Enqueue(child)
+		}
+	}
+	return automaton
+}
+
+// next gets the state after reading a byte in a state, following fail links
+// until a state has a child for it.
+func (automaton *AhoCorasick) next(state int, b byte) int {
+	for {
+		current := &automaton.states[state]
+		if i, found := slices.BinarySearch(current.labels, b); found {
+			return current.children[i]
+		}
+		if state == 0 {
+			return 0
+		}
+		state = current.fail
+	}
+}
+
+// scan feeds text starting at offset through the automaton from a state,
+// calling fn on each match until it returns false, and returns the state
+// after the text and whether fn stopped.
+func (automaton *AhoCorasick) scan(state, offset int, text []byte, fn func(AhoCorasickMatch) bool) (int, bool) {
+	for i, b := range text {
+		state = automaton.next(state, b)
+		end := offset + i + 1
+		for output := state; output >= 0; output = automaton.states[output].output {
+			for _, pattern := range automaton.states[output].patterns {
+				if !fn(AhoCorasickMatch{pattern, end - automaton.lengths[pattern], end}) {
+					return state, true
+				}
+			}
+		}
+	}
+	return state, false
+}
+
+// FindAll finds every occurrence of the patterns in text, ordered by end and
+// then from the longest.
+func (automaton *AhoCorasick) FindAll(text []byte) []AhoCorasickMatch {
+	if automaton == nil {
+		return nil
+	}
+	var matches []AhoCorasickMatch
+	automaton.scan(0, 0, text, func(match AhoCorasickMatch) bool {
+		matches = append(matches, match)
+		return true
+	})
+	return matches
+}
+
+// Contains reports whether any pattern occurs in text.
+func (automaton *AhoCorasick) Contains(text []byte) bool {
+	if automaton == nil {
+		return false
+	}
+	_, stopped := automaton.scan(0, 0, text, func(AhoCorasickMatch) bool { return false })
+	return stopped
+}
+
+// Scan streams text from a reader through the automaton, calling fn on each
+// occurrence of the patterns, with offsets from the start of the stream,
+// until fn returns false or the reader is at end of file. Occurrences that
+// span reads are found, since the automaton keeps its state between them.
+func (automaton *AhoCorasick) Scan(reader io.Reader, fn func(AhoCorasickMatch) bool) error {
+	if automaton == nil {
+		return nilError("aho-corasick automaton")
+	}
+	buffer := make([]byte, 32*1024)
+	state, offset := 0, 0
+	for {
+		n, err := reader.Read(buffer)
+		var stopped bool
+		state, stopped = automaton.scan(state, offset, buffer[:n], fn)
+		offset += n
+		if stopped || errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// String converts AhoCorasick data into a string of the number of patterns
+// and states.
+func (automaton *AhoCorasick) String() string {
+	if automaton == nil {
+		return ""
+	}
+	return fmt.Sprintf("Patterns: %d, States: %d", len(automaton.lengths), len(automaton.states))
+}
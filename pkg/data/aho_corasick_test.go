@@ -0,0 +1,82 @@
+package data_test
+
+import (
+	"bytes"
+	"errors"
+	. "fun/pkg/data"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func Test_AhoCorasick(t *testing.T) {
+	automaton := NewAhoCorasick([]byte("he"), []byte("she"), []byte("his"), []byte("hers"), nil)
+	matches := automaton.FindAll([]byte("ushers"))
+	expected := []AhoCorasickMatch{{1, 1, 4}, {0, 2, 4}, {3, 2, 6}}
+	if !slices.Equal(matches, expected) {
+		t.Error("unexpected matches", matches)
+	}
+	if !automaton.Contains([]byte("this")) || automaton.Contains([]byte("hi")) {
+		t.Error("unexpected containment")
+	}
+
+	// Matches spanning reads of a stream are found, at stream offsets.
+	var streamed []AhoCorasickMatch
+	err := automaton.Scan(iotest.OneByteReader(strings.NewReader("ushers")), func(match AhoCorasickMatch) bool {
+		streamed = append(streamed, match)
+		return true
+	})
+	if err != nil || !slices.Equal(streamed, expected) {
+		t.Error("unexpected streamed matches", streamed, err)
+	}
+	streamed = nil
+	automaton.Scan(strings.NewReader("ushers"), func(match AhoCorasickMatch) bool {
+		streamed = append(streamed, match)
+		return false
+	})
+	if len(streamed) != 1 {
+		t.Error("expected the scan to stop after the first match", streamed)
+	}
+	if err := automaton.Scan(iotest.ErrReader(iotest.ErrTimeout), func(AhoCorasickMatch) bool { return true }); !errors.Is(err, iotest.ErrTimeout) {
+		t.Error("expected the read error, got", err)
+	}
+}
+
+func Test_AhoCorasickRandom(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	word := func(n int) []byte {
+		word := make([]byte, n)
+		for i := range word {
+			word[i] = byte('a' + random.Intn(2))
+		}
+		return word
+	}
+	for run := 0; run < 200; run++ {
+		patterns := make([][]byte, 1+random.Intn(5))
+		for i := range patterns {
+			patterns[i] = word(1 + random.Intn(4))
+		}
+		text := word(random.Intn(50))
+		matches := NewAhoCorasick(patterns...).FindAll(text)
+		// Every occurrence is found exactly once, ending where it should.
+		var expected []AhoCorasickMatch
+		for end := 1; end <= len(text); end++ {
+			for i, pattern := range patterns {
+				if bytes.HasSuffix(text[:end], pattern) {
+					expected = append(expected, AhoCorasickMatch{i, end - len(pattern), end})
+				}
+			}
+		}
+		compare := func(a, b AhoCorasickMatch) int {
+			if a.End != b.End {
+				return a.End - b.End
+			}
+			return a.Pattern - b.Pattern
+		}
+		if !slices.Equal(slices.SortedFunc(slices.Values(matches), compare), expected) {
+			t.Fatalf("%q in %q: found %v, expected %v", patterns, text, matches, expected)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package data
+
+import "sync"
+
+// DefaultArenaChunk is the number of values per chunk of an Arena created
+// with a chunk size of 0.
+const DefaultArenaChunk = 1024
+
+// Arena allocates values of type N from chunks, so building a large
+// structure takes one allocation per chunk instead of one per node. Values
+// are not freed individually; Release drops every chunk at once. Lists
+// allocate from an Arena[ListNode[T]] given to WithArena.
+type Arena[N any] struct {
+	chunkSize int         // Values per chunk.
+	chunks    [][]N       // Chunks allocated so far, the last one in use.
+	used      int         // Values taken from the last chunk.
+	allocated int         // Values allocated since the last Release.
+	mux       *sync.Mutex // Lock allocation.
+}
+
+// Create a new arena allocating chunkSize values per chunk, or
+// DefaultArenaChunk if chunkSize is 0.
+func NewArena[N any](chunkSize int) *Arena[N] {
+	if chunkSize <= 0 {
+		chunkSize = DefaultArenaChunk
+	}
+	return &Arena[N]{chunkSize: chunkSize, mux: &sync.Mutex{}}
+}
+
+// Alloc gets a pointer to a new zero value.
+func (arena *Arena[N]) Alloc() *N {
+	arena.mux.Lock()
+	defer arena.mux.Unlock()
+	if len(arena.chunks) == 0 || arena.used == arena.chunkSize {
+		arena.chunks = append(arena.chunks, make([]N, arena.chunkSize))
+		arena.used = 0
+	}
+	value := &arena.chunks[len(arena.chunks)-1][arena.used]
+	arena.used++
+	arena.allocated++
+	return value
+}
+
+// Allocated reports the number of values allocated since the last Release.
+func (arena *Arena[N]) Allocated() int {
+	arena.mux.Lock()
+	defer arena.mux.Unlock()
+	return arena.allocated
+}
+
+// Release drops every chunk. Chunks are never reused, so structures still
+// holding values from the arena stay valid, and the memory is reclaimed once
+// they are gone too.
+func (arena *Arena[N]) Release() {
+	arena.mux.Lock()
+	defer arena.mux.Unlock()
+	arena.chunks, arena.used, arena.allocated = nil, 0, 0
+}
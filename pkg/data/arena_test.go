@@ -0,0 +1,40 @@
+package data_test
+
+import (
+	. "fun/pkg/data"
+	"testing"
+)
+
+func Test_Arena(t *testing.T) {
+	arena := NewArena[ListNode[Data]](4)
+	list := NewList[Data](WithArena(arena))
+	for i := 0; i < 10; i++ {
+		list.Append(Data(i))
+	}
+	list.Insert(-1)
+	if arena.Allocated() != 11 {
+		t.Error("expected 11 nodes from the arena, got", arena.Allocated())
+	}
+	list.Delete(5)
+	listAssert(t, list, []Data{-1, 0, 1, 2, 3, 4, 6, 7, 8, 9})
+
+	arena.Release()
+	if arena.Allocated() != 0 {
+		t.Error("expected an empty arena after Release")
+	}
+	list.Append(10)
+	listAssert(t, list, []Data{-1, 0, 1, 2, 3, 4, 6, 7, 8, 9, 10})
+
+	pooled := NewList[Data](WithArena(arena), WithNodePool())
+	pooled.Append(1)
+	pooled.DeleteHead()
+	pooled.Append(2)
+	listAssert(t, pooled, []Data{2})
+
+	allocated := arena.Allocated()
+	other := NewList[label](WithArena(arena))
+	other.Append("a")
+	if arena.Allocated() != allocated {
+		t.Error("expected an arena of another node type to be ignored")
+	}
+}
@@ -0,0 +1,458 @@
+package data_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"fun/pkg/constraints"
+	. "fun/pkg/data"
+	"testing"
+)
+
+// benchSize is the number of elements preloaded for benchmarks.
+const benchSize = 100
+
+// benchList is the interface shared by the list implementations.
+type benchList interface {
+	Insert(Data) error
+	Append(Data) error
+	Delete(Data) bool
+	DeleteHead() (Data, bool)
+	DeleteTail() (Data, bool)
+	Length() int
+}
+
+// listImplementations are benchmarked side by side.
+var listImplementations = []struct {
+	name string
+	new  func() benchList
+}{
+	{"List", func() benchList { return NewList[Data]() }},
+	{"UnlockedList", func() benchList { return NewList[Data](WithLocking(false)) }},
+	{"PooledList", func() benchList { return NewList[Data](WithNodePool()) }},
+	{"UnrolledList", func() benchList { return NewUnrolledList[Data](0) }},
+}
+
+// preload fills a list with benchSize elements.
+func preload(list benchList) benchList {
+	for i := 0; i < benchSize; i++ {
+		list.Append(Data(i))
+	}
+	return list
+}
+
+func BenchmarkList(b *testing.B) {
+	for _, impl := range listImplementations {
+		b.Run("Insert/"+impl.name, func(b *testing.B) {
+			b.ReportAllocs()
+			list := impl.new()
+			for i := 0; i < b.N; i++ {
+				list.Insert(Data(i))
+			}
+		})
+		b.Run("Append/"+impl.name, func(b *testing.B) {
+			b.ReportAllocs()
+			list := impl.new()
+			for i := 0; i < b.N; i++ {
+				list.Append(Data(i))
+			}
+		})
+		b.Run("Delete/"+impl.name, func(b *testing.B) {
+			b.ReportAllocs()
+			list := preload(impl.new())
+			for i := 0; i < b.N; i++ {
+				list.Delete(Data(i % benchSize))
+				list.Append(Data(i % benchSize))
+			}
+		})
+		b.Run("DeleteHead/"+impl.name, func(b *testing.B) {
+			b.ReportAllocs()
+			list := preload(impl.new())
+			for i := 0; i < b.N; i++ {
+				list.DeleteHead()
+				list.Append(Data(i))
+			}
+		})
+		b.Run("DeleteTail/"+impl.name, func(b *testing.B) {
+			b.ReportAllocs()
+			list := preload(impl.new())
+			for i := 0; i < b.N; i++ {
+				list.DeleteTail()
+				list.Insert(Data(i))
+			}
+		})
+	}
+}
+
+func BenchmarkListFind(b *testing.B) {
+	for _, locking := range []bool{true, false} {
+		b.Run(lockingName(locking), func(b *testing.B) {
+			b.ReportAllocs()
+			list := NewList[Data](WithLocking(locking))
+			preload(list)
+			for i := 0; i < b.N; i++ {
+				list.Find(Data(i % benchSize))
+			}
+		})
+	}
+}
+
+func BenchmarkUnrolledListContains(b *testing.B) {
+	b.ReportAllocs()
+	list := NewUnrolledList[Data](0)
+	preload(list)
+	for i := 0; i < b.N; i++ {
+		list.Contains(Data(i % benchSize))
+	}
+}
+
+func BenchmarkListString(b *testing.B) {
+	for _, locking := range []bool{true, false} {
+		b.Run(lockingName(locking), func(b *testing.B) {
+			b.ReportAllocs()
+			list := NewList[Data](WithLocking(locking))
+			preload(list)
+			for i := 0; i < b.N; i++ {
+				_ = list.String()
+			}
+		})
+	}
+}
+
+// lockingName names a benchmark by whether the list takes locks.
+func lockingName(locking bool) string {
+	if locking {
+		return "Locked"
+	}
+	return "Unlocked"
+}
+
+func BenchmarkSnapshot(b *testing.B) {
+	b.ReportAllocs()
+	snapshotter := NewSnapshotter()
+	snapshotter.Register("a", preload(NewList[Data]()).(*List[Data]))
+	snapshotter.Register("b", preload(NewList[Data]()).(*List[Data]))
+	for i := 0; i < b.N; i++ {
+		snapshotter.Snapshot()
+	}
+}
+
+func BenchmarkListBulk(b *testing.B) {
+	values := make([]Data, benchSize)
+	for i := range values {
+		values[i] = Data(i)
+	}
+	b.Run("Append/Loop", func(b *testing.B) {
+		b.ReportAllocs()
+		list := NewList[Data]()
+		for i := 0; i < b.N; i++ {
+			for _, value := range values {
+				list.Append(value)
+			}
+		}
+	})
+	b.Run("Append/AppendAll", func(b *testing.B) {
+		b.ReportAllocs()
+		list := NewList[Data]()
+		for i := 0; i < b.N; i++ {
+			list.AppendAll(values...)
+		}
+	})
+	b.Run("Append/Arena", func(b *testing.B) {
+		b.ReportAllocs()
+		arena := NewArena[ListNode[Data]](0)
+		list := NewList[Data](WithArena(arena))
+		for i := 0; i < b.N; i++ {
+			list.AppendAll(values...)
+		}
+	})
+}
+
+func BenchmarkBlockingQueue(b *testing.B) {
+	b.ReportAllocs()
+	ctx := context.Background()
+	queue := NewBlockingQueue[Data](benchSize)
+	for i := 0; i < b.N; i++ {
+		queue.Put(ctx, Data(i))
+		queue.Take(ctx)
+	}
+}
+
+func BenchmarkQueue(b *testing.B) {
+	for _, locking := range []bool{true, false} {
+		b.Run(lockingName(locking), func(b *testing.B) {
+			b.ReportAllocs()
+			queue := NewQueue[Data](WithLocking(locking))
+			for i := 0; i < benchSize; i++ {
+				queue.Enqueue(Data(i))
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				queue.Enqueue(Data(i))
+				queue.Dequeue()
+			}
+		})
+	}
+}
+
+func BenchmarkPriorityQueue(b *testing.B) {
+	b.ReportAllocs()
+	queue := NewPriorityQueue(func(a, b Data) bool { return a < b })
+	for i := 0; i < benchSize; i++ {
+		queue.Push(Data(i * 7 % benchSize))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		queue.Push(Data(i % benchSize))
+		queue.Pop()
+	}
+}
+
+func BenchmarkMinMaxHeap(b *testing.B) {
+	b.ReportAllocs()
+	heap := NewMinMaxHeap(func(a, b Data) bool { return a < b })
+	for i := 0; i < benchSize; i++ {
+		heap.Push(Data(i * 7 % benchSize))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heap.Push(Data(i % benchSize))
+		if i%2 == 0 {
+			heap.PopMin()
+		} else {
+			heap.PopMax()
+		}
+	}
+}
+
+func BenchmarkPairingHeap(b *testing.B) {
+	b.ReportAllocs()
+	heap := NewPairingHeap(func(a, b Data) bool { return a < b })
+	for i := 0; i < benchSize; i++ {
+		heap.Push(Data(i * 7 % benchSize))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heap.Push(Data(i % benchSize))
+		heap.Pop()
+	}
+}
+
+func BenchmarkFibonacciHeap(b *testing.B) {
+	b.ReportAllocs()
+	heap := NewFibonacciHeap(func(a, b Data) bool { return a < b })
+	for i := 0; i < benchSize; i++ {
+		heap.Push(Data(i * 7 % benchSize))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		heap.Push(Data(i % benchSize))
+		heap.Pop()
+	}
+}
+
+func BenchmarkIndexedPriorityQueue(b *testing.B) {
+	b.ReportAllocs()
+	queue := NewIndexedPriorityQueue[int](func(a, b Data) bool { return a < b })
+	for i := 0; i < benchSize; i++ {
+		queue.Push(i, Data(i*7%benchSize))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		queue.Update(i%benchSize, Data(i*13%benchSize))
+	}
+}
+
+func BenchmarkBST(b *testing.B) {
+	b.ReportAllocs()
+	tree := NewBST[int, Data](constraints.OrderedComparer[int]())
+	for i := 0; i < benchSize; i++ {
+		key := i * 7919 % benchSize
+		tree.Insert(key, Data(key))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Find(i % benchSize)
+	}
+}
+
+func BenchmarkBTree(b *testing.B) {
+	for _, degree := range []int{2, 16} {
+		b.Run(fmt.Sprint("Degree", degree), func(b *testing.B) {
+			b.ReportAllocs()
+			tree := NewBTree[int, Data](degree, constraints.OrderedComparer[int]())
+			for i := 0; i < benchSize; i++ {
+				key := i * 7919 % benchSize
+				tree.Insert(key, Data(key))
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tree.Find(i % benchSize)
+			}
+		})
+	}
+}
+
+func BenchmarkRangeScan(b *testing.B) {
+	btree := NewBTree[int, Data](16, constraints.OrderedComparer[int]())
+	bplus := NewBPlusTree[int, Data](16, constraints.OrderedComparer[int]())
+	for i := 0; i < benchSize; i++ {
+		btree.Insert(i, Data(i))
+		bplus.Insert(i, Data(i))
+	}
+	b.Run("BTree", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for range btree.Range(benchSize/4, benchSize*3/4) {
+			}
+		}
+	})
+	b.Run("BPlusTree", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			bplus.Range(benchSize/4, benchSize*3/4, func(int, Data) bool { return true })
+		}
+	})
+}
+
+func BenchmarkBuildSuffixArray(b *testing.B) {
+	b.ReportAllocs()
+	text := make([]byte, 1<<16)
+	for i := range text {
+		text[i] = "acgt"[i*7919%len(text)%4]
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BuildSuffixArray(text)
+	}
+}
+
+func BenchmarkAhoCorasick(b *testing.B) {
+	automaton := NewAhoCorasick([]byte("acgt"), []byte("ttag"), []byte("gattaca"), []byte("cc"))
+	text := make([]byte, 1<<16)
+	for i := range text {
+		text[i] = "acgt"[i*7919%len(text)%4]
+	}
+	b.SetBytes(int64(len(text)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		automaton.Scan(bytes.NewReader(text), func(AhoCorasickMatch) bool { return true })
+	}
+}
+
+func BenchmarkHashMap(b *testing.B) {
+	b.Run("Put/HashMap", func(b *testing.B) {
+		b.ReportAllocs()
+		hash := NewHashMap[int, Data](WithLocking(false))
+		for i := 0; i < b.N; i++ {
+			hash.Put(i%(benchSize*100), Data(i))
+		}
+	})
+	b.Run("Put/map", func(b *testing.B) {
+		b.ReportAllocs()
+		hash := map[int]Data{}
+		for i := 0; i < b.N; i++ {
+			hash[i%(benchSize*100)] = Data(i)
+		}
+	})
+	b.Run("Get/HashMap", func(b *testing.B) {
+		b.ReportAllocs()
+		hash := NewHashMap[int, Data](WithLocking(false))
+		for i := 0; i < benchSize; i++ {
+			hash.Put(i, Data(i))
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			hash.Get(i % (2 * benchSize))
+		}
+	})
+	b.Run("Get/map", func(b *testing.B) {
+		b.ReportAllocs()
+		hash := map[int]Data{}
+		for i := 0; i < benchSize; i++ {
+			hash[i] = Data(i)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = hash[i%(2*benchSize)]
+		}
+	})
+	b.Run("All/HashMap", func(b *testing.B) {
+		b.ReportAllocs()
+		hash := NewHashMap[int, Data](WithLocking(false))
+		for i := 0; i < benchSize; i++ {
+			hash.Put(i, Data(i))
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for range hash.All() {
+			}
+		}
+	})
+	b.Run("All/map", func(b *testing.B) {
+		b.ReportAllocs()
+		hash := map[int]Data{}
+		for i := 0; i < benchSize; i++ {
+			hash[i] = Data(i)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for range hash {
+			}
+		}
+	})
+}
+
+func BenchmarkSortedSet(b *testing.B) {
+	b.ReportAllocs()
+	set := NewSortedSet(constraints.OrderedComparer[int]())
+	for i := 0; i < benchSize; i++ {
+		set.Add(i * 7919 % benchSize)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rank, _ := set.Rank(i % benchSize)
+		set.Select(rank)
+	}
+}
+
+func BenchmarkConcurrentSet(b *testing.B) {
+	b.Run("LockFreeList", func(b *testing.B) {
+		list := NewLockFreeList[int]()
+		for i := 0; i < benchSize; i += 2 {
+			list.Insert(i)
+		}
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				value := i % benchSize
+				switch i % 4 {
+				case 0:
+					list.Insert(value)
+				case 1:
+					list.Delete(value)
+				default:
+					list.Contains(value)
+				}
+			}
+		})
+	})
+	b.Run("List", func(b *testing.B) {
+		list := NewList[Data]()
+		for i := 0; i < benchSize; i += 2 {
+			list.Append(Data(i))
+		}
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				value := Data(i % benchSize)
+				switch i % 4 {
+				case 0:
+					list.Append(value)
+				case 1:
+					list.Delete(value)
+				default:
+					list.Find(value)
+				}
+			}
+		})
+	})
+}
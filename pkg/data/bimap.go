@@ -0,0 +1,232 @@
+package data
+
+import (
+	"fmt"
+	"iter"
+)
+
+// BiMap is a one-to-one map, which can also be searched by value: each
+// value is stored under at most one key. Put refuses a value that is
+// already under another key, and ForcePut replaces whichever pairs hold the
+// key or the value, so conflicts never depend on the order of the maps.
+type BiMap[K, V comparable] struct {
+	forward map[K]V  // Value of each key.
+	inverse map[V]K  // Key of each value.
+	metrics *Metrics // Instrumentation, nil when disabled.
+	mux     locker   // Lock read and write operations.
+}
+
+// Create a new bidirectional map, configured by WithLocking and
+// WithMetrics.
+func NewBiMap[K, V comparable](opts ...Option) *BiMap[K, V] {
+	settings := newOptions(opts)
+	return &BiMap[K, V]{
+		forward: map[K]V{},
+		inverse: map[V]K{},
+		metrics: settings.metrics,
+		mux:     settings.newLocker(),
+	}
+}
+
+// Length reports the number of pairs in the map.
+func (bimap *BiMap[K, V]) Length() int {
+	if bimap == nil {
+		return 0
+	}
+	bimap.mux.RLock()
+	defer bimap.mux.RUnlock()
+	return len(bimap.forward)
+}
+
+// Put stores a value under a key, replacing any value already there. It
+// returns ErrDuplicate and leaves the map unchanged if the value is under
+// another key.
+func (bimap *BiMap[K, V]) Put(key K, value V) error {
+	if bimap == nil {
+		return nilError("bimap")
+	}
+	start := bimap.metrics.begin()
+	bimap.mux.Lock()
+	defer bimap.mux.Unlock()
+	defer bimap.metrics.end("Put", start, bimap.metrics.acquired())
+	defer bimap.debugCheck()
+	if other, ok := bimap.inverse[value]; ok && other != key {
+		return fmt.Errorf("%w: bimap value %v is under key %v", ErrDuplicate, value, other)
+	}
+	bimap.put(key, value)
+	return nil
+}
+
+// ForcePut stores a value under a key, removing the pairs with either the
+// key or the value first.
+func (bimap *BiMap[K, V]) ForcePut(key K, value V) error {
+	if bimap == nil {
+		return nilError("bimap")
+	}
+	start := bimap.metrics.begin()
+	bimap.mux.Lock()
+	defer bimap.mux.Unlock()
+	defer bimap.metrics.end("ForcePut", start, bimap.metrics.acquired())
+	defer bimap.debugCheck()
+	if other, ok := bimap.inverse[value]; ok {
+		delete(bimap.forward, other)
+	}
+	bimap.put(key, value)
+	return nil
+}
+
+// put stores a pair whose value is under no other key, lock must be held.
+func (bimap *BiMap[K, V]) put(key K, value V) {
+	if old, ok := bimap.forward[key]; ok {
+		delete(bimap.inverse, old)
+	}
+	bimap.forward[key] = value
+	bimap.inverse[value] = key
+}
+
+// Get gets the value stored under a key.
+func (bimap *BiMap[K, V]) Get(key K) (V, bool) {
+	if bimap == nil {
+		var unset V
+		return unset, false
+	}
+	bimap.mux.RLock()
+	defer bimap.mux.RUnlock()
+	value, ok := bimap.forward[key]
+	return value, ok
+}
+
+// InverseGet gets the key a value is stored under.
+func (bimap *BiMap[K, V]) InverseGet(value V) (K, bool) {
+	if bimap == nil {
+		var unset K
+		return unset, false
+	}
+	bimap.mux.RLock()
+	defer bimap.mux.RUnlock()
+	key, ok := bimap.inverse[value]
+	return key, ok
+}
+
+// ContainsKey reports whether a key is in the map.
+func (bimap *BiMap[K, V]) ContainsKey(key K) bool {
+	_, ok := bimap.Get(key)
+	return ok
+}
+
+// ContainsValue reports whether a value is in the map.
+func (bimap *BiMap[K, V]) ContainsValue(value V) bool {
+	_, ok := bimap.InverseGet(value)
+	return ok
+}
+
+// Delete removes a key and its value.
+func (bimap *BiMap[K, V]) Delete(key K) (V, bool) {
+	var unset V
+	if bimap == nil {
+		return unset, false
+	}
+	start := bimap.metrics.begin()
+	bimap.mux.Lock()
+	defer bimap.mux.Unlock()
+	defer bimap.metrics.end("Delete", start, bimap.metrics.acquired())
+	defer bimap.debugCheck()
+	value, ok := bimap.forward[key]
+	if !ok {
+		return unset, false
+	}
+	delete(bimap.forward, key)
+	delete(bimap.inverse, value)
+	return value, true
+}
+
+// InverseDelete removes a value and its key.
+func (bimap *BiMap[K, V]) InverseDelete(value V) (K, bool) {
+	var unset K
+	if bimap == nil {
+		return unset, false
+	}
+	start := bimap.metrics.begin()
+	bimap.mux.Lock()
+	defer bimap.mux.Unlock()
+	defer bimap.metrics.end("InverseDelete", start, bimap.metrics.acquired())
+	defer bimap.debugCheck()
+	key, ok := bimap.inverse[value]
+	if !ok {
+		return unset, false
+	}
+	delete(bimap.inverse, value)
+	delete(bimap.forward, key)
+	return key, true
+}
+
+// All gets a sequence of the keys and values as of the call, in no
+// particular order.
+func (bimap *BiMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if bimap == nil {
+			return
+		}
+		bimap.mux.RLock()
+		keys := make([]K, 0, len(bimap.forward))
+		values := make([]V, 0, len(bimap.forward))
+		for key, value := range bimap.forward {
+			keys = append(keys, key)
+			values = append(values, value)
+		}
+		bimap.mux.RUnlock()
+		for i, key := range keys {
+			if !yield(key, values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// String converts BiMap data into a string of key:value pairs, in no
+// particular order.
+func (bimap *BiMap[K, V]) String() string {
+	if bimap == nil {
+		return ""
+	}
+	s := fmt.Sprintf("Length: %d, Data:", bimap.Length())
+	for key, value := range bimap.All() {
+		s += fmt.Sprintf(" %v:%v", key, value)
+	}
+	return s
+}
+
+// CheckInvariants verifies that the inverse map holds exactly the pairs of
+// the forward map.
+func (bimap *BiMap[K, V]) CheckInvariants() error {
+	if bimap == nil {
+		return nil
+	}
+	bimap.mux.RLock()
+	defer bimap.mux.RUnlock()
+	return bimap.checkInvariants()
+}
+
+// checkInvariants verifies the bimap, lock must be held.
+func (bimap *BiMap[K, V]) checkInvariants() error {
+	if len(bimap.forward) != len(bimap.inverse) {
+		return fmt.Errorf("bimap: %d keys but %d values", len(bimap.forward), len(bimap.inverse))
+	}
+	for key, value := range bimap.forward {
+		if other, ok := bimap.inverse[value]; !ok || other != key {
+			return fmt.Errorf("bimap: key %v has value %v, which is under key %v", key, value, other)
+		}
+	}
+	return nil
+}
+
+// debugCheck panics if the bimap invariants are violated in debug builds,
+// lock must be held.
+func (bimap *BiMap[K, V]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := bimap.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
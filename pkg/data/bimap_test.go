@@ -0,0 +1,108 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"maps"
+	"testing"
+)
+
+func Test_BiMap(t *testing.T) {
+	bimap := NewBiMap[string, int]()
+	bimap.Put("one", 1)
+	bimap.Put("two", 2)
+	if key, ok := bimap.InverseGet(2); !ok || key != "two" {
+		t.Error("unexpected key of 2", key, ok)
+	}
+	if err := bimap.Put("uno", 1); !errors.Is(err, ErrDuplicate) || bimap.ContainsKey("uno") {
+		t.Error("expected a duplicate value error, got", err)
+	}
+	// Replacing the value of a key frees the old value.
+	if err := bimap.Put("one", 10); err != nil || bimap.ContainsValue(1) {
+		t.Error("expected to replace the value of one", err)
+	}
+	// ForcePut drops both the pair with the key and the pair with the value.
+	bimap.ForcePut("one", 2)
+	if bimap.Length() != 1 || bimap.ContainsKey("two") || bimap.ContainsValue(10) {
+		t.Error("unexpected pairs", bimap)
+	}
+	if bimap.String() != "Length: 1, Data: one:2" {
+		t.Error("unexpected map", bimap.String())
+	}
+	if key, ok := bimap.InverseDelete(2); !ok || key != "one" || bimap.Length() != 0 {
+		t.Error("expected to delete 2", key, ok)
+	}
+
+	var unset *BiMap[string, int]
+	if err := unset.Put("a", 1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
+
+// biMapMachine checks a BiMap against a map model of its forward pairs.
+var biMapMachine = datatest.Machine[*BiMap[int, int], map[int]int]{
+	NewSystem: func() *BiMap[int, int] { return NewBiMap[int, int](WithLocking(false)) },
+	NewModel:  func() map[int]int { return map[int]int{} },
+	Commands: []datatest.Command[*BiMap[int, int], map[int]int]{
+		{Name: "Put", Run: func(bimap *BiMap[int, int], model map[int]int, arg int) error {
+			key, value := arg%4, arg/4
+			duplicate := false
+			for other, v := range model {
+				duplicate = duplicate || (v == value && other != key)
+			}
+			if !duplicate {
+				model[key] = value
+			}
+			if err := bimap.Put(key, value); errors.Is(err, ErrDuplicate) != duplicate {
+				return fmt.Errorf("Put returned %v", err)
+			}
+			return nil
+		}},
+		{Name: "ForcePut", Run: func(bimap *BiMap[int, int], model map[int]int, arg int) error {
+			key, value := arg%4, arg/4
+			maps.DeleteFunc(model, func(_, v int) bool { return v == value })
+			model[key] = value
+			return bimap.ForcePut(key, value)
+		}},
+		{Name: "Delete", Run: func(bimap *BiMap[int, int], model map[int]int, arg int) error {
+			expected, found := model[arg%4]
+			delete(model, arg%4)
+			if value, ok := bimap.Delete(arg % 4); ok != found || value != expected {
+				return fmt.Errorf("Delete returned %d %t, expected %d %t", value, ok, expected, found)
+			}
+			return nil
+		}},
+		{Name: "InverseDelete", Run: func(bimap *BiMap[int, int], model map[int]int, arg int) error {
+			expected, found := 0, false
+			for key, value := range model {
+				if value == arg/4 {
+					expected, found = key, true
+				}
+			}
+			if found {
+				delete(model, expected)
+			}
+			if key, ok := bimap.InverseDelete(arg / 4); ok != found || key != expected {
+				return fmt.Errorf("InverseDelete returned %d %t, expected %d %t", key, ok, expected, found)
+			}
+			return nil
+		}},
+	},
+	Check: func(bimap *BiMap[int, int], model map[int]int) error {
+		if all := maps.Collect(bimap.All()); !maps.Equal(all, model) {
+			return fmt.Errorf("expected %v, got %v", model, all)
+		}
+		for key, value := range model {
+			if other, ok := bimap.InverseGet(value); !ok || other != key {
+				return fmt.Errorf("value %d is under %d %t, expected %d", value, other, ok, key)
+			}
+		}
+		return nil
+	},
+}
+
+func Test_BiMapModel(t *testing.T) {
+	biMapMachine.Test(t, datatest.Config{Runs: 200, Length: 100})
+}
@@ -0,0 +1,209 @@
+package data
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// BlockingQueue is a FIFO queue whose Put and Take block until space or an
+// element is available, or the context is done.
+type BlockingQueue[T any] struct {
+	items    []T           // Queued elements, oldest first.
+	capacity int           // Maximum number of elements, 0 for unbounded.
+	closed   bool          // Whether Close has been called.
+	notEmpty chan struct{} // Closed and replaced when an element is added.
+	notFull  chan struct{} // Closed and replaced when an element is removed.
+	mux      *sync.Mutex   // Lock read and write operations.
+}
+
+// Create a new blocking queue holding at most capacity elements, or an
+// unbounded queue if capacity is 0, configured by WithCapacity.
+func NewBlockingQueue[T any](capacity int, opts ...Option) *BlockingQueue[T] {
+	capacity = newOptions(opts).capacityOr(capacity)
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &BlockingQueue[T]{
+		capacity: capacity,
+		notEmpty: make(chan struct{}),
+		notFull:  make(chan struct{}),
+		mux:      &sync.Mutex{},
+	}
+}
+
+// Length reports the number of queued elements.
+func (queue *BlockingQueue[T]) Length() int {
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	return len(queue.items)
+}
+
+// Capacity reports the maximum number of elements, 0 if unbounded.
+func (queue *BlockingQueue[T]) Capacity() int {
+	return queue.capacity
+}
+
+// isClosed reports whether the queue has been closed.
+func (queue *BlockingQueue[T]) isClosed() bool {
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	return queue.closed
+}
+
+// full reports whether the queue is at capacity, lock must be held.
+func (queue *BlockingQueue[T]) full() bool {
+	return queue.capacity > 0 && len(queue.items) >= queue.capacity
+}
+
+// push adds an element and wakes waiting takers, lock must be held.
+func (queue *BlockingQueue[T]) push(value T) {
+	queue.items = append(queue.items, value)
+	queue.signal(&queue.notEmpty)
+}
+
+// pop removes the oldest element and wakes waiting putters, lock must be held.
+func (queue *BlockingQueue[T]) pop() T {
+	var unset T
+	value := queue.items[0]
+	queue.items[0] = unset
+	queue.items = queue.items[1:]
+	queue.signal(&queue.notFull)
+	return value
+}
+
+// signal wakes the waiters on a channel, lock must be held. Channels stay
+// closed once the queue is closed.
+func (queue *BlockingQueue[T]) signal(waiters *chan struct{}) {
+	if queue.closed {
+		return
+	}
+	close(*waiters)
+	*waiters = make(chan struct{})
+}
+
+// Put adds an element, blocking while the queue is full. Nothing is added
+// once ctx is done.
+func (queue *BlockingQueue[T]) Put(ctx context.Context, value T) error {
+	if queue == nil {
+		return nilError("queue")
+	}
+	for {
+		if ctx.Err() != nil {
+			return canceled(ctx)
+		}
+		queue.mux.Lock()
+		if queue.closed {
+			queue.mux.Unlock()
+			return ErrClosed
+		}
+		if !queue.full() {
+			queue.push(value)
+			queue.mux.Unlock()
+			return nil
+		}
+		notFull := queue.notFull
+		queue.mux.Unlock()
+
+		select {
+		case <-notFull:
+		case <-ctx.Done():
+			return canceled(ctx)
+		}
+	}
+}
+
+// TryPut adds an element if the queue is not full, without blocking.
+func (queue *BlockingQueue[T]) TryPut(value T) bool {
+	if queue == nil {
+		return false
+	}
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	if queue.closed || queue.full() {
+		return false
+	}
+	queue.push(value)
+	return true
+}
+
+// Take removes the oldest element, blocking while the queue is empty. Nothing
+// is removed once ctx is done. Once the queue is closed, remaining elements
+// are still returned, then ErrClosed.
+func (queue *BlockingQueue[T]) Take(ctx context.Context) (T, error) {
+	var unset T
+	if queue == nil {
+		return unset, nilError("queue")
+	}
+	for {
+		if ctx.Err() != nil {
+			return unset, canceled(ctx)
+		}
+		queue.mux.Lock()
+		if len(queue.items) > 0 {
+			value := queue.pop()
+			queue.mux.Unlock()
+			return value, nil
+		}
+		if queue.closed {
+			queue.mux.Unlock()
+			return unset, ErrClosed
+		}
+		notEmpty := queue.notEmpty
+		queue.mux.Unlock()
+
+		select {
+		case <-notEmpty:
+		case <-ctx.Done():
+			return unset, canceled(ctx)
+		}
+	}
+}
+
+// TryTake removes the oldest element if there is one, without blocking.
+func (queue *BlockingQueue[T]) TryTake() (T, bool) {
+	var unset T
+	if queue == nil {
+		return unset, false
+	}
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	if len(queue.items) == 0 {
+		return unset, false
+	}
+	return queue.pop(), true
+}
+
+// Close stops the queue accepting elements and wakes all blocked callers.
+func (queue *BlockingQueue[T]) Close() {
+	if queue == nil {
+		return
+	}
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	if queue.closed {
+		return
+	}
+	queue.closed = true
+	close(queue.notEmpty)
+	close(queue.notFull)
+}
+
+// Collect reports the length and capacity of the queue.
+func (queue *BlockingQueue[T]) Collect(emit func(Sample)) {
+	emit(Sample{Name: "length", Help: "Number of queued elements.", Value: float64(queue.Length())})
+	emit(Sample{Name: "capacity", Help: "Maximum number of elements, 0 if unbounded.", Value: float64(queue.capacity)})
+}
+
+// Iterator creates an iterator over the queued elements, oldest first, as of
+// the call. Iterating does not remove elements.
+func (queue *BlockingQueue[T]) Iterator() Iterator[T] {
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	return FromSlice(append([]T(nil), queue.items...))
+}
+
+// All gets a sequence of the queued elements as of the call.
+func (queue *BlockingQueue[T]) All() iter.Seq[T] {
+	return Seq(queue.Iterator())
+}
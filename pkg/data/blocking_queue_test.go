@@ -0,0 +1,113 @@
+package data_test
+
+import (
+	"context"
+	"errors"
+	. "fun/pkg/data"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_BlockingQueue(t *testing.T) {
+	ctx := context.Background()
+	queue := NewBlockingQueue[int](2)
+	if queue.Capacity() != 2 {
+		t.Error("expected capacity 2, got", queue.Capacity())
+	}
+	queue.Put(ctx, 1)
+	queue.Put(ctx, 2)
+	if queue.TryPut(3) {
+		t.Error("expected TryPut to fail on full queue")
+	}
+	if queue.Length() != 2 {
+		t.Error("expected length 2, got", queue.Length())
+	}
+	if value, err := queue.Take(ctx); err != nil || value != 1 {
+		t.Error("expected to take 1, got", value, err)
+	}
+	if value, ok := queue.TryTake(); !ok || value != 2 {
+		t.Error("expected to take 2, got", value, ok)
+	}
+	if _, ok := queue.TryTake(); ok {
+		t.Error("expected TryTake to fail on empty queue")
+	}
+}
+
+func Test_BlockingQueueCancel(t *testing.T) {
+	queue := NewBlockingQueue[int](1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := queue.Take(ctx)
+	if !errors.Is(err, ErrCanceled) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("expected canceled deadline error, got", err)
+	}
+
+	queue.TryPut(1)
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	err = queue.Put(ctx, 2)
+	if !errors.Is(err, ErrCanceled) || !errors.Is(err, context.Canceled) {
+		t.Error("expected canceled error, got", err)
+	}
+}
+
+func Test_BlockingQueueClose(t *testing.T) {
+	ctx := context.Background()
+	queue := NewBlockingQueue[int](0)
+	queue.Put(ctx, 1)
+
+	done := make(chan error)
+	full := NewBlockingQueue[int](1)
+	full.TryPut(1)
+	go func() {
+		done <- full.Put(ctx, 2)
+	}()
+	full.Close()
+	if err := <-done; !errors.Is(err, ErrClosed) {
+		t.Error("expected blocked Put to fail with ErrClosed, got", err)
+	}
+
+	queue.Close()
+	queue.Close()
+	if err := queue.Put(ctx, 2); !errors.Is(err, ErrClosed) {
+		t.Error("expected Put on closed queue to fail, got", err)
+	}
+	if value, err := queue.Take(ctx); err != nil || value != 1 {
+		t.Error("expected to drain 1 from closed queue, got", value, err)
+	}
+	if _, err := queue.Take(ctx); !errors.Is(err, ErrClosed) {
+		t.Error("expected Take on drained closed queue to fail, got", err)
+	}
+}
+
+func Test_BlockingQueueConcurrency(t *testing.T) {
+	const threads = 10
+	const iterations = 100
+
+	ctx := context.Background()
+	queue := NewBlockingQueue[int](5)
+	var wg sync.WaitGroup
+	wg.Add(threads)
+	for i := 0; i < threads; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				queue.Put(ctx, j)
+			}
+		}()
+	}
+
+	sum := 0
+	for i := 0; i < threads*iterations; i++ {
+		value, err := queue.Take(ctx)
+		if err != nil {
+			t.Fatal("unexpected error", err)
+		}
+		sum += value
+	}
+	wg.Wait()
+	if expected := threads * iterations * (iterations - 1) / 2; sum != expected {
+		t.Error("expected sum", expected, "got", sum)
+	}
+}
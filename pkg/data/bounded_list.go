@@ -0,0 +1,242 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sync"
+)
+
+// EvictPolicy selects what a BoundedList does with an element appended while
+// it is full.
+type EvictPolicy int
+
+const (
+	EvictError EvictPolicy = iota // Reject the element with ErrFull.
+	EvictHead                     // Drop the oldest element to make room.
+	EvictBlock                    // Wait until an element is removed.
+)
+
+// String names the evict policy.
+func (policy EvictPolicy) String() string {
+	switch policy {
+	case EvictError:
+		return "error"
+	case EvictHead:
+		return "head"
+	case EvictBlock:
+		return "block"
+	}
+	return fmt.Sprintf("EvictPolicy(%d)", int(policy))
+}
+
+// BoundedList is a list holding at most a fixed number of elements, usable as
+// a fixed-size buffer. Appending to a full list follows its EvictPolicy.
+type BoundedList[T ListData] struct {
+	list     *List[T]      // Elements, oldest first.
+	capacity int           // Maximum number of elements.
+	policy   EvictPolicy   // Handling of appends while full.
+	closed   bool          // Whether Close has been called.
+	notFull  chan struct{} // Closed and replaced when an element is removed.
+	mux      *sync.Mutex   // Lock changes to the length.
+}
+
+// Create a new bounded list holding at most capacity elements, at least 1,
+// configured by WithCapacity and the options of NewList.
+func NewBoundedList[T ListData](capacity int, policy EvictPolicy, opts ...Option) *BoundedList[T] {
+	capacity = newOptions(opts).capacityOr(capacity)
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &BoundedList[T]{
+		list:     NewList[T](opts...),
+		capacity: capacity,
+		policy:   policy,
+		notFull:  make(chan struct{}),
+		mux:      &sync.Mutex{},
+	}
+}
+
+// Length reports the number of elements in the list.
+func (bounded *BoundedList[T]) Length() int {
+	if bounded == nil {
+		return 0
+	}
+	return bounded.list.Length()
+}
+
+// Capacity reports the maximum number of elements.
+func (bounded *BoundedList[T]) Capacity() int {
+	if bounded == nil {
+		return 0
+	}
+	return bounded.capacity
+}
+
+// Policy reports the handling of appends while the list is full.
+func (bounded *BoundedList[T]) Policy() EvictPolicy {
+	if bounded == nil {
+		return EvictError
+	}
+	return bounded.policy
+}
+
+// Append adds an element at the end of the list. If the list is full, it
+// returns ErrFull, drops the head, or blocks until there is room, following
+// the policy.
+func (bounded *BoundedList[T]) Append(value T) error {
+	return bounded.Put(context.Background(), value)
+}
+
+// Put is Append, except that a list with the EvictBlock policy stops waiting
+// for room once ctx is done, and nothing is added.
+func (bounded *BoundedList[T]) Put(ctx context.Context, value T) error {
+	if bounded == nil {
+		return nilError("bounded list")
+	}
+	for {
+		if ctx.Err() != nil {
+			return canceled(ctx)
+		}
+		bounded.mux.Lock()
+		if bounded.closed {
+			bounded.mux.Unlock()
+			return ErrClosed
+		}
+		if bounded.list.Length() < bounded.capacity {
+			err := bounded.list.Append(value)
+			bounded.mux.Unlock()
+			return err
+		}
+		switch bounded.policy {
+		case EvictHead:
+			bounded.list.DeleteHead()
+			err := bounded.list.Append(value)
+			bounded.mux.Unlock()
+			return err
+		case EvictBlock:
+		default:
+			bounded.mux.Unlock()
+			return ErrFull
+		}
+		notFull := bounded.notFull
+		bounded.mux.Unlock()
+
+		select {
+		case <-notFull:
+		case <-ctx.Done():
+			return canceled(ctx)
+		}
+	}
+}
+
+// removed wakes appends waiting for room if an element was removed, lock
+// must be held. The channel stays closed once the list is closed.
+func (bounded *BoundedList[T]) removed(ok bool) {
+	if !ok || bounded.closed {
+		return
+	}
+	close(bounded.notFull)
+	bounded.notFull = make(chan struct{})
+}
+
+// DeleteHead removes the oldest element.
+func (bounded *BoundedList[T]) DeleteHead() (T, bool) {
+	if bounded == nil {
+		var unset T
+		return unset, false
+	}
+	bounded.mux.Lock()
+	defer bounded.mux.Unlock()
+	value, ok := bounded.list.DeleteHead()
+	bounded.removed(ok)
+	return value, ok
+}
+
+// DeleteTail removes the newest element.
+func (bounded *BoundedList[T]) DeleteTail() (T, bool) {
+	if bounded == nil {
+		var unset T
+		return unset, false
+	}
+	bounded.mux.Lock()
+	defer bounded.mux.Unlock()
+	value, ok := bounded.list.DeleteTail()
+	bounded.removed(ok)
+	return value, ok
+}
+
+// Delete the first element equal to value, reporting whether there was one.
+func (bounded *BoundedList[T]) Delete(value T) bool {
+	if bounded == nil {
+		return false
+	}
+	bounded.mux.Lock()
+	defer bounded.mux.Unlock()
+	ok := bounded.list.Delete(value)
+	bounded.removed(ok)
+	return ok
+}
+
+// Contains reports whether a value is an element of the list.
+func (bounded *BoundedList[T]) Contains(value T) bool {
+	if bounded == nil {
+		return false
+	}
+	return bounded.list.Find(value) != nil
+}
+
+// Close stops the list accepting elements and wakes blocked appends, which
+// return ErrClosed. Elements can still be read and removed.
+func (bounded *BoundedList[T]) Close() {
+	if bounded == nil {
+		return
+	}
+	bounded.mux.Lock()
+	defer bounded.mux.Unlock()
+	if bounded.closed {
+		return
+	}
+	bounded.closed = true
+	close(bounded.notFull)
+}
+
+// All gets a sequence of the elements, oldest first, with the consistency of
+// List.Iterator.
+func (bounded *BoundedList[T]) All() iter.Seq[T] {
+	if bounded == nil {
+		return func(func(T) bool) {}
+	}
+	return bounded.list.All()
+}
+
+// View captures an immutable view of the list.
+func (bounded *BoundedList[T]) View() *ListView[T] {
+	if bounded == nil {
+		return &ListView[T]{}
+	}
+	return bounded.list.View()
+}
+
+// String converts BoundedList data into a string.
+func (bounded *BoundedList[T]) String() string {
+	if bounded == nil {
+		return ""
+	}
+	return bounded.list.String()
+}
+
+// CheckInvariants verifies the underlying list and that it is within its
+// capacity.
+func (bounded *BoundedList[T]) CheckInvariants() error {
+	if bounded == nil {
+		return nil
+	}
+	if err := bounded.list.CheckInvariants(); err != nil {
+		return err
+	}
+	if length := bounded.list.View().Length(); length > bounded.capacity {
+		return fmt.Errorf("bounded list: length %d exceeds capacity %d", length, bounded.capacity)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package data_test
+
+import (
+	"context"
+	"errors"
+	. "fun/pkg/data"
+	"slices"
+	"testing"
+	"time"
+)
+
+func Test_BoundedList(t *testing.T) {
+	rejecting := NewBoundedList[Data](2, EvictError)
+	rejecting.Append(1)
+	rejecting.Append(2)
+	if err := rejecting.Append(3); !errors.Is(err, ErrFull) {
+		t.Error("expected ErrFull appending to a full list, got", err)
+	}
+	if rejecting.String() != "Length: 2, Data: 1 2" {
+		t.Error("unexpected bounded list", rejecting.String())
+	}
+
+	ring := NewBoundedList[Data](3, EvictHead)
+	for value := Data(1); value <= 5; value++ {
+		if err := ring.Append(value); err != nil {
+			t.Error("expected append to evict the head, got", err)
+		}
+	}
+	if values := slices.Collect(ring.All()); !slices.Equal(values, []Data{3, 4, 5}) {
+		t.Error("expected the newest elements to be kept", values)
+	}
+	if err := ring.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+	if ring.Policy().String() != "head" || ring.Capacity() != 3 {
+		t.Error("unexpected policy or capacity", ring.Policy(), ring.Capacity())
+	}
+
+	var unset *BoundedList[Data]
+	if err := unset.Append(1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
+
+func Test_BoundedListBlock(t *testing.T) {
+	blocking := NewBoundedList[Data](1, EvictBlock)
+	blocking.Append(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := blocking.Put(ctx, 2); !errors.Is(err, ErrCanceled) {
+		t.Error("expected canceled error, got", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- blocking.Append(2)
+	}()
+	if value, ok := blocking.DeleteHead(); !ok || value != 1 {
+		t.Error("expected to delete 1, got", value, ok)
+	}
+	if err := <-done; err != nil {
+		t.Error("expected blocked append to succeed, got", err)
+	}
+	if !blocking.Contains(2) || blocking.Length() != 1 {
+		t.Error("unexpected bounded list", blocking.String())
+	}
+
+	go func() {
+		done <- blocking.Append(3)
+	}()
+	blocking.Close()
+	if err := <-done; !errors.Is(err, ErrClosed) {
+		t.Error("expected blocked append to fail with ErrClosed, got", err)
+	}
+}
@@ -0,0 +1,512 @@
+package data
+
+import (
+	"fmt"
+	"fun/pkg/constraints"
+	"iter"
+	"slices"
+)
+
+// bplusNode is a node of a BPlusTree. An internal node has a child after
+// each key and one before the first, and a leaf has a value for each key.
+type bplusNode[K, V any] struct {
+	keys     []K                // Keys in order, separators for an internal node.
+	values   []V                // Value of each key of a leaf.
+	children []*bplusNode[K, V] // Subtrees around the keys, nil for a leaf.
+	next     *bplusNode[K, V]   // Next leaf in key order.
+}
+
+// leaf reports whether the node has no children.
+func (node *bplusNode[K, V]) leaf() bool {
+	return node.children == nil
+}
+
+// BPlusTree is an ordered map stored in a B+ tree of minimum degree t: the
+// values are only in the leaves, which are linked in key order, and the
+// internal nodes hold copies of keys to guide searches. Every node but the
+// root holds between t-1 and 2t-1 keys, and all leaves are at the same depth.
+// Insert, Delete, and Find take O(t log n / log t) time, and Range scans
+// along the leaves without returning to the internal nodes.
+type BPlusTree[K, V any] struct {
+	root     *bplusNode[K, V]        // Root, nil when empty.
+	first    *bplusNode[K, V]        // Leaf of the least keys, nil when empty.
+	degree   int                     // Minimum degree t.
+	length   int                     // Number of keys stored in the tree.
+	comparer constraints.Comparer[K] // Order of the keys.
+	metrics  *Metrics                // Instrumentation, nil when disabled.
+	mux      locker                  // Lock read and write operations.
+}
+
+// Create a new B+ tree of minimum degree at least 2, ordered by comparer and
+// configured by WithLocking, WithMetrics, and WithComparator.
+func NewBPlusTree[K, V any](degree int, comparer constraints.Comparer[K], opts ...Option) *BPlusTree[K, V] {
+	settings := newOptions(opts)
+	return &BPlusTree[K, V]{
+		degree:   max(degree, 2),
+		comparer: comparerOr(settings, comparer),
+		metrics:  settings.metrics,
+		mux:      settings.newLocker(),
+	}
+}
+
+// Length reports the number of keys in the tree.
+func (tree *BPlusTree[K, V]) Length() int {
+	if tree == nil {
+		return 0
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	return tree.length
+}
+
+// Degree reports the minimum degree of the tree.
+func (tree *BPlusTree[K, V]) Degree() int {
+	if tree == nil {
+		return 0
+	}
+	return tree.degree
+}
+
+// child finds the index of the child of an internal node whose keys may
+// include key: the child after the last separator not greater than key.
+func (tree *BPlusTree[K, V]) child(node *bplusNode[K, V], key K) int {
+	i, found := slices.BinarySearchFunc(node.keys, key, tree.comparer.Compare)
+	if found {
+		i++
+	}
+	return i
+}
+
+// findLeaf descends from the root to the leaf whose keys may include key,
+// lock must be held.
+func (tree *BPlusTree[K, V]) findLeaf(key K) *bplusNode[K, V] {
+	node := tree.root
+	for node != nil && !node.leaf() {
+		node = node.children[tree.child(node, key)]
+	}
+	return node
+}
+
+// Insert stores a value under a key, replacing any value already there.
+func (tree *BPlusTree[K, V]) Insert(key K, value V) error {
+	if tree == nil {
+		return nilError("b+ tree")
+	}
+	start := tree.metrics.begin()
+	tree.mux.Lock()
+	defer tree.mux.Unlock()
+	defer tree.metrics.end("Insert", start, tree.metrics.acquired())
+	defer tree.debugCheck()
+	if tree.root == nil {
+		tree.root = &bplusNode[K, V]{}
+		tree.first = tree.root
+	}
+	if separator, right := tree.insert(tree.root, key, value); right != nil {
+		tree.root = &bplusNode[K, V]{keys: []K{separator}, children: []*bplusNode[K, V]{tree.root, right}}
+	}
+	return nil
+}
+
+// insert stores a value under a key in the subtree of node, returning the
+// new node and its separator if the node split, lock must be held.
+func (tree *BPlusTree[K, V]) insert(node *bplusNode[K, V], key K, value V) (K, *bplusNode[K, V]) {
+	var separator K
+	if node.leaf() {
+		i, found := slices.BinarySearchFunc(node.keys, key, tree.comparer.Compare)
+		if found {
+			node.values[i] = value
+			return separator, nil
+		}
+		node.keys = slices.Insert(node.keys, i, key)
+		node.values = slices.Insert(node.values, i, value)
+		tree.length++
+		if len(node.keys) < 2*tree.degree {
+			return separator, nil
+		}
+		// Split the leaf in half, copying its first key up to the parent.
+		middle := len(node.keys) / 2
+		right := &bplusNode[K, V]{
+			keys:   slices.Clone(node.keys[middle:]),
+			values: slices.Clone(node.values[middle:]),
+			next:   node.next,
+		}
+		node.keys = slices.Delete(node.keys, middle, len(node.keys))
+		node.values = slices.Delete(node.values, middle, len(node.values))
+		node.next = right
+		return right.keys[0], right
+	}
+	i := tree.child(node, key)
+	childSeparator, child := tree.insert(node.children[i], key, value)
+	if child == nil {
+		return separator, nil
+	}
+	node.keys = slices.Insert(node.keys, i, childSeparator)
+	node.children = slices.Insert(node.children, i+1, child)
+	if len(node.keys) < 2*tree.degree {
+		return separator, nil
+	}
+	// Split the internal node around its middle key, moving that up.
+	middle := len(node.keys) / 2
+	separator = node.keys[middle]
+	right := &bplusNode[K, V]{
+		keys:     slices.Clone(node.keys[middle+1:]),
+		children: slices.Clone(node.children[middle+1:]),
+	}
+	node.keys = slices.Delete(node.keys, middle, len(node.keys))
+	node.children = slices.Delete(node.children, middle+1, len(node.children))
+	return separator, right
+}
+
+// Find gets the value stored under a key.
+func (tree *BPlusTree[K, V]) Find(key K) (V, bool) {
+	var unset V
+	if tree == nil {
+		return unset, false
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	if leaf := tree.findLeaf(key); leaf != nil {
+		if i, found := slices.BinarySearchFunc(leaf.keys, key, tree.comparer.Compare); found {
+			return leaf.values[i], true
+		}
+	}
+	return unset, false
+}
+
+// Contains reports whether a key is in the tree.
+func (tree *BPlusTree[K, V]) Contains(key K) bool {
+	_, ok := tree.Find(key)
+	return ok
+}
+
+// Delete removes a key, reporting whether there was one.
+func (tree *BPlusTree[K, V]) Delete(key K) bool {
+	if tree == nil {
+		return false
+	}
+	start := tree.metrics.begin()
+	tree.mux.Lock()
+	defer tree.mux.Unlock()
+	defer tree.metrics.end("Delete", start, tree.metrics.acquired())
+	defer tree.debugCheck()
+	if tree.root == nil || !tree.delete(tree.root, key) {
+		return false
+	}
+	switch {
+	case len(tree.root.keys) > 0:
+	case tree.root.leaf():
+		tree.root, tree.first = nil, nil
+	default:
+		tree.root = tree.root.children[0]
+	}
+	return true
+}
+
+// delete removes a key from the subtree of node, reporting whether there
+// was one, and refills a child left with fewer than t-1 keys. Separators
+// are left in place even if their key is deleted, since they still divide
+// the keys of the children, lock must be held.
+func (tree *BPlusTree[K, V]) delete(node *bplusNode[K, V], key K) bool {
+	if node.leaf() {
+		i, found := slices.BinarySearchFunc(node.keys, key, tree.comparer.Compare)
+		if found {
+			node.keys = slices.Delete(node.keys, i, i+1)
+			node.values = slices.Delete(node.values, i, i+1)
+			tree.length--
+		}
+		return found
+	}
+	i := tree.child(node, key)
+	if !tree.delete(node.children[i], key) {
+		return false
+	}
+	if len(node.children[i].keys) < tree.degree-1 {
+		tree.fill(node, i)
+	}
+	return true
+}
+
+// fill gives the child at i of a node t-1 keys, borrowing from a sibling or
+// merging with one, lock must be held.
+func (tree *BPlusTree[K, V]) fill(node *bplusNode[K, V], i int) {
+	child := node.children[i]
+	switch {
+	case i > 0 && len(node.children[i-1].keys) >= tree.degree:
+		left := node.children[i-1]
+		last := len(left.keys) - 1
+		if child.leaf() {
+			child.keys = slices.Insert(child.keys, 0, left.keys[last])
+			child.values = slices.Insert(child.values, 0, left.values[last])
+			left.values = slices.Delete(left.values, last, last+1)
+			node.keys[i-1] = child.keys[0]
+		} else {
+			child.keys = slices.Insert(child.keys, 0, node.keys[i-1])
+			child.children = slices.Insert(child.children, 0, left.children[last+1])
+			left.children = slices.Delete(left.children, last+1, last+2)
+			node.keys[i-1] = left.keys[last]
+		}
+		left.keys = slices.Delete(left.keys, last, last+1)
+	case i < len(node.children)-1 && len(node.children[i+1].keys) >= tree.degree:
+		right := node.children[i+1]
+		if child.leaf() {
+			child.keys = append(child.keys, right.keys[0])
+			child.values = append(child.values, right.values[0])
+			right.values = slices.Delete(right.values, 0, 1)
+			node.keys[i] = right.keys[1]
+		} else {
+			child.keys = append(child.keys, node.keys[i])
+			child.children = append(child.children, right.children[0])
+			right.children = slices.Delete(right.children, 0, 1)
+			node.keys[i] = right.keys[0]
+		}
+		right.keys = slices.Delete(right.keys, 0, 1)
+	default:
+		if i == len(node.children)-1 {
+			i--
+		}
+		tree.merge(node, i)
+	}
+}
+
+// merge moves the child after the key at i of a node into the child before
+// it, dropping the separator for leaves and moving it down between internal
+// nodes, lock must be held.
+func (tree *BPlusTree[K, V]) merge(node *bplusNode[K, V], i int) {
+	left, right := node.children[i], node.children[i+1]
+	if left.leaf() {
+		left.keys = append(left.keys, right.keys...)
+		left.values = append(left.values, right.values...)
+		left.next = right.next
+	} else {
+		left.keys = append(append(left.keys, node.keys[i]), right.keys...)
+		left.children = append(left.children, right.children...)
+	}
+	node.keys = slices.Delete(node.keys, i, i+1)
+	node.children = slices.Delete(node.children, i+1, i+2)
+}
+
+// Range calls fn on each key from lo up to but not including hi, and its
+// value, in key order until fn returns false. The read lock is held
+// throughout, so fn must not modify the tree.
+func (tree *BPlusTree[K, V]) Range(lo, hi K, fn func(K, V) bool) {
+	if tree == nil {
+		return
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	leaf := tree.findLeaf(lo)
+	if leaf == nil {
+		return
+	}
+	i, _ := slices.BinarySearchFunc(leaf.keys, lo, tree.comparer.Compare)
+	for ; leaf != nil; leaf, i = leaf.next, 0 {
+		for ; i < len(leaf.keys); i++ {
+			if tree.comparer.Compare(leaf.keys[i], hi) >= 0 || !fn(leaf.keys[i], leaf.values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// ForEach calls fn on each key and its value in key order until fn returns
+// false. The read lock is held throughout, so fn must not modify the tree.
+func (tree *BPlusTree[K, V]) ForEach(fn func(K, V) bool) {
+	if tree == nil {
+		return
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	for leaf := tree.first; leaf != nil; leaf = leaf.next {
+		for i, key := range leaf.keys {
+			if !fn(key, leaf.values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Min gets the least key and its value.
+func (tree *BPlusTree[K, V]) Min() (K, V, bool) {
+	var key K
+	var value V
+	if tree == nil {
+		return key, value, false
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	if tree.first == nil || len(tree.first.keys) == 0 {
+		return key, value, false
+	}
+	return tree.first.keys[0], tree.first.values[0], true
+}
+
+// Max gets the greatest key and its value.
+func (tree *BPlusTree[K, V]) Max() (K, V, bool) {
+	var key K
+	var value V
+	if tree == nil {
+		return key, value, false
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	node := tree.root
+	if node == nil || len(node.keys) == 0 {
+		return key, value, false
+	}
+	for !node.leaf() {
+		node = node.children[len(node.children)-1]
+	}
+	return node.keys[len(node.keys)-1], node.values[len(node.values)-1], true
+}
+
+// Height reports the number of nodes on each path from the root to a leaf.
+func (tree *BPlusTree[K, V]) Height() int {
+	if tree == nil {
+		return 0
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	height := 0
+	for node := tree.root; node != nil; height++ {
+		if node.leaf() {
+			node = nil
+		} else {
+			node = node.children[0]
+		}
+	}
+	return height
+}
+
+// Keys copies the keys into a slice, in order.
+func (tree *BPlusTree[K, V]) Keys() []K {
+	var keys []K
+	tree.ForEach(func(key K, value V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// All gets a sequence of the keys and values as of the call, in key order.
+func (tree *BPlusTree[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var keys []K
+		var values []V
+		tree.ForEach(func(key K, value V) bool {
+			keys = append(keys, key)
+			values = append(values, value)
+			return true
+		})
+		for i, key := range keys {
+			if !yield(key, values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// String converts BPlusTree data into a string of key:value pairs, in key
+// order.
+func (tree *BPlusTree[K, V]) String() string {
+	if tree == nil {
+		return ""
+	}
+	s := fmt.Sprintf("Length: %d, Data:", tree.Length())
+	for key, value := range tree.All() {
+		s += fmt.Sprintf(" %v:%v", key, value)
+	}
+	return s
+}
+
+// CheckInvariants verifies that every node but the root has between t-1 and
+// 2t-1 keys in strictly increasing order, that the keys of each subtree are
+// between the separators around it, that all leaves are at the same depth
+// and linked in order, and that the length is the number of keys.
+func (tree *BPlusTree[K, V]) CheckInvariants() error {
+	if tree == nil {
+		return nil
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	return tree.checkInvariants()
+}
+
+// checkInvariants verifies the b+ tree, lock must be held.
+func (tree *BPlusTree[K, V]) checkInvariants() error {
+	if tree.root == nil {
+		if tree.length != 0 || tree.first != nil {
+			return fmt.Errorf("b+ tree: length %d with no root", tree.length)
+		}
+		return nil
+	}
+	var leaves []*bplusNode[K, V]
+	count, leafDepth := 0, -1
+	var check func(node *bplusNode[K, V], depth int, lo, hi *K) error
+	check = func(node *bplusNode[K, V], depth int, lo, hi *K) error {
+		if n := len(node.keys); n > 2*tree.degree-1 || (node != tree.root && n < tree.degree-1) {
+			return fmt.Errorf("b+ tree: node at depth %d has %d keys", depth, n)
+		}
+		for i, key := range node.keys {
+			// A subtree's keys are at least the separator before it.
+			if (i > 0 && tree.comparer.Compare(node.keys[i-1], key) >= 0) ||
+				(lo != nil && tree.comparer.Compare(*lo, key) > 0) ||
+				(hi != nil && tree.comparer.Compare(key, *hi) >= 0) {
+				return fmt.Errorf("b+ tree: key %v is out of order", key)
+			}
+		}
+		if node.leaf() {
+			if len(node.values) != len(node.keys) {
+				return fmt.Errorf("b+ tree: leaf has %d keys and %d values", len(node.keys), len(node.values))
+			}
+			if leafDepth >= 0 && depth != leafDepth {
+				return fmt.Errorf("b+ tree: leaves at depths %d and %d", leafDepth, depth)
+			}
+			leafDepth = depth
+			count += len(node.keys)
+			leaves = append(leaves, node)
+			return nil
+		}
+		if len(node.children) != len(node.keys)+1 {
+			return fmt.Errorf("b+ tree: node at depth %d has %d keys and %d children", depth, len(node.keys), len(node.children))
+		}
+		for i, child := range node.children {
+			childLo, childHi := lo, hi
+			if i > 0 {
+				childLo = &node.keys[i-1]
+			}
+			if i < len(node.keys) {
+				childHi = &node.keys[i]
+			}
+			if err := check(child, depth+1, childLo, childHi); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := check(tree.root, 0, nil, nil); err != nil {
+		return err
+	}
+	if count != tree.length {
+		return fmt.Errorf("b+ tree: length %d, counted %d keys", tree.length, count)
+	}
+	if tree.first != leaves[0] {
+		return fmt.Errorf("b+ tree: first leaf is not the leftmost")
+	}
+	for i, leaf := range leaves {
+		if (i < len(leaves)-1 && leaf.next != leaves[i+1]) || (i == len(leaves)-1 && leaf.next != nil) {
+			return fmt.Errorf("b+ tree: leaf %d is not linked to the next leaf", i)
+		}
+	}
+	return nil
+}
+
+// debugCheck panics if the b+ tree invariants are violated in debug builds,
+// lock must be held.
+func (tree *BPlusTree[K, V]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := tree.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,128 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	"fun/pkg/constraints"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func Test_BPlusTree(t *testing.T) {
+	tree := NewBPlusTree[int, string](2, constraints.OrderedComparer[int]())
+	if _, _, ok := tree.Min(); ok {
+		t.Error("expected no least key of an empty tree")
+	}
+	random := rand.New(rand.NewSource(1))
+	for _, key := range random.Perm(50) {
+		tree.Insert(key, fmt.Sprint("v", key))
+		if err := tree.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tree.Insert(7, "seven")
+	if value, ok := tree.Find(7); !ok || value != "seven" || tree.Length() != 50 {
+		t.Error("expected Insert to replace the value", value, ok, tree.Length())
+	}
+	if key, _, _ := tree.Min(); key != 0 {
+		t.Error("unexpected least key", key)
+	}
+	if key, value, _ := tree.Max(); key != 49 || value != "v49" {
+		t.Error("unexpected greatest key", key, value)
+	}
+
+	var keys []int
+	tree.Range(10, 20, func(key int, value string) bool {
+		keys = append(keys, key)
+		return key < 14
+	})
+	if !slices.Equal(keys, []int{10, 11, 12, 13, 14}) {
+		t.Error("unexpected range", keys)
+	}
+	keys = nil
+	tree.Range(45, 100, func(key int, value string) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if !slices.Equal(keys, []int{45, 46, 47, 48, 49}) {
+		t.Error("unexpected range past the greatest key", keys)
+	}
+
+	for _, key := range random.Perm(50) {
+		if !tree.Delete(key) {
+			t.Error("expected to delete", key)
+		}
+		if err := tree.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if tree.Length() != 0 || tree.Delete(1) {
+		t.Error("expected an empty tree", tree)
+	}
+	tree.Range(0, 10, func(int, string) bool {
+		t.Error("expected no keys in an empty tree")
+		return true
+	})
+
+	var unset *BPlusTree[int, int]
+	if err := unset.Insert(1, 1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
+
+// bplusTreeMachine checks a BPlusTree against a map model.
+var bplusTreeMachine = datatest.Machine[*BPlusTree[int, int], map[int]int]{
+	NewSystem: func() *BPlusTree[int, int] { return NewBPlusTree[int, int](2, constraints.OrderedComparer[int]()) },
+	NewModel:  func() map[int]int { return map[int]int{} },
+	ArgRange:  64,
+	Commands: []datatest.Command[*BPlusTree[int, int], map[int]int]{
+		{Name: "Insert", Run: func(tree *BPlusTree[int, int], model map[int]int, arg int) error {
+			model[arg] = len(model)
+			return tree.Insert(arg, model[arg])
+		}},
+		{Name: "Delete", Run: func(tree *BPlusTree[int, int], model map[int]int, arg int) error {
+			_, found := model[arg]
+			delete(model, arg)
+			if ok := tree.Delete(arg); ok != found {
+				return fmt.Errorf("Delete returned %t", ok)
+			}
+			return nil
+		}},
+		{Name: "Range", Run: func(tree *BPlusTree[int, int], model map[int]int, arg int) error {
+			var keys []int
+			tree.Range(arg, arg+8, func(key, value int) bool {
+				keys = append(keys, key)
+				return true
+			})
+			var expected []int
+			for _, key := range slices.Sorted(maps.Keys(model)) {
+				if key >= arg && key < arg+8 {
+					expected = append(expected, key)
+				}
+			}
+			if !slices.Equal(keys, expected) {
+				return fmt.Errorf("Range returned %v, expected %v", keys, expected)
+			}
+			return nil
+		}},
+	},
+	Check: func(tree *BPlusTree[int, int], model map[int]int) error {
+		if keys := tree.Keys(); !slices.Equal(keys, slices.Sorted(maps.Keys(model))) {
+			return fmt.Errorf("unexpected keys %v", keys)
+		}
+		for key, expected := range model {
+			if value, ok := tree.Find(key); !ok || value != expected {
+				return fmt.Errorf("key %d has value %d %t, expected %d", key, value, ok, expected)
+			}
+		}
+		return nil
+	},
+}
+
+func Test_BPlusTreeModel(t *testing.T) {
+	bplusTreeMachine.Test(t, datatest.Config{Runs: 200, Length: 200})
+}
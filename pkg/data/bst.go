@@ -0,0 +1,295 @@
+package data
+
+import (
+	"fmt"
+	"fun/pkg/constraints"
+	"iter"
+)
+
+// bstNode is a node of a BST.
+type bstNode[K, V any] struct {
+	key   K              // Key ordering the node.
+	value V              // Value stored under the key.
+	left  *bstNode[K, V] // Subtree of lesser keys.
+	right *bstNode[K, V] // Subtree of greater keys.
+}
+
+// BST is an ordered map stored in an unbalanced binary search tree, with
+// keys ordered by a comparer. Insert, Delete, and Find take time in
+// proportion to the height of the tree, O(log n) for keys in random order
+// but O(n) for keys inserted in sorted order.
+type BST[K, V any] struct {
+	root     *bstNode[K, V]          // Root, nil when empty.
+	length   int                     // Number of keys stored in the tree.
+	comparer constraints.Comparer[K] // Order of the keys.
+	metrics  *Metrics                // Instrumentation, nil when disabled.
+	mux      locker                  // Lock read and write operations.
+}
+
+// Create a new binary search tree ordered by comparer, configured by
+// WithLocking, WithMetrics, and WithComparator.
+func NewBST[K, V any](comparer constraints.Comparer[K], opts ...Option) *BST[K, V] {
+	settings := newOptions(opts)
+	return &BST[K, V]{comparer: comparerOr(settings, comparer), metrics: settings.metrics, mux: settings.newLocker()}
+}
+
+// Length reports the number of keys in the tree.
+func (tree *BST[K, V]) Length() int {
+	if tree == nil {
+		return 0
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	return tree.length
+}
+
+// Insert stores a value under a key, replacing any value already there.
+func (tree *BST[K, V]) Insert(key K, value V) error {
+	if tree == nil {
+		return nilError("binary search tree")
+	}
+	start := tree.metrics.begin()
+	tree.mux.Lock()
+	defer tree.mux.Unlock()
+	defer tree.metrics.end("Insert", start, tree.metrics.acquired())
+	defer tree.debugCheck()
+	if link := tree.find(key); *link != nil {
+		(*link).value = value
+	} else {
+		*link = &bstNode[K, V]{key: key, value: value}
+		tree.length++
+	}
+	return nil
+}
+
+// Find gets the value stored under a key.
+func (tree *BST[K, V]) Find(key K) (V, bool) {
+	var unset V
+	if tree == nil {
+		return unset, false
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	if node := *tree.find(key); node != nil {
+		return node.value, true
+	}
+	return unset, false
+}
+
+// Contains reports whether a key is in the tree.
+func (tree *BST[K, V]) Contains(key K) bool {
+	_, ok := tree.Find(key)
+	return ok
+}
+
+// Delete removes a key, reporting whether there was one.
+func (tree *BST[K, V]) Delete(key K) bool {
+	if tree == nil {
+		return false
+	}
+	start := tree.metrics.begin()
+	tree.mux.Lock()
+	defer tree.mux.Unlock()
+	defer tree.metrics.end("Delete", start, tree.metrics.acquired())
+	defer tree.debugCheck()
+	link := tree.find(key)
+	node := *link
+	if node == nil {
+		return false
+	}
+	switch {
+	case node.left == nil:
+		*link = node.right
+	case node.right == nil:
+		*link = node.left
+	default:
+		// Replace the node with its successor, the least key on its right.
+		successor := &node.right
+		for (*successor).left != nil {
+			successor = &(*successor).left
+		}
+		next := *successor
+		*successor = next.right
+		next.left, next.right = node.left, node.right
+		*link = next
+	}
+	tree.length--
+	return true
+}
+
+// find gets the link to the node of a key, or to where it would be
+// inserted, lock must be held.
+func (tree *BST[K, V]) find(key K) **bstNode[K, V] {
+	link := &tree.root
+	for *link != nil {
+		switch order := tree.comparer.Compare(key, (*link).key); {
+		case order < 0:
+			link = &(*link).left
+		case order > 0:
+			link = &(*link).right
+		default:
+			return link
+		}
+	}
+	return link
+}
+
+// Min gets the least key and its value.
+func (tree *BST[K, V]) Min() (K, V, bool) {
+	return tree.extreme(func(node *bstNode[K, V]) *bstNode[K, V] { return node.left })
+}
+
+// Max gets the greatest key and its value.
+func (tree *BST[K, V]) Max() (K, V, bool) {
+	return tree.extreme(func(node *bstNode[K, V]) *bstNode[K, V] { return node.right })
+}
+
+// extreme follows the child links from the root to the last node.
+func (tree *BST[K, V]) extreme(child func(*bstNode[K, V]) *bstNode[K, V]) (K, V, bool) {
+	var key K
+	var value V
+	if tree == nil {
+		return key, value, false
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	node := tree.root
+	if node == nil {
+		return key, value, false
+	}
+	for child(node) != nil {
+		node = child(node)
+	}
+	return node.key, node.value, true
+}
+
+// Height reports the number of nodes on the longest path from the root.
+func (tree *BST[K, V]) Height() int {
+	if tree == nil {
+		return 0
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	type level struct {
+		node  *bstNode[K, V]
+		depth int
+	}
+	height := 0
+	stack := []level{{tree.root, 1}}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if top.node == nil {
+			continue
+		}
+		height = max(height, top.depth)
+		stack = append(stack, level{top.node.left, top.depth + 1}, level{top.node.right, top.depth + 1})
+	}
+	return height
+}
+
+// inOrder calls f on each node in key order until it returns false, lock
+// must be held.
+func (tree *BST[K, V]) inOrder(f func(*bstNode[K, V]) bool) {
+	var stack []*bstNode[K, V]
+	for node := tree.root; node != nil || len(stack) > 0; node = node.right {
+		for ; node != nil; node = node.left {
+			stack = append(stack, node)
+		}
+		node = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !f(node) {
+			return
+		}
+	}
+}
+
+// Keys copies the keys into a slice, in order.
+func (tree *BST[K, V]) Keys() []K {
+	if tree == nil {
+		return nil
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	keys := make([]K, 0, tree.length)
+	tree.inOrder(func(node *bstNode[K, V]) bool {
+		keys = append(keys, node.key)
+		return true
+	})
+	return keys
+}
+
+// All gets a sequence of the keys and values as of the call, in key order.
+func (tree *BST[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if tree == nil {
+			return
+		}
+		tree.mux.RLock()
+		nodes := make([]bstNode[K, V], 0, tree.length)
+		tree.inOrder(func(node *bstNode[K, V]) bool {
+			nodes = append(nodes, bstNode[K, V]{key: node.key, value: node.value})
+			return true
+		})
+		tree.mux.RUnlock()
+		for _, node := range nodes {
+			if !yield(node.key, node.value) {
+				return
+			}
+		}
+	}
+}
+
+// String converts BST data into a string of key:value pairs, in key order.
+func (tree *BST[K, V]) String() string {
+	if tree == nil {
+		return ""
+	}
+	s := fmt.Sprintf("Length: %d, Data:", tree.Length())
+	for key, value := range tree.All() {
+		s += fmt.Sprintf(" %v:%v", key, value)
+	}
+	return s
+}
+
+// CheckInvariants verifies that the keys are in strictly increasing order
+// and that the length is the number of nodes.
+func (tree *BST[K, V]) CheckInvariants() error {
+	if tree == nil {
+		return nil
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	return tree.checkInvariants()
+}
+
+// checkInvariants verifies the tree, lock must be held.
+func (tree *BST[K, V]) checkInvariants() error {
+	var err error
+	var prev *bstNode[K, V]
+	count := 0
+	tree.inOrder(func(node *bstNode[K, V]) bool {
+		if prev != nil && tree.comparer.Compare(prev.key, node.key) >= 0 {
+			err = fmt.Errorf("binary search tree: key %v is not before %v", prev.key, node.key)
+			return false
+		}
+		prev = node
+		count++
+		return true
+	})
+	if err == nil && count != tree.length {
+		err = fmt.Errorf("binary search tree: length %d, counted %d nodes", tree.length, count)
+	}
+	return err
+}
+
+// debugCheck panics if the tree invariants are violated in debug builds,
+// lock must be held.
+func (tree *BST[K, V]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := tree.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,105 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	"fun/pkg/constraints"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func Test_BST(t *testing.T) {
+	tree := NewBST[int, string](constraints.OrderedComparer[int]())
+	if _, _, ok := tree.Min(); ok {
+		t.Error("expected no least key of an empty tree")
+	}
+	for _, key := range []int{5, 3, 8, 1, 4, 7, 9} {
+		tree.Insert(key, fmt.Sprint("v", key))
+	}
+	tree.Insert(4, "four")
+	if value, ok := tree.Find(4); !ok || value != "four" || tree.Length() != 7 {
+		t.Error("expected Insert to replace the value", value, ok, tree.Length())
+	}
+	if _, ok := tree.Find(6); ok {
+		t.Error("expected a missing key")
+	}
+	if key, value, _ := tree.Min(); key != 1 || value != "v1" {
+		t.Error("unexpected least key", key, value)
+	}
+	if key, _, _ := tree.Max(); key != 9 {
+		t.Error("unexpected greatest key", key)
+	}
+	// Delete a leaf, a node with one child, and nodes with two children.
+	for _, key := range []int{1, 3, 8, 5} {
+		if !tree.Delete(key) {
+			t.Error("expected to delete", key)
+		}
+		if err := tree.CheckInvariants(); err != nil {
+			t.Error(err)
+		}
+	}
+	if tree.Delete(5) {
+		t.Error("expected no second deletion")
+	}
+	if tree.String() != "Length: 3, Data: 4:four 7:v7 9:v9" {
+		t.Error("unexpected tree", tree)
+	}
+	for key := range tree.All() {
+		if key != 4 {
+			t.Error("expected iteration to stop at the first key")
+		}
+		break
+	}
+
+	sorted := NewBST[int, int](constraints.OrderedComparer[int]())
+	for key := range 100 {
+		sorted.Insert(key, key)
+	}
+	if sorted.Height() != 100 {
+		t.Error("expected sorted keys to make a path", sorted.Height())
+	}
+
+	var unset *BST[int, int]
+	if err := unset.Insert(1, 1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
+
+// bstMachine checks a BST against a map model.
+var bstMachine = datatest.Machine[*BST[int, int], map[int]int]{
+	NewSystem: func() *BST[int, int] { return NewBST[int, int](constraints.OrderedComparer[int]()) },
+	NewModel:  func() map[int]int { return map[int]int{} },
+	ArgRange:  32,
+	Commands: []datatest.Command[*BST[int, int], map[int]int]{
+		{Name: "Insert", Run: func(tree *BST[int, int], model map[int]int, arg int) error {
+			model[arg] = len(model)
+			return tree.Insert(arg, model[arg])
+		}},
+		{Name: "Delete", Run: func(tree *BST[int, int], model map[int]int, arg int) error {
+			_, found := model[arg]
+			delete(model, arg)
+			if ok := tree.Delete(arg); ok != found {
+				return fmt.Errorf("Delete returned %t", ok)
+			}
+			return nil
+		}},
+	},
+	Check: func(tree *BST[int, int], model map[int]int) error {
+		if keys := tree.Keys(); !slices.Equal(keys, slices.Sorted(maps.Keys(model))) {
+			return fmt.Errorf("unexpected keys %v", keys)
+		}
+		for key, expected := range model {
+			if value, ok := tree.Find(key); !ok || value != expected {
+				return fmt.Errorf("key %d has value %d %t, expected %d", key, value, ok, expected)
+			}
+		}
+		return nil
+	},
+}
+
+func Test_BSTModel(t *testing.T) {
+	bstMachine.Test(t, datatest.Config{Runs: 200, Length: 100})
+}
@@ -0,0 +1,645 @@
+package data
+
+import (
+	"fmt"
+	"fun/pkg/constraints"
+	"iter"
+	"slices"
+)
+
+// btreeNode is a node of a BTree, holding its keys in order and their values
+// in parallel slices.
+type btreeNode[K, V any] struct {
+	keys     []K                // Keys in order.
+	values   []V                // Value of each key.
+	children []*btreeNode[K, V] // Subtrees between the keys, nil for a leaf.
+}
+
+// leaf reports whether the node has no children.
+func (node *btreeNode[K, V]) leaf() bool {
+	return node.children == nil
+}
+
+// BTree is an ordered map stored in a B-tree of minimum degree t: every node
+// but the root holds between t-1 and 2t-1 keys, and all leaves are at the
+// same depth. Keys are stored contiguously in each node, so for large key
+// sets a search touches O(log n / log t) nodes rather than the O(log n) of a
+// binary tree. Insert, Delete, and Find take O(t log n / log t) time.
+type BTree[K, V any] struct {
+	root     *btreeNode[K, V]        // Root, nil when empty.
+	degree   int                     // Minimum degree t.
+	length   int                     // Number of keys stored in the tree.
+	comparer constraints.Comparer[K] // Order of the keys.
+	metrics  *Metrics                // Instrumentation, nil when disabled.
+	mux      locker                  // Lock read and write operations.
+}
+
+// Create a new B-tree of minimum degree at least 2, ordered by comparer and
+// configured by WithLocking, WithMetrics, and WithComparator.
+func NewBTree[K, V any](degree int, comparer constraints.Comparer[K], opts ...Option) *BTree[K, V] {
+	settings := newOptions(opts)
+	return &BTree[K, V]{
+		degree:   max(degree, 2),
+		comparer: comparerOr(settings, comparer),
+		metrics:  settings.metrics,
+		mux:      settings.newLocker(),
+	}
+}
+
+// Create a new B-tree like NewBTree, bulk-loading keys and values in
+// strictly increasing key order in O(n) time. The nodes are filled evenly
+// rather than split as they would be by Insert, and an error is returned if
+// the keys are out of order.
+func NewBTreeFromSorted[K, V any](degree int, comparer constraints.Comparer[K], sorted iter.Seq2[K, V], opts ...Option) (*BTree[K, V], error) {
+	tree := NewBTree[K, V](degree, comparer, opts...)
+	var keys []K
+	var values []V
+	for key, value := range sorted {
+		if len(keys) > 0 && tree.comparer.Compare(keys[len(keys)-1], key) >= 0 {
+			return nil, fmt.Errorf("%w: btree keys are not in strictly increasing order", ErrInvalid)
+		}
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	tree.load(keys, values)
+	return tree, nil
+}
+
+// load builds the tree from keys in order, a level at a time from the
+// leaves, splitting each level into as few nodes as fit.
+func (tree *BTree[K, V]) load(keys []K, values []V) {
+	tree.length = len(keys)
+	if len(keys) == 0 {
+		return
+	}
+	// Each leaf but the last is followed by a separator key for its parent.
+	var nodes []*btreeNode[K, V]
+	var separatorKeys []K
+	var separatorValues []V
+	count := (len(keys) + 2*tree.degree) / (2 * tree.degree)
+	start := 0
+	for i, size := range evenSizes(len(keys)-(count-1), count) {
+		nodes = append(nodes, tree.newNode(keys[start:start+size], values[start:start+size], nil))
+		start += size
+		if i < count-1 {
+			separatorKeys = append(separatorKeys, keys[start])
+			separatorValues = append(separatorValues, values[start])
+			start++
+		}
+	}
+	for len(nodes) > 1 {
+		// Group the nodes under parents, keeping the separators between
+		// groups for the next level.
+		var parents []*btreeNode[K, V]
+		var parentKeys []K
+		var parentValues []V
+		count := (len(nodes) + 2*tree.degree - 1) / (2 * tree.degree)
+		start := 0
+		for i, size := range evenSizes(len(nodes), count) {
+			end := start + size
+			parents = append(parents, tree.newNode(separatorKeys[start:end-1], separatorValues[start:end-1], nodes[start:end]))
+			if i < count-1 {
+				parentKeys = append(parentKeys, separatorKeys[end-1])
+				parentValues = append(parentValues, separatorValues[end-1])
+			}
+			start = end
+		}
+		nodes, separatorKeys, separatorValues = parents, parentKeys, parentValues
+	}
+	tree.root = nodes[0]
+}
+
+// evenSizes splits n into count sizes that differ by at most 1.
+func evenSizes(n, count int) []int {
+	sizes := make([]int, count)
+	for i := range sizes {
+		sizes[i] = n / count
+		if i < n%count {
+			sizes[i]++
+		}
+	}
+	return sizes
+}
+
+// newNode creates a node with room for a full set of keys, copying its keys,
+// values, and children, which are nil for a leaf.
+func (tree *BTree[K, V]) newNode(keys []K, values []V, children []*btreeNode[K, V]) *btreeNode[K, V] {
+	full := 2*tree.degree - 1
+	node := &btreeNode[K, V]{
+		keys:   append(make([]K, 0, full), keys...),
+		values: append(make([]V, 0, full), values...),
+	}
+	if children != nil {
+		node.children = append(make([]*btreeNode[K, V], 0, full+1), children...)
+	}
+	return node
+}
+
+// Length reports the number of keys in the tree.
+func (tree *BTree[K, V]) Length() int {
+	if tree == nil {
+		return 0
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	return tree.length
+}
+
+// Degree reports the minimum degree of the tree.
+func (tree *BTree[K, V]) Degree() int {
+	if tree == nil {
+		return 0
+	}
+	return tree.degree
+}
+
+// search finds the index of the first key of a node that is not less than
+// key, and whether it is equal.
+func (tree *BTree[K, V]) search(node *btreeNode[K, V], key K) (int, bool) {
+	return slices.BinarySearchFunc(node.keys, key, tree.comparer.Compare)
+}
+
+// Insert stores a value under a key, replacing any value already there.
+func (tree *BTree[K, V]) Insert(key K, value V) error {
+	if tree == nil {
+		return nilError("btree")
+	}
+	start := tree.metrics.begin()
+	tree.mux.Lock()
+	defer tree.mux.Unlock()
+	defer tree.metrics.end("Insert", start, tree.metrics.acquired())
+	defer tree.debugCheck()
+	if tree.root == nil {
+		tree.root = tree.newNode(nil, nil, nil)
+	}
+	if len(tree.root.keys) == 2*tree.degree-1 {
+		tree.root = tree.newNode(nil, nil, []*btreeNode[K, V]{tree.root})
+		tree.split(tree.root, 0)
+	}
+	// Split full nodes on the way down, so there is room for a median.
+	node := tree.root
+	for {
+		i, found := tree.search(node, key)
+		if found {
+			node.values[i] = value
+			return nil
+		}
+		if node.leaf() {
+			node.keys = slices.Insert(node.keys, i, key)
+			node.values = slices.Insert(node.values, i, value)
+			tree.length++
+			return nil
+		}
+		if len(node.children[i].keys) == 2*tree.degree-1 {
+			tree.split(node, i)
+			switch order := tree.comparer.Compare(key, node.keys[i]); {
+			case order == 0:
+				node.values[i] = value
+				return nil
+			case order > 0:
+				i++
+			}
+		}
+		node = node.children[i]
+	}
+}
+
+// split moves the upper half of a full child to a new node after it,
+// lifting the median key into the parent, lock must be held.
+func (tree *BTree[K, V]) split(parent *btreeNode[K, V], i int) {
+	child, median := parent.children[i], tree.degree-1
+	var children []*btreeNode[K, V]
+	if !child.leaf() {
+		children = child.children[median+1:]
+	}
+	right := tree.newNode(child.keys[median+1:], child.values[median+1:], children)
+	parent.keys = slices.Insert(parent.keys, i, child.keys[median])
+	parent.values = slices.Insert(parent.values, i, child.values[median])
+	parent.children = slices.Insert(parent.children, i+1, right)
+	child.keys = slices.Delete(child.keys, median, len(child.keys))
+	child.values = slices.Delete(child.values, median, len(child.values))
+	if !child.leaf() {
+		child.children = slices.Delete(child.children, median+1, len(child.children))
+	}
+}
+
+// Find gets the value stored under a key.
+func (tree *BTree[K, V]) Find(key K) (V, bool) {
+	var unset V
+	if tree == nil {
+		return unset, false
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	for node := tree.root; node != nil; {
+		i, found := tree.search(node, key)
+		if found {
+			return node.values[i], true
+		}
+		if node.leaf() {
+			break
+		}
+		node = node.children[i]
+	}
+	return unset, false
+}
+
+// Contains reports whether a key is in the tree.
+func (tree *BTree[K, V]) Contains(key K) bool {
+	_, ok := tree.Find(key)
+	return ok
+}
+
+// Delete removes a key, reporting whether there was one.
+func (tree *BTree[K, V]) Delete(key K) bool {
+	if tree == nil {
+		return false
+	}
+	start := tree.metrics.begin()
+	tree.mux.Lock()
+	defer tree.mux.Unlock()
+	defer tree.metrics.end("Delete", start, tree.metrics.acquired())
+	defer tree.debugCheck()
+	if tree.root == nil {
+		return false
+	}
+	deleted := tree.delete(key)
+	if len(tree.root.keys) == 0 {
+		if tree.root.leaf() {
+			tree.root = nil
+		} else {
+			tree.root = tree.root.children[0]
+		}
+	}
+	return deleted
+}
+
+// delete removes a key from the tree, making sure each node it descends to
+// has at least t keys so that one can be removed, lock must be held.
+func (tree *BTree[K, V]) delete(key K) bool {
+	node := tree.root
+	for {
+		i, found := tree.search(node, key)
+		switch {
+		case found && node.leaf():
+			node.keys = slices.Delete(node.keys, i, i+1)
+			node.values = slices.Delete(node.values, i, i+1)
+			tree.length--
+			return true
+		case found:
+			// Replace the key with its predecessor or successor and delete
+			// that from the subtree, or merge the subtrees around the key.
+			left, right := node.children[i], node.children[i+1]
+			switch {
+			case len(left.keys) >= tree.degree:
+				last := left
+				for !last.leaf() {
+					last = last.children[len(last.children)-1]
+				}
+				key = last.keys[len(last.keys)-1]
+				node.keys[i], node.values[i] = key, last.values[len(last.values)-1]
+				node = left
+			case len(right.keys) >= tree.degree:
+				first := right
+				for !first.leaf() {
+					first = first.children[0]
+				}
+				key = first.keys[0]
+				node.keys[i], node.values[i] = key, first.values[0]
+				node = right
+			default:
+				tree.merge(node, i)
+				node = left
+			}
+		case node.leaf():
+			return false
+		default:
+			if len(node.children[i].keys) < tree.degree {
+				i = tree.fill(node, i)
+			}
+			node = node.children[i]
+		}
+	}
+}
+
+// fill gives the child at i of a node at least t keys, borrowing from a
+// sibling or merging with one, and returns the index of the child, lock must
+// be held.
+func (tree *BTree[K, V]) fill(node *btreeNode[K, V], i int) int {
+	child := node.children[i]
+	if i > 0 && len(node.children[i-1].keys) >= tree.degree {
+		// Rotate the last key of the left sibling through the parent.
+		left := node.children[i-1]
+		last := len(left.keys) - 1
+		child.keys = slices.Insert(child.keys, 0, node.keys[i-1])
+		child.values = slices.Insert(child.values, 0, node.values[i-1])
+		node.keys[i-1], node.values[i-1] = left.keys[last], left.values[last]
+		left.keys = slices.Delete(left.keys, last, last+1)
+		left.values = slices.Delete(left.values, last, last+1)
+		if !child.leaf() {
+			child.children = slices.Insert(child.children, 0, left.children[last+1])
+			left.children = slices.Delete(left.children, last+1, last+2)
+		}
+		return i
+	}
+	if i < len(node.children)-1 && len(node.children[i+1].keys) >= tree.degree {
+		// Rotate the first key of the right sibling through the parent.
+		right := node.children[i+1]
+		child.keys = append(child.keys, node.keys[i])
+		child.values = append(child.values, node.values[i])
+		node.keys[i], node.values[i] = right.keys[0], right.values[0]
+		right.keys = slices.Delete(right.keys, 0, 1)
+		right.values = slices.Delete(right.values, 0, 1)
+		if !child.leaf() {
+			child.children = append(child.children, right.children[0])
+			right.children = slices.Delete(right.children, 0, 1)
+		}
+		return i
+	}
+	if i == len(node.children)-1 {
+		i--
+	}
+	tree.merge(node, i)
+	return i
+}
+
+// merge moves the key at i of a node and the child after it into the child
+// before it, lock must be held.
+func (tree *BTree[K, V]) merge(node *btreeNode[K, V], i int) {
+	left, right := node.children[i], node.children[i+1]
+	left.keys = append(append(left.keys, node.keys[i]), right.keys...)
+	left.values = append(append(left.values, node.values[i]), right.values...)
+	if !left.leaf() {
+		left.children = append(left.children, right.children...)
+	}
+	node.keys = slices.Delete(node.keys, i, i+1)
+	node.values = slices.Delete(node.values, i, i+1)
+	node.children = slices.Delete(node.children, i+1, i+2)
+}
+
+// Min gets the least key and its value.
+func (tree *BTree[K, V]) Min() (K, V, bool) {
+	return tree.extreme(false)
+}
+
+// Max gets the greatest key and its value.
+func (tree *BTree[K, V]) Max() (K, V, bool) {
+	return tree.extreme(true)
+}
+
+// extreme follows the first children from the root to a leaf and takes its
+// first key, or the last children and last key if last is true.
+func (tree *BTree[K, V]) extreme(last bool) (K, V, bool) {
+	var key K
+	var value V
+	if tree == nil {
+		return key, value, false
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	node := tree.root
+	if node == nil {
+		return key, value, false
+	}
+	for !node.leaf() {
+		if last {
+			node = node.children[len(node.children)-1]
+		} else {
+			node = node.children[0]
+		}
+	}
+	i := 0
+	if last {
+		i = len(node.keys) - 1
+	}
+	return node.keys[i], node.values[i], true
+}
+
+// Floor gets the greatest key not greater than key, and its value.
+func (tree *BTree[K, V]) Floor(key K) (K, V, bool) {
+	return tree.nearest(key, false)
+}
+
+// Ceiling gets the least key not less than key, and its value.
+func (tree *BTree[K, V]) Ceiling(key K) (K, V, bool) {
+	return tree.nearest(key, true)
+}
+
+// nearest searches for key, keeping the closest key before it, or after it
+// if after is true, from each node on the way down, since the keys of a
+// deeper node lie between those of its parent.
+func (tree *BTree[K, V]) nearest(key K, after bool) (K, V, bool) {
+	var nearestKey K
+	var nearestValue V
+	if tree == nil {
+		return nearestKey, nearestValue, false
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	ok := false
+	for node := tree.root; node != nil; {
+		i, found := tree.search(node, key)
+		if found {
+			return node.keys[i], node.values[i], true
+		}
+		j := i - 1
+		if after {
+			j = i
+		}
+		if j >= 0 && j < len(node.keys) {
+			nearestKey, nearestValue, ok = node.keys[j], node.values[j], true
+		}
+		if node.leaf() {
+			break
+		}
+		node = node.children[i]
+	}
+	return nearestKey, nearestValue, ok
+}
+
+// Height reports the number of nodes on each path from the root to a leaf.
+func (tree *BTree[K, V]) Height() int {
+	if tree == nil {
+		return 0
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	height := 0
+	for node := tree.root; node != nil; height++ {
+		if node.leaf() {
+			node = nil
+		} else {
+			node = node.children[0]
+		}
+	}
+	return height
+}
+
+// ascend calls f on each key from the first not less than lo, or from the
+// least if from is false, in order until it returns false, lock must be
+// held.
+func (tree *BTree[K, V]) ascend(node *btreeNode[K, V], lo K, from bool, f func(K, V) bool) bool {
+	i := 0
+	if from {
+		i, _ = tree.search(node, lo)
+	}
+	for ; i <= len(node.keys); i++ {
+		if !node.leaf() && !tree.ascend(node.children[i], lo, from, f) {
+			return false
+		}
+		// Keys after the first are past lo, so the children after it are too.
+		from = false
+		if i < len(node.keys) && !f(node.keys[i], node.values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// collect copies the keys and values found by ascend until stop reports
+// true of a key.
+func (tree *BTree[K, V]) collect(lo K, from bool, stop func(K) bool) ([]K, []V) {
+	if tree == nil {
+		return nil, nil
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	var keys []K
+	var values []V
+	if tree.root != nil {
+		tree.ascend(tree.root, lo, from, func(key K, value V) bool {
+			if stop != nil && stop(key) {
+				return false
+			}
+			keys = append(keys, key)
+			values = append(values, value)
+			return true
+		})
+	}
+	return keys, values
+}
+
+// Keys copies the keys into a slice, in order.
+func (tree *BTree[K, V]) Keys() []K {
+	var lo K
+	keys, _ := tree.collect(lo, false, nil)
+	return keys
+}
+
+// All gets a sequence of the keys and values as of the call, in key order.
+func (tree *BTree[K, V]) All() iter.Seq2[K, V] {
+	var lo K
+	return tree.sequence(lo, false, nil)
+}
+
+// Range gets a sequence of the keys from lo up to but not including hi, and
+// their values, as of the call, in key order.
+func (tree *BTree[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return tree.sequence(lo, true, func(key K) bool { return tree.comparer.Compare(key, hi) >= 0 })
+}
+
+// sequence yields the keys and values collected until stop.
+func (tree *BTree[K, V]) sequence(lo K, from bool, stop func(K) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		keys, values := tree.collect(lo, from, stop)
+		for i, key := range keys {
+			if !yield(key, values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// String converts BTree data into a string of key:value pairs, in key order.
+func (tree *BTree[K, V]) String() string {
+	if tree == nil {
+		return ""
+	}
+	s := fmt.Sprintf("Length: %d, Data:", tree.Length())
+	for key, value := range tree.All() {
+		s += fmt.Sprintf(" %v:%v", key, value)
+	}
+	return s
+}
+
+// CheckInvariants verifies that every node but the root has between t-1 and
+// 2t-1 keys in strictly increasing order, that every key is between the keys
+// around its subtree, that all leaves are at the same depth, and that the
+// length is the number of keys.
+func (tree *BTree[K, V]) CheckInvariants() error {
+	if tree == nil {
+		return nil
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	return tree.checkInvariants()
+}
+
+// checkInvariants verifies the btree, lock must be held.
+func (tree *BTree[K, V]) checkInvariants() error {
+	if tree.root == nil {
+		if tree.length != 0 {
+			return fmt.Errorf("btree: length %d with no root", tree.length)
+		}
+		return nil
+	}
+	count, leafDepth := 0, -1
+	var check func(node *btreeNode[K, V], depth int, lo, hi *K) error
+	check = func(node *btreeNode[K, V], depth int, lo, hi *K) error {
+		if n := len(node.keys); n > 2*tree.degree-1 || (node != tree.root && n < tree.degree-1) || n == 0 {
+			return fmt.Errorf("btree: node at depth %d has %d keys", depth, n)
+		}
+		if len(node.values) != len(node.keys) {
+			return fmt.Errorf("btree: node at depth %d has %d keys and %d values", depth, len(node.keys), len(node.values))
+		}
+		for i, key := range node.keys {
+			if (i > 0 && tree.comparer.Compare(node.keys[i-1], key) >= 0) ||
+				(lo != nil && tree.comparer.Compare(*lo, key) >= 0) ||
+				(hi != nil && tree.comparer.Compare(key, *hi) >= 0) {
+				return fmt.Errorf("btree: key %v is out of order", key)
+			}
+		}
+		count += len(node.keys)
+		if node.leaf() {
+			if leafDepth >= 0 && depth != leafDepth {
+				return fmt.Errorf("btree: leaves at depths %d and %d", leafDepth, depth)
+			}
+			leafDepth = depth
+			return nil
+		}
+		if len(node.children) != len(node.keys)+1 {
+			return fmt.Errorf("btree: node at depth %d has %d keys and %d children", depth, len(node.keys), len(node.children))
+		}
+		for i, child := range node.children {
+			childLo, childHi := lo, hi
+			if i > 0 {
+				childLo = &node.keys[i-1]
+			}
+			if i < len(node.keys) {
+				childHi = &node.keys[i]
+			}
+			if err := check(child, depth+1, childLo, childHi); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := check(tree.root, 0, nil, nil); err != nil {
+		return err
+	}
+	if count != tree.length {
+		return fmt.Errorf("btree: length %d, counted %d keys", tree.length, count)
+	}
+	return nil
+}
+
+// debugCheck panics if the btree invariants are violated in debug builds,
+// lock must be held.
+func (tree *BTree[K, V]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := tree.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
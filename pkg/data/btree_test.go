@@ -0,0 +1,144 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	"fun/pkg/constraints"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func Test_BTree(t *testing.T) {
+	tree := NewBTree[int, string](2, constraints.OrderedComparer[int]())
+	if _, _, ok := tree.Max(); ok {
+		t.Error("expected no greatest key of an empty tree")
+	}
+	random := rand.New(rand.NewSource(1))
+	for _, key := range random.Perm(50) {
+		tree.Insert(key, fmt.Sprint("v", key))
+		if err := tree.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tree.Insert(7, "seven")
+	if value, ok := tree.Find(7); !ok || value != "seven" || tree.Length() != 50 {
+		t.Error("expected Insert to replace the value", value, ok, tree.Length())
+	}
+	if key, value, _ := tree.Min(); key != 0 || value != "v0" {
+		t.Error("unexpected least key", key, value)
+	}
+	if key, _, _ := tree.Max(); key != 49 {
+		t.Error("unexpected greatest key", key)
+	}
+	var keys []int
+	for key := range tree.Range(10, 15) {
+		keys = append(keys, key)
+	}
+	if !slices.Equal(keys, []int{10, 11, 12, 13, 14}) {
+		t.Error("unexpected range", keys)
+	}
+	tree.Delete(20)
+	if key, _, ok := tree.Floor(20); !ok || key != 19 {
+		t.Error("unexpected floor", key, ok)
+	}
+	if key, _, ok := tree.Ceiling(20); !ok || key != 21 {
+		t.Error("unexpected ceiling", key, ok)
+	}
+	if _, _, ok := tree.Ceiling(50); ok {
+		t.Error("expected no ceiling past the greatest key")
+	}
+	tree.Insert(20, "v20")
+	for _, key := range random.Perm(50) {
+		if !tree.Delete(key) {
+			t.Error("expected to delete", key)
+		}
+		if err := tree.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if tree.Length() != 0 || tree.Delete(1) {
+		t.Error("expected an empty tree", tree)
+	}
+
+	var unset *BTree[int, int]
+	if err := unset.Insert(1, 1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
+
+func Test_BTreeFromSorted(t *testing.T) {
+	for _, n := range []int{0, 1, 5, 7, 8, 100, 1000} {
+		values := map[int]int{}
+		for i := range n {
+			values[i*2] = i
+		}
+		tree, err := NewBTreeFromSorted(3, constraints.OrderedComparer[int](), func(yield func(int, int) bool) {
+			for _, key := range slices.Sorted(maps.Keys(values)) {
+				if !yield(key, values[key]) {
+					return
+				}
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tree.CheckInvariants(); err != nil {
+			t.Error(n, err)
+		}
+		if tree.Length() != n || !slices.Equal(tree.Keys(), slices.Sorted(maps.Keys(values))) {
+			t.Error("unexpected keys of loaded tree", n, tree.Keys())
+		}
+		// A loaded tree stays valid as it changes.
+		tree.Insert(1, 1)
+		tree.Delete(0)
+		if err := tree.CheckInvariants(); err != nil {
+			t.Error(n, err)
+		}
+	}
+	unsorted := func(yield func(int, int) bool) {
+		_ = yield(2, 0) && yield(1, 0)
+	}
+	if _, err := NewBTreeFromSorted(3, constraints.OrderedComparer[int](), unsorted); err == nil {
+		t.Error("expected an error loading unsorted keys")
+	}
+}
+
+// btreeMachine checks a BTree against a map model.
+var btreeMachine = datatest.Machine[*BTree[int, int], map[int]int]{
+	NewSystem: func() *BTree[int, int] { return NewBTree[int, int](2, constraints.OrderedComparer[int]()) },
+	NewModel:  func() map[int]int { return map[int]int{} },
+	ArgRange:  64,
+	Commands: []datatest.Command[*BTree[int, int], map[int]int]{
+		{Name: "Insert", Run: func(tree *BTree[int, int], model map[int]int, arg int) error {
+			model[arg] = len(model)
+			return tree.Insert(arg, model[arg])
+		}},
+		{Name: "Delete", Run: func(tree *BTree[int, int], model map[int]int, arg int) error {
+			_, found := model[arg]
+			delete(model, arg)
+			if ok := tree.Delete(arg); ok != found {
+				return fmt.Errorf("Delete returned %t", ok)
+			}
+			return nil
+		}},
+	},
+	Check: func(tree *BTree[int, int], model map[int]int) error {
+		if keys := tree.Keys(); !slices.Equal(keys, slices.Sorted(maps.Keys(model))) {
+			return fmt.Errorf("unexpected keys %v", keys)
+		}
+		for key, expected := range model {
+			if value, ok := tree.Find(key); !ok || value != expected {
+				return fmt.Errorf("key %d has value %d %t, expected %d", key, value, ok, expected)
+			}
+		}
+		return nil
+	},
+}
+
+func Test_BTreeModel(t *testing.T) {
+	btreeMachine.Test(t, datatest.Config{Runs: 200, Length: 200})
+}
@@ -0,0 +1,7 @@
+//go:build !datadebug
+
+package data
+
+// debugInvariants enables invariant checks after every mutation, build with
+// -tags datadebug to turn them on.
+const debugInvariants = false
@@ -0,0 +1,6 @@
+//go:build datadebug
+
+package data
+
+// debugInvariants enables invariant checks after every mutation.
+const debugInvariants = true
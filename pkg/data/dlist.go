@@ -0,0 +1,348 @@
+package data
+
+import (
+	"fmt"
+	"iter"
+)
+
+// DListNode is a node of a doubly-linked list.
+type DListNode[T ListData] struct {
+	value T             // Value is storage for data in the list.
+	prev  *DListNode[T] // Pointer to the previous element in the list.
+	next  *DListNode[T] // Pointer to the next element in the list.
+}
+
+// DList is a doubly-linked list, which can be walked in both directions and
+// removes its tail in constant time.
+type DList[T ListData] struct {
+	head    *DListNode[T] // Head of the list.
+	tail    *DListNode[T] // Tail of the list.
+	length  int           // Number of elements stored in the list.
+	metrics *Metrics      // Instrumentation, nil when disabled.
+	tracer  *traceHook    // Tracing of scans, nil when disabled.
+	mux     locker        // Lock read and write operations.
+}
+
+// Create a new doubly-linked list, configured by WithLocking, WithMetrics,
+// and WithTracer.
+func NewDList[T ListData](opts ...Option) *DList[T] {
+	settings := newOptions(opts)
+	return &DList[T]{
+		metrics: settings.metrics,
+		tracer:  newTraceHook(settings.tracer, settings.threshold),
+		mux:     settings.newLocker(),
+	}
+}
+
+// Length reports the number of elements in the list.
+func (list *DList[T]) Length() int {
+	if list == nil {
+		return 0
+	}
+	return list.length
+}
+
+// Head gets the head of the list.
+func (list *DList[T]) Head() *DListNode[T] {
+	if list == nil {
+		return nil
+	}
+	return list.head
+}
+
+// Tail gets the tail of the list.
+func (list *DList[T]) Tail() *DListNode[T] {
+	if list == nil {
+		return nil
+	}
+	return list.tail
+}
+
+// Value gets the value of a DListNode.
+func (listNode *DListNode[T]) Value() (T, bool) {
+	var unset T
+	if listNode == nil {
+		return unset, false
+	}
+	return listNode.value, true
+}
+
+// Next gets the next node of a DListNode.
+func (listNode *DListNode[T]) Next() *DListNode[T] {
+	if listNode == nil {
+		return nil
+	}
+	return listNode.next
+}
+
+// Prev gets the previous node of a DListNode.
+func (listNode *DListNode[T]) Prev() *DListNode[T] {
+	if listNode == nil {
+		return nil
+	}
+	return listNode.prev
+}
+
+// Insert adds an element at the beginning of a list.
+func (list *DList[T]) Insert(value T) error {
+	if list == nil {
+		return nilError("list")
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("Insert", start, list.metrics.acquired())
+	defer list.debugCheck()
+	listNode := &DListNode[T]{value: value, next: list.head}
+	if list.head == nil {
+		list.tail = listNode
+	} else {
+		list.head.prev = listNode
+	}
+	list.head = listNode
+	list.length++
+	return nil
+}
+
+// Append adds an element at the end of a list.
+func (list *DList[T]) Append(value T) error {
+	if list == nil {
+		return nilError("list")
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("Append", start, list.metrics.acquired())
+	defer list.debugCheck()
+	listNode := &DListNode[T]{value: value, prev: list.tail}
+	if list.tail == nil {
+		list.head = listNode
+	} else {
+		list.tail.next = listNode
+	}
+	list.tail = listNode
+	list.length++
+	return nil
+}
+
+// find finds the first node holding value, lock must be held.
+func (list *DList[T]) find(value T) *DListNode[T] {
+	for node := list.head; node != nil; node = node.next {
+		if node.value == value {
+			return node
+		}
+	}
+	return nil
+}
+
+// Find a value in the list.
+func (list *DList[T]) Find(value T) *DListNode[T] {
+	if list == nil {
+		return nil
+	}
+	start := list.metrics.begin()
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	defer list.metrics.end("Find", start, list.metrics.acquired())
+	defer list.tracer.end("DList.Find", list.tracer.begin(), list.length)
+	return list.find(value)
+}
+
+// Delete the first element holding value from the list.
+func (list *DList[T]) Delete(value T) bool {
+	if list == nil {
+		return false
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("Delete", start, list.metrics.acquired())
+	defer list.tracer.end("DList.Delete", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	found := list.find(value)
+	if found == nil {
+		return false
+	}
+	list.unlink(found)
+	return true
+}
+
+// Delete the head node in the list.
+func (list *DList[T]) DeleteHead() (T, bool) {
+	var unset T
+	if list == nil {
+		return unset, false
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("DeleteHead", start, list.metrics.acquired())
+	defer list.debugCheck()
+	if list.head == nil {
+		return unset, false
+	}
+	value := list.head.value
+	list.unlink(list.head)
+	return value, true
+}
+
+// Delete the tail node in the list.
+func (list *DList[T]) DeleteTail() (T, bool) {
+	var unset T
+	if list == nil {
+		return unset, false
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("DeleteTail", start, list.metrics.acquired())
+	defer list.debugCheck()
+	if list.tail == nil {
+		return unset, false
+	}
+	value := list.tail.value
+	list.unlink(list.tail)
+	return value, true
+}
+
+// unlink removes a node of the list, lock must be held.
+func (list *DList[T]) unlink(node *DListNode[T]) {
+	if node.prev == nil {
+		list.head = node.next
+	} else {
+		node.prev.next = node.next
+	}
+	if node.next == nil {
+		list.tail = node.prev
+	} else {
+		node.next.prev = node.prev
+	}
+	node.prev, node.next = nil, nil
+	list.length--
+}
+
+// String converts DList data into a string.
+func (list *DList[T]) String() string {
+	if list == nil {
+		return ""
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	defer list.tracer.end("DList.String", list.tracer.begin(), list.length)
+	s := fmt.Sprintf("Length: %d, Data:", list.length)
+	for node := list.head; node != nil; node = node.next {
+		s += " " + fmt.Sprint(node.value)
+	}
+	return s
+}
+
+// dlistIterator walks the nodes of a list in one direction, taking the read
+// lock for each step so the list can be changed during iteration.
+type dlistIterator[T ListData] struct {
+	list     *DList[T]     // List being iterated.
+	node     *DListNode[T] // Current node, nil before the first step and once done.
+	started  bool          // Whether Next has been called.
+	backward bool          // Whether to walk from tail to head.
+}
+
+// Iterator creates an iterator over the values of the list from head to
+// tail, with the consistency of List.Iterator.
+func (list *DList[T]) Iterator() Iterator[T] {
+	return &dlistIterator[T]{list: list}
+}
+
+// Backward creates an iterator over the values of the list from tail to
+// head, with the consistency of List.Iterator.
+func (list *DList[T]) Backward() Iterator[T] {
+	return &dlistIterator[T]{list: list, backward: true}
+}
+
+// All gets a sequence of the values of the list from head to tail.
+func (list *DList[T]) All() iter.Seq[T] {
+	return Seq(list.Iterator())
+}
+
+// Reversed gets a sequence of the values of the list from tail to head.
+func (list *DList[T]) Reversed() iter.Seq[T] {
+	return Seq(list.Backward())
+}
+
+func (it *dlistIterator[T]) Next() bool {
+	if it.list == nil {
+		return false
+	}
+	it.list.mux.RLock()
+	defer it.list.mux.RUnlock()
+	switch {
+	case !it.started && it.backward:
+		it.started = true
+		it.node = it.list.tail
+	case !it.started:
+		it.started = true
+		it.node = it.list.head
+	case it.node != nil && it.backward:
+		it.node = it.node.prev
+	case it.node != nil:
+		it.node = it.node.next
+	}
+	return it.node != nil
+}
+
+func (it *dlistIterator[T]) Value() T {
+	var unset T
+	if it.node == nil {
+		return unset
+	}
+	return it.node.value
+}
+
+// CheckInvariants verifies the list length matches the chain of nodes and
+// that the previous links mirror the next links.
+func (list *DList[T]) CheckInvariants() error {
+	if list == nil {
+		return nil
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	return list.checkInvariants()
+}
+
+// checkInvariants verifies the list, lock must be held.
+func (list *DList[T]) checkInvariants() error {
+	if (list.head == nil) != (list.tail == nil) {
+		return fmt.Errorf("dlist: head %p and tail %p must both be nil or both be set", list.head, list.tail)
+	}
+	if list.head != nil && list.head.prev != nil {
+		return fmt.Errorf("dlist: head %p has a previous node", list.head)
+	}
+	count := 0
+	var last *DListNode[T]
+	for node := list.head; node != nil; node = node.next {
+		count++
+		if count > list.length {
+			return fmt.Errorf("dlist: chain has more than length %d nodes (cycle or stale length)", list.length)
+		}
+		if node.prev != last {
+			return fmt.Errorf("dlist: node %p links back to %p, not %p", node, node.prev, last)
+		}
+		last = node
+	}
+	if count != list.length {
+		return fmt.Errorf("dlist: length is %d but chain has %d nodes", list.length, count)
+	}
+	if last != list.tail {
+		return fmt.Errorf("dlist: tail %p is not the last node %p", list.tail, last)
+	}
+	return nil
+}
+
+// debugCheck panics if the list invariants are violated in debug builds, lock
+// must be held.
+func (list *DList[T]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := list.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
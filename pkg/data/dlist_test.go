@@ -0,0 +1,86 @@
+package data_test
+
+import (
+	. "fun/pkg/data"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// dlistAssert verifies a doubly-linked list in both directions.
+func dlistAssert(t *testing.T, list *DList[Data], values []Data) {
+	t.Helper()
+	if err := list.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+	if forward := slices.Collect(list.All()); !slices.Equal(forward, values) {
+		t.Error("expected", values, "got", forward)
+	}
+	backward := slices.Collect(list.Reversed())
+	slices.Reverse(backward)
+	if !slices.Equal(backward, values) {
+		t.Error("expected reversed", values, "got", backward)
+	}
+}
+
+func Test_DList(t *testing.T) {
+	list := NewDList[Data]()
+	dlistAssert(t, list, nil)
+	list.Append(2)
+	list.Append(3)
+	list.Insert(1)
+	dlistAssert(t, list, []Data{1, 2, 3})
+
+	if node := list.Find(2); node == nil || node.Prev() != list.Head() || node.Next() != list.Tail() {
+		t.Error("unexpected links of the middle node")
+	}
+	if list.Find(4) != nil {
+		t.Error("expected no node for a missing value")
+	}
+	if !list.Delete(2) || list.Delete(2) {
+		t.Error("expected to delete 2 once")
+	}
+	dlistAssert(t, list, []Data{1, 3})
+
+	if value, ok := list.DeleteTail(); !ok || value != 3 {
+		t.Error("unexpected tail", value, ok)
+	}
+	if value, ok := list.DeleteHead(); !ok || value != 1 {
+		t.Error("unexpected head", value, ok)
+	}
+	if _, ok := list.DeleteTail(); ok {
+		t.Error("expected an empty list")
+	}
+	dlistAssert(t, list, nil)
+	if list.String() != "Length: 0, Data:" {
+		t.Error("unexpected string", list.String())
+	}
+
+	var missing *DList[Data]
+	if missing.Insert(1) == nil || missing.Length() != 0 || missing.Find(1) != nil {
+		t.Error("expected a nil list to be empty and reject inserts")
+	}
+}
+
+func Test_DListConcurrent(t *testing.T) {
+	list := NewDList[Data]()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				list.Append(Data(i))
+				list.Insert(Data(j))
+				list.DeleteTail()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if list.Length() != 800 {
+		t.Error("expected 800 values, got", list.Length())
+	}
+	if err := list.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+}
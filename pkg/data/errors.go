@@ -0,0 +1,113 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrCanceled is returned by blocking operations when their context is
+// canceled or its deadline expires. The context error is wrapped as well, so
+// errors.Is(err, context.DeadlineExceeded) also works.
+var ErrCanceled = errors.New("operation canceled")
+
+// ErrClosed is returned by operations on a closed structure.
+var ErrClosed = errors.New("structure is closed")
+
+// ErrFull is returned when a bounded structure has no room.
+var ErrFull = errors.New("structure is full")
+
+// ErrEmpty is returned when an operation needs an element and there is
+// none.
+var ErrEmpty = errors.New("structure is empty")
+
+// ErrNotFound is returned when a value or key is not in a structure.
+var ErrNotFound = errors.New("not found")
+
+// ErrDuplicate is returned when a key or value is already in a structure
+// that keeps them unique.
+var ErrDuplicate = errors.New("duplicate")
+
+// ErrOutOfRange is returned when an index or weight is outside the bounds of
+// a structure.
+var ErrOutOfRange = errors.New("out of range")
+
+// ErrInvalid is returned when an argument or configuration can never work,
+// such as concatenating a list onto itself.
+var ErrInvalid = errors.New("invalid argument")
+
+// ErrState is returned when an operation is not allowed in the current
+// state of a structure, such as undoing while a transaction is open.
+var ErrState = errors.New("invalid state")
+
+// ErrNilReceiver is returned by methods called on a nil structure.
+var ErrNilReceiver = errors.New("nil receiver")
+
+// ErrSkipRecord is returned by a ReadCSV parse function to skip a record,
+// such as a header row.
+var ErrSkipRecord = errors.New("skip record")
+
+// PanicError is the error of a callback that panicked.
+type PanicError struct {
+	Value any    // Value passed to panic.
+	Stack []byte // Stack of the panicking goroutine.
+}
+
+// Error describes the panic.
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("panicked: %v", err.Value)
+}
+
+// canceledError wraps a context error as ErrCanceled.
+type canceledError struct {
+	cause error // Context error that caused the cancellation.
+}
+
+// canceled converts the error of a done context.
+func canceled(ctx context.Context) error {
+	return canceledError{ctx.Err()}
+}
+
+// Error describes the cancellation and its cause.
+func (err canceledError) Error() string {
+	return ErrCanceled.Error() + ": " + err.cause.Error()
+}
+
+// Is reports whether the target is ErrCanceled.
+func (err canceledError) Is(target error) bool {
+	return target == ErrCanceled
+}
+
+// Unwrap gets the context error.
+func (err canceledError) Unwrap() error {
+	return err.cause
+}
+
+// nilError reports a method called on a nil structure, named by the value.
+type nilError string
+
+// Error names the nil structure.
+func (err nilError) Error() string {
+	return string(err) + " is nil"
+}
+
+// Is reports whether the target is ErrNilReceiver.
+func (err nilError) Is(target error) bool {
+	return target == ErrNilReceiver
+}
+
+// RangeError reports an index outside the bounds of a structure.
+type RangeError struct {
+	Index  int // Requested index.
+	Length int // Length of the structure.
+}
+
+// Error describes the index and bounds.
+func (err RangeError) Error() string {
+	return fmt.Sprintf("index %d out of range [0, %d)", err.Index, err.Length)
+}
+
+// Is reports whether the target is ErrOutOfRange.
+func (err RangeError) Is(target error) bool {
+	return target == ErrOutOfRange
+}
@@ -0,0 +1,65 @@
+package data_test
+
+import (
+	"context"
+	"errors"
+	. "fun/pkg/data"
+	"testing"
+)
+
+func Test_SentinelErrors(t *testing.T) {
+	var nilList *List[Data]
+	var nilQueue *BlockingQueue[int]
+	var nilTopic *Topic[int]
+	var nilRegistry *Registry
+	var nilSnapshotter *Snapshotter
+	nilErrors := map[string]error{
+		"list":        nilList.Append(1),
+		"queue":       nilQueue.Put(context.Background(), 1),
+		"topic":       nilTopic.Publish(context.Background(), 1),
+		"registry":    nilRegistry.Register("list", NewList[Data]()),
+		"snapshotter": nilSnapshotter.Register("list", NewList[Data]()),
+	}
+	for name, err := range nilErrors {
+		if !errors.Is(err, ErrNilReceiver) || err.Error() != name+" is nil" {
+			t.Errorf("%s: expected nil receiver error, got %v", name, err)
+		}
+	}
+
+	semaphore := NewSemaphore(2)
+	if err := semaphore.Acquire(context.Background(), 3); !errors.Is(err, ErrOutOfRange) {
+		t.Error("expected out of range error, got", err)
+	}
+	if err := semaphore.Acquire(context.Background(), -1); !errors.Is(err, ErrOutOfRange) {
+		t.Error("expected out of range error, got", err)
+	}
+	if _, err := AnyFuture[int]().Get(context.Background()); !errors.Is(err, ErrEmpty) {
+		t.Error("expected empty error, got", err)
+	}
+
+	var tx *ListTx[Data]
+	NewList[Data]().Batch(func(batch *ListTx[Data]) { tx = batch })
+	if err := tx.Append(1); !errors.Is(err, ErrClosed) {
+		t.Error("expected closed error, got", err)
+	}
+
+	list := NewList[Data]()
+	if err := list.Concat(list); !errors.Is(err, ErrInvalid) {
+		t.Error("expected invalid error, got", err)
+	}
+	snapshotter := NewSnapshotter()
+	snapshotter.Register("list", list)
+	if err := snapshotter.Register("list", list); !errors.Is(err, ErrDuplicate) {
+		t.Error("expected duplicate error, got", err)
+	}
+	history := NewHistory(list, 0)
+	history.Begin()
+	if err := history.Undo(); !errors.Is(err, ErrState) {
+		t.Error("expected state error, got", err)
+	}
+
+	err := error(RangeError{Index: 5, Length: 3})
+	if !errors.Is(err, ErrOutOfRange) || err.Error() != "index 5 out of range [0, 3)" {
+		t.Error("unexpected range error", err)
+	}
+}
@@ -0,0 +1,405 @@
+package data
+
+import (
+	"fmt"
+	"iter"
+	"unsafe"
+)
+
+// FibonacciNode is an element of a FibonacciHeap, returned by Push as a
+// handle for DecreaseKey.
+type FibonacciNode[T any] struct {
+	value   T                 // Value of the element.
+	parent  *FibonacciNode[T] // Parent, nil for a root.
+	child   *FibonacciNode[T] // Any child, no less than the node.
+	left    *FibonacciNode[T] // Previous node in the circular list of siblings.
+	right   *FibonacciNode[T] // Next node in the circular list of siblings.
+	degree  int               // Number of children.
+	marked  bool              // Lost a child since it became a child itself.
+	removed bool              // Popped from its heap.
+}
+
+// Value gets the value of the element.
+func (node *FibonacciNode[T]) Value() T {
+	return node.value
+}
+
+// FibonacciHeap is a priority queue ordered by a less function, stored as a
+// list of trees whose nodes are no less than their parents. Push, Meld,
+// Peek, and DecreaseKey take amortized constant time, and Pop amortized
+// O(log n) time, the bounds that Dijkstra's and Prim's algorithms assume.
+type FibonacciHeap[T any] struct {
+	min     *FibonacciNode[T]   // Least root, nil when empty.
+	length  int                 // Number of elements stored in the heap.
+	less    func(a, b T) bool   // Order of the elements.
+	roots   []*FibonacciNode[T] // Scratch space for consolidating roots.
+	degrees []*FibonacciNode[T] // Scratch space for roots by degree.
+	metrics *Metrics            // Instrumentation, nil when disabled.
+	mux     locker              // Lock read and write operations.
+}
+
+// Create a new Fibonacci heap ordered by less, configured by WithLocking and
+// WithMetrics.
+func NewFibonacciHeap[T any](less func(a, b T) bool, opts ...Option) *FibonacciHeap[T] {
+	settings := newOptions(opts)
+	return &FibonacciHeap[T]{less: less, metrics: settings.metrics, mux: settings.newLocker()}
+}
+
+// Length reports the number of elements in the heap.
+func (heap *FibonacciHeap[T]) Length() int {
+	if heap == nil {
+		return 0
+	}
+	heap.mux.RLock()
+	defer heap.mux.RUnlock()
+	return heap.length
+}
+
+// Push adds an element, returning its node.
+func (heap *FibonacciHeap[T]) Push(value T) (*FibonacciNode[T], error) {
+	if heap == nil {
+		return nil, nilError("fibonacci heap")
+	}
+	start := heap.metrics.begin()
+	heap.mux.Lock()
+	defer heap.mux.Unlock()
+	defer heap.metrics.end("Push", start, heap.metrics.acquired())
+	defer heap.debugCheck()
+	node := &FibonacciNode[T]{value: value}
+	node.left, node.right = node, node
+	heap.addRoots(node)
+	heap.length++
+	return node, nil
+}
+
+// Pop removes the least element.
+func (heap *FibonacciHeap[T]) Pop() (T, bool) {
+	var unset T
+	if heap == nil {
+		return unset, false
+	}
+	start := heap.metrics.begin()
+	heap.mux.Lock()
+	defer heap.mux.Unlock()
+	defer heap.metrics.end("Pop", start, heap.metrics.acquired())
+	defer heap.debugCheck()
+	least := heap.min
+	if least == nil {
+		return unset, false
+	}
+	if child := least.child; child != nil {
+		for node := child; ; node = node.right {
+			node.parent, node.marked = nil, false
+			if node.right == child {
+				break
+			}
+		}
+		least.splice(child)
+	}
+	if least.right == least {
+		heap.min = nil
+	} else {
+		heap.min = least.right
+		least.unlink()
+		heap.consolidate()
+	}
+	heap.length--
+	least.child, least.degree, least.removed = nil, 0, true
+	return least.value, true
+}
+
+// Peek gets the least element without removing it.
+func (heap *FibonacciHeap[T]) Peek() (T, bool) {
+	var unset T
+	if heap == nil {
+		return unset, false
+	}
+	heap.mux.RLock()
+	defer heap.mux.RUnlock()
+	if heap.min == nil {
+		return unset, false
+	}
+	return heap.min.value, true
+}
+
+// DecreaseKey replaces the value of a node in the heap with one that is not
+// greater, returning ErrNotFound for a node that was popped and
+// ErrOutOfRange for a greater value. The node must have been pushed to this
+// heap or to one melded into it.
+func (heap *FibonacciHeap[T]) DecreaseKey(node *FibonacciNode[T], value T) error {
+	if heap == nil {
+		return nilError("fibonacci heap")
+	}
+	start := heap.metrics.begin()
+	heap.mux.Lock()
+	defer heap.mux.Unlock()
+	defer heap.metrics.end("DecreaseKey", start, heap.metrics.acquired())
+	defer heap.debugCheck()
+	if node == nil || node.removed {
+		return ErrNotFound
+	}
+	if heap.less(node.value, value) {
+		return ErrOutOfRange
+	}
+	node.value = value
+	if parent := node.parent; parent != nil && heap.less(value, parent.value) {
+		heap.cut(node)
+		// Cut marked ancestors too, so trees stay exponential in size.
+		for ancestor := parent; ancestor.parent != nil; {
+			if !ancestor.marked {
+				ancestor.marked = true
+				break
+			}
+			next := ancestor.parent
+			heap.cut(ancestor)
+			ancestor = next
+		}
+	}
+	if heap.less(value, heap.min.value) {
+		heap.min = node
+	}
+	return nil
+}
+
+// Meld moves every element of other into the heap in constant time, leaving
+// other empty. Nodes of other stay valid for DecreaseKey on the heap.
+func (heap *FibonacciHeap[T]) Meld(other *FibonacciHeap[T]) error {
+	if heap == nil {
+		return nilError("fibonacci heap")
+	}
+	if other == nil {
+		return nil
+	}
+	if other == heap {
+		return fmt.Errorf("%w: cannot meld a heap into itself", ErrInvalid)
+	}
+	start := heap.metrics.begin()
+	unlock := lockInOrder(unsafe.Pointer(heap), heap.mux, unsafe.Pointer(other), other.mux)
+	defer unlock()
+	defer heap.metrics.end("Meld", start, heap.metrics.acquired())
+	defer other.debugCheck()
+	defer heap.debugCheck()
+	if other.min != nil {
+		heap.addRoots(other.min)
+	}
+	heap.length += other.length
+	other.min, other.length = nil, 0
+	return nil
+}
+
+// addRoots adds a circular list of roots to the root list, updating the
+// least root, lock must be held.
+func (heap *FibonacciHeap[T]) addRoots(roots *FibonacciNode[T]) {
+	if heap.min == nil {
+		heap.min = roots
+		return
+	}
+	heap.min.splice(roots)
+	if heap.less(roots.value, heap.min.value) {
+		heap.min = roots
+	}
+}
+
+// consolidate links roots of the same degree until every degree is unique,
+// then finds the least root, lock must be held.
+func (heap *FibonacciHeap[T]) consolidate() {
+	roots := heap.roots[:0]
+	for node := heap.min; ; node = node.right {
+		roots = append(roots, node)
+		if node.right == heap.min {
+			break
+		}
+	}
+	degrees := heap.degrees[:0]
+	for _, root := range roots {
+		for root.degree < len(degrees) && degrees[root.degree] != nil {
+			other := degrees[root.degree]
+			degrees[root.degree] = nil
+			if heap.less(other.value, root.value) {
+				root, other = other, root
+			}
+			heap.link(other, root)
+		}
+		for len(degrees) <= root.degree {
+			degrees = append(degrees, nil)
+		}
+		degrees[root.degree] = root
+	}
+	heap.min = nil
+	for _, root := range degrees {
+		if root != nil && (heap.min == nil || heap.less(root.value, heap.min.value)) {
+			heap.min = root
+		}
+	}
+	clear(roots)
+	clear(degrees)
+	heap.roots, heap.degrees = roots, degrees
+}
+
+// link makes a root the child of another root, lock must be held.
+func (heap *FibonacciHeap[T]) link(child, parent *FibonacciNode[T]) {
+	child.unlink()
+	child.parent, child.marked = parent, false
+	if parent.child == nil {
+		parent.child = child
+	} else {
+		parent.child.splice(child)
+	}
+	parent.degree++
+}
+
+// cut moves a node that is not a root, with its children, to the root list,
+// lock must be held.
+func (heap *FibonacciHeap[T]) cut(node *FibonacciNode[T]) {
+	parent := node.parent
+	if parent.child == node {
+		parent.child = node.right
+		if node.right == node {
+			parent.child = nil
+		}
+	}
+	node.unlink()
+	parent.degree--
+	node.parent, node.marked = nil, false
+	heap.min.splice(node)
+}
+
+// splice joins two circular lists of nodes, inserting the list of other
+// after the node.
+func (node *FibonacciNode[T]) splice(other *FibonacciNode[T]) {
+	right, otherLeft := node.right, other.left
+	node.right, other.left = other, node
+	otherLeft.right, right.left = right, otherLeft
+}
+
+// unlink removes the node from its circular list, leaving it alone in a
+// list.
+func (node *FibonacciNode[T]) unlink() {
+	node.left.right, node.right.left = node.right, node.left
+	node.left, node.right = node, node
+}
+
+// Values copies the elements into a slice in no particular order except
+// that it starts with the least element.
+func (heap *FibonacciHeap[T]) Values() []T {
+	if heap == nil {
+		return nil
+	}
+	heap.mux.RLock()
+	defer heap.mux.RUnlock()
+	values := make([]T, 0, heap.length)
+	var walk func(first *FibonacciNode[T])
+	walk = func(first *FibonacciNode[T]) {
+		for node := first; ; node = node.right {
+			values = append(values, node.value)
+			if node.child != nil {
+				walk(node.child)
+			}
+			if node.right == first {
+				return
+			}
+		}
+	}
+	if heap.min != nil {
+		walk(heap.min)
+	}
+	return values
+}
+
+// All gets a sequence of the elements as of the call, in the order of
+// Values.
+func (heap *FibonacciHeap[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range heap.Values() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// String converts FibonacciHeap data into a string, in the order of Values.
+func (heap *FibonacciHeap[T]) String() string {
+	if heap == nil {
+		return ""
+	}
+	values := heap.Values()
+	s := fmt.Sprintf("Length: %d, Data:", len(values))
+	for _, v := range values {
+		s += " " + fmt.Sprint(v)
+	}
+	return s
+}
+
+// CheckInvariants verifies that no node is less than its parent or the
+// least root, that sibling and parent links are consistent, that degrees
+// count children, and that the length is the number of nodes.
+func (heap *FibonacciHeap[T]) CheckInvariants() error {
+	if heap == nil {
+		return nil
+	}
+	heap.mux.RLock()
+	defer heap.mux.RUnlock()
+	return heap.checkInvariants()
+}
+
+// checkInvariants verifies the heap, lock must be held.
+func (heap *FibonacciHeap[T]) checkInvariants() error {
+	count := 0
+	var check func(first, parent *FibonacciNode[T]) (int, error)
+	check = func(first, parent *FibonacciNode[T]) (int, error) {
+		siblings := 0
+		for node := first; ; node = node.right {
+			siblings++
+			count++
+			if count > heap.length {
+				return 0, fmt.Errorf("fibonacci heap: more than length %d nodes", heap.length)
+			}
+			if node.right.left != node {
+				return 0, fmt.Errorf("fibonacci heap: node %v has a wrong back link", node.right.value)
+			}
+			if node.parent != parent {
+				return 0, fmt.Errorf("fibonacci heap: node %v has a wrong parent", node.value)
+			}
+			if parent != nil && heap.less(node.value, parent.value) {
+				return 0, fmt.Errorf("fibonacci heap: node %v is less than its parent %v", node.value, parent.value)
+			}
+			if parent == nil && heap.less(node.value, heap.min.value) {
+				return 0, fmt.Errorf("fibonacci heap: root %v is less than the minimum %v", node.value, heap.min.value)
+			}
+			children := 0
+			if node.child != nil {
+				var err error
+				if children, err = check(node.child, node); err != nil {
+					return 0, err
+				}
+			}
+			if children != node.degree {
+				return 0, fmt.Errorf("fibonacci heap: node %v has degree %d, counted %d children", node.value, node.degree, children)
+			}
+			if node.right == first {
+				return siblings, nil
+			}
+		}
+	}
+	if heap.min != nil {
+		if _, err := check(heap.min, nil); err != nil {
+			return err
+		}
+	}
+	if count != heap.length {
+		return fmt.Errorf("fibonacci heap: length %d, counted %d nodes", heap.length, count)
+	}
+	return nil
+}
+
+// debugCheck panics if the heap invariants are violated in debug builds,
+// lock must be held.
+func (heap *FibonacciHeap[T]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := heap.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
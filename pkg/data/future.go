@@ -0,0 +1,217 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// Future is a value that is completed, or failed, at most once and can be
+// waited on by any number of goroutines.
+type Future[T any] struct {
+	value T             // Value once completed.
+	err   error         // Error once failed.
+	done  chan struct{} // Closed once completed or failed.
+	once  *sync.Once    // Settle the future once.
+}
+
+// Create a new pending future.
+func NewFuture[T any]() *Future[T] {
+	return &Future[T]{done: make(chan struct{}), once: &sync.Once{}}
+}
+
+// Create a future already completed with a value.
+func CompletedFuture[T any](value T) *Future[T] {
+	future := NewFuture[T]()
+	future.Complete(value)
+	return future
+}
+
+// Create a future already failed with an error.
+func FailedFuture[T any](err error) *Future[T] {
+	future := NewFuture[T]()
+	future.Fail(err)
+	return future
+}
+
+// RunFuture runs f in a new goroutine and settles the future with its result.
+// If f panics, the future fails with a *PanicError.
+func RunFuture[T any](f func() (T, error)) *Future[T] {
+	future := NewFuture[T]()
+	go future.run(f)
+	return future
+}
+
+// run settles the future with the result of f, failing it with a *PanicError
+// if f panics.
+func (future *Future[T]) run(f func() (T, error)) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			future.Fail(&PanicError{Value: recovered, Stack: debug.Stack()})
+		}
+	}()
+	future.settle(f())
+}
+
+// settle completes or fails the future, reporting whether it was pending.
+func (future *Future[T]) settle(value T, err error) bool {
+	settled := false
+	future.once.Do(func() {
+		future.value = value
+		future.err = err
+		settled = true
+		close(future.done)
+	})
+	return settled
+}
+
+// Complete the future with a value, reporting whether it was pending.
+func (future *Future[T]) Complete(value T) bool {
+	return future.settle(value, nil)
+}
+
+// Fail the future with an error, reporting whether it was pending.
+func (future *Future[T]) Fail(err error) bool {
+	var unset T
+	if err == nil {
+		err = fmt.Errorf("%w: future failed without an error", ErrInvalid)
+	}
+	return future.settle(unset, err)
+}
+
+// Done is closed once the future is completed or failed.
+func (future *Future[T]) Done() <-chan struct{} {
+	return future.done
+}
+
+// Get waits for the future and gets its value or error. If ctx is done first,
+// an error wrapping ErrCanceled is returned.
+func (future *Future[T]) Get(ctx context.Context) (T, error) {
+	select {
+	case <-future.done:
+		return future.value, future.err
+	case <-ctx.Done():
+		var unset T
+		return unset, canceled(ctx)
+	}
+}
+
+// Poll gets the value or error without waiting, ok is false while pending.
+func (future *Future[T]) Poll() (value T, ok bool, err error) {
+	select {
+	case <-future.done:
+		return future.value, true, future.err
+	default:
+		return value, false, nil
+	}
+}
+
+// ThenFuture chains f after a future completes. A failure skips f and fails
+// the chained future with the same error. If f panics, the chained future
+// fails with a *PanicError.
+func ThenFuture[T, R any](future *Future[T], f func(T) (R, error)) *Future[R] {
+	chained := NewFuture[R]()
+	go func() {
+		<-future.done
+		if future.err != nil {
+			chained.Fail(future.err)
+			return
+		}
+		chained.run(func() (R, error) {
+			return f(future.value)
+		})
+	}()
+	return chained
+}
+
+// MapFuture converts the value of a future once it completes.
+func MapFuture[T, R any](future *Future[T], f func(T) R) *Future[R] {
+	return ThenFuture(future, func(value T) (R, error) {
+		return f(value), nil
+	})
+}
+
+// AllFutures completes with every value, in order, once all futures complete,
+// or fails with the first failure.
+func AllFutures[T any](futures ...*Future[T]) *Future[[]T] {
+	all := NewFuture[[]T]()
+	if len(futures) == 0 {
+		all.Complete([]T{})
+		return all
+	}
+	values := make([]T, len(futures))
+	var wg sync.WaitGroup
+	wg.Add(len(futures))
+	for i, future := range futures {
+		go func(i int, future *Future[T]) {
+			defer wg.Done()
+			select {
+			case <-future.done:
+			case <-all.done:
+				return
+			}
+			if future.err != nil {
+				all.Fail(future.err)
+				return
+			}
+			values[i] = future.value
+		}(i, future)
+	}
+	go func() {
+		wg.Wait()
+		all.Complete(values)
+	}()
+	return all
+}
+
+// AnyFuture completes with the first value to complete, or fails once every
+// future has failed.
+func AnyFuture[T any](futures ...*Future[T]) *Future[T] {
+	anyFuture := NewFuture[T]()
+	if len(futures) == 0 {
+		anyFuture.Fail(fmt.Errorf("%w: no futures", ErrEmpty))
+		return anyFuture
+	}
+	var mux sync.Mutex
+	failed := 0
+	for _, future := range futures {
+		go func(future *Future[T]) {
+			select {
+			case <-future.done:
+			case <-anyFuture.done:
+				return
+			}
+			if future.err == nil {
+				anyFuture.Complete(future.value)
+				return
+			}
+			mux.Lock()
+			defer mux.Unlock()
+			failed++
+			if failed == len(futures) {
+				anyFuture.Fail(fmt.Errorf("all %d futures failed: %w", len(futures), future.err))
+			}
+		}(future)
+	}
+	return anyFuture
+}
+
+// RaceFutures settles like the first future to complete or fail.
+func RaceFutures[T any](futures ...*Future[T]) *Future[T] {
+	race := NewFuture[T]()
+	if len(futures) == 0 {
+		race.Fail(fmt.Errorf("%w: no futures", ErrEmpty))
+		return race
+	}
+	for _, future := range futures {
+		go func(future *Future[T]) {
+			select {
+			case <-future.done:
+				race.settle(future.value, future.err)
+			case <-race.done:
+			}
+		}(future)
+	}
+	return race
+}
@@ -0,0 +1,117 @@
+package data_test
+
+import (
+	"context"
+	"errors"
+	. "fun/pkg/data"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func Test_Future(t *testing.T) {
+	ctx := context.Background()
+	future := NewFuture[int]()
+	if _, ok, _ := future.Poll(); ok {
+		t.Error("expected new future to be pending")
+	}
+	if !future.Complete(1) {
+		t.Error("expected to complete pending future")
+	}
+	if future.Complete(2) || future.Fail(errors.New("late")) {
+		t.Error("expected future to settle once")
+	}
+	if value, err := future.Get(ctx); err != nil || value != 1 {
+		t.Error("expected 1, got", value, err)
+	}
+
+	failure := errors.New("failure")
+	if _, err := FailedFuture[int](failure).Get(ctx); err != failure {
+		t.Error("expected failure, got", err)
+	}
+	if value, ok, err := CompletedFuture(3).Poll(); !ok || err != nil || value != 3 {
+		t.Error("expected completed 3, got", value, err, ok)
+	}
+
+	pending := NewFuture[int]()
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := pending.Get(timeout); !errors.Is(err, ErrCanceled) {
+		t.Error("expected get to be canceled, got", err)
+	}
+
+	run := RunFuture(func() (string, error) { return "run", nil })
+	if value, _ := run.Get(ctx); value != "run" {
+		t.Error("expected run, got", value)
+	}
+}
+
+func Test_FutureChain(t *testing.T) {
+	ctx := context.Background()
+	future := NewFuture[int]()
+	chained := MapFuture(ThenFuture(future, func(value int) (int, error) {
+		return value * 2, nil
+	}), strconv.Itoa)
+	future.Complete(21)
+	if value, err := chained.Get(ctx); err != nil || value != "42" {
+		t.Error("expected 42, got", value, err)
+	}
+
+	failure := errors.New("failure")
+	called := false
+	failed := ThenFuture(FailedFuture[int](failure), func(value int) (int, error) {
+		called = true
+		return value, nil
+	})
+	if _, err := failed.Get(ctx); err != failure || called {
+		t.Error("expected failure to skip the chain, got", err)
+	}
+
+	var panicErr *PanicError
+	panicked := MapFuture(CompletedFuture(0), func(value int) int {
+		return 1 / value
+	})
+	if _, err := panicked.Get(ctx); !errors.As(err, &panicErr) || len(panicErr.Stack) == 0 {
+		t.Error("expected panic error, got", err)
+	}
+	run := RunFuture(func() (int, error) { panic("run") })
+	if _, err := run.Get(ctx); !errors.As(err, &panicErr) || panicErr.Value != "run" {
+		t.Error("expected panic error, got", err)
+	}
+}
+
+func Test_FutureCombinators(t *testing.T) {
+	ctx := context.Background()
+	failure := errors.New("failure")
+
+	all, err := AllFutures(CompletedFuture(1), CompletedFuture(2), CompletedFuture(3)).Get(ctx)
+	if err != nil || len(all) != 3 || all[0] != 1 || all[2] != 3 {
+		t.Error("expected [1 2 3], got", all, err)
+	}
+	if _, err := AllFutures(CompletedFuture(1), FailedFuture[int](failure), NewFuture[int]()).Get(ctx); err != failure {
+		t.Error("expected all to fail fast, got", err)
+	}
+	if empty, err := AllFutures[int]().Get(ctx); err != nil || len(empty) != 0 {
+		t.Error("expected empty all to complete, got", empty, err)
+	}
+
+	if value, err := AnyFuture(FailedFuture[int](failure), CompletedFuture(2), NewFuture[int]()).Get(ctx); err != nil || value != 2 {
+		t.Error("expected any to complete with 2, got", value, err)
+	}
+	if _, err := AnyFuture(FailedFuture[int](failure), FailedFuture[int](failure)).Get(ctx); !errors.Is(err, failure) {
+		t.Error("expected any to fail when all fail, got", err)
+	}
+	if _, err := AnyFuture[int]().Get(ctx); err == nil {
+		t.Error("expected any of no futures to fail")
+	}
+
+	if _, err := RaceFutures(FailedFuture[int](failure), NewFuture[int]()).Get(ctx); err != failure {
+		t.Error("expected race to fail with the first failure, got", err)
+	}
+	if value, err := RaceFutures(NewFuture[int](), CompletedFuture(5)).Get(ctx); err != nil || value != 5 {
+		t.Error("expected race to complete with 5, got", value, err)
+	}
+	if _, err := RaceFutures[int]().Get(ctx); err == nil {
+		t.Error("expected race of no futures to fail")
+	}
+}
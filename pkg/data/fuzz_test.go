@@ -0,0 +1,117 @@
+package data_test
+
+import (
+	. "fun/pkg/data"
+	"testing"
+)
+
+// List operations applied by FuzzList.
+const (
+	fuzzInsert = iota
+	fuzzAppend
+	fuzzDelete
+	fuzzDeleteHead
+	fuzzDeleteTail
+	fuzzFind
+	fuzzOps
+)
+
+// listModelAssert verifies a list matches the reference model, failing fast so
+// the fuzzer reports the first divergent operation.
+func listModelAssert(t *testing.T, step int, list *List[Data], model []Data) {
+	t.Helper()
+	if list.Length() != len(model) {
+		t.Fatalf("step %d: expected length %d, got %d", step, len(model), list.Length())
+	}
+	i := 0
+	var last *ListNode[Data]
+	for node := list.Head(); node != nil; node = node.Next() {
+		if i >= len(model) {
+			t.Fatalf("step %d: list longer than model %v: %s", step, model, list.String())
+		}
+		if value, _ := node.Value(); value != model[i] {
+			t.Fatalf("step %d: expected %v, got %s", step, model, list.String())
+		}
+		last = node
+		i++
+	}
+	if i != len(model) {
+		t.Fatalf("step %d: list shorter than model %v: %s", step, model, list.String())
+	}
+	if list.Tail() != last {
+		t.Fatalf("step %d: tail is %p, expected %p", step, list.Tail(), last)
+	}
+}
+
+// FuzzList applies operations encoded as (op, value) byte pairs to a list and
+// to a slice model, asserting they stay equivalent.
+func FuzzList(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{fuzzInsert, 1, fuzzAppend, 2, fuzzDeleteTail, 0, fuzzDeleteTail, 0})
+	f.Add([]byte{fuzzAppend, 1, fuzzAppend, 1, fuzzDelete, 1, fuzzDeleteHead, 0, fuzzFind, 1})
+	f.Add([]byte{fuzzInsert, 3, fuzzInsert, 2, fuzzInsert, 1, fuzzDelete, 3, fuzzAppend, 4})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		list := NewList[Data]()
+		var model []Data
+		for step := 0; step+1 < len(ops); step += 2 {
+			value := Data(ops[step+1] % 8)
+			switch ops[step] % fuzzOps {
+			case fuzzInsert:
+				list.Insert(value)
+				model = append([]Data{value}, model...)
+			case fuzzAppend:
+				list.Append(value)
+				model = append(model, value)
+			case fuzzDelete:
+				deleted := list.Delete(value)
+				expected := false
+				for i, v := range model {
+					if v == value {
+						model = append(model[:i], model[i+1:]...)
+						expected = true
+						break
+					}
+				}
+				if deleted != expected {
+					t.Fatalf("step %d: Delete(%d) returned %t", step, value, deleted)
+				}
+			case fuzzDeleteHead:
+				got, ok := list.DeleteHead()
+				if ok != (len(model) > 0) {
+					t.Fatalf("step %d: DeleteHead returned %t", step, ok)
+				}
+				if ok {
+					if got != model[0] {
+						t.Fatalf("step %d: DeleteHead returned %d, expected %d", step, got, model[0])
+					}
+					model = model[1:]
+				}
+			case fuzzDeleteTail:
+				got, ok := list.DeleteTail()
+				if ok != (len(model) > 0) {
+					t.Fatalf("step %d: DeleteTail returned %t", step, ok)
+				}
+				if ok {
+					if got != model[len(model)-1] {
+						t.Fatalf("step %d: DeleteTail returned %d, expected %d", step, got, model[len(model)-1])
+					}
+					model = model[:len(model)-1]
+				}
+			case fuzzFind:
+				found := list.Find(value)
+				expected := false
+				for _, v := range model {
+					expected = expected || v == value
+				}
+				if (found != nil) != expected {
+					t.Fatalf("step %d: Find(%d) returned %v", step, value, found)
+				}
+				if got, ok := found.Value(); found != nil && (!ok || got != value) {
+					t.Fatalf("step %d: Find(%d) found %d", step, value, got)
+				}
+			}
+			listModelAssert(t, step, list, model)
+		}
+	})
+}
@@ -0,0 +1,286 @@
+package data
+
+import (
+	"fmt"
+	"fun/pkg/constraints"
+	"iter"
+)
+
+// hashMapMinCapacity is the number of slots a HashMap first makes.
+const hashMapMinCapacity = 8
+
+// hashSlot is a slot of a HashMap.
+type hashSlot[K comparable, V any] struct {
+	key   K      // Key stored in the slot.
+	value V      // Value stored under the key.
+	probe uint32 // Distance from the slot of the key's hash plus 1, 0 when empty.
+}
+
+// HashMap is a hash map stored in one slice of slots by open addressing
+// with Robin Hood probing: a key is placed at the first free slot after the
+// slot of its hash, displacing keys that are closer to their own slots, so
+// probe lengths stay short and even. Deletion shifts the following keys
+// back rather than leaving tombstones. Put, Get, and Delete take expected
+// constant time, and there are no per-entry allocations or pointers.
+type HashMap[K comparable, V any] struct {
+	slots   []hashSlot[K, V]      // Slots, len is a power of 2.
+	length  int                   // Number of keys stored in the map.
+	hasher  constraints.Hasher[K] // Hashes and compares the keys.
+	metrics *Metrics              // Instrumentation, nil when disabled.
+	mux     locker                // Lock read and write operations.
+}
+
+// Create a new hash map, configured by WithLocking, WithMetrics,
+// WithCapacity, and WithHasher.
+func NewHashMap[K comparable, V any](opts ...Option) *HashMap[K, V] {
+	settings := newOptions(opts)
+	slots := hashMapMinCapacity
+	// Make room for the capacity at the load factor Put grows at.
+	for 7*slots < 8*settings.capacityOr(0) {
+		slots *= 2
+	}
+	return &HashMap[K, V]{
+		slots:   make([]hashSlot[K, V], slots),
+		hasher:  hasherOr(settings, constraints.ComparableHasher[K]()),
+		metrics: settings.metrics,
+		mux:     settings.newLocker(),
+	}
+}
+
+// Length reports the number of keys in the map.
+func (hash *HashMap[K, V]) Length() int {
+	if hash == nil {
+		return 0
+	}
+	hash.mux.RLock()
+	defer hash.mux.RUnlock()
+	return hash.length
+}
+
+// Capacity reports the number of slots.
+func (hash *HashMap[K, V]) Capacity() int {
+	if hash == nil {
+		return 0
+	}
+	hash.mux.RLock()
+	defer hash.mux.RUnlock()
+	return len(hash.slots)
+}
+
+// home gets the slot of the hash of a key, lock must be held.
+func (hash *HashMap[K, V]) home(key K) int {
+	return int(hash.hasher.Hash(key) & uint64(len(hash.slots)-1))
+}
+
+// find gets the slot of a key, or -1, lock must be held.
+func (hash *HashMap[K, V]) find(key K) int {
+	mask := len(hash.slots) - 1
+	for i, probe := hash.home(key), uint32(1); ; i, probe = (i+1)&mask, probe+1 {
+		slot := &hash.slots[i]
+		// Keys are ordered by probe length, so one closer to home ends it.
+		if slot.probe < probe {
+			return -1
+		}
+		if slot.probe == probe && hash.hasher.Equal(slot.key, key) {
+			return i
+		}
+	}
+}
+
+// Put stores a value under a key, replacing any value already there.
+func (hash *HashMap[K, V]) Put(key K, value V) error {
+	if hash == nil {
+		return nilError("hash map")
+	}
+	start := hash.metrics.begin()
+	hash.mux.Lock()
+	defer hash.mux.Unlock()
+	defer hash.metrics.end("Put", start, hash.metrics.acquired())
+	defer hash.debugCheck()
+	if i := hash.find(key); i >= 0 {
+		hash.slots[i].value = value
+		return nil
+	}
+	// Grow at a load factor of 7/8.
+	if 8*(hash.length+1) > 7*len(hash.slots) {
+		hash.resize(2 * len(hash.slots))
+	}
+	hash.insert(hashSlot[K, V]{key: key, value: value})
+	hash.length++
+	return nil
+}
+
+// insert places an entry whose key is not in the map, lock must be held.
+func (hash *HashMap[K, V]) insert(entry hashSlot[K, V]) {
+	mask := len(hash.slots) - 1
+	entry.probe = 1
+	for i := hash.home(entry.key); ; i = (i + 1) & mask {
+		slot := &hash.slots[i]
+		if slot.probe == 0 {
+			*slot = entry
+			return
+		}
+		// Take the slot from a key closer to home, then place that key.
+		if slot.probe < entry.probe {
+			*slot, entry = entry, *slot
+		}
+		entry.probe++
+	}
+}
+
+// resize moves the entries to a number of slots, a power of 2, lock must
+// be held.
+func (hash *HashMap[K, V]) resize(capacity int) {
+	slots := hash.slots
+	hash.slots = make([]hashSlot[K, V], capacity)
+	for _, slot := range slots {
+		if slot.probe != 0 {
+			hash.insert(slot)
+		}
+	}
+}
+
+// Get gets the value stored under a key.
+func (hash *HashMap[K, V]) Get(key K) (V, bool) {
+	var unset V
+	if hash == nil {
+		return unset, false
+	}
+	hash.mux.RLock()
+	defer hash.mux.RUnlock()
+	if i := hash.find(key); i >= 0 {
+		return hash.slots[i].value, true
+	}
+	return unset, false
+}
+
+// Contains reports whether a key is in the map.
+func (hash *HashMap[K, V]) Contains(key K) bool {
+	_, ok := hash.Get(key)
+	return ok
+}
+
+// Delete removes a key, reporting whether there was one.
+func (hash *HashMap[K, V]) Delete(key K) bool {
+	if hash == nil {
+		return false
+	}
+	start := hash.metrics.begin()
+	hash.mux.Lock()
+	defer hash.mux.Unlock()
+	defer hash.metrics.end("Delete", start, hash.metrics.acquired())
+	defer hash.debugCheck()
+	i := hash.find(key)
+	if i < 0 {
+		return false
+	}
+	// Shift the following keys back a slot until one is at home.
+	mask := len(hash.slots) - 1
+	for next := (i + 1) & mask; hash.slots[next].probe > 1; i, next = next, (next+1)&mask {
+		hash.slots[i] = hash.slots[next]
+		hash.slots[i].probe--
+	}
+	hash.slots[i] = hashSlot[K, V]{}
+	hash.length--
+	// Shrink at a load factor of 1/4.
+	if len(hash.slots) > hashMapMinCapacity && 4*hash.length < len(hash.slots) {
+		hash.resize(len(hash.slots) / 2)
+	}
+	return true
+}
+
+// entries copies the occupied slots, in slot order.
+func (hash *HashMap[K, V]) entries() []hashSlot[K, V] {
+	hash.mux.RLock()
+	defer hash.mux.RUnlock()
+	entries := make([]hashSlot[K, V], 0, hash.length)
+	for _, slot := range hash.slots {
+		if slot.probe != 0 {
+			entries = append(entries, slot)
+		}
+	}
+	return entries
+}
+
+// All gets a sequence of the keys and values as of the call, in no
+// particular order.
+func (hash *HashMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if hash == nil {
+			return
+		}
+		for _, entry := range hash.entries() {
+			if !yield(entry.key, entry.value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys copies the keys into a slice, in no particular order.
+func (hash *HashMap[K, V]) Keys() []K {
+	var keys []K
+	for key := range hash.All() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// String converts HashMap data into a string of key:value pairs, in no
+// particular order.
+func (hash *HashMap[K, V]) String() string {
+	if hash == nil {
+		return ""
+	}
+	entries := hash.entries()
+	s := fmt.Sprintf("Length: %d, Data:", len(entries))
+	for _, entry := range entries {
+		s += fmt.Sprintf(" %v:%v", entry.key, entry.value)
+	}
+	return s
+}
+
+// CheckInvariants verifies that each slot records its distance from the
+// slot of its key's hash, that no probe grows by more than 1 from one slot
+// to the next, and that the length is the number of keys.
+func (hash *HashMap[K, V]) CheckInvariants() error {
+	if hash == nil {
+		return nil
+	}
+	hash.mux.RLock()
+	defer hash.mux.RUnlock()
+	return hash.checkInvariants()
+}
+
+// checkInvariants verifies the hash map, lock must be held.
+func (hash *HashMap[K, V]) checkInvariants() error {
+	mask := len(hash.slots) - 1
+	count := 0
+	for i, slot := range hash.slots {
+		if slot.probe == 0 {
+			continue
+		}
+		count++
+		if home := hash.home(slot.key); (home+int(slot.probe)-1)&mask != i {
+			return fmt.Errorf("hash map: key %v at slot %d has probe %d from slot %d", slot.key, i, slot.probe, home)
+		}
+		if prev := hash.slots[(i-1)&mask]; slot.probe > prev.probe+1 {
+			return fmt.Errorf("hash map: probe %d at slot %d follows probe %d", slot.probe, i, prev.probe)
+		}
+	}
+	if count != hash.length {
+		return fmt.Errorf("hash map: length %d, counted %d keys", hash.length, count)
+	}
+	return nil
+}
+
+// debugCheck panics if the hash map invariants are violated in debug builds,
+// lock must be held.
+func (hash *HashMap[K, V]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := hash.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
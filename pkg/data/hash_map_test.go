@@ -0,0 +1,136 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	"fun/pkg/constraints"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func Test_HashMap(t *testing.T) {
+	hash := NewHashMap[string, int]()
+	if _, ok := hash.Get("a"); ok {
+		t.Error("expected no value in an empty map")
+	}
+	hash.Put("a", 1)
+	hash.Put("b", 2)
+	hash.Put("a", 3)
+	if value, ok := hash.Get("a"); !ok || value != 3 || hash.Length() != 2 {
+		t.Error("expected Put to replace a value", value, ok, hash.Length())
+	}
+	if !hash.Delete("b") || hash.Delete("b") || hash.Contains("b") {
+		t.Error("expected to delete b once")
+	}
+	if hash.String() != "Length: 1, Data: a:3" {
+		t.Error("unexpected map", hash.String())
+	}
+
+	var unset *HashMap[string, int]
+	if err := unset.Put("a", 1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
+
+func Test_HashMapResize(t *testing.T) {
+	hash := NewHashMap[int, int]()
+	for i := 0; i < 1000; i++ {
+		hash.Put(i, -i)
+	}
+	if err := hash.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+	if hash.Length() != 1000 || hash.Capacity() < 1000 {
+		t.Error("unexpected size", hash.Length(), hash.Capacity())
+	}
+	for i := 0; i < 1000; i += 2 {
+		hash.Delete(i)
+	}
+	for i := 0; i < 1000; i++ {
+		if value, ok := hash.Get(i); ok != (i%2 == 1) || (ok && value != -i) {
+			t.Fatal("unexpected value", i, value, ok)
+		}
+	}
+	for i := 1; i < 1000; i += 2 {
+		hash.Delete(i)
+	}
+	if err := hash.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+	if hash.Length() != 0 || hash.Capacity() != 8 {
+		t.Error("expected an empty map to shrink", hash.Length(), hash.Capacity())
+	}
+}
+
+// hashMapMachine checks a HashMap against the built-in map, with enough
+// keys to grow and shrink it.
+var hashMapMachine = datatest.Machine[*HashMap[int, int], map[int]int]{
+	NewSystem: func() *HashMap[int, int] { return NewHashMap[int, int](WithLocking(false)) },
+	NewModel:  func() map[int]int { return map[int]int{} },
+	ArgRange:  256,
+	Commands: []datatest.Command[*HashMap[int, int], map[int]int]{
+		{Name: "Put", Run: func(hash *HashMap[int, int], model map[int]int, arg int) error {
+			model[arg%64] = arg
+			return hash.Put(arg%64, arg)
+		}},
+		{Name: "Delete", Run: func(hash *HashMap[int, int], model map[int]int, arg int) error {
+			_, found := model[arg%64]
+			delete(model, arg%64)
+			if ok := hash.Delete(arg % 64); ok != found {
+				return fmt.Errorf("Delete returned %t, expected %t", ok, found)
+			}
+			return nil
+		}},
+		{Name: "Get", Run: func(hash *HashMap[int, int], model map[int]int, arg int) error {
+			expected, found := model[arg%64]
+			if value, ok := hash.Get(arg % 64); ok != found || value != expected {
+				return fmt.Errorf("Get returned %d %t, expected %d %t", value, ok, expected, found)
+			}
+			return nil
+		}},
+	},
+	Check: func(hash *HashMap[int, int], model map[int]int) error {
+		if all := maps.Collect(hash.All()); !maps.Equal(all, model) {
+			return fmt.Errorf("expected %v, got %v", model, all)
+		}
+		if keys := hash.Keys(); !slices.Equal(slices.Sorted(slices.Values(keys)), slices.Sorted(maps.Keys(model))) {
+			return fmt.Errorf("unexpected keys %v", keys)
+		}
+		return nil
+	},
+}
+
+func Test_HashMapModel(t *testing.T) {
+	hashMapMachine.Test(t, datatest.Config{Runs: 200, Length: 200})
+}
+
+func Test_HashMapHasher(t *testing.T) {
+	hash := NewHashMap[string, int](WithHasher(constraints.FoldHasher()))
+	hash.Put("Go", 1)
+	hash.Put("GO", 2)
+	if hash.Length() != 1 {
+		t.Error("expected keys differing in case to be the same, got length", hash.Length())
+	}
+	if value, ok := hash.Get("go"); !ok || value != 2 {
+		t.Error("expected 2, got", value, ok)
+	}
+	for i := 0; i < 100; i++ {
+		hash.Put(fmt.Sprint("Key", i), i)
+	}
+	if value, ok := hash.Get("KEY42"); !ok || value != 42 {
+		t.Error("expected 42 after growing, got", value, ok)
+	}
+	if !hash.Delete("gO") || hash.Contains("Go") {
+		t.Error("expected Delete to ignore case")
+	}
+
+	// A hasher of another key type is ignored.
+	other := NewHashMap[int, int](WithHasher(constraints.FoldHasher()))
+	other.Put(1, 1)
+	if !other.Contains(1) {
+		t.Error("expected the default hasher")
+	}
+}
@@ -0,0 +1,203 @@
+package data
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Operation is a reversible change to a model of type T.
+type Operation[T any] struct {
+	Name string              // Description of the change.
+	Do   func(model T) error // Apply the change.
+	Undo func(model T) error // Reverse the change after Do succeeded.
+}
+
+// History applies operations to a model and keeps them for undo and redo,
+// as an editor would. Operations done between Begin and Commit are undone
+// and redone together.
+type History[T any] struct {
+	model T                // Model the operations change.
+	depth int              // Maximum undoable entries, 0 for unlimited.
+	undo  [][]Operation[T] // Undoable entries, oldest first.
+	redo  [][]Operation[T] // Undone entries, most recently undone last.
+	group []Operation[T]   // Operations of the open transaction.
+	open  bool             // Whether a transaction is open.
+	mux   *sync.Mutex      // Lock the history.
+}
+
+// Create a new history of a model, keeping at most depth undoable entries,
+// or all of them if depth is 0.
+func NewHistory[T any](model T, depth int) *History[T] {
+	return &History[T]{model: model, depth: depth, mux: &sync.Mutex{}}
+}
+
+// Model gets the model the operations change.
+func (history *History[T]) Model() T {
+	return history.model
+}
+
+// Do applies an operation and records it, discarding anything undone. A
+// failed operation is not recorded.
+func (history *History[T]) Do(op Operation[T]) error {
+	if history == nil {
+		return nilError("history")
+	}
+	if op.Do == nil || op.Undo == nil {
+		return fmt.Errorf("%w: operation %s must have Do and Undo", ErrInvalid, op.Name)
+	}
+	history.mux.Lock()
+	defer history.mux.Unlock()
+	if err := op.Do(history.model); err != nil {
+		return err
+	}
+	history.redo = nil
+	if history.open {
+		history.group = append(history.group, op)
+		return nil
+	}
+	history.push([]Operation[T]{op})
+	return nil
+}
+
+// Undo reverses the most recent entry. If an operation fails to undo, the
+// rest of the entry is redone so the model is unchanged.
+func (history *History[T]) Undo() error {
+	if history == nil {
+		return nilError("history")
+	}
+	history.mux.Lock()
+	defer history.mux.Unlock()
+	if history.open {
+		return fmt.Errorf("%w: transaction is open", ErrState)
+	}
+	if len(history.undo) == 0 {
+		return ErrEmpty
+	}
+	entry := history.undo[len(history.undo)-1]
+	if err := undoAll(history.model, entry); err != nil {
+		return err
+	}
+	history.undo = history.undo[:len(history.undo)-1]
+	history.redo = append(history.redo, entry)
+	return nil
+}
+
+// Redo applies the most recently undone entry again. If an operation fails,
+// the rest of the entry is undone so the model is unchanged.
+func (history *History[T]) Redo() error {
+	if history == nil {
+		return nilError("history")
+	}
+	history.mux.Lock()
+	defer history.mux.Unlock()
+	if history.open {
+		return fmt.Errorf("%w: transaction is open", ErrState)
+	}
+	if len(history.redo) == 0 {
+		return ErrEmpty
+	}
+	entry := history.redo[len(history.redo)-1]
+	for i, op := range entry {
+		if err := op.Do(history.model); err != nil {
+			undoAll(history.model, entry[:i])
+			return err
+		}
+	}
+	history.redo = history.redo[:len(history.redo)-1]
+	history.push(entry)
+	return nil
+}
+
+// Begin opens a transaction, grouping the following operations.
+func (history *History[T]) Begin() error {
+	if history == nil {
+		return nilError("history")
+	}
+	history.mux.Lock()
+	defer history.mux.Unlock()
+	if history.open {
+		return fmt.Errorf("%w: transaction is already open", ErrState)
+	}
+	history.open = true
+	return nil
+}
+
+// Commit closes the transaction, recording its operations as one entry.
+func (history *History[T]) Commit() error {
+	if history == nil {
+		return nilError("history")
+	}
+	history.mux.Lock()
+	defer history.mux.Unlock()
+	if !history.open {
+		return fmt.Errorf("%w: no transaction is open", ErrState)
+	}
+	if len(history.group) > 0 {
+		history.push(history.group)
+	}
+	history.group, history.open = nil, false
+	return nil
+}
+
+// Rollback closes the transaction, undoing its operations.
+func (history *History[T]) Rollback() error {
+	if history == nil {
+		return nilError("history")
+	}
+	history.mux.Lock()
+	defer history.mux.Unlock()
+	if !history.open {
+		return fmt.Errorf("%w: no transaction is open", ErrState)
+	}
+	group := history.group
+	history.group, history.open = nil, false
+	for i := len(group) - 1; i >= 0; i-- {
+		if err := group[i].Undo(history.model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CanUndo reports whether there is an entry to undo.
+func (history *History[T]) CanUndo() bool {
+	if history == nil {
+		return false
+	}
+	history.mux.Lock()
+	defer history.mux.Unlock()
+	return len(history.undo) > 0
+}
+
+// CanRedo reports whether there is an entry to redo.
+func (history *History[T]) CanRedo() bool {
+	if history == nil {
+		return false
+	}
+	history.mux.Lock()
+	defer history.mux.Unlock()
+	return len(history.redo) > 0
+}
+
+// push records an undoable entry, dropping the oldest beyond the depth, lock
+// must be held.
+func (history *History[T]) push(entry []Operation[T]) {
+	history.undo = append(history.undo, entry)
+	if history.depth > 0 && len(history.undo) > history.depth {
+		history.undo = append([][]Operation[T](nil), history.undo[len(history.undo)-history.depth:]...)
+	}
+}
+
+// undoAll undoes the operations of an entry in reverse order. If one fails,
+// the operations already undone are done again.
+func undoAll[T any](model T, entry []Operation[T]) error {
+	for i := len(entry) - 1; i >= 0; i-- {
+		if err := entry[i].Undo(model); err != nil {
+			for _, op := range entry[i+1:] {
+				op.Do(model)
+			}
+			return err
+		}
+	}
+	return nil
+}
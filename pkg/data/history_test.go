@@ -0,0 +1,117 @@
+package data_test
+
+import (
+	"errors"
+	. "fun/pkg/data"
+	"testing"
+)
+
+// appendOp appends a value and undoes by deleting the tail.
+func appendOp(value Data) Operation[*List[Data]] {
+	return Operation[*List[Data]]{
+		Name: "append",
+		Do:   func(list *List[Data]) error { return list.Append(value) },
+		Undo: func(list *List[Data]) error {
+			if _, ok := list.DeleteTail(); !ok {
+				return ErrEmpty
+			}
+			return nil
+		},
+	}
+}
+
+func Test_HistoryUndoRedo(t *testing.T) {
+	history := NewHistory(NewList[Data](), 0)
+	history.Do(appendOp(1))
+	history.Do(appendOp(2))
+	if err := history.Undo(); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if s := history.Model().String(); s != "Length: 1, Data: 1" {
+		t.Error("unexpected model after undo", s)
+	}
+	if err := history.Redo(); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if s := history.Model().String(); s != "Length: 2, Data: 1 2" {
+		t.Error("unexpected model after redo", s)
+	}
+	if err := history.Redo(); !errors.Is(err, ErrEmpty) {
+		t.Error("expected nothing to redo, got", err)
+	}
+
+	history.Undo()
+	history.Do(appendOp(3))
+	if history.CanRedo() {
+		t.Error("expected Do to discard undone entries")
+	}
+	history.Undo()
+	history.Undo()
+	if err := history.Undo(); !errors.Is(err, ErrEmpty) {
+		t.Error("expected nothing to undo, got", err)
+	}
+	if history.Model().Length() != 0 {
+		t.Error("expected an empty model")
+	}
+}
+
+func Test_HistoryTransactions(t *testing.T) {
+	history := NewHistory(NewList[Data](), 0)
+	history.Begin()
+	history.Do(appendOp(1))
+	history.Do(appendOp(2))
+	if err := history.Undo(); err == nil {
+		t.Error("expected an error undoing inside a transaction")
+	}
+	history.Commit()
+	history.Do(appendOp(3))
+
+	history.Undo()
+	history.Undo()
+	if history.Model().Length() != 0 || history.CanUndo() {
+		t.Error("expected the transaction to undo together", history.Model())
+	}
+	history.Redo()
+	if s := history.Model().String(); s != "Length: 2, Data: 1 2" {
+		t.Error("expected the transaction to redo together", s)
+	}
+
+	history.Begin()
+	history.Do(appendOp(4))
+	if err := history.Rollback(); err != nil {
+		t.Error("unexpected error", err)
+	}
+	if history.Model().Length() != 2 {
+		t.Error("expected rollback to undo the transaction", history.Model())
+	}
+	if err := history.Commit(); err == nil {
+		t.Error("expected an error committing with no transaction")
+	}
+}
+
+func Test_HistoryDepth(t *testing.T) {
+	history := NewHistory(NewList[Data](), 2)
+	for i := 1; i <= 4; i++ {
+		history.Do(appendOp(Data(i)))
+	}
+	for history.CanUndo() {
+		history.Undo()
+	}
+	if s := history.Model().String(); s != "Length: 2, Data: 1 2" {
+		t.Error("expected only the last 2 entries to undo", s)
+	}
+
+	failing := Operation[*List[Data]]{
+		Do:   func(*List[Data]) error { return errors.New("failed") },
+		Undo: func(*List[Data]) error { return nil },
+	}
+	if err := history.Do(failing); err == nil {
+		t.Error("expected the operation error")
+	}
+	if !history.CanRedo() {
+		t.Error("expected a failed operation to keep undone entries")
+	}
+	if err := history.Do(Operation[*List[Data]]{Name: "partial"}); err == nil {
+		t.Error("expected an error for an operation without Do")
+	}
+}
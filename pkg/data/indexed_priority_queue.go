@@ -0,0 +1,291 @@
+package data
+
+import (
+	"fmt"
+	"iter"
+)
+
+// indexedEntry is an element of an IndexedPriorityQueue.
+type indexedEntry[K comparable, P any] struct {
+	id       K // Key of the element.
+	priority P // Priority ordering the element.
+}
+
+// IndexedPriorityQueue is a binary heap of IDs ordered by priority, with an
+// index from each ID to its place in the heap, so the priority of an element
+// can be updated or the element removed by ID. Push, Pop, Update, and Remove
+// take O(log n) time, and Peek and Contains constant time.
+type IndexedPriorityQueue[K comparable, P any] struct {
+	entries []indexedEntry[K, P] // Heap, each priority no greater than its children.
+	index   map[K]int            // Heap index of each ID.
+	less    func(a, b P) bool    // Order of the priorities.
+	metrics *Metrics             // Instrumentation, nil when disabled.
+	mux     locker               // Lock read and write operations.
+}
+
+// Create a new indexed priority queue ordered by less, configured by
+// WithLocking and WithMetrics.
+func NewIndexedPriorityQueue[K comparable, P any](less func(a, b P) bool, opts ...Option) *IndexedPriorityQueue[K, P] {
+	settings := newOptions(opts)
+	return &IndexedPriorityQueue[K, P]{
+		index:   map[K]int{},
+		less:    less,
+		metrics: settings.metrics,
+		mux:     settings.newLocker(),
+	}
+}
+
+// Length reports the number of elements in the queue.
+func (queue *IndexedPriorityQueue[K, P]) Length() int {
+	if queue == nil {
+		return 0
+	}
+	queue.mux.RLock()
+	defer queue.mux.RUnlock()
+	return len(queue.entries)
+}
+
+// Push adds an ID with a priority, or updates the priority of an ID already
+// in the queue.
+func (queue *IndexedPriorityQueue[K, P]) Push(id K, priority P) error {
+	if queue == nil {
+		return nilError("indexed priority queue")
+	}
+	start := queue.metrics.begin()
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	defer queue.metrics.end("Push", start, queue.metrics.acquired())
+	defer queue.debugCheck()
+	if i, ok := queue.index[id]; ok {
+		queue.update(i, priority)
+		return nil
+	}
+	queue.entries = append(queue.entries, indexedEntry[K, P]{id, priority})
+	queue.index[id] = len(queue.entries) - 1
+	queue.up(len(queue.entries) - 1)
+	return nil
+}
+
+// Update changes the priority of an ID, returning ErrNotFound if it is not
+// in the queue.
+func (queue *IndexedPriorityQueue[K, P]) Update(id K, priority P) error {
+	if queue == nil {
+		return nilError("indexed priority queue")
+	}
+	start := queue.metrics.begin()
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	defer queue.metrics.end("Update", start, queue.metrics.acquired())
+	defer queue.debugCheck()
+	i, ok := queue.index[id]
+	if !ok {
+		return ErrNotFound
+	}
+	queue.update(i, priority)
+	return nil
+}
+
+// Remove deletes an ID, returning its priority.
+func (queue *IndexedPriorityQueue[K, P]) Remove(id K) (P, bool) {
+	var unset P
+	if queue == nil {
+		return unset, false
+	}
+	start := queue.metrics.begin()
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	defer queue.metrics.end("Remove", start, queue.metrics.acquired())
+	defer queue.debugCheck()
+	i, ok := queue.index[id]
+	if !ok {
+		return unset, false
+	}
+	return queue.remove(i).priority, true
+}
+
+// Pop removes the ID with the least priority.
+func (queue *IndexedPriorityQueue[K, P]) Pop() (K, P, bool) {
+	var id K
+	var priority P
+	if queue == nil {
+		return id, priority, false
+	}
+	start := queue.metrics.begin()
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	defer queue.metrics.end("Pop", start, queue.metrics.acquired())
+	defer queue.debugCheck()
+	if len(queue.entries) == 0 {
+		return id, priority, false
+	}
+	entry := queue.remove(0)
+	return entry.id, entry.priority, true
+}
+
+// Peek gets the ID with the least priority without removing it.
+func (queue *IndexedPriorityQueue[K, P]) Peek() (K, P, bool) {
+	var id K
+	var priority P
+	if queue == nil {
+		return id, priority, false
+	}
+	queue.mux.RLock()
+	defer queue.mux.RUnlock()
+	if len(queue.entries) == 0 {
+		return id, priority, false
+	}
+	return queue.entries[0].id, queue.entries[0].priority, true
+}
+
+// Priority gets the priority of an ID.
+func (queue *IndexedPriorityQueue[K, P]) Priority(id K) (P, bool) {
+	var unset P
+	if queue == nil {
+		return unset, false
+	}
+	queue.mux.RLock()
+	defer queue.mux.RUnlock()
+	i, ok := queue.index[id]
+	if !ok {
+		return unset, false
+	}
+	return queue.entries[i].priority, true
+}
+
+// Contains reports whether an ID is in the queue.
+func (queue *IndexedPriorityQueue[K, P]) Contains(id K) bool {
+	_, ok := queue.Priority(id)
+	return ok
+}
+
+// update changes the priority of the entry at i and restores the order,
+// lock must be held.
+func (queue *IndexedPriorityQueue[K, P]) update(i int, priority P) {
+	queue.entries[i].priority = priority
+	queue.up(i)
+	queue.down(queue.index[queue.entries[i].id])
+}
+
+// remove deletes the entry at i, replacing it with the last entry, lock must
+// be held.
+func (queue *IndexedPriorityQueue[K, P]) remove(i int) indexedEntry[K, P] {
+	entry := queue.entries[i]
+	last := len(queue.entries) - 1
+	queue.swap(i, last)
+	queue.entries[last] = indexedEntry[K, P]{}
+	queue.entries = queue.entries[:last]
+	delete(queue.index, entry.id)
+	if i < last {
+		queue.up(i)
+		queue.down(queue.index[queue.entries[i].id])
+	}
+	return entry
+}
+
+// swap exchanges two entries and updates their indexes, lock must be held.
+func (queue *IndexedPriorityQueue[K, P]) swap(i, j int) {
+	queue.entries[i], queue.entries[j] = queue.entries[j], queue.entries[i]
+	queue.index[queue.entries[i].id] = i
+	queue.index[queue.entries[j].id] = j
+}
+
+// up moves the entry at i toward the root until its parent is not greater,
+// lock must be held.
+func (queue *IndexedPriorityQueue[K, P]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !queue.less(queue.entries[i].priority, queue.entries[parent].priority) {
+			return
+		}
+		queue.swap(i, parent)
+		i = parent
+	}
+}
+
+// down moves the entry at i toward the leaves until no child is less, lock
+// must be held.
+func (queue *IndexedPriorityQueue[K, P]) down(i int) {
+	for {
+		least := i
+		if left := 2*i + 1; left < len(queue.entries) && queue.less(queue.entries[left].priority, queue.entries[least].priority) {
+			least = left
+		}
+		if right := 2*i + 2; right < len(queue.entries) && queue.less(queue.entries[right].priority, queue.entries[least].priority) {
+			least = right
+		}
+		if least == i {
+			return
+		}
+		queue.swap(i, least)
+		i = least
+	}
+}
+
+// All gets a sequence of the IDs and priorities as of the call, in heap
+// order.
+func (queue *IndexedPriorityQueue[K, P]) All() iter.Seq2[K, P] {
+	return func(yield func(K, P) bool) {
+		if queue == nil {
+			return
+		}
+		queue.mux.RLock()
+		entries := append([]indexedEntry[K, P](nil), queue.entries...)
+		queue.mux.RUnlock()
+		for _, entry := range entries {
+			if !yield(entry.id, entry.priority) {
+				return
+			}
+		}
+	}
+}
+
+// String converts IndexedPriorityQueue data into a string of id:priority
+// pairs, in heap order.
+func (queue *IndexedPriorityQueue[K, P]) String() string {
+	if queue == nil {
+		return ""
+	}
+	s := fmt.Sprintf("Length: %d, Data:", queue.Length())
+	for id, priority := range queue.All() {
+		s += fmt.Sprintf(" %v:%v", id, priority)
+	}
+	return s
+}
+
+// CheckInvariants verifies that no priority is less than its parent's and
+// that the index matches the heap.
+func (queue *IndexedPriorityQueue[K, P]) CheckInvariants() error {
+	if queue == nil {
+		return nil
+	}
+	queue.mux.RLock()
+	defer queue.mux.RUnlock()
+	return queue.checkInvariants()
+}
+
+// checkInvariants verifies the queue, lock must be held.
+func (queue *IndexedPriorityQueue[K, P]) checkInvariants() error {
+	if len(queue.index) != len(queue.entries) {
+		return fmt.Errorf("indexed priority queue: %d indexed IDs, %d entries", len(queue.index), len(queue.entries))
+	}
+	for i, entry := range queue.entries {
+		if j, ok := queue.index[entry.id]; !ok || j != i {
+			return fmt.Errorf("indexed priority queue: ID %v at %d is indexed at %d", entry.id, i, j)
+		}
+		if parent := (i - 1) / 2; i > 0 && queue.less(entry.priority, queue.entries[parent].priority) {
+			return fmt.Errorf("indexed priority queue: entry %d is less than its parent %d", i, parent)
+		}
+	}
+	return nil
+}
+
+// debugCheck panics if the queue invariants are violated in debug builds,
+// lock must be held.
+func (queue *IndexedPriorityQueue[K, P]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := queue.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
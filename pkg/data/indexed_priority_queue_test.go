@@ -0,0 +1,114 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func Test_IndexedPriorityQueue(t *testing.T) {
+	queue := NewIndexedPriorityQueue[string](func(a, b int) bool { return a < b })
+	queue.Push("build", 3)
+	queue.Push("test", 5)
+	queue.Push("lint", 4)
+	queue.Push("deploy", 9)
+	if id, priority, ok := queue.Peek(); !ok || id != "build" || priority != 3 {
+		t.Error("unexpected least priority", id, priority, ok)
+	}
+	// Reprioritize pending work.
+	if err := queue.Update("deploy", 1); err != nil {
+		t.Error(err)
+	}
+	if err := queue.Update("release", 1); !errors.Is(err, ErrNotFound) {
+		t.Error("expected an error updating a missing ID, got", err)
+	}
+	if priority, ok := queue.Remove("lint"); !ok || priority != 4 || queue.Contains("lint") {
+		t.Error("unexpected removal", priority, ok)
+	}
+	if _, ok := queue.Remove("lint"); ok {
+		t.Error("expected no second removal")
+	}
+	queue.Push("test", 2)
+	if priority, _ := queue.Priority("test"); priority != 2 || queue.Length() != 3 {
+		t.Error("expected Push to update an existing ID", priority, queue.Length())
+	}
+	var order []string
+	for id, _, ok := queue.Pop(); ok; id, _, ok = queue.Pop() {
+		order = append(order, id)
+	}
+	if !slices.Equal(order, []string{"deploy", "test", "build"}) {
+		t.Error("unexpected order", order)
+	}
+
+	var unset *IndexedPriorityQueue[string, int]
+	if err := unset.Push("a", 1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
+
+// indexedQueueMachine checks an IndexedPriorityQueue against a map model of
+// priorities by ID.
+var indexedQueueMachine = datatest.Machine[*IndexedPriorityQueue[int, int], map[int]int]{
+	NewSystem: func() *IndexedPriorityQueue[int, int] {
+		return NewIndexedPriorityQueue[int](func(a, b int) bool { return a < b })
+	},
+	NewModel: func() map[int]int { return map[int]int{} },
+	ArgRange: 64,
+	Commands: []datatest.Command[*IndexedPriorityQueue[int, int], map[int]int]{
+		{Name: "Push", Run: func(queue *IndexedPriorityQueue[int, int], model map[int]int, arg int) error {
+			model[arg%16] = arg
+			return queue.Push(arg%16, arg)
+		}},
+		{Name: "Update", Run: func(queue *IndexedPriorityQueue[int, int], model map[int]int, arg int) error {
+			_, ok := model[arg%16]
+			if ok {
+				model[arg%16] = -arg
+			}
+			if err := queue.Update(arg%16, -arg); ok != (err == nil) {
+				return fmt.Errorf("Update returned %v", err)
+			}
+			return nil
+		}},
+		{Name: "Remove", Run: func(queue *IndexedPriorityQueue[int, int], model map[int]int, arg int) error {
+			expected, found := model[arg%16]
+			delete(model, arg%16)
+			if priority, ok := queue.Remove(arg % 16); ok != found || priority != expected {
+				return fmt.Errorf("Remove returned %d %t, expected %d %t", priority, ok, expected, found)
+			}
+			return nil
+		}},
+		{Name: "Pop", Run: func(queue *IndexedPriorityQueue[int, int], model map[int]int, arg int) error {
+			id, priority, ok := queue.Pop()
+			if ok != (len(model) > 0) {
+				return fmt.Errorf("Pop returned %t", ok)
+			}
+			if !ok {
+				return nil
+			}
+			if least := slices.Min(slices.Collect(maps.Values(model))); priority != least || model[id] != least {
+				return fmt.Errorf("Pop returned %d:%d, expected priority %d", id, priority, least)
+			}
+			delete(model, id)
+			return nil
+		}},
+	},
+	Check: func(queue *IndexedPriorityQueue[int, int], model map[int]int) error {
+		if queue.Length() != len(model) {
+			return fmt.Errorf("expected length %d, got %d", len(model), queue.Length())
+		}
+		for id, expected := range model {
+			if priority, ok := queue.Priority(id); !ok || priority != expected {
+				return fmt.Errorf("ID %d has priority %d %t, expected %d", id, priority, ok, expected)
+			}
+		}
+		return nil
+	},
+}
+
+func Test_IndexedPriorityQueueModel(t *testing.T) {
+	indexedQueueMachine.Test(t, datatest.Config{Runs: 200, Length: 100})
+}
@@ -0,0 +1,122 @@
+package data
+
+import "fmt"
+
+// InvariantChecker is a structure that can verify its internal consistency.
+//
+// When built with -tags datadebug, the containers in this package check their
+// invariants after every mutation and panic with the violation. SortedList,
+// BoundedList, and SortedMap check the structure they wrap.
+type InvariantChecker interface {
+	CheckInvariants() error
+}
+
+// CheckInvariants verifies the list length matches the chain of nodes, that
+// the head and tail are consistent, and that the nodes belong to the list.
+func (list *List[T]) CheckInvariants() error {
+	if list == nil {
+		return nil
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	return list.checkInvariants()
+}
+
+// checkInvariants verifies the list, lock must be held.
+func (list *List[T]) checkInvariants() error {
+	if list.length < 0 {
+		return fmt.Errorf("list: length %d is negative", list.length)
+	}
+	if (list.head == nil) != (list.tail == nil) {
+		return fmt.Errorf("list: head %p and tail %p must both be nil or both be set", list.head, list.tail)
+	}
+	count := 0
+	var last *ListNode[T]
+	for node := list.head; node != nil; node = node.next {
+		count++
+		if count > list.length {
+			return fmt.Errorf("list: chain has more than length %d nodes (cycle or stale length)", list.length)
+		}
+		if !list.shared && node.prev != last {
+			return fmt.Errorf("list: node %p links back to %p, not %p", node, node.prev, last)
+		}
+		if !list.shared && !list.owns(node) {
+			return fmt.Errorf("list: node %p belongs to another list", node)
+		}
+		last = node
+	}
+	if count != list.length {
+		return fmt.Errorf("list: length is %d but chain has %d nodes", list.length, count)
+	}
+	if last != list.tail {
+		return fmt.Errorf("list: tail %p is not the last node %p", list.tail, last)
+	}
+	return nil
+}
+
+// debugCheck panics if the list invariants are violated in debug builds, lock
+// must be held.
+func (list *List[T]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := list.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
+
+// HasCycle reports whether following the links from the head of the list
+// loops forever, using Floyd's tortoise and hare.
+func (list *List[T]) HasCycle() bool {
+	if list == nil {
+		return false
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	return list.cycleStart() != nil
+}
+
+// BreakCycle cuts the link that closes a cycle, so the list ends at the last
+// node before the cycle repeats, and repairs the tail and length. It reports
+// whether there was a cycle.
+func (list *List[T]) BreakCycle() bool {
+	if list == nil {
+		return false
+	}
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.debugCheck()
+	start := list.cycleStart()
+	if start == nil {
+		return false
+	}
+	last := start
+	for last.next != start {
+		last = last.next
+	}
+	last.next = nil
+	list.tail = last
+	list.length = 0
+	for node := list.head; node != nil; node = node.next {
+		list.length++
+	}
+	if !list.shared {
+		list.relink()
+	}
+	return true
+}
+
+// cycleStart finds the first node of a cycle, or nil if the chain ends, lock
+// must be held.
+func (list *List[T]) cycleStart() *ListNode[T] {
+	slow, fast := list.head, list.head
+	for fast != nil && fast.next != nil {
+		slow, fast = slow.next, fast.next.next
+		if slow == fast {
+			for slow = list.head; slow != fast; slow, fast = slow.next, fast.next {
+			}
+			return slow
+		}
+	}
+	return nil
+}
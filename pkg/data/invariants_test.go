@@ -0,0 +1,142 @@
+package data
+
+import (
+	"fun/pkg/constraints"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// invariantData is the type of data stored when corrupting lists.
+type invariantData int
+
+// String converts invariantData to a string
+func (data invariantData) String() string {
+	return strconv.Itoa(int(data))
+}
+
+// corruptList builds a list of 3 values for the test to corrupt.
+func corruptList() *List[invariantData] {
+	list := NewList[invariantData]()
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+	return list
+}
+
+func Test_CheckInvariants(t *testing.T) {
+	if err := corruptList().CheckInvariants(); err != nil {
+		t.Error("unexpected violation", err)
+	}
+	if err := NewList[invariantData]().CheckInvariants(); err != nil {
+		t.Error("unexpected violation in empty list", err)
+	}
+
+	tests := []struct {
+		name     string
+		corrupt  func(list *List[invariantData])
+		expected string
+	}{
+		{"negative length", func(list *List[invariantData]) { list.length = -1 }, "is negative"},
+		{"stale length", func(list *List[invariantData]) { list.length = 4 }, "length is 4 but chain has 3 nodes"},
+		{"short length", func(list *List[invariantData]) { list.length = 2 }, "more than length 2 nodes"},
+		{"nil tail", func(list *List[invariantData]) { list.tail = nil }, "both be nil"},
+		{"wrong tail", func(list *List[invariantData]) { list.tail = list.head }, "is not the last node"},
+		{"cycle", func(list *List[invariantData]) { list.tail.next = list.head }, "cycle"},
+	}
+	for _, test := range tests {
+		list := corruptList()
+		test.corrupt(list)
+		err := list.CheckInvariants()
+		if err == nil {
+			t.Error(test.name, "expected violation")
+		} else if !strings.Contains(err.Error(), test.expected) {
+			t.Error(test.name, "expected", test.expected, "got", err)
+		}
+	}
+}
+
+func Test_DebugCheck(t *testing.T) {
+	list := corruptList()
+	list.length = 7
+	defer func() {
+		recovered := recover()
+		if debugInvariants && recovered == nil {
+			t.Error("expected debug mode to panic on mutation of a corrupt list")
+		}
+		if !debugInvariants && recovered != nil {
+			t.Error("unexpected panic outside debug mode", recovered)
+		}
+	}()
+	list.Append(4)
+}
+
+func Test_DebugCheckContainers(t *testing.T) {
+	tree := NewBST[int, string](constraints.OrderedComparer[int]())
+	tree.Insert(2, "")
+	tree.Insert(1, "")
+	tree.root.key = 0
+	queue := NewPriorityQueue(func(a, b int) bool { return a < b })
+	queue.Push(1)
+	queue.Push(2)
+	queue.values[0] = 3
+	mutations := map[string]func(){
+		"tree": func() { tree.Insert(3, "") },
+		"heap": func() { queue.Push(4) },
+	}
+	for name, mutate := range mutations {
+		func() {
+			defer func() {
+				recovered := recover()
+				if debugInvariants && recovered == nil {
+					t.Errorf("%s: expected debug mode to panic on mutation of a corrupt structure", name)
+				}
+				if !debugInvariants && recovered != nil {
+					t.Errorf("%s: unexpected panic outside debug mode %v", name, recovered)
+				}
+			}()
+			mutate()
+		}()
+	}
+}
+
+func Test_BreakCycle(t *testing.T) {
+	list := corruptList()
+	if list.HasCycle() || list.BreakCycle() {
+		t.Error("expected no cycle in a valid list")
+	}
+	list.tail.next = list.head.next
+	if !list.HasCycle() {
+		t.Error("expected a cycle")
+	}
+	if !list.BreakCycle() {
+		t.Error("expected to break the cycle")
+	}
+	if err := list.CheckInvariants(); err != nil {
+		t.Error("unexpected violation after repair", err)
+	}
+	if list.String() != "Length: 3, Data: 1 2 3" {
+		t.Error("unexpected repaired list", list.String())
+	}
+
+	list.tail.next = list.tail
+	list.BreakCycle()
+	if list.HasCycle() || list.Length() != 3 {
+		t.Error("expected a self loop to be broken", list.String())
+	}
+}
+
+func Test_ToDOTCorrupt(t *testing.T) {
+	list := corruptList()
+	list.tail.next = list.head.next
+	list.tail = &ListNode[invariantData]{value: 9}
+	var b strings.Builder
+	if err := list.ToDOT(&b); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	for _, expected := range []string{"n2 -> n1;", "tail -> unknown [style=dashed];", `unknown [label="?", shape=circle];`} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("expected %s in %q", expected, b.String())
+		}
+	}
+}
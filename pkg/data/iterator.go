@@ -0,0 +1,137 @@
+package data
+
+import (
+	"context"
+	"iter"
+)
+
+// Iterator steps through the values of a structure. Next advances to the
+// next value, reporting false when there are no more, and Value gets the
+// current value.
+type Iterator[T any] interface {
+	Next() bool
+	Value() T
+}
+
+// Iterable is a structure whose values can be iterated, implemented by the
+// containers in this package.
+type Iterable[T any] interface {
+	Iterator() Iterator[T]
+}
+
+// Collect copies the remaining values of an iterator into a slice.
+func Collect[T any](it Iterator[T]) []T {
+	var values []T
+	for it.Next() {
+		values = append(values, it.Value())
+	}
+	return values
+}
+
+// Seq adapts an iterator to a range-over-func sequence.
+func Seq[T any](it Iterator[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for it.Next() {
+			if !yield(it.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// seqIterator iterates a pulled sequence.
+type seqIterator[T any] struct {
+	next  func() (T, bool) // Pulls the next value.
+	stop  func()           // Stops the sequence.
+	value T                // Current value.
+}
+
+// FromSeq adapts a sequence to an iterator. Like iter.Pull, the returned
+// stop function must be called if the iterator is not read to the end.
+func FromSeq[T any](seq iter.Seq[T]) (Iterator[T], func()) {
+	next, stop := iter.Pull(seq)
+	return &seqIterator[T]{next: next, stop: stop}, stop
+}
+
+func (it *seqIterator[T]) Next() bool {
+	value, ok := it.next()
+	if !ok {
+		var unset T
+		it.value = unset
+		it.stop()
+		return false
+	}
+	it.value = value
+	return true
+}
+
+func (it *seqIterator[T]) Value() T {
+	return it.value
+}
+
+// sliceIterator iterates a slice.
+type sliceIterator[T any] struct {
+	values []T // Values to iterate.
+	index  int // Index of the current value, plus one.
+}
+
+// FromSlice creates an iterator over the values of a slice.
+func FromSlice[T any](values []T) Iterator[T] {
+	return &sliceIterator[T]{values: values}
+}
+
+func (it *sliceIterator[T]) Next() bool {
+	if it.index >= len(it.values) {
+		it.index = len(it.values) + 1
+		return false
+	}
+	it.index++
+	return true
+}
+
+func (it *sliceIterator[T]) Value() T {
+	var unset T
+	if it.index == 0 || it.index > len(it.values) {
+		return unset
+	}
+	return it.values[it.index-1]
+}
+
+// chanIterator iterates the values received from a channel.
+type chanIterator[T any] struct {
+	values <-chan T // Source of values.
+	value  T        // Current value.
+}
+
+// FromChan creates an iterator over the values received from a channel
+// until it is closed.
+func FromChan[T any](values <-chan T) Iterator[T] {
+	return &chanIterator[T]{values: values}
+}
+
+func (it *chanIterator[T]) Next() bool {
+	value, ok := <-it.values
+	it.value = value
+	return ok
+}
+
+func (it *chanIterator[T]) Value() T {
+	return it.value
+}
+
+// ToChan sends the remaining values of an iterator on a channel, which is
+// closed when the iterator is exhausted or ctx is done.
+func ToChan[T any](ctx context.Context, it Iterator[T]) <-chan T {
+	values := make(chan T)
+	go func() {
+		defer close(values)
+		for it.Next() {
+			select {
+			case values <- it.Value():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return values
+}
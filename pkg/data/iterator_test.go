@@ -0,0 +1,144 @@
+package data_test
+
+import (
+	"context"
+	. "fun/pkg/data"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// sum adds the values of any iterable structure.
+func sum[S Iterable[int]](structure S) int {
+	total := 0
+	for value := range Seq(structure.Iterator()) {
+		total += value
+	}
+	return total
+}
+
+func Test_Iterators(t *testing.T) {
+	values := FromSlice([]int{1, 2, 3})
+	if values.Value() != 0 {
+		t.Error("expected zero value before Next")
+	}
+	if collected := Collect(values); !reflect.DeepEqual(collected, []int{1, 2, 3}) {
+		t.Error("unexpected values", collected)
+	}
+	if values.Next() || values.Value() != 0 {
+		t.Error("expected exhausted iterator")
+	}
+
+	it, stop := FromSeq(Seq(FromSlice([]int{4, 5})))
+	defer stop()
+	if collected := Collect(it); !reflect.DeepEqual(collected, []int{4, 5}) {
+		t.Error("unexpected values", collected)
+	}
+
+	ch := ToChan(context.Background(), FromSlice([]int{6, 7}))
+	if collected := Collect(FromChan(ch)); !reflect.DeepEqual(collected, []int{6, 7}) {
+		t.Error("unexpected values", collected)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch = ToChan(ctx, FromSlice([]int{8, 9}))
+	<-ch
+	cancel()
+	for range ch {
+	}
+
+	for value := range Seq(FromSlice([]int{1, 2, 3})) {
+		if value == 2 {
+			break
+		}
+	}
+
+	queue := NewBlockingQueue[int](0)
+	queue.TryPut(1)
+	queue.TryPut(2)
+	if total := sum(queue); total != 3 || queue.Length() != 2 {
+		t.Error("expected queue iteration to leave elements, got", total, queue.Length())
+	}
+}
+
+func Test_ListIterator(t *testing.T) {
+	list := NewList[Data]()
+	list.AppendAll(1, 2, 3)
+	var values []Data
+	for value := range list.All() {
+		values = append(values, value)
+		if value == 1 {
+			list.Delete(2)
+			list.Append(4)
+		}
+	}
+	if !reflect.DeepEqual(values, []Data{1, 3, 4}) {
+		t.Error("expected iteration to see changes ahead of it, got", values)
+	}
+
+	view := list.View()
+	list.Append(5)
+	if collected := Collect(view.Iterator()); !reflect.DeepEqual(collected, []Data{1, 3, 4}) {
+		t.Error("unexpected view values", collected)
+	}
+	count := 0
+	for range view.All() {
+		count++
+	}
+	if count != 3 {
+		t.Error("expected 3 view values, got", count)
+	}
+
+	var nilList *List[Data]
+	if nilList.Iterator().Next() {
+		t.Error("expected nil list to have no values")
+	}
+}
+
+func Test_ListIteratorConcurrentSwap(t *testing.T) {
+	list := NewListOf[Data](1, 2, 3, 4)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			list.Swap(0, 1)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		count := 0
+		for range list.All() {
+			count++
+		}
+		if count != 4 {
+			t.Error("expected 4 values, got", count)
+		}
+	}
+	wg.Wait()
+}
+
+func Test_ListAllNodes(t *testing.T) {
+	list := NewList[Data]()
+	list.AppendAll(1, 2, 3)
+	var values []Data
+	for node := range list.AllNodes() {
+		value, _ := node.Value()
+		values = append(values, value)
+		if value == 2 {
+			list.Delete(2)
+		}
+	}
+	if !reflect.DeepEqual(values, []Data{1, 2, 3}) {
+		t.Error("expected deleting the current node to continue the walk, got", values)
+	}
+	for node := range list.AllNodes() {
+		if value, _ := node.Value(); value != 1 {
+			t.Error("expected to stop after the first node")
+		}
+		break
+	}
+	var nilList *List[Data]
+	for range nilList.AllNodes() {
+		t.Error("expected nil list to have no nodes")
+	}
+}
@@ -2,34 +2,105 @@
 package data
 
 import (
-	"errors"
 	"fmt"
 	"sync"
 )
 
-// ListData must be comparable, and can be converted to a string.
+// ListData must be comparable. Values are formatted with fmt.Sprint, so
+// types with a String method print with it.
 type ListData interface {
 	comparable
-	String() string
 }
 
-// ListNode is a singly-linked list data structure.
+// ListNode is an element of a List.
 type ListNode[T ListData] struct {
 	value T            // Value is storage for data in the list.
 	next  *ListNode[T] // Pointer to the next element in the list.
+	prev  *ListNode[T] // Pointer to the previous element, stale while the node is shared with a snapshot.
+	owner *listOwner   // Owner of the list holding the node, nil once it is deleted.
+}
+
+// listOwner identifies the list a node belongs to. Concat forwards the owner
+// of the nodes it moves to the owner of the list they join, so moving them
+// takes constant time, and a list takes a new owner to disown all of its old
+// nodes at once.
+type listOwner struct {
+	forward *listOwner // Owner the nodes were moved to, nil if still current.
+}
+
+// resolve follows the forwarded owners to the current one.
+func (owner *listOwner) resolve() *listOwner {
+	for owner != nil && owner.forward != nil {
+		owner = owner.forward
+	}
+	return owner
 }
 
 // List data structure.
 type List[T ListData] struct {
-	head   *ListNode[T]  // Head of the list.
-	tail   *ListNode[T]  // Tail of the list.
-	length int           // Number of elements stored in the list.
-	mux    *sync.RWMutex // Lock read and write operations.
+	head    *ListNode[T]            // Head of the list.
+	tail    *ListNode[T]            // Tail of the list.
+	length  int                     // Number of elements stored in the list.
+	shared  bool                    // Whether the nodes are shared with a snapshot.
+	nodes   *sync.Pool              // Recycled nodes, nil when pooling is disabled.
+	arena   *Arena[ListNode[T]]     // Allocator of nodes, nil to allocate each node.
+	metrics *Metrics                // Instrumentation, nil when disabled.
+	tracer  *traceHook              // Tracing of scans, nil when disabled.
+	hooks   *listHooks[T]           // Observers of mutations, nil when there are none.
+	owner   *listOwner              // Owner of the nodes, nil until the first is created.
+	cursors map[*Cursor[T]]struct{} // Open cursors, moved off nodes as they are deleted.
+	mux     locker                  // Lock read and write operations.
+}
+
+// Create a new list, configured by WithLocking, WithMetrics, WithTracer,
+// WithNodePool, WithPool, and WithArena.
+func NewList[T ListData](opts ...Option) *List[T] {
+	settings := newOptions(opts)
+	list := &List[T]{
+		metrics: settings.metrics,
+		tracer:  newTraceHook(settings.tracer, settings.threshold),
+		mux:     settings.newLocker(),
+	}
+	list.arena, _ = settings.arena.(*Arena[ListNode[T]])
+	if settings.pool != nil {
+		list.nodes = settings.pool
+	} else if settings.nodePool {
+		list.nodes = &sync.Pool{New: func() any { return list.allocNode() }}
+	}
+	return list
+}
+
+// Create a new list of values, in order, with the default options.
+func NewListOf[T ListData](values ...T) *List[T] {
+	list := NewList[T]()
+	for _, value := range values {
+		list.append(value)
+	}
+	return list
+}
+
+// SetMetrics records the operations of the list in metrics, or stops
+// recording if metrics is nil. It must be called before the list is shared
+// between goroutines.
+func (list *List[T]) SetMetrics(metrics *Metrics) {
+	list.metrics = metrics
+}
+
+// Stats copies the measurements of the list's metrics, empty when the list
+// has none. Metrics shared with other structures include their operations.
+func (list *List[T]) Stats() Stats {
+	if list == nil {
+		return Stats{Operations: map[string]OperationStats{}}
+	}
+	return list.metrics.Stats()
 }
 
-// Create a new list.
-func NewList[T ListData]() *List[T] {
-	return &List[T]{mux: &sync.RWMutex{}}
+// WithTracer reports scans of the list that meet threshold to tracer, or
+// stops tracing if tracer is nil, and returns the list. It must be called
+// before the list is shared between goroutines.
+func (list *List[T]) WithTracer(tracer Tracer, threshold TraceThreshold) *List[T] {
+	list.tracer = newTraceHook(tracer, threshold)
+	return list
 }
 
 // Length reports the number of elements in the list.
@@ -73,27 +144,48 @@ func (listNode *ListNode[T]) Next() *ListNode[T] {
 // Insert adds an element at the beginning of a list.
 func (list *List[T]) Insert(value T) error {
 	if list == nil {
-		return errors.New("list is nil")
+		return nilError("list")
 	}
+	start := list.metrics.begin()
 	list.mux.Lock()
 	defer list.mux.Unlock()
-	listNode := &ListNode[T]{value, list.head}
+	defer list.metrics.end("Insert", start, list.metrics.acquired())
+	defer list.debugCheck()
+	list.insert(value)
+	return nil
+}
+
+// insert adds an element at the beginning of a list, lock must be held.
+func (list *List[T]) insert(value T) {
+	listNode := list.newNode(value, nil, list.head)
 	if list.tail == nil {
 		list.tail = listNode
+	} else if !list.shared {
+		list.head.prev = listNode
 	}
 	list.head = listNode
 	list.length++
-	return nil
+	list.hooks.insert(value)
 }
 
 // Append adds an element at the end of a list.
 func (list *List[T]) Append(value T) error {
 	if list == nil {
-		return errors.New("list is nil")
+		return nilError("list")
 	}
+	start := list.metrics.begin()
 	list.mux.Lock()
 	defer list.mux.Unlock()
-	listNode := &ListNode[T]{value, nil}
+	defer list.metrics.end("Append", start, list.metrics.acquired())
+	defer list.debugCheck()
+	list.append(value)
+	return nil
+}
+
+// append adds an element at the end of a list, lock must be held.
+func (list *List[T]) append(value T) {
+	list.unshare()
+	listNode := list.newNode(value, list.tail, nil)
 	if list.tail == nil {
 		list.tail = listNode
 		list.head = listNode
@@ -102,9 +194,121 @@ func (list *List[T]) Append(value T) error {
 		list.tail = listNode
 	}
 	list.length++
+	list.hooks.insert(value)
+}
+
+// InsertAfter adds an element after a node of the list, returned by Find,
+// Head, Tail, or AllNodes, in constant time. If the list shares its nodes
+// with a snapshot, the node is located by a scan first.
+func (list *List[T]) InsertAfter(node *ListNode[T], value T) error {
+	if list == nil {
+		return nilError("list")
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("InsertAfter", start, list.metrics.acquired())
+	defer list.debugCheck()
+	node, err := list.own(node)
+	if err != nil {
+		return err
+	}
+	list.insertAfter(node, value)
+	return nil
+}
+
+// InsertBefore adds an element before a node of the list, returned by Find,
+// Head, Tail, or AllNodes, in constant time. If the list shares its nodes
+// with a snapshot, the node is located by a scan first.
+func (list *List[T]) InsertBefore(node *ListNode[T], value T) error {
+	if list == nil {
+		return nilError("list")
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("InsertBefore", start, list.metrics.acquired())
+	defer list.debugCheck()
+	node, err := list.own(node)
+	if err != nil {
+		return err
+	}
+	if node.prev == nil {
+		list.insert(value)
+	} else {
+		list.insertAfter(node.prev, value)
+	}
 	return nil
 }
 
+// own gets the node of the list a caller refers to, unsharing the nodes so
+// the node can be modified, write lock must be held. A shared node is
+// replaced by its copy at the same index.
+func (list *List[T]) own(node *ListNode[T]) (*ListNode[T], error) {
+	if node == nil {
+		return nil, fmt.Errorf("%w: nil node", ErrNotFound)
+	}
+	if !list.shared {
+		if !list.owns(node) {
+			return nil, fmt.Errorf("%w: node is not in the list", ErrNotFound)
+		}
+		node.owner = list.owner
+		return node, nil
+	}
+	i := list.indexOf(node)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: node is not in the list", ErrNotFound)
+	}
+	list.unshare()
+	return list.nodeAt(i), nil
+}
+
+// owns reports whether a node is in the list, lock must be held and the
+// nodes must not be shared.
+func (list *List[T]) owns(node *ListNode[T]) bool {
+	owner := node.owner.resolve()
+	return owner != nil && owner == list.owner
+}
+
+// disown gives the list a new owner, so the nodes it held before no longer
+// belong to it, lock must be held.
+func (list *List[T]) disown() {
+	list.owner = &listOwner{}
+}
+
+// claim gives the list a new owner and marks its nodes with it, so nodes
+// deleted without being detached no longer belong to it, lock must be held
+// and the nodes must not be shared.
+func (list *List[T]) claim() {
+	list.disown()
+	for node := list.head; node != nil; node = node.next {
+		node.owner = list.owner
+	}
+}
+
+// clear removes every element, disowning the nodes and reporting each value
+// to the delete hooks, lock must be held.
+func (list *List[T]) clear() {
+	list.hooks.deleteAll(list.head)
+	list.head, list.tail, list.length, list.shared = nil, nil, 0, false
+	list.disown()
+	list.resetCursors()
+}
+
+// insertAfter adds an element after a node, lock must be held and the
+// nodes must not be shared.
+func (list *List[T]) insertAfter(parent *ListNode[T], value T) {
+	if parent == list.tail {
+		list.append(value)
+		return
+	}
+	node := list.newNode(value, parent, parent.next)
+	parent.next.prev = node
+	parent.next = node
+	list.length++
+	list.hooks.insert(value)
+}
+
 // findParent finds a node by its value and the parent.
 func (list *List[T]) findParent(value T) (parent *ListNode[T], found *ListNode[T]) {
 	if list == nil {
@@ -125,18 +329,31 @@ func (list *List[T]) findParent(value T) (parent *ListNode[T], found *ListNode[T
 
 // Find a value in the list.
 func (list *List[T]) Find(value T) (listNode *ListNode[T]) {
+	start := list.metrics.begin()
 	list.mux.RLock()
 	defer func() {
 		list.mux.RUnlock()
 	}()
+	defer list.metrics.end("Find", start, list.metrics.acquired())
+	defer list.tracer.end("List.Find", list.tracer.begin(), list.length)
 	_, found := list.findParent(value)
 	return found
 }
 
 // Delete Data in the list.
 func (list *List[T]) Delete(value T) bool {
+	start := list.metrics.begin()
 	list.mux.Lock()
 	defer list.mux.Unlock()
+	defer list.metrics.end("Delete", start, list.metrics.acquired())
+	defer list.tracer.end("List.Delete", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	return list.delete(value)
+}
+
+// delete removes the first node holding value, lock must be held.
+func (list *List[T]) delete(value T) bool {
+	list.unshare()
 	parent, found := list.findParent(value)
 	if found == nil {
 		return false
@@ -144,6 +361,9 @@ func (list *List[T]) Delete(value T) bool {
 	if parent != nil {
 		parent.next = found.next
 	}
+	if found.next != nil {
+		found.next.prev = parent
+	}
 	if list.head == found {
 		list.head = found.next
 	}
@@ -151,43 +371,161 @@ func (list *List[T]) Delete(value T) bool {
 		list.tail = parent
 	}
 	list.length--
+	list.hooks.delete(found.value)
+	list.moveCursors(found, parent)
+	list.releaseNode(found)
 	return true
 }
 
+// DeleteAll removes every element holding value in one pass and reports how
+// many were removed.
+func (list *List[T]) DeleteAll(value T) int {
+	if list == nil {
+		return 0
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("DeleteAll", start, list.metrics.acquired())
+	defer list.tracer.end("List.DeleteAll", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	return list.deleteIf(func(v T) bool { return v == value })
+}
+
+// deleteIf removes every node whose value matches, lock must be held.
+func (list *List[T]) deleteIf(match func(T) bool) int {
+	list.unshare()
+	removed := 0
+	var parent *ListNode[T]
+	for node := list.head; node != nil; {
+		next := node.next
+		if !match(node.value) {
+			parent, node = node, next
+			continue
+		}
+		if parent == nil {
+			list.head = next
+		} else {
+			parent.next = next
+		}
+		if next != nil {
+			next.prev = parent
+		}
+		if list.tail == node {
+			list.tail = parent
+		}
+		removed++
+		list.hooks.delete(node.value)
+		list.moveCursors(node, parent)
+		list.releaseNode(node)
+		node = next
+	}
+	list.length -= removed
+	return removed
+}
+
+// Dedup removes elements equal to the element before them, so runs of a
+// value become one, and reports how many were removed.
+func (list *List[T]) Dedup() int {
+	if list == nil {
+		return 0
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("Dedup", start, list.metrics.acquired())
+	defer list.tracer.end("List.Dedup", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	if list.head == nil {
+		return 0
+	}
+	previous := list.head.value
+	first := true
+	return list.deleteIf(func(value T) bool {
+		if first {
+			first = false
+			return false
+		}
+		duplicate := value == previous
+		previous = value
+		return duplicate
+	})
+}
+
+// DedupAll removes every element equal to an earlier element, keeping first
+// occurrences in order, and reports how many were removed.
+func (list *List[T]) DedupAll() int {
+	if list == nil {
+		return 0
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("DedupAll", start, list.metrics.acquired())
+	defer list.tracer.end("List.DedupAll", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	seen := make(map[T]struct{}, list.length)
+	return list.deleteIf(func(value T) bool {
+		if _, ok := seen[value]; ok {
+			return true
+		}
+		seen[value] = struct{}{}
+		return false
+	})
+}
+
 // Delete the head node in the list.
 func (list *List[T]) DeleteHead() (T, bool) {
+	start := list.metrics.begin()
 	list.mux.Lock()
 	defer list.mux.Unlock()
+	defer list.metrics.end("DeleteHead", start, list.metrics.acquired())
+	defer list.debugCheck()
+	return list.deleteHead()
+}
+
+// deleteHead removes the head node, lock must be held.
+func (list *List[T]) deleteHead() (T, bool) {
 	var value T
 	if list.head == nil {
 		return value, false
 	}
-	value = list.head.value
-	list.head = list.head.next
+	removed := list.head
+	value = removed.value
+	list.head = removed.next
 	if list.head == nil {
 		list.tail = nil
+	} else if !list.shared {
+		list.head.prev = nil
 	}
 	list.length--
+	list.hooks.delete(value)
+	list.moveCursors(removed, nil)
+	list.releaseNode(removed)
 	return value, true
 }
 
 // Delete the tail node in the list.
 func (list *List[T]) DeleteTail() (T, bool) {
+	start := list.metrics.begin()
 	list.mux.Lock()
 	defer list.mux.Unlock()
+	defer list.metrics.end("DeleteTail", start, list.metrics.acquired())
+	defer list.debugCheck()
+	return list.deleteTail()
+}
+
+// deleteTail removes the tail node, lock must be held.
+func (list *List[T]) deleteTail() (T, bool) {
 	var value T
 	if list.tail == nil {
 		return value, false
 	} else {
 		value = list.tail.value
 	}
-	// find the parent of list.Tail
-	var parent *ListNode[T]
-	for node := list.head; node != nil; node = node.next {
-		if node.next == list.tail {
-			parent = node
-		}
-	}
+	list.unshare()
+	removed := list.tail
+	parent := removed.prev
 	if parent == nil {
 		list.head = nil
 		list.tail = nil
@@ -196,16 +534,163 @@ func (list *List[T]) DeleteTail() (T, bool) {
 		list.tail = parent
 	}
 	list.length--
+	list.hooks.delete(value)
+	list.moveCursors(removed, parent)
+	list.releaseNode(removed)
 	return value, true
 }
 
-// For each value in the list, execute a method.
+// Reverse flips the order of the list in place.
+func (list *List[T]) Reverse() {
+	if list == nil {
+		return
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("Reverse", start, list.metrics.acquired())
+	defer list.tracer.end("List.Reverse", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	list.reverse()
+}
+
+// reverse flips the links of the list, lock must be held.
+func (list *List[T]) reverse() {
+	list.unshare()
+	var previous *ListNode[T]
+	for node := list.head; node != nil; {
+		next := node.next
+		node.next, node.prev = previous, next
+		previous, node = node, next
+	}
+	list.head, list.tail = list.tail, list.head
+}
+
+// relink rebuilds the previous pointers from the next pointers, lock must be
+// held and the nodes must not be shared.
+func (list *List[T]) relink() {
+	var previous *ListNode[T]
+	for node := list.head; node != nil; node = node.next {
+		node.prev = previous
+		previous = node
+	}
+}
+
+// ReverseInGroups reverses each consecutive group of k elements in place. A
+// final group shorter than k keeps its order.
+func (list *List[T]) ReverseInGroups(k int) {
+	if list == nil || k < 2 {
+		return
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("ReverseInGroups", start, list.metrics.acquired())
+	defer list.tracer.end("List.ReverseInGroups", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	list.unshare()
+	var parent *ListNode[T] // Last node of the previous group.
+	for remaining := list.length; remaining >= k; remaining -= k {
+		first := list.head
+		if parent != nil {
+			first = parent.next
+		}
+		// Reverse the group, ending with previous as its new first node and
+		// node as the first node after it.
+		previous, node := (*ListNode[T])(nil), first
+		for i := 0; i < k; i++ {
+			next := node.next
+			node.next = previous
+			previous, node = node, next
+		}
+		first.next = node
+		if parent == nil {
+			list.head = previous
+		} else {
+			parent.next = previous
+		}
+		if node == nil {
+			list.tail = first
+		}
+		parent = first
+	}
+	list.relink()
+}
+
+// RotateLeft moves the first n elements to the end of the list. A negative n
+// rotates right.
+func (list *List[T]) RotateLeft(n int) {
+	if list == nil {
+		return
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("RotateLeft", start, list.metrics.acquired())
+	defer list.tracer.end("List.RotateLeft", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	list.rotateLeft(n)
+}
+
+// RotateRight moves the last n elements to the beginning of the list. A
+// negative n rotates left.
+func (list *List[T]) RotateRight(n int) {
+	if list == nil {
+		return
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("RotateRight", start, list.metrics.acquired())
+	defer list.tracer.end("List.RotateRight", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	list.rotateLeft(-n)
+}
+
+// rotateLeft relinks the list to start after its first n elements, lock must
+// be held.
+func (list *List[T]) rotateLeft(n int) {
+	if list.length == 0 {
+		return
+	}
+	n %= list.length
+	if n < 0 {
+		n += list.length
+	}
+	if n == 0 {
+		return
+	}
+	list.unshare()
+	newTail := list.nodeAt(n - 1)
+	list.tail.next, list.head.prev = list.head, list.tail
+	list.head = newTail.next
+	list.head.prev, newTail.next = nil, nil
+	list.tail = newTail
+}
+
+// For each value in the list, execute a method. The read lock is held while
+// f runs, so f must not modify the list.
 func (list *List[T]) ForEach(f func(T)) {
+	list.ForEachUntil(func(value T) bool {
+		f(value)
+		return true
+	})
+}
+
+// ForEachUntil executes a method for each value in the list, stopping once
+// it returns false. The read lock is held while f runs, so f must not modify
+// the list.
+func (list *List[T]) ForEachUntil(f func(T) bool) {
+	if list == nil {
+		return
+	}
 	list.mux.RLock()
 	defer list.mux.RUnlock()
-	currentNode := list.head
-	for currentNode != nil {
-		f(currentNode.value)
+	defer list.tracer.end("List.ForEach", list.tracer.begin(), list.length)
+	for currentNode := list.head; currentNode != nil; currentNode = currentNode.next {
+		if !f(currentNode.value) {
+			return
+		}
 	}
 }
 
@@ -216,6 +701,7 @@ func (list *List[T]) String() string {
 	}
 	list.mux.RLock()
 	defer list.mux.RUnlock()
+	defer list.tracer.end("List.String", list.tracer.begin(), list.length)
 	currentNode := list.head
 	values := make([]T, 0, list.length)
 	for currentNode != nil {
@@ -225,7 +711,18 @@ func (list *List[T]) String() string {
 
 	s := fmt.Sprintf("Length: %d, Data:", list.length)
 	for _, v := range values {
-		s += " " + v.String()
+		s += " " + fmt.Sprint(v)
 	}
 	return s
 }
+
+// Collect reports the length of the list.
+func (list *List[T]) Collect(emit func(Sample)) {
+	if list == nil {
+		return
+	}
+	list.mux.RLock()
+	length := list.length
+	list.mux.RUnlock()
+	emit(Sample{Name: "length", Help: "Number of elements in the list.", Value: float64(length)})
+}
@@ -0,0 +1,188 @@
+package data
+
+import "fmt"
+
+// ListTx gives access to a list while its write lock is held by Batch.
+// It must not be used after the Batch callback returns.
+type ListTx[T ListData] struct {
+	list *List[T] // List being mutated, nil once the batch is done.
+}
+
+// AppendAll adds the values, in order, at the end of a list, holding the
+// lock once so other goroutines never see part of the values.
+func (list *List[T]) AppendAll(values ...T) error {
+	if list == nil {
+		return nilError("list")
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("AppendAll", start, list.metrics.acquired())
+	defer list.debugCheck()
+	for _, value := range values {
+		list.append(value)
+	}
+	return nil
+}
+
+// InsertAll adds the values, in order, at the beginning of a list, so the
+// list starts with values[0]. Like AppendAll, other goroutines never see
+// part of the values.
+func (list *List[T]) InsertAll(values ...T) error {
+	if list == nil {
+		return nilError("list")
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("InsertAll", start, list.metrics.acquired())
+	defer list.debugCheck()
+	for i := len(values) - 1; i >= 0; i-- {
+		list.insert(values[i])
+	}
+	return nil
+}
+
+// Batch runs f holding the write lock once for all of its operations.
+func (list *List[T]) Batch(f func(tx *ListTx[T])) error {
+	if list == nil {
+		return nilError("list")
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("Batch", start, list.metrics.acquired())
+	defer list.debugCheck()
+	tx := &ListTx[T]{list}
+	defer func() {
+		tx.list = nil
+	}()
+	f(tx)
+	return nil
+}
+
+// Tx runs f holding the write lock once for all of its operations, and
+// rolls them back if f returns an error or panics, so other goroutines see
+// either all of the operations or none. The error of f is returned. The
+// nodes are copied before the first change, as for a Snapshot, so a
+// rollback restores the original nodes. Hooks observe the operations once
+// they are committed, and never observe those rolled back. A rollback moves
+// open cursors before the head.
+func (list *List[T]) Tx(f func(tx *ListTx[T]) error) (err error) {
+	if list == nil {
+		return nilError("list")
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("Tx", start, list.metrics.acquired())
+	defer list.debugCheck()
+	head, tail, length, shared, owner := list.head, list.tail, list.length, list.shared, list.owner
+	// Nodes created by the transaction get a new owner, so a rollback
+	// disowns them along with any copies.
+	list.shared = true
+	list.disown()
+	list.hooks.deferCalls()
+	tx := &ListTx[T]{list}
+	committed := false
+	defer func() {
+		tx.list = nil
+		defer list.hooks.flush(committed)
+		if !committed {
+			list.head, list.tail, list.length, list.shared, list.owner = head, tail, length, shared, owner
+			list.resetCursors()
+			return
+		}
+		if list.shared && !shared {
+			// Nothing was copied, so no snapshot holds the nodes, but Insert
+			// and DeleteHead skipped their previous links and kept the
+			// owner of deleted heads.
+			list.shared = false
+			if list.head != head || list.length != length {
+				list.relink()
+				list.claim()
+			} else {
+				list.owner = owner
+			}
+		}
+	}()
+	if err := f(tx); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// Length reports the number of elements in the list.
+func (tx *ListTx[T]) Length() int {
+	if tx.list == nil {
+		return 0
+	}
+	return tx.list.length
+}
+
+// Head gets the head of the list.
+func (tx *ListTx[T]) Head() *ListNode[T] {
+	if tx.list == nil {
+		return nil
+	}
+	return tx.list.head
+}
+
+// Tail gets the tail of the list.
+func (tx *ListTx[T]) Tail() *ListNode[T] {
+	if tx.list == nil {
+		return nil
+	}
+	return tx.list.tail
+}
+
+// Insert adds an element at the beginning of the list.
+func (tx *ListTx[T]) Insert(value T) error {
+	if tx.list == nil {
+		return fmt.Errorf("%w: transaction is done", ErrClosed)
+	}
+	tx.list.insert(value)
+	return nil
+}
+
+// Append adds an element at the end of the list.
+func (tx *ListTx[T]) Append(value T) error {
+	if tx.list == nil {
+		return fmt.Errorf("%w: transaction is done", ErrClosed)
+	}
+	tx.list.append(value)
+	return nil
+}
+
+// Find a value in the list.
+func (tx *ListTx[T]) Find(value T) *ListNode[T] {
+	_, found := tx.list.findParent(value)
+	return found
+}
+
+// Delete a value in the list.
+func (tx *ListTx[T]) Delete(value T) bool {
+	if tx.list == nil {
+		return false
+	}
+	return tx.list.delete(value)
+}
+
+// DeleteHead deletes the head node in the list.
+func (tx *ListTx[T]) DeleteHead() (T, bool) {
+	if tx.list == nil {
+		var unset T
+		return unset, false
+	}
+	return tx.list.deleteHead()
+}
+
+// DeleteTail deletes the tail node in the list.
+func (tx *ListTx[T]) DeleteTail() (T, bool) {
+	if tx.list == nil {
+		var unset T
+		return unset, false
+	}
+	return tx.list.deleteTail()
+}
@@ -0,0 +1,210 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	. "fun/pkg/data"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func Test_AppendAll(t *testing.T) {
+	list := NewList[Data]()
+	list.Append(1)
+	if err := list.AppendAll(2, 3, 4); err != nil {
+		t.Error("unexpected error", err)
+	}
+	listAssert(t, list, []Data{1, 2, 3, 4})
+
+	list.AppendAll()
+	listAssert(t, list, []Data{1, 2, 3, 4})
+
+	var nilList *List[Data]
+	if err := nilList.AppendAll(1); err == nil {
+		t.Error("expected error appending to nil list")
+	}
+}
+
+func Test_InsertAll(t *testing.T) {
+	list := NewList[Data]()
+	list.InsertAll(3, 4)
+	listAssert(t, list, []Data{3, 4})
+	if err := list.InsertAll(1, 2); err != nil {
+		t.Error("unexpected error", err)
+	}
+	listAssert(t, list, []Data{1, 2, 3, 4})
+
+	var nilList *List[Data]
+	if err := nilList.InsertAll(1); err == nil {
+		t.Error("expected error inserting into nil list")
+	}
+}
+
+func Test_Batch(t *testing.T) {
+	list := NewList[Data]()
+	list.AppendAll(1, 2, 3)
+
+	var saved *ListTx[Data]
+	err := list.Batch(func(tx *ListTx[Data]) {
+		saved = tx
+		tx.Append(4)
+		tx.Insert(0)
+		if !tx.Delete(2) {
+			t.Error("failed to delete 2 in batch")
+		}
+		if tx.Find(3) == nil {
+			t.Error("failed to find 3 in batch")
+		}
+		if value, ok := tx.DeleteHead(); !ok || value != 0 {
+			t.Error("expected to delete head 0, got", value)
+		}
+		if value, ok := tx.DeleteTail(); !ok || value != 4 {
+			t.Error("expected to delete tail 4, got", value)
+		}
+		if tx.Length() != 2 {
+			t.Error("expected batch length 2, got", tx.Length())
+		}
+		if value, _ := tx.Head().Value(); value != 1 {
+			t.Error("expected head 1, got", value)
+		}
+		if value, _ := tx.Tail().Value(); value != 3 {
+			t.Error("expected tail 3, got", value)
+		}
+	})
+	if err != nil {
+		t.Error("unexpected error", err)
+	}
+	listAssert(t, list, []Data{1, 3})
+
+	if err := saved.Append(5); err == nil {
+		t.Error("expected error using transaction after batch")
+	}
+	listAssert(t, list, []Data{1, 3})
+}
+
+func Test_BatchConcurrency(t *testing.T) {
+	const threads = 10
+	const batch = 100
+
+	list := NewList[Data]()
+	var wg sync.WaitGroup
+	wg.Add(2 * threads)
+	for i := 0; i < threads; i++ {
+		go func() {
+			defer wg.Done()
+			values := make([]Data, batch)
+			for j := range values {
+				values[j] = Data(j)
+			}
+			list.AppendAll(values...)
+		}()
+		go func() {
+			defer wg.Done()
+			list.Batch(func(tx *ListTx[Data]) {
+				for j := 0; j < batch; j++ {
+					tx.Insert(Data(j))
+				}
+			})
+		}()
+	}
+	wg.Wait()
+	if list.Length() != 2*threads*batch {
+		t.Error("expected length", 2*threads*batch, "got", list.Length())
+	}
+}
+
+func Test_Tx(t *testing.T) {
+	list := NewListOf[Data](1, 2, 3)
+	failed := errors.New("failed")
+	err := list.Tx(func(tx *ListTx[Data]) error {
+		tx.Insert(0)
+		tx.Delete(2)
+		tx.Append(4)
+		if tx.Length() != 4 {
+			t.Error("expected the transaction to see its changes, got length", tx.Length())
+		}
+		return failed
+	})
+	if err != failed {
+		t.Error("expected the callback error, got", err)
+	}
+	listAssert(t, list, []Data{1, 2, 3})
+	if err := list.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+
+	err = list.Tx(func(tx *ListTx[Data]) error {
+		tx.Insert(0)
+		tx.DeleteTail()
+		return nil
+	})
+	if err != nil {
+		t.Error("unexpected error", err)
+	}
+	listAssert(t, list, []Data{0, 1, 2})
+
+	list.Tx(func(tx *ListTx[Data]) error {
+		tx.Insert(-1)
+		tx.DeleteHead()
+		tx.DeleteHead()
+		return nil
+	})
+	listAssert(t, list, []Data{1, 2})
+	if err := list.CheckInvariants(); err != nil {
+		t.Error("expected previous links to be rebuilt", err)
+	}
+
+	snapshot := list.Snapshot()
+	list.Tx(func(tx *ListTx[Data]) error {
+		tx.Append(3)
+		return failed
+	})
+	listAssert(t, list, []Data{1, 2})
+	listAssert(t, snapshot, []Data{1, 2})
+	list.Append(3)
+	listAssert(t, snapshot, []Data{1, 2})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the panic to propagate")
+			}
+		}()
+		list.Tx(func(tx *ListTx[Data]) error {
+			tx.DeleteTail()
+			panic("failed")
+		})
+	}()
+	listAssert(t, list, []Data{1, 2, 3})
+}
+
+func Test_TxHooks(t *testing.T) {
+	list := NewListOf[Data](1, 2)
+	var events []string
+	list.OnInsert(func(value Data) { events = append(events, fmt.Sprint("+", value)) })
+	list.OnDelete(func(value Data) { events = append(events, fmt.Sprint("-", value)) })
+	list.Tx(func(tx *ListTx[Data]) error {
+		tx.Append(3)
+		tx.DeleteHead()
+		if len(events) != 0 {
+			t.Error("expected hooks to wait for the commit, got", events)
+		}
+		return errors.New("failed")
+	})
+	if len(events) != 0 {
+		t.Error("expected rolled back operations to skip hooks, got", events)
+	}
+	list.Tx(func(tx *ListTx[Data]) error {
+		tx.Append(3)
+		tx.DeleteHead()
+		return nil
+	})
+	if !slices.Equal(events, []string{"+3", "-1"}) {
+		t.Error("expected committed operations in order, got", events)
+	}
+	list.Append(4)
+	if len(events) != 3 {
+		t.Error("expected hooks to run directly after the transaction, got", events)
+	}
+}
@@ -0,0 +1,33 @@
+package data
+
+import "context"
+
+// ToChan sends the values of the list from head to tail on a channel, which
+// is closed after the tail or when ctx is done. Values are read with the
+// consistency of Iterator, so the sending goroutine never holds the lock
+// while blocked on the channel.
+func (list *List[T]) ToChan(ctx context.Context) <-chan T {
+	return ToChan(ctx, list.Iterator())
+}
+
+// FillFromChan appends each value received from ch until it is closed, or
+// returns a cancellation error when ctx is done. Values received before
+// cancellation stay in the list.
+func (list *List[T]) FillFromChan(ctx context.Context, ch <-chan T) error {
+	if list == nil {
+		return nilError("list")
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return canceled(ctx)
+		case value, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := list.Append(value); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package data_test
+
+import (
+	"context"
+	"errors"
+	. "fun/pkg/data"
+	"testing"
+)
+
+func Test_ListChannels(t *testing.T) {
+	list := NewList[Data]()
+	list.AppendAll(1, 2, 3)
+	copied := NewList[Data]()
+	if err := copied.FillFromChan(context.Background(), list.ToChan(context.Background())); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	listAssert(t, copied, []Data{1, 2, 3})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	values := list.ToChan(ctx)
+	if value := <-values; value != 1 {
+		t.Error("expected the head first, got", value)
+	}
+	cancel()
+	for range values {
+	}
+
+	ch := make(chan Data)
+	ctx, cancel = context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- copied.FillFromChan(ctx, ch)
+	}()
+	ch <- 4
+	cancel()
+	if err := <-done; !errors.Is(err, ErrCanceled) || !errors.Is(err, context.Canceled) {
+		t.Error("expected a cancellation error, got", err)
+	}
+	listAssert(t, copied, []Data{1, 2, 3, 4})
+}
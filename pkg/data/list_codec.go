@@ -0,0 +1,98 @@
+package data
+
+import (
+	"fun/pkg/codec"
+	"io"
+)
+
+// EncodeTo writes the length of the list followed by each value, in list
+// order, encoded by c.
+func (list *List[T]) EncodeTo(w io.Writer, c codec.Codec[T]) error {
+	if list == nil {
+		return nilError("list")
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	defer list.tracer.end("List.EncodeTo", list.tracer.begin(), list.length)
+	writer := codec.NewWriter(w, c)
+	if err := writer.WriteHeader(list.length); err != nil {
+		return err
+	}
+	for currentNode := list.head; currentNode != nil; currentNode = currentNode.next {
+		if err := writer.Write(currentNode.value); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// DecodeFrom replaces the contents of the list with values written by
+// EncodeTo. The list is unchanged if decoding fails.
+func (list *List[T]) DecodeFrom(r io.Reader, c codec.Codec[T]) error {
+	if list == nil {
+		return nilError("list")
+	}
+	values, err := codec.NewReader(r, c).ReadAll()
+	if err != nil {
+		return err
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("DecodeFrom", start, list.metrics.acquired())
+	defer list.debugCheck()
+	list.clear()
+	for _, value := range values {
+		list.append(value)
+	}
+	return nil
+}
+
+// EncodeStream writes the values of the list, in list order, as a chunked
+// stream of chunkSize values per chunk, so no more than one chunk is
+// buffered at a time.
+func (list *List[T]) EncodeStream(w io.Writer, c codec.Codec[T], chunkSize int) error {
+	if list == nil {
+		return nilError("list")
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	defer list.tracer.end("List.EncodeStream", list.tracer.begin(), list.length)
+	writer := codec.NewStreamWriter(w, c, chunkSize)
+	for currentNode := list.head; currentNode != nil; currentNode = currentNode.next {
+		if err := writer.Write(currentNode.value); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// AppendStream decodes values from a stream and adds them at the end of the
+// list, a batch at a time, until the end of the stream. Values decoded before
+// an error stay in the list, and decoding can be resumed from
+// reader.Checkpoint().
+func (list *List[T]) AppendStream(reader *codec.StreamReader[T]) error {
+	if list == nil {
+		return nilError("list")
+	}
+	batch := make([]T, 0, codec.DefaultChunkSize)
+	for {
+		value, err := reader.Read()
+		if err == nil {
+			batch = append(batch, value)
+			if len(batch) < cap(batch) {
+				continue
+			}
+		}
+		if len(batch) > 0 {
+			list.AppendAll(batch...)
+			batch = batch[:0]
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
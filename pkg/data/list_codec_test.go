@@ -0,0 +1,91 @@
+package data_test
+
+import (
+	"bytes"
+	"fun/pkg/codec"
+	. "fun/pkg/data"
+	"testing"
+)
+
+func Test_ListCodec(t *testing.T) {
+	codecs := map[string]codec.Codec[Data]{
+		"json":    codec.JSON[Data](),
+		"gob":     codec.Gob[Data](),
+		"msgpack": codec.MsgPack[Data](),
+	}
+	for name, c := range codecs {
+		list := NewList[Data]()
+		list.AppendAll(1, 2, 3)
+		var buffer bytes.Buffer
+		if err := list.EncodeTo(&buffer, c); err != nil {
+			t.Fatal(name, "unexpected error", err)
+		}
+		data := buffer.Bytes()
+
+		decoded := NewList[Data]()
+		decoded.Append(9)
+		if err := decoded.DecodeFrom(bytes.NewReader(data), c); err != nil {
+			t.Fatal(name, "unexpected error", err)
+		}
+		listAssert(t, decoded, []Data{1, 2, 3})
+
+		if err := decoded.DecodeFrom(bytes.NewReader(data[:len(data)-1]), c); err == nil {
+			t.Error(name, "expected truncated data to fail")
+		}
+		listAssert(t, decoded, []Data{1, 2, 3})
+	}
+
+	var empty bytes.Buffer
+	NewList[Data]().EncodeTo(&empty, codec.JSON[Data]())
+	decoded := NewList[Data]()
+	decoded.Append(1)
+	if err := decoded.DecodeFrom(&empty, codec.JSON[Data]()); err != nil {
+		t.Error("unexpected error", err)
+	}
+	listAssert(t, decoded, []Data{})
+
+	var nilList *List[Data]
+	if err := nilList.EncodeTo(&empty, codec.JSON[Data]()); err == nil {
+		t.Error("expected error encoding nil list")
+	}
+	if err := nilList.DecodeFrom(&empty, codec.JSON[Data]()); err == nil {
+		t.Error("expected error decoding nil list")
+	}
+}
+
+func Test_ListStream(t *testing.T) {
+	list := NewList[Data]()
+	for i := 0; i < 2500; i++ {
+		list.Append(Data(i))
+	}
+	var buffer bytes.Buffer
+	if err := list.EncodeStream(&buffer, codec.MsgPack[Data](), 100); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	data := buffer.Bytes()
+
+	decoded := NewList[Data]()
+	reader := codec.NewStreamReader(bytes.NewReader(data[:len(data)/2]), codec.MsgPack[Data]())
+	if err := decoded.AppendStream(reader); err == nil {
+		t.Error("expected truncated stream to fail")
+	}
+	checkpoint := reader.Checkpoint()
+	if decoded.Length() != int(checkpoint.Values) || decoded.Length() == 0 {
+		t.Errorf("expected %d values kept, got %d", checkpoint.Values, decoded.Length())
+	}
+	reader = codec.ResumeStreamReader(bytes.NewReader(data[checkpoint.Offset:]), codec.MsgPack[Data](), checkpoint)
+	if err := decoded.AppendStream(reader); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if decoded.String() != list.String() {
+		t.Error("expected resumed stream to match the original list")
+	}
+
+	var nilList *List[Data]
+	if err := nilList.EncodeStream(&buffer, codec.MsgPack[Data](), 0); err == nil {
+		t.Error("expected error encoding nil list")
+	}
+	if err := nilList.AppendStream(reader); err == nil {
+		t.Error("expected error decoding into nil list")
+	}
+}
@@ -0,0 +1,162 @@
+package data
+
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// Concat moves every element of other to the end of the list in constant
+// time, leaving other empty. If either list has hooks, they observe each
+// moved value, which takes time linear in the length of other.
+func (list *List[T]) Concat(other *List[T]) error {
+	if list == nil {
+		return nilError("list")
+	}
+	if other == nil {
+		return nil
+	}
+	if other == list {
+		return fmt.Errorf("%w: cannot concat a list onto itself", ErrInvalid)
+	}
+	start := list.metrics.begin()
+	unlock := lockPair(list, other)
+	defer unlock()
+	defer list.metrics.end("Concat", start, list.metrics.acquired())
+	defer other.debugCheck()
+	defer list.debugCheck()
+	if other.head == nil {
+		return nil
+	}
+	moved := other.head
+	list.unshare()
+	if list.tail == nil {
+		list.head = other.head
+	} else {
+		list.tail.next = other.head
+	}
+	if !other.shared {
+		other.head.prev = list.tail
+	}
+	list.tail = other.tail
+	list.length += other.length
+	list.shared = other.shared
+	if list.owner == nil {
+		list.disown()
+	}
+	// A snapshot that was never written owns none of its nodes.
+	if other.owner != nil {
+		other.owner.forward = list.owner
+	}
+	other.disown()
+	other.head, other.tail, other.length, other.shared = nil, nil, 0, false
+	other.resetCursors()
+	other.hooks.deleteAll(moved)
+	list.hooks.insertAll(moved)
+	return nil
+}
+
+// ConcatCopy appends copies of the elements of other to the list, leaving
+// other unchanged. The list may be concatenated with itself.
+func (list *List[T]) ConcatCopy(other *List[T]) error {
+	if list == nil {
+		return nilError("list")
+	}
+	if other == nil {
+		return nil
+	}
+	values := other.View().values
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("ConcatCopy", start, list.metrics.acquired())
+	defer list.debugCheck()
+	for _, value := range values {
+		list.append(value)
+	}
+	return nil
+}
+
+// SplitAt moves the first i elements of the list into a new list and the
+// rest into another, leaving the list empty. An index outside [0, length] is
+// clamped. The new lists have the options of the list and their own locks,
+// but no hooks, and the delete hooks of the list observe every moved value.
+func (list *List[T]) SplitAt(i int) (*List[T], *List[T]) {
+	if list == nil {
+		return nil, nil
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("SplitAt", start, list.metrics.acquired())
+	defer list.tracer.end("List.SplitAt", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	i = max(0, min(i, list.length))
+	list.unshare()
+	front, back := list.newLike(), list.newLike()
+	// The back keeps the owner of the list, and the front claims its nodes
+	// while walking to the split.
+	back.owner = list.owner
+	if i > 0 {
+		front.disown()
+		last := list.head
+		for j := 1; j < i; j++ {
+			last.owner = front.owner
+			last = last.next
+		}
+		last.owner = front.owner
+		front.head, front.tail, front.length = list.head, last, i
+		back.head = last.next
+		last.next = nil
+	} else {
+		back.head = list.head
+	}
+	if back.head != nil {
+		back.head.prev = nil
+		back.tail, back.length = list.tail, list.length-i
+	}
+	list.head, list.tail, list.length = nil, nil, 0
+	list.disown()
+	list.resetCursors()
+	list.hooks.deleteAll(front.head)
+	list.hooks.deleteAll(back.head)
+	return front, back
+}
+
+// newLike creates an empty list with the metrics, tracer, locking, node pool,
+// and arena of the list.
+func (list *List[T]) newLike() *List[T] {
+	mux := locker(noLock{})
+	if _, unlocked := list.mux.(noLock); !unlocked {
+		mux = &sync.RWMutex{}
+	}
+	return &List[T]{nodes: list.nodes, arena: list.arena, metrics: list.metrics, tracer: list.tracer, mux: mux}
+}
+
+// lockPair takes the write locks of two different lists in key order, so
+// concurrent operations on the same pair cannot deadlock, and returns a
+// function that releases them.
+func lockPair[T ListData](a, b *List[T]) func() {
+	return lockInOrder(unsafe.Pointer(a), a.mux, unsafe.Pointer(b), b.mux)
+}
+
+// lockKey orders the locks of structures taken together. Every operation
+// holding the locks of several structures at once takes them in increasing
+// key order, so no two of them can deadlock.
+func lockKey(structure unsafe.Pointer) uintptr {
+	return uintptr(structure)
+}
+
+// lockInOrder takes the write locks of two different structures, a with
+// lock aMux and b with lock bMux, in key order.
+func lockInOrder(a unsafe.Pointer, aMux locker, b unsafe.Pointer, bMux locker) func() {
+	if lockKey(a) > lockKey(b) {
+		aMux, bMux = bMux, aMux
+	}
+	aMux.Lock()
+	bMux.Lock()
+	return func() {
+		bMux.Unlock()
+		aMux.Unlock()
+	}
+}
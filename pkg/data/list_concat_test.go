@@ -0,0 +1,118 @@
+package data_test
+
+import (
+	. "fun/pkg/data"
+	"sync"
+	"testing"
+)
+
+func Test_Concat(t *testing.T) {
+	list := NewList[Data]()
+	other := NewList[Data]()
+	other.AppendAll(1, 2)
+	if err := list.Concat(other); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	listAssert(t, list, []Data{1, 2})
+	listAssert(t, other, nil)
+
+	other.AppendAll(3, 4)
+	list.Concat(other)
+	list.Concat(NewList[Data]())
+	list.Concat(nil)
+	listAssert(t, list, []Data{1, 2, 3, 4})
+	listAssert(t, other, nil)
+	other.Append(5)
+	listAssert(t, other, []Data{5})
+	listAssert(t, list, []Data{1, 2, 3, 4})
+
+	if err := list.Concat(list); err == nil {
+		t.Error("expected an error concatenating a list onto itself")
+	}
+	list.ConcatCopy(list)
+	listAssert(t, list, []Data{1, 2, 3, 4, 1, 2, 3, 4})
+	list.ConcatCopy(other)
+	listAssert(t, other, []Data{5})
+	if list.Length() != 9 {
+		t.Error("expected 9 values, got", list.Length())
+	}
+}
+
+func Test_ConcatSnapshot(t *testing.T) {
+	source := NewListOf[Data](1, 2)
+	list := NewList[Data]()
+	if err := list.Concat(source.Snapshot()); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if err := list.Concat(source.Snapshot().Snapshot()); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	listAssert(t, list, []Data{1, 2, 1, 2})
+	list.Append(3)
+	source.Append(4)
+	listAssert(t, list, []Data{1, 2, 1, 2, 3})
+	listAssert(t, source, []Data{1, 2, 4})
+	for _, l := range []*List[Data]{list, source} {
+		if err := l.CheckInvariants(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func Test_ConcatConcurrent(t *testing.T) {
+	a := NewList[Data]()
+	b := NewList[Data]()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b.Append(Data(j))
+				a.Concat(b)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				a.Append(Data(j))
+				b.Concat(a)
+			}
+		}()
+	}
+	wg.Wait()
+	if a.Length()+b.Length() != 800 {
+		t.Error("expected 800 values, got", a.Length()+b.Length())
+	}
+}
+
+func Test_SplitAt(t *testing.T) {
+	for i, expected := range [][2][]Data{
+		{nil, {1, 2, 3}},
+		{{1}, {2, 3}},
+		{{1, 2}, {3}},
+		{{1, 2, 3}, nil},
+	} {
+		list := NewList[Data]()
+		list.AppendAll(1, 2, 3)
+		front, back := list.SplitAt(i)
+		listAssert(t, front, expected[0])
+		listAssert(t, back, expected[1])
+		listAssert(t, list, nil)
+		front.Append(9)
+		back.Insert(0)
+		if err := front.CheckInvariants(); err != nil {
+			t.Error(err)
+		}
+		if err := back.CheckInvariants(); err != nil {
+			t.Error(err)
+		}
+	}
+
+	list := NewList[Data](WithLocking(false))
+	list.AppendAll(1, 2)
+	front, back := list.SplitAt(5)
+	listAssert(t, front, []Data{1, 2})
+	listAssert(t, back, nil)
+	front.Concat(back)
+}
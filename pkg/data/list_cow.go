@@ -0,0 +1,58 @@
+package data
+
+// Snapshot creates a list with the current contents of the list in constant
+// time. The two lists share their nodes until either is changed in a way
+// that would modify a shared node, which first copies the nodes of the list
+// being changed. Readers can traverse a snapshot under its own lock while
+// writers keep changing the original. The snapshot has the options of the
+// list.
+func (list *List[T]) Snapshot() *List[T] {
+	if list == nil {
+		return nil
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("Snapshot", start, list.metrics.acquired())
+	snapshot := list.newLike()
+	if list.head != nil {
+		list.shared = true
+		snapshot.head, snapshot.tail, snapshot.length, snapshot.shared = list.head, list.tail, list.length, true
+	}
+	return snapshot
+}
+
+// unshare copies the nodes of the list if they are shared with a snapshot,
+// so they can be modified, write lock must be held. Insert and DeleteHead
+// only move the head and need no copy.
+func (list *List[T]) unshare() {
+	if !list.shared {
+		return
+	}
+	list.shared = false
+	list.disown()
+	var copies map[*ListNode[T]]*ListNode[T]
+	if len(list.cursors) > 0 {
+		copies = make(map[*ListNode[T]]*ListNode[T], list.length)
+	}
+	var head, tail *ListNode[T]
+	node := list.head
+	for i := 0; i < list.length; i++ {
+		copied := list.newNode(node.value, tail, nil)
+		if tail == nil {
+			head = copied
+		} else {
+			tail.next = copied
+		}
+		if copies != nil {
+			copies[node] = copied
+		}
+		tail, node = copied, node.next
+	}
+	list.head, list.tail = head, tail
+	for cursor := range list.cursors {
+		if cursor.node != nil {
+			cursor.node = copies[cursor.node]
+		}
+	}
+}
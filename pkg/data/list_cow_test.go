@@ -0,0 +1,82 @@
+package data_test
+
+import (
+	. "fun/pkg/data"
+	"sync"
+	"testing"
+)
+
+func Test_Snapshot(t *testing.T) {
+	list := NewList[Data]()
+	if snapshot := list.Snapshot(); snapshot.Length() != 0 {
+		t.Error("expected an empty snapshot")
+	}
+	list.AppendAll(1, 2, 3)
+	snapshot := list.Snapshot()
+	listAssert(t, snapshot, []Data{1, 2, 3})
+
+	list.Insert(0)
+	list.DeleteHead()
+	list.Append(4)
+	list.Delete(2)
+	listAssert(t, list, []Data{1, 3, 4})
+	listAssert(t, snapshot, []Data{1, 2, 3})
+
+	snapshot.Reverse()
+	listAssert(t, snapshot, []Data{3, 2, 1})
+	listAssert(t, list, []Data{1, 3, 4})
+
+	// Operations that rewrite links all copy first.
+	for _, change := range []func(*List[Data]){
+		func(l *List[Data]) { l.DeleteTail() },
+		func(l *List[Data]) { l.Sort(func(a, b Data) bool { return a > b }) },
+		func(l *List[Data]) { l.RotateLeft(1) },
+		func(l *List[Data]) { l.InsertAt(1, 9) },
+		func(l *List[Data]) { l.RemoveAt(1) },
+		func(l *List[Data]) { l.DeleteAll(1) },
+		func(l *List[Data]) { l.ReverseInGroups(2) },
+		func(l *List[Data]) { l.SplitAt(1) },
+		func(l *List[Data]) { l.Concat(NewList[Data]()) },
+		func(l *List[Data]) { l.Batch(func(tx *ListTx[Data]) { tx.Append(7) }) },
+	} {
+		original := NewList[Data]()
+		original.AppendAll(1, 2, 3)
+		snapshot := original.Snapshot()
+		change(original)
+		listAssert(t, snapshot, []Data{1, 2, 3})
+		if err := original.CheckInvariants(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func Test_SnapshotConcurrent(t *testing.T) {
+	list := NewList[Data]()
+	list.AppendAll(1, 2, 3)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				list.Append(Data(j))
+				list.DeleteTail()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				snapshot := list.Snapshot()
+				length := 0
+				for range snapshot.All() {
+					length++
+				}
+				if length != snapshot.Length() {
+					t.Error("expected a consistent snapshot")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	listAssert(t, list, []Data{1, 2, 3})
+}
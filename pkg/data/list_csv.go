@@ -0,0 +1,64 @@
+package data
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// WriteCSV writes the values of the list, in list order, as CSV records made
+// by rowFn, preceded by a header record from headerFn unless it is nil.
+func (list *List[T]) WriteCSV(w io.Writer, headerFn func() []string, rowFn func(T) []string) error {
+	if list == nil {
+		return nilError("list")
+	}
+	writer := csv.NewWriter(w)
+	if headerFn != nil {
+		if err := writer.Write(headerFn()); err != nil {
+			return err
+		}
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	defer list.tracer.end("List.WriteCSV", list.tracer.begin(), list.length)
+	for currentNode := list.head; currentNode != nil; currentNode = currentNode.next {
+		if err := writer.Write(rowFn(currentNode.value)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadCSV parses every CSV record with parseFn and adds the values at the end
+// of the list. Records for which parseFn returns ErrSkipRecord are ignored.
+// The list is unchanged if reading or parsing fails.
+func (list *List[T]) ReadCSV(r io.Reader, parseFn func([]string) (T, error)) error {
+	if list == nil {
+		return nilError("list")
+	}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	var values []T
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		value, err := parseFn(record)
+		if errors.Is(err, ErrSkipRecord) {
+			continue
+		}
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		values = append(values, value)
+	}
+	return list.AppendAll(values...)
+}
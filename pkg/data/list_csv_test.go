@@ -0,0 +1,53 @@
+package data_test
+
+import (
+	"bytes"
+	. "fun/pkg/data"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func Test_ListCSV(t *testing.T) {
+	list := NewList[Data]()
+	list.AppendAll(1, 2, 3)
+	var buffer bytes.Buffer
+	header := func() []string { return []string{"value", "square"} }
+	row := func(value Data) []string {
+		return []string{value.String(), strconv.Itoa(int(value * value))}
+	}
+	if err := list.WriteCSV(&buffer, header, row); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if expected := "value,square\n1,1\n2,4\n3,9\n"; buffer.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buffer.String())
+	}
+
+	parse := func(record []string) (Data, error) {
+		if record[0] == "value" {
+			return 0, ErrSkipRecord
+		}
+		value, err := strconv.Atoi(record[0])
+		return Data(value), err
+	}
+	decoded := NewList[Data]()
+	decoded.Append(0)
+	if err := decoded.ReadCSV(&buffer, parse); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	listAssert(t, decoded, []Data{0, 1, 2, 3})
+
+	err := decoded.ReadCSV(strings.NewReader("4\nx\n"), parse)
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Error("expected parse error on line 2, got", err)
+	}
+	listAssert(t, decoded, []Data{0, 1, 2, 3})
+
+	var nilList *List[Data]
+	if err := nilList.WriteCSV(&buffer, nil, row); err == nil {
+		t.Error("expected error writing nil list")
+	}
+	if err := nilList.ReadCSV(&buffer, parse); err == nil {
+		t.Error("expected error reading into nil list")
+	}
+}
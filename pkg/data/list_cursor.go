@@ -0,0 +1,88 @@
+package data
+
+// Cursor walks a list from head to tail and stays valid while other
+// goroutines change the list. When the node it is on is deleted, the cursor
+// moves back to the node before it, so the next step continues with the
+// node that followed the deleted one. Elements added after the cursor's
+// position are visited. A cursor must be closed when it is no longer needed,
+// and must not be used by more than one goroutine at a time.
+type Cursor[T ListData] struct {
+	list   *List[T]     // List being walked, nil once closed.
+	node   *ListNode[T] // Node last visited, nil before the head.
+	value  T            // Value of the node last visited.
+	closed bool         // Whether Close has been called.
+}
+
+// Cursor creates a cursor before the head of the list. Each open cursor adds
+// a step to every deletion, so cursors should be closed promptly.
+func (list *List[T]) Cursor() *Cursor[T] {
+	cursor := &Cursor[T]{list: list}
+	if list == nil {
+		return cursor
+	}
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	if list.cursors == nil {
+		list.cursors = map[*Cursor[T]]struct{}{}
+	}
+	list.cursors[cursor] = struct{}{}
+	return cursor
+}
+
+// Next moves the cursor to the next element, reporting whether there was
+// one. At the end of the list the cursor stays on the tail, so a later call
+// visits elements appended since.
+func (cursor *Cursor[T]) Next() bool {
+	list := cursor.list
+	if list == nil || cursor.closed {
+		return false
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	next := list.head
+	if cursor.node != nil {
+		next = cursor.node.next
+	}
+	if next == nil {
+		return false
+	}
+	cursor.node, cursor.value = next, next.value
+	return true
+}
+
+// Value gets the value of the element last visited by Next, even if it has
+// been deleted since.
+func (cursor *Cursor[T]) Value() T {
+	return cursor.value
+}
+
+// Close stops the cursor, which returns false from Next afterwards.
+func (cursor *Cursor[T]) Close() {
+	list := cursor.list
+	if list == nil || cursor.closed {
+		return
+	}
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	cursor.closed = true
+	cursor.node = nil
+	delete(list.cursors, cursor)
+}
+
+// moveCursors moves the cursors on a node being deleted to the node before
+// it, nil for the head, lock must be held.
+func (list *List[T]) moveCursors(deleted, parent *ListNode[T]) {
+	for cursor := range list.cursors {
+		if cursor.node == deleted {
+			cursor.node = parent
+		}
+	}
+}
+
+// resetCursors moves every cursor before the head, when the nodes of the
+// list are replaced or moved to another list, lock must be held.
+func (list *List[T]) resetCursors() {
+	for cursor := range list.cursors {
+		cursor.node = nil
+	}
+}
@@ -0,0 +1,96 @@
+package data_test
+
+import (
+	. "fun/pkg/data"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func Test_Cursor(t *testing.T) {
+	list := NewListOf[Data](1, 2, 3, 4, 5)
+	cursor := list.Cursor()
+	defer cursor.Close()
+	var visited []Data
+	next := func() {
+		if cursor.Next() {
+			visited = append(visited, cursor.Value())
+		}
+	}
+
+	next()
+	list.Delete(1)
+	next()
+	next()
+	list.Delete(3)
+	list.Delete(4)
+	if cursor.Value() != 3 {
+		t.Error("expected the deleted value to stay visible, got", cursor.Value())
+	}
+	next()
+	if !slices.Equal(visited, []Data{1, 2, 3, 5}) {
+		t.Error("expected the cursor to skip deleted nodes", visited)
+	}
+
+	if cursor.Next() {
+		t.Error("expected the cursor to be done")
+	}
+	list.Append(6)
+	next()
+	if visited[len(visited)-1] != 6 {
+		t.Error("expected the cursor to visit an appended element", visited)
+	}
+
+	list.Snapshot()
+	list.DeleteTail()
+	list.Append(7)
+	next()
+	if visited[len(visited)-1] != 7 {
+		t.Error("expected the cursor to follow copied nodes", visited)
+	}
+
+	cursor.Close()
+	if cursor.Next() {
+		t.Error("expected a closed cursor to stop")
+	}
+
+	pooled := NewList[Data](WithNodePool())
+	pooled.AppendAll(1, 2, 3)
+	head := pooled.Cursor()
+	head.Next()
+	pooled.DeleteHead()
+	pooled.DeleteHead()
+	if !head.Next() || head.Value() != 3 {
+		t.Error("expected the cursor to move past recycled nodes, got", head.Value())
+	}
+	head.Close()
+}
+
+func Test_CursorConcurrency(t *testing.T) {
+	const length = 1000
+	list := NewList[Data]()
+	for value := Data(0); value < length; value++ {
+		list.Append(value)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for value := Data(0); value < length; value += 2 {
+			list.Delete(value)
+		}
+	}()
+	cursor := list.Cursor()
+	defer cursor.Close()
+	previous := Data(-1)
+	for cursor.Next() {
+		if cursor.Value() <= previous {
+			t.Fatal("expected increasing values, got", cursor.Value(), "after", previous)
+		}
+		previous = cursor.Value()
+	}
+	wg.Wait()
+	if list.Length() != length/2 {
+		t.Error("expected half the values to remain, got", list.Length())
+	}
+}
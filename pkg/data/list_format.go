@@ -0,0 +1,63 @@
+package data
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Format implements fmt.Formatter. %v prints the values like a slice, with
+// the flags and width applied to each value, %+v adds the length and the
+// address of each node, and %#v prints a call to NewListOf that builds the
+// list. %s prints String, and other verbs are applied to each value.
+func (list *List[T]) Format(f fmt.State, verb rune) {
+	if verb == 's' {
+		io.WriteString(f, list.String())
+		return
+	}
+	if list == nil {
+		if verb == 'v' && f.Flag('#') {
+			fmt.Fprintf(f, "(%T)(nil)", list)
+		} else {
+			io.WriteString(f, "[]")
+		}
+		return
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	defer list.tracer.end("List.Format", list.tracer.begin(), list.length)
+
+	var b strings.Builder
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		fmt.Fprintf(&b, "data.NewListOf[%s](", reflect.TypeFor[T]())
+		for node := list.head; node != nil; node = node.next {
+			if node != list.head {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "%#v", node.value)
+		}
+		b.WriteString(")")
+	case verb == 'v' && f.Flag('+'):
+		fmt.Fprintf(&b, "Length: %d, Nodes: [", list.length)
+		for node := list.head; node != nil; node = node.next {
+			if node != list.head {
+				b.WriteString(" ")
+			}
+			fmt.Fprintf(&b, "%+v@%p", node.value, node)
+		}
+		b.WriteString("]")
+	default:
+		format := fmt.FormatString(f, verb)
+		b.WriteString("[")
+		for node := list.head; node != nil; node = node.next {
+			if node != list.head {
+				b.WriteString(" ")
+			}
+			fmt.Fprintf(&b, format, node.value)
+		}
+		b.WriteString("]")
+	}
+	io.WriteString(f, b.String())
+}
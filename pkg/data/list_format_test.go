@@ -0,0 +1,41 @@
+package data_test
+
+import (
+	"fmt"
+	. "fun/pkg/data"
+	"regexp"
+	"testing"
+)
+
+func Test_Format(t *testing.T) {
+	list := NewListOf[Data](1, 2, 3)
+	if s := fmt.Sprintf("%v", list); s != "[1 2 3]" {
+		t.Errorf("unexpected %%v %q", s)
+	}
+	if s := fmt.Sprint(list); s != "[1 2 3]" {
+		t.Errorf("unexpected Sprint %q", s)
+	}
+	if s := fmt.Sprintf("%s", list); s != "Length: 3, Data: 1 2 3" {
+		t.Errorf("unexpected %%s %q", s)
+	}
+	if s := fmt.Sprintf("%03d", list); s != "[001 002 003]" {
+		t.Errorf("unexpected %%03d %q", s)
+	}
+	if s := fmt.Sprintf("%+v", list); !regexp.MustCompile(`^Length: 3, Nodes: \[1@0x[0-9a-f]+ 2@0x[0-9a-f]+ 3@0x[0-9a-f]+\]$`).MatchString(s) {
+		t.Errorf("unexpected %%+v %q", s)
+	}
+	if s := fmt.Sprintf("%#v", NewListOf("a", "b")); s != `data.NewListOf[string]("a", "b")` {
+		t.Errorf("unexpected %%#v %q", s)
+	}
+	if s := fmt.Sprintf("%#v", NewListOf[any](1, "a")); s != `data.NewListOf[interface {}](1, "a")` {
+		t.Errorf("unexpected %%#v of interface values %q", s)
+	}
+
+	var unset *List[int]
+	if s := fmt.Sprintf("%v", unset); s != "[]" {
+		t.Errorf("unexpected nil %%v %q", s)
+	}
+	if s := fmt.Sprintf("%#v", unset); s != "(*data.List[int])(nil)" {
+		t.Errorf("unexpected nil %%#v %q", s)
+	}
+}
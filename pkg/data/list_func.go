@@ -0,0 +1,137 @@
+package data
+
+import (
+	"fmt"
+	"fun/pkg/constraints"
+	"slices"
+)
+
+// MapList creates a new list of the results of f on each value of list, in
+// order.
+func MapList[T, U ListData](list *List[T], f func(T) U) *List[U] {
+	mapped := NewList[U]()
+	for _, value := range list.View().values {
+		mapped.append(f(value))
+	}
+	return mapped
+}
+
+// FilterList creates a new list of the values of list that satisfy keep, in
+// order.
+func FilterList[T ListData](list *List[T], keep func(T) bool) *List[T] {
+	filtered := NewList[T]()
+	for _, value := range list.View().values {
+		if keep(value) {
+			filtered.append(value)
+		}
+	}
+	return filtered
+}
+
+// ReduceList combines the values of list from head to tail into an
+// accumulator, starting with initial.
+func ReduceList[T ListData, A any](list *List[T], initial A, f func(A, T) A) A {
+	accumulator := initial
+	for _, value := range list.View().values {
+		accumulator = f(accumulator, value)
+	}
+	return accumulator
+}
+
+// MinList gets the smallest value of list, the first of equal values.
+func MinList[T interface {
+	ListData
+	constraints.Ordered
+}](list *List[T]) (T, bool) {
+	return extremeList(list, func(a, b T) bool { return a < b })
+}
+
+// MaxList gets the largest value of list, the first of equal values.
+func MaxList[T interface {
+	ListData
+	constraints.Ordered
+}](list *List[T]) (T, bool) {
+	return extremeList(list, func(a, b T) bool { return a > b })
+}
+
+// extremeList gets the first value of list that no later value beats.
+func extremeList[T ListData](list *List[T], beats func(a, b T) bool) (T, bool) {
+	var extreme T
+	values := list.View().values
+	if len(values) == 0 {
+		return extreme, false
+	}
+	extreme = values[0]
+	for _, value := range values[1:] {
+		if beats(value, extreme) {
+			extreme = value
+		}
+	}
+	return extreme, true
+}
+
+// SumList adds the values of list, 0 if it is empty.
+func SumList[T interface {
+	ListData
+	constraints.Number
+}](list *List[T]) T {
+	var sum T
+	for _, value := range list.View().values {
+		sum += value
+	}
+	return sum
+}
+
+// Pair holds two values, and is itself a list value.
+type Pair[A, B ListData] struct {
+	First  A // First value.
+	Second B // Second value.
+}
+
+// String converts a Pair into a string.
+func (pair Pair[A, B]) String() string {
+	return fmt.Sprintf("(%v, %v)", pair.First, pair.Second)
+}
+
+// Zip creates a new list pairing the values of a and b by position, as long
+// as the shorter list.
+func Zip[A, B ListData](a *List[A], b *List[B]) *List[Pair[A, B]] {
+	first, second := a.View().values, b.View().values
+	zipped := NewList[Pair[A, B]]()
+	for i := 0; i < len(first) && i < len(second); i++ {
+		zipped.append(Pair[A, B]{first[i], second[i]})
+	}
+	return zipped
+}
+
+// Unzip creates new lists of the first and second values of the pairs.
+func Unzip[A, B ListData](list *List[Pair[A, B]]) (*List[A], *List[B]) {
+	first, second := NewList[A](), NewList[B]()
+	for _, pair := range list.View().values {
+		first.append(pair.First)
+		second.append(pair.Second)
+	}
+	return first, second
+}
+
+// Equal reports whether two lists hold equal values in the same order. Each
+// list is read at a single point in time.
+func Equal[T ListData](a, b *List[T]) bool {
+	if a == b {
+		return true
+	}
+	return slices.Equal(a.View().values, b.View().values)
+}
+
+// Compare orders two lists lexicographically by cmp, returning a negative
+// number if a is before b, a positive number if a is after b, and 0 if they
+// are equal. A list is before any longer list it is a prefix of.
+func Compare[T ListData](a, b *List[T], cmp func(T, T) int) int {
+	first, second := a.View().values, b.View().values
+	for i := 0; i < len(first) && i < len(second); i++ {
+		if c := cmp(first[i], second[i]); c != 0 {
+			return c
+		}
+	}
+	return len(first) - len(second)
+}
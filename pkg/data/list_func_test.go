@@ -0,0 +1,114 @@
+package data_test
+
+import (
+	. "fun/pkg/data"
+	"testing"
+)
+
+// label is a string list value.
+type label string
+
+// String converts a label to a string.
+func (l label) String() string {
+	return string(l)
+}
+
+func Test_ListFunctions(t *testing.T) {
+	list := NewList[Data]()
+	list.AppendAll(1, 2, 3, 4)
+
+	doubled := MapList(list, func(value Data) Data { return value * 2 })
+	listAssert(t, doubled, []Data{2, 4, 6, 8})
+	labels := MapList(list, func(value Data) label { return label("#" + value.String()) })
+	listAssert(t, labels, []label{"#1", "#2", "#3", "#4"})
+
+	even := FilterList(list, func(value Data) bool { return value%2 == 0 })
+	listAssert(t, even, []Data{2, 4})
+
+	if sum := ReduceList(list, 0, func(total int, value Data) int { return total + int(value) }); sum != 10 {
+		t.Error("expected sum 10, got", sum)
+	}
+	listAssert(t, list, []Data{1, 2, 3, 4})
+
+	var missing *List[Data]
+	if MapList(missing, func(value Data) Data { return value }).Length() != 0 {
+		t.Error("expected an empty list from a nil list")
+	}
+}
+
+func Test_Aggregates(t *testing.T) {
+	list := NewListOf[Data](3, 1, 4, 1, 5)
+	if value, ok := MinList(list); !ok || value != 1 {
+		t.Error("unexpected minimum", value, ok)
+	}
+	if value, ok := MaxList(list); !ok || value != 5 {
+		t.Error("unexpected maximum", value, ok)
+	}
+	if sum := SumList(list); sum != 14 {
+		t.Error("expected sum 14, got", sum)
+	}
+	if sum := SumList(NewListOf(0.5, 0.25)); sum != 0.75 {
+		t.Error("expected float sum 0.75, got", sum)
+	}
+	if value, ok := MaxList(NewListOf("b", "c", "a")); !ok || value != "c" {
+		t.Error("unexpected string maximum", value, ok)
+	}
+
+	empty := NewList[Data]()
+	if _, ok := MinList(empty); ok {
+		t.Error("expected no minimum of an empty list")
+	}
+	if _, ok := MaxList(empty); ok {
+		t.Error("expected no maximum of an empty list")
+	}
+	if SumList(empty) != 0 {
+		t.Error("expected an empty sum of 0")
+	}
+}
+
+func Test_Zip(t *testing.T) {
+	numbers := NewList[Data]()
+	numbers.AppendAll(1, 2, 3)
+	labels := NewList[label]()
+	labels.AppendAll("a", "b")
+
+	zipped := Zip(numbers, labels)
+	listAssert(t, zipped, []Pair[Data, label]{{1, "a"}, {2, "b"}})
+	if zipped.String() != "Length: 2, Data: (1, a) (2, b)" {
+		t.Error("unexpected zipped list", zipped.String())
+	}
+
+	first, second := Unzip(zipped)
+	listAssert(t, first, []Data{1, 2})
+	listAssert(t, second, []label{"a", "b"})
+}
+
+func Test_EqualCompare(t *testing.T) {
+	a, b := NewList[Data](), NewList[Data]()
+	if !Equal(a, b) || Compare(a, b, compareData) != 0 {
+		t.Error("expected empty lists to be equal")
+	}
+	a.AppendAll(1, 2, 3)
+	b.AppendAll(1, 2)
+	if Equal(a, b) || Compare(a, b, compareData) <= 0 || Compare(b, a, compareData) >= 0 {
+		t.Error("expected a prefix to be before the longer list")
+	}
+	b.Append(4)
+	if Equal(a, b) || Compare(a, b, compareData) >= 0 {
+		t.Error("expected the list with the smaller element first")
+	}
+	b.DeleteTail()
+	b.Append(3)
+	if !Equal(a, b) || !Equal(a, a) || Compare(a, b, compareData) != 0 {
+		t.Error("expected equal lists")
+	}
+	var missing *List[Data]
+	if !Equal(missing, NewList[Data]()) {
+		t.Error("expected a nil list to equal an empty list")
+	}
+}
+
+// compareData orders Data values.
+func compareData(a, b Data) int {
+	return int(a) - int(b)
+}
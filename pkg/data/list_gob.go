@@ -0,0 +1,108 @@
+package data
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// Structures implement gob.GobEncoder and gob.GobDecoder by encoding their
+// values in order as a gob slice, so they can be sent over net/rpc or
+// embedded in gob-encoded types. Decoding replaces the contents and works on
+// the zero value that gob allocates, which gets the default options.
+
+// GobEncode encodes the values of the list in order.
+func (list *List[T]) GobEncode() ([]byte, error) {
+	if list == nil {
+		return nil, nilError("list")
+	}
+	return gobEncodeValues(list.View().values)
+}
+
+// GobDecode replaces the contents of the list with values encoded by
+// GobEncode. The list is unchanged if decoding fails.
+func (list *List[T]) GobDecode(b []byte) error {
+	if list == nil {
+		return nilError("list")
+	}
+	values, err := gobDecodeValues[T](b)
+	if err != nil {
+		return err
+	}
+	if list.mux == nil {
+		list.mux = newOptions(nil).newLocker()
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("GobDecode", start, list.metrics.acquired())
+	defer list.debugCheck()
+	list.clear()
+	for _, value := range values {
+		list.append(value)
+	}
+	return nil
+}
+
+// GobEncode encodes the values of the list from head to tail.
+func (list *DList[T]) GobEncode() ([]byte, error) {
+	if list == nil {
+		return nil, nilError("list")
+	}
+	list.mux.RLock()
+	values := make([]T, 0, list.length)
+	for node := list.head; node != nil; node = node.next {
+		values = append(values, node.value)
+	}
+	list.mux.RUnlock()
+	return gobEncodeValues(values)
+}
+
+// GobDecode replaces the contents of the list with values encoded by
+// GobEncode. The list is unchanged if decoding fails.
+func (list *DList[T]) GobDecode(b []byte) error {
+	if list == nil {
+		return nilError("list")
+	}
+	values, err := gobDecodeValues[T](b)
+	if err != nil {
+		return err
+	}
+	if list.mux == nil {
+		list.mux = newOptions(nil).newLocker()
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("GobDecode", start, list.metrics.acquired())
+	defer list.debugCheck()
+	list.head, list.tail, list.length = nil, nil, 0
+	for _, value := range values {
+		node := &DListNode[T]{value: value, prev: list.tail}
+		if list.tail == nil {
+			list.head = node
+		} else {
+			list.tail.next = node
+		}
+		list.tail = node
+		list.length++
+	}
+	return nil
+}
+
+// gobEncodeValues encodes values as a gob slice.
+func gobEncodeValues[T any](values []T) ([]byte, error) {
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(values); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// gobDecodeValues decodes a gob slice written by gobEncodeValues.
+func gobDecodeValues[T any](b []byte) ([]T, error) {
+	var values []T
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
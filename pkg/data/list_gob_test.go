@@ -0,0 +1,48 @@
+package data_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	. "fun/pkg/data"
+	"slices"
+	"testing"
+)
+
+// document embeds lists in a gob-encoded type.
+type document struct {
+	Name  string       // Name of the document.
+	Lines *List[Data]  // Singly-linked values.
+	Marks *DList[Data] // Doubly-linked values.
+}
+
+func Test_Gob(t *testing.T) {
+	original := document{Name: "doc", Lines: NewList[Data](), Marks: NewDList[Data]()}
+	original.Lines.AppendAll(1, 2, 3)
+	original.Marks.Append(7)
+	original.Marks.Append(8)
+
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(original); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	var decoded document
+	if err := gob.NewDecoder(&b).Decode(&decoded); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if decoded.Name != "doc" {
+		t.Error("unexpected name", decoded.Name)
+	}
+	listAssert(t, decoded.Lines, []Data{1, 2, 3})
+	decoded.Lines.Append(4)
+	listAssert(t, decoded.Lines, []Data{1, 2, 3, 4})
+	if values := slices.Collect(decoded.Marks.Reversed()); !slices.Equal(values, []Data{8, 7}) {
+		t.Error("unexpected marks", values)
+	}
+
+	list := NewList[Data]()
+	list.Append(9)
+	if err := list.GobDecode([]byte("not gob")); err == nil {
+		t.Error("expected an error decoding garbage")
+	}
+	listAssert(t, list, []Data{9})
+}
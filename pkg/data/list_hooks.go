@@ -0,0 +1,123 @@
+package data
+
+// listHooks are the callbacks observing mutations of a list. A nil
+// *listHooks calls nothing, so unobserved lists pay only a nil check.
+type listHooks[T ListData] struct {
+	inserted []func(value T) // Called after an element is added.
+	deleted  []func(value T) // Called after an element is removed.
+	deferred bool            // Whether calls are buffered until flush.
+	events   []listEvent[T]  // Buffered calls, in order.
+}
+
+// listEvent is a buffered hook call.
+type listEvent[T ListData] struct {
+	value   T    // Value added or removed.
+	deleted bool // Whether the value was removed.
+}
+
+// OnInsert registers f to be called with each value added to the list by
+// Insert, Append, InsertAt, batches, decoding, and Concat, after it is added.
+// f runs while the lock is held, so it must not use the list.
+func (list *List[T]) OnInsert(f func(value T)) {
+	if list == nil || f == nil {
+		return
+	}
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	if list.hooks == nil {
+		list.hooks = &listHooks[T]{}
+	}
+	list.hooks.inserted = append(list.hooks.inserted, f)
+}
+
+// OnDelete registers f to be called with each value removed from the list
+// by the Delete methods, Dedup, DedupAll, and RemoveAt, and with the values
+// replaced by decoding or moved out by Concat and SplitAt, after they are
+// removed. f runs while the lock is held, so it must not use the list.
+func (list *List[T]) OnDelete(f func(value T)) {
+	if list == nil || f == nil {
+		return
+	}
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	if list.hooks == nil {
+		list.hooks = &listHooks[T]{}
+	}
+	list.hooks.deleted = append(list.hooks.deleted, f)
+}
+
+// insert reports an added value.
+func (hooks *listHooks[T]) insert(value T) {
+	if hooks == nil {
+		return
+	}
+	if hooks.deferred {
+		hooks.events = append(hooks.events, listEvent[T]{value: value})
+		return
+	}
+	for _, f := range hooks.inserted {
+		f(value)
+	}
+}
+
+// delete reports a removed value.
+func (hooks *listHooks[T]) delete(value T) {
+	if hooks == nil {
+		return
+	}
+	if hooks.deferred {
+		hooks.events = append(hooks.events, listEvent[T]{value: value, deleted: true})
+		return
+	}
+	for _, f := range hooks.deleted {
+		f(value)
+	}
+}
+
+// insertAll reports the values of a chain of nodes as added.
+func (hooks *listHooks[T]) insertAll(head *ListNode[T]) {
+	if hooks == nil {
+		return
+	}
+	for node := head; node != nil; node = node.next {
+		hooks.insert(node.value)
+	}
+}
+
+// deleteAll reports the values of a chain of nodes as removed.
+func (hooks *listHooks[T]) deleteAll(head *ListNode[T]) {
+	if hooks == nil {
+		return
+	}
+	for node := head; node != nil; node = node.next {
+		hooks.delete(node.value)
+	}
+}
+
+// deferCalls buffers hook calls until flush.
+func (hooks *listHooks[T]) deferCalls() {
+	if hooks == nil {
+		return
+	}
+	hooks.deferred = true
+}
+
+// flush stops buffering and makes the buffered calls if commit is set, or
+// discards them.
+func (hooks *listHooks[T]) flush(commit bool) {
+	if hooks == nil {
+		return
+	}
+	events := hooks.events
+	hooks.deferred, hooks.events = false, nil
+	if !commit {
+		return
+	}
+	for _, event := range events {
+		if event.deleted {
+			hooks.delete(event.value)
+		} else {
+			hooks.insert(event.value)
+		}
+	}
+}
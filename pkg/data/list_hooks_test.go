@@ -0,0 +1,73 @@
+package data_test
+
+import (
+	"bytes"
+	"fun/pkg/codec"
+	. "fun/pkg/data"
+	"slices"
+	"testing"
+)
+
+func Test_ListHooks(t *testing.T) {
+	list := NewList[Data]()
+	var inserted, deleted []Data
+	list.OnInsert(func(value Data) { inserted = append(inserted, value) })
+	list.OnDelete(func(value Data) { deleted = append(deleted, value) })
+
+	list.Append(2)
+	list.Insert(1)
+	list.InsertAt(2, 3)
+	list.AppendAll(3, 4)
+	if !slices.Equal(inserted, []Data{2, 1, 3, 3, 4}) {
+		t.Error("unexpected inserted values", inserted)
+	}
+
+	list.Delete(9)
+	list.DeleteHead()
+	list.DeleteTail()
+	list.Dedup()
+	list.RemoveAt(0)
+	if !slices.Equal(deleted, []Data{1, 4, 3, 2}) {
+		t.Error("unexpected deleted values", deleted)
+	}
+
+	// A hook may maintain a derived index of the list.
+	counts := map[Data]int{}
+	indexed := NewList[Data]()
+	indexed.OnInsert(func(value Data) { counts[value]++ })
+	indexed.OnDelete(func(value Data) { counts[value]-- })
+	for _, value := range []Data{1, 2, 1, 3} {
+		indexed.Append(value)
+	}
+	indexed.DeleteAll(1)
+	if counts[1] != 0 || counts[2] != 1 || counts[3] != 1 {
+		t.Error("unexpected derived counts", counts)
+	}
+}
+
+func Test_ListHooksMove(t *testing.T) {
+	counts := map[Data]int{}
+	list := NewListOf[Data](1, 2)
+	list.OnInsert(func(value Data) { counts[value]++ })
+	list.OnDelete(func(value Data) { counts[value]-- })
+	var moved []Data
+	other := NewListOf[Data](3, 4)
+	other.OnDelete(func(value Data) { moved = append(moved, value) })
+
+	list.Concat(other)
+	if counts[3] != 1 || counts[4] != 1 || !slices.Equal(moved, []Data{3, 4}) {
+		t.Error("expected Concat to report moved values, got", counts, moved)
+	}
+
+	var buf bytes.Buffer
+	NewListOf[Data](5).EncodeTo(&buf, codec.JSON[Data]())
+	list.DecodeFrom(&buf, codec.JSON[Data]())
+	if counts[5] != 1 || counts[1] != -1 || counts[3] != 0 {
+		t.Error("expected decoding to report replaced values, got", counts)
+	}
+
+	list.SplitAt(0)
+	if counts[5] != 0 {
+		t.Error("expected SplitAt to report moved values, got", counts)
+	}
+}
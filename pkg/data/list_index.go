@@ -0,0 +1,204 @@
+package data
+
+import "fmt"
+
+// Get gets the value at an index, counting from the head.
+func (list *List[T]) Get(i int) (T, bool) {
+	var unset T
+	if list == nil {
+		return unset, false
+	}
+	start := list.metrics.begin()
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	defer list.metrics.end("Get", start, list.metrics.acquired())
+	defer list.tracer.end("List.Get", list.tracer.begin(), list.length)
+	if i < 0 || i >= list.length {
+		return unset, false
+	}
+	return list.nodeAt(i).value, true
+}
+
+// InsertAt adds an element so it is at index i, shifting later elements
+// back. An index equal to the length appends.
+func (list *List[T]) InsertAt(i int, value T) error {
+	if list == nil {
+		return nilError("list")
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("InsertAt", start, list.metrics.acquired())
+	defer list.tracer.end("List.InsertAt", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	switch {
+	case i < 0 || i > list.length:
+		return RangeError{Index: i, Length: list.length + 1}
+	case i == 0:
+		list.insert(value)
+	case i == list.length:
+		list.append(value)
+	default:
+		list.unshare()
+		list.insertAfter(list.nodeAt(i-1), value)
+	}
+	return nil
+}
+
+// RemoveAt deletes the element at index i and returns its value.
+func (list *List[T]) RemoveAt(i int) (T, bool) {
+	var unset T
+	if list == nil {
+		return unset, false
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("RemoveAt", start, list.metrics.acquired())
+	defer list.tracer.end("List.RemoveAt", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	if i < 0 || i >= list.length {
+		return unset, false
+	}
+	if i == 0 {
+		return list.deleteHead()
+	}
+	list.unshare()
+	parent := list.nodeAt(i - 1)
+	removed := parent.next
+	parent.next = removed.next
+	if removed.next != nil {
+		removed.next.prev = parent
+	}
+	if list.tail == removed {
+		list.tail = parent
+	}
+	list.length--
+	value := removed.value
+	list.hooks.delete(value)
+	list.moveCursors(removed, parent)
+	list.releaseNode(removed)
+	return value, true
+}
+
+// Swap exchanges the values at indexes i and j. Nodes keep their positions,
+// so a node reference sees the value now at its index.
+func (list *List[T]) Swap(i, j int) error {
+	if list == nil {
+		return nilError("list")
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("Swap", start, list.metrics.acquired())
+	defer list.tracer.end("List.Swap", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	for _, index := range []int{i, j} {
+		if index < 0 || index >= list.length {
+			return RangeError{Index: index, Length: list.length}
+		}
+	}
+	if i == j {
+		return nil
+	}
+	list.unshare()
+	a, b := list.nodeAt(min(i, j)), list.nodeAt(max(i, j))
+	a.value, b.value = b.value, a.value
+	return nil
+}
+
+// SwapNodes exchanges the values of two nodes of the list, returned by Find,
+// Head, Tail, or AllNodes, in constant time. If the list shares its nodes
+// with a snapshot, the nodes are located by a scan first.
+func (list *List[T]) SwapNodes(a, b *ListNode[T]) error {
+	if list == nil {
+		return nilError("list")
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("SwapNodes", start, list.metrics.acquired())
+	defer list.debugCheck()
+	if a == nil || b == nil {
+		return fmt.Errorf("%w: nil node", ErrNotFound)
+	}
+	if list.shared {
+		i, j := list.indexOf(a), list.indexOf(b)
+		if i < 0 || j < 0 {
+			return fmt.Errorf("%w: node is not in the list", ErrNotFound)
+		}
+		list.unshare()
+		a, b = list.nodeAt(i), list.nodeAt(j)
+	} else if !list.owns(a) || !list.owns(b) {
+		return fmt.Errorf("%w: node is not in the list", ErrNotFound)
+	}
+	a.value, b.value = b.value, a.value
+	return nil
+}
+
+// indexOf finds the index of a node, -1 if it is not in the list.
+func (list *List[T]) indexOf(node *ListNode[T]) int {
+	i := 0
+	for current := list.head; current != nil && i < list.length; current = current.next {
+		if current == node {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+// Middle gets the middle value of the list, the second of the two middle
+// values if the length is even.
+func (list *List[T]) Middle() (T, bool) {
+	var unset T
+	if list == nil {
+		return unset, false
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	defer list.tracer.end("List.Middle", list.tracer.begin(), list.length)
+	if list.head == nil {
+		return unset, false
+	}
+	slow, fast := list.head, list.head
+	for fast != nil && fast.next != nil {
+		slow, fast = slow.next, fast.next.next
+	}
+	return slow.value, true
+}
+
+// NthFromEnd gets the value n places before the tail, so 0 is the tail.
+func (list *List[T]) NthFromEnd(n int) (T, bool) {
+	var unset T
+	if list == nil || n < 0 {
+		return unset, false
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	defer list.tracer.end("List.NthFromEnd", list.tracer.begin(), list.length)
+	lead := list.head
+	for ; n >= 0; n-- {
+		if lead == nil {
+			return unset, false
+		}
+		lead = lead.next
+	}
+	trail := list.head
+	for lead != nil {
+		lead, trail = lead.next, trail.next
+	}
+	return trail.value, true
+}
+
+// nodeAt walks to the node at an index in range, lock must be held.
+func (list *List[T]) nodeAt(i int) *ListNode[T] {
+	if i == list.length-1 {
+		return list.tail
+	}
+	node := list.head
+	for ; i > 0; i-- {
+		node = node.next
+	}
+	return node
+}
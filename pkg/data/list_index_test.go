@@ -0,0 +1,202 @@
+package data_test
+
+import (
+	"errors"
+	. "fun/pkg/data"
+	"testing"
+)
+
+func Test_IndexAccess(t *testing.T) {
+	list := NewList[Data]()
+	if err := list.InsertAt(1, 5); !errors.Is(err, ErrOutOfRange) {
+		t.Error("expected out of range error, got", err)
+	}
+	list.InsertAt(0, 2)
+	list.InsertAt(1, 4)
+	list.InsertAt(0, 1)
+	list.InsertAt(2, 3)
+	listAssert(t, list, []Data{1, 2, 3, 4})
+
+	for i := 0; i < 4; i++ {
+		if value, ok := list.Get(i); !ok || value != Data(i+1) {
+			t.Error("unexpected value at", i, value, ok)
+		}
+	}
+	if _, ok := list.Get(4); ok {
+		t.Error("expected Get past the end to fail")
+	}
+	if _, ok := list.Get(-1); ok {
+		t.Error("expected Get before the start to fail")
+	}
+
+	if value, ok := list.RemoveAt(3); !ok || value != 4 {
+		t.Error("unexpected removed tail", value, ok)
+	}
+	listAssert(t, list, []Data{1, 2, 3})
+	if value, ok := list.RemoveAt(1); !ok || value != 2 {
+		t.Error("unexpected removed value", value, ok)
+	}
+	if value, ok := list.RemoveAt(0); !ok || value != 1 {
+		t.Error("unexpected removed head", value, ok)
+	}
+	if _, ok := list.RemoveAt(1); ok {
+		t.Error("expected RemoveAt past the end to fail")
+	}
+	listAssert(t, list, []Data{3})
+
+	var rangeErr RangeError
+	if err := list.InsertAt(-1, 0); !errors.As(err, &rangeErr) || rangeErr.Index != -1 {
+		t.Error("expected a range error, got", err)
+	}
+}
+
+func Test_MiddleAndNthFromEnd(t *testing.T) {
+	list := NewList[Data]()
+	if _, ok := list.Middle(); ok {
+		t.Error("expected no middle of an empty list")
+	}
+	if _, ok := list.NthFromEnd(0); ok {
+		t.Error("expected no tail of an empty list")
+	}
+	list.AppendAll(1, 2, 3, 4, 5)
+	if value, ok := list.Middle(); !ok || value != 3 {
+		t.Error("unexpected middle", value, ok)
+	}
+	list.Append(6)
+	if value, _ := list.Middle(); value != 4 {
+		t.Error("expected the second middle of an even list, got", value)
+	}
+	for n, expected := range []Data{6, 5, 4, 3, 2, 1} {
+		if value, ok := list.NthFromEnd(n); !ok || value != expected {
+			t.Error("unexpected value", n, "from the end", value, ok)
+		}
+	}
+	if _, ok := list.NthFromEnd(6); ok {
+		t.Error("expected NthFromEnd past the head to fail")
+	}
+	if _, ok := list.NthFromEnd(-1); ok {
+		t.Error("expected a negative NthFromEnd to fail")
+	}
+}
+
+func Test_InsertAfterBefore(t *testing.T) {
+	list := NewListOf[Data](1, 3)
+	if err := list.InsertAfter(list.Find(1), 2); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	list.InsertAfter(list.Tail(), 5)
+	list.InsertBefore(list.Find(5), 4)
+	list.InsertBefore(list.Head(), 0)
+	listAssert(t, list, []Data{0, 1, 2, 3, 4, 5})
+	if err := list.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+
+	snapshot := list.Snapshot()
+	node := list.Find(3)
+	if err := list.InsertAfter(node, 9); err != nil {
+		t.Error("unexpected error inserting into a shared list", err)
+	}
+	listAssert(t, list, []Data{0, 1, 2, 3, 9, 4, 5})
+	listAssert(t, snapshot, []Data{0, 1, 2, 3, 4, 5})
+
+	list.Snapshot()
+	if err := list.InsertAfter(NewListOf[Data](7).Head(), 9); !errors.Is(err, ErrNotFound) {
+		t.Error("expected ErrNotFound for a node of another list, got", err)
+	}
+	if err := list.InsertBefore(nil, 9); !errors.Is(err, ErrNotFound) {
+		t.Error("expected ErrNotFound for a nil node, got", err)
+	}
+}
+
+func Test_InsertAfterForeignNode(t *testing.T) {
+	list, other := NewListOf[Data](1, 2, 3), NewListOf[Data](7, 8)
+	if err := list.InsertAfter(other.Head(), 9); !errors.Is(err, ErrNotFound) {
+		t.Error("expected ErrNotFound for a node of an unshared list, got", err)
+	}
+	tail := list.Tail()
+	list.DeleteTail()
+	if err := list.InsertAfter(tail, 9); !errors.Is(err, ErrNotFound) {
+		t.Error("expected ErrNotFound for a deleted node, got", err)
+	}
+	if err := list.InsertBefore(tail, 9); !errors.Is(err, ErrNotFound) {
+		t.Error("expected ErrNotFound for a deleted node, got", err)
+	}
+	// Nodes follow Concat and SplitAt to the list now holding them.
+	head := other.Head()
+	list.Concat(other)
+	if err := other.InsertAfter(head, 9); !errors.Is(err, ErrNotFound) {
+		t.Error("expected ErrNotFound for a node moved away, got", err)
+	}
+	if err := list.InsertAfter(head, 9); err != nil {
+		t.Error("unexpected error inserting after a moved node", err)
+	}
+	listAssert(t, list, []Data{1, 2, 7, 9, 8})
+	front, back := list.SplitAt(2)
+	if err := list.InsertAfter(head, 0); !errors.Is(err, ErrNotFound) {
+		t.Error("expected ErrNotFound for a node split off, got", err)
+	}
+	if err := front.InsertAfter(head, 0); !errors.Is(err, ErrNotFound) {
+		t.Error("expected ErrNotFound for a node of the back, got", err)
+	}
+	if err := back.InsertBefore(head, 0); err != nil {
+		t.Error("unexpected error inserting before a split node", err)
+	}
+	// A rolled back transaction disowns the nodes it created.
+	var created *ListNode[Data]
+	front.Tx(func(tx *ListTx[Data]) error {
+		tx.Insert(5)
+		created = tx.Head()
+		return errors.New("roll back")
+	})
+	if err := front.InsertAfter(created, 0); !errors.Is(err, ErrNotFound) {
+		t.Error("expected ErrNotFound for a node rolled back, got", err)
+	}
+	for _, l := range []*List[Data]{list, other, front, back} {
+		if err := l.CheckInvariants(); err != nil {
+			t.Error(err)
+		}
+	}
+	listAssert(t, front, []Data{1, 2})
+	listAssert(t, back, []Data{0, 7, 9, 8})
+}
+
+func Test_Swap(t *testing.T) {
+	list := NewListOf[Data](1, 2, 3, 4)
+	if err := list.Swap(0, 3); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	list.Swap(2, 1)
+	list.Swap(1, 1)
+	listAssert(t, list, []Data{4, 3, 2, 1})
+	if err := list.Swap(0, 4); !errors.Is(err, ErrOutOfRange) {
+		t.Error("expected out of range error, got", err)
+	}
+
+	if err := list.SwapNodes(list.Head(), list.Find(2)); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	listAssert(t, list, []Data{2, 3, 4, 1})
+
+	snapshot := list.Snapshot()
+	if err := list.SwapNodes(list.Find(3), list.Tail()); err != nil {
+		t.Fatal("unexpected error swapping shared nodes", err)
+	}
+	listAssert(t, list, []Data{2, 1, 4, 3})
+	listAssert(t, snapshot, []Data{2, 3, 4, 1})
+
+	list.Snapshot()
+	if err := list.SwapNodes(list.Head(), NewListOf[Data](9).Head()); !errors.Is(err, ErrNotFound) {
+		t.Error("expected ErrNotFound for a node of another list, got", err)
+	}
+	if err := list.SwapNodes(nil, list.Head()); !errors.Is(err, ErrNotFound) {
+		t.Error("expected ErrNotFound for a nil node, got", err)
+	}
+
+	a, b := NewListOf[Data](1, 2), NewListOf[Data](3, 4)
+	if err := a.SwapNodes(a.Head(), b.Head()); !errors.Is(err, ErrNotFound) {
+		t.Error("expected ErrNotFound for a node of an unshared list, got", err)
+	}
+	listAssert(t, a, []Data{1, 2})
+	listAssert(t, b, []Data{3, 4})
+}
@@ -0,0 +1,74 @@
+package data
+
+import "iter"
+
+// listIterator walks the nodes of a list, taking the read lock for each step
+// so the list can be changed during iteration.
+type listIterator[T ListData] struct {
+	list    *List[T]     // List being iterated.
+	node    *ListNode[T] // Current node, nil before the first step and once done.
+	value   T            // Value of the current node when Next reached it.
+	started bool         // Whether Next has been called.
+}
+
+// Iterator creates an iterator over the values of the list from head to
+// tail. It is weakly consistent: it never holds the lock between steps, and
+// reflects changes made to the part of the list it has not reached yet.
+func (list *List[T]) Iterator() Iterator[T] {
+	return &listIterator[T]{list: list}
+}
+
+// All gets a sequence of the values of the list from head to tail, with the
+// consistency of Iterator.
+func (list *List[T]) All() iter.Seq[T] {
+	return Seq(list.Iterator())
+}
+
+// AllNodes gets a sequence of the nodes of the list from head to tail, with
+// the consistency of Iterator. The read lock is not held while the loop body
+// runs, so the body may change the list; a node deleted by it still leads to
+// the rest of the chain it was cut from.
+func (list *List[T]) AllNodes() iter.Seq[*ListNode[T]] {
+	return func(yield func(*ListNode[T]) bool) {
+		if list == nil {
+			return
+		}
+		list.mux.RLock()
+		node := list.head
+		list.mux.RUnlock()
+		for node != nil {
+			if !yield(node) {
+				return
+			}
+			list.mux.RLock()
+			node = node.next
+			list.mux.RUnlock()
+		}
+	}
+}
+
+func (it *listIterator[T]) Next() bool {
+	if it.list == nil {
+		return false
+	}
+	it.list.mux.RLock()
+	defer it.list.mux.RUnlock()
+	if !it.started {
+		it.started = true
+		it.node = it.list.head
+	} else if it.node != nil {
+		it.node = it.node.next
+	}
+	if it.node == nil {
+		var unset T
+		it.value = unset
+		return false
+	}
+	// Copy the value under the lock, since Swap may change it later.
+	it.value = it.node.value
+	return true
+}
+
+func (it *listIterator[T]) Value() T {
+	return it.value
+}
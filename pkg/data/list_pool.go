@@ -0,0 +1,42 @@
+package data
+
+// newNode creates a node, reusing a released one if pooling is enabled.
+func (list *List[T]) newNode(value T, prev, next *ListNode[T]) *ListNode[T] {
+	var node *ListNode[T]
+	if list.nodes != nil {
+		node, _ = list.nodes.Get().(*ListNode[T])
+	}
+	if node == nil {
+		node = list.allocNode()
+	}
+	if list.owner == nil {
+		list.disown()
+	}
+	node.value, node.prev, node.next, node.owner = value, prev, next, list.owner
+	return node
+}
+
+// allocNode allocates an empty node from the arena, if there is one.
+func (list *List[T]) allocNode() *ListNode[T] {
+	if list.arena == nil {
+		return &ListNode[T]{}
+	}
+	return list.arena.Alloc()
+}
+
+// releaseNode detaches a deleted node from the list and returns it to the
+// pool, clearing it so it does not keep its value or successors alive. Nodes
+// shared with a snapshot are left to the garbage collector, and disowned when
+// the list unshares.
+func (list *List[T]) releaseNode(node *ListNode[T]) {
+	if list.shared {
+		return
+	}
+	node.owner = nil
+	if list.nodes == nil {
+		return
+	}
+	var unset T
+	node.value, node.prev, node.next = unset, nil, nil
+	list.nodes.Put(node)
+}
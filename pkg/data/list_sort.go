@@ -0,0 +1,129 @@
+package data
+
+import "math/rand"
+
+// Sort orders the list by less in place. It is a stable bottom-up merge sort
+// of the nodes, taking O(n log n) time and no extra memory.
+func (list *List[T]) Sort(less func(a, b T) bool) {
+	if list == nil {
+		return
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("Sort", start, list.metrics.acquired())
+	defer list.tracer.end("List.Sort", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	list.sort(less)
+}
+
+// Shuffle randomly permutes the list in place with a Fisher-Yates shuffle of
+// its nodes, drawing from r, or from the default source if r is nil.
+func (list *List[T]) Shuffle(r *rand.Rand) {
+	if list == nil {
+		return
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("Shuffle", start, list.metrics.acquired())
+	defer list.tracer.end("List.Shuffle", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	if list.length < 2 {
+		return
+	}
+	list.unshare()
+	nodes := make([]*ListNode[T], 0, list.length)
+	for node := list.head; node != nil; node = node.next {
+		nodes = append(nodes, node)
+	}
+	swap := func(i, j int) { nodes[i], nodes[j] = nodes[j], nodes[i] }
+	if r == nil {
+		rand.Shuffle(len(nodes), swap)
+	} else {
+		r.Shuffle(len(nodes), swap)
+	}
+	for i := 1; i < len(nodes); i++ {
+		nodes[i-1].next = nodes[i]
+	}
+	nodes[len(nodes)-1].next = nil
+	list.head, list.tail = nodes[0], nodes[len(nodes)-1]
+	list.relink()
+}
+
+// sort merges runs of doubling width until one run remains, lock must be
+// held. If less panics, the merged and unmerged chains are joined back
+// together, so every node stays in the list, before the panic continues.
+func (list *List[T]) sort(less func(a, b T) bool) {
+	list.unshare()
+	// head and tail are the merged part of the pass, left and right the runs
+	// being merged, and rest the runs after them.
+	var head, tail, left, right, rest *ListNode[T]
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			list.head, list.tail = head, tail
+			for _, chain := range []*ListNode[T]{left, right, rest} {
+				list.join(chain)
+			}
+			list.relink()
+			panic(recovered)
+		}
+	}()
+	for width := 1; width < list.length; width *= 2 {
+		head, tail, rest = nil, nil, list.head
+		for rest != nil {
+			left = rest
+			right = splitAfter(left, width)
+			rest = splitAfter(right, width)
+			// Take from left on ties, so the sort is stable.
+			for left != nil && right != nil {
+				node := left
+				if less(right.value, left.value) {
+					node, right = right, right.next
+				} else {
+					left = left.next
+				}
+				if tail == nil {
+					head = node
+				} else {
+					tail.next = node
+				}
+				tail = node
+			}
+			list.head, list.tail = head, tail
+			list.join(left)
+			list.join(right)
+			head, tail, left, right = list.head, list.tail, nil, nil
+		}
+	}
+	list.relink()
+}
+
+// join appends a nil terminated chain to the nodes from the head to the tail,
+// ending it at the tail, lock must be held.
+func (list *List[T]) join(chain *ListNode[T]) {
+	if list.tail != nil {
+		list.tail.next = chain
+	}
+	if chain == nil {
+		return
+	}
+	if list.head == nil {
+		list.head = chain
+	}
+	for list.tail = chain; list.tail.next != nil; list.tail = list.tail.next {
+	}
+}
+
+// splitAfter cuts a chain after n nodes and returns the remainder.
+func splitAfter[T ListData](node *ListNode[T], n int) *ListNode[T] {
+	for ; node != nil && n > 1; n-- {
+		node = node.next
+	}
+	if node == nil {
+		return nil
+	}
+	rest := node.next
+	node.next = nil
+	return rest
+}
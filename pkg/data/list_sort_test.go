@@ -0,0 +1,103 @@
+package data_test
+
+import (
+	. "fun/pkg/data"
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+// pair sorts by Key and keeps Order to check stability.
+type pair struct {
+	Key   int // Sort key.
+	Order int // Position before sorting.
+}
+
+// String converts a pair to a string.
+func (p pair) String() string {
+	return string(rune('a'+p.Key)) + string(rune('0'+p.Order%10))
+}
+
+func Test_Sort(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, length := range []int{0, 1, 2, 3, 7, 8, 100, 257} {
+		list := NewList[Data]()
+		values := make([]Data, length)
+		for i := range values {
+			values[i] = Data(random.Intn(50))
+			list.Append(values[i])
+		}
+		list.Sort(func(a, b Data) bool { return a < b })
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		listAssert(t, list, values)
+		if err := list.CheckInvariants(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func Test_SortPanic(t *testing.T) {
+	for calls := range 7 {
+		list := NewListOf[Data](5, 4, 3, 2, 1, 0)
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected the panic of less to continue")
+				}
+			}()
+			n := 0
+			list.Sort(func(a, b Data) bool {
+				if n++; n > calls {
+					panic("less")
+				}
+				return a < b
+			})
+		}()
+		if err := list.CheckInvariants(); err != nil {
+			t.Error("after", calls, "calls:", err)
+		}
+		values := list.View().Values()
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		if !slices.Equal(values, []Data{0, 1, 2, 3, 4, 5}) {
+			t.Error("after", calls, "calls: expected every value to stay in the list, got", list)
+		}
+	}
+}
+
+func Test_SortStable(t *testing.T) {
+	list := NewList[pair]()
+	var values []pair
+	for i := 0; i < 40; i++ {
+		value := pair{i % 3, i}
+		values = append(values, value)
+		list.Append(value)
+	}
+	list.Sort(func(a, b pair) bool { return a.Key < b.Key })
+	sort.SliceStable(values, func(i, j int) bool { return values[i].Key < values[j].Key })
+	listAssert(t, list, values)
+}
+
+func Test_Shuffle(t *testing.T) {
+	list := NewListOf[Data](1, 2, 3, 4, 5, 6, 7, 8)
+	list.Shuffle(rand.New(rand.NewSource(1)))
+	if err := list.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+	values := list.View().Values()
+	if sort.SliceIsSorted(values, func(i, j int) bool { return values[i] < values[j] }) {
+		t.Error("expected shuffled values", values)
+	}
+	list.Sort(func(a, b Data) bool { return a < b })
+	listAssert(t, list, []Data{1, 2, 3, 4, 5, 6, 7, 8})
+
+	again := NewListOf[Data](1, 2, 3, 4, 5, 6, 7, 8)
+	again.Shuffle(rand.New(rand.NewSource(1)))
+	if again.String() != NewListOf(values...).String() {
+		t.Error("expected the same seed to give the same order", again.String())
+	}
+
+	single := NewListOf[Data](1)
+	single.Shuffle(nil)
+	listAssert(t, single, []Data{1})
+}
@@ -0,0 +1,67 @@
+package data
+
+// Take creates a new list of the first n elements, or all of them if there
+// are fewer. The new list has the options of the list.
+func (list *List[T]) Take(n int) *List[T] {
+	return list.slice("Take", func(length int) (int, int) {
+		return 0, max(0, min(n, length))
+	})
+}
+
+// Drop creates a new list of the elements after the first n, empty if there
+// are fewer. The new list has the options of the list.
+func (list *List[T]) Drop(n int) *List[T] {
+	return list.slice("Drop", func(length int) (int, int) {
+		return max(0, min(n, length)), length
+	})
+}
+
+// TakeWhile creates a new list of the leading elements that satisfy keep,
+// stopping at the first that does not. The new list has the options of the
+// list. keep runs while the lock is held, so it must not use the list.
+func (list *List[T]) TakeWhile(keep func(T) bool) *List[T] {
+	return list.slice("TakeWhile", func(int) (int, int) {
+		return 0, list.prefix(keep)
+	})
+}
+
+// DropWhile creates a new list of the elements from the first that does not
+// satisfy skip. The new list has the options of the list. skip runs while
+// the lock is held, so it must not use the list.
+func (list *List[T]) DropWhile(skip func(T) bool) *List[T] {
+	return list.slice("DropWhile", func(length int) (int, int) {
+		return list.prefix(skip), length
+	})
+}
+
+// slice copies the elements in [from, to) of the bounds computed from the
+// length into a new list, holding the read lock.
+func (list *List[T]) slice(name string, bounds func(length int) (from, to int)) *List[T] {
+	if list == nil {
+		return NewList[T]()
+	}
+	start := list.metrics.begin()
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	defer list.metrics.end(name, start, list.metrics.acquired())
+	defer list.tracer.end("List."+name, list.tracer.begin(), list.length)
+	from, to := bounds(list.length)
+	sliced := list.newLike()
+	node := list.head
+	for i := 0; i < to; i++ {
+		if i >= from {
+			sliced.append(node.value)
+		}
+		node = node.next
+	}
+	return sliced
+}
+
+// prefix counts the leading elements that satisfy match, lock must be held.
+func (list *List[T]) prefix(match func(T) bool) int {
+	n := 0
+	for node := list.head; node != nil && match(node.value); node = node.next {
+		n++
+	}
+	return n
+}
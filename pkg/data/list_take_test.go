@@ -0,0 +1,33 @@
+package data_test
+
+import (
+	. "fun/pkg/data"
+	"testing"
+)
+
+func Test_TakeDrop(t *testing.T) {
+	list := NewListOf[Data](1, 2, 3, 4, 5)
+	listAssert(t, list.Take(2), []Data{1, 2})
+	listAssert(t, list.Take(9), []Data{1, 2, 3, 4, 5})
+	listAssert(t, list.Take(-1), nil)
+	listAssert(t, list.Drop(2), []Data{3, 4, 5})
+	listAssert(t, list.Drop(9), nil)
+	listAssert(t, list.Drop(0), []Data{1, 2, 3, 4, 5})
+
+	small := func(value Data) bool { return value < 3 }
+	listAssert(t, list.TakeWhile(small), []Data{1, 2})
+	listAssert(t, list.DropWhile(small), []Data{3, 4, 5})
+	listAssert(t, list.TakeWhile(func(Data) bool { return false }), nil)
+	listAssert(t, list.DropWhile(func(Data) bool { return true }), nil)
+	listAssert(t, list, []Data{1, 2, 3, 4, 5})
+
+	taken := list.Take(2)
+	taken.Append(9)
+	listAssert(t, list, []Data{1, 2, 3, 4, 5})
+	if err := taken.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+
+	var unset *List[Data]
+	listAssert(t, unset.Drop(1), nil)
+}
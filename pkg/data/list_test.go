@@ -2,6 +2,7 @@ package data_test
 
 import (
 	. "fun/pkg/data"
+	"slices"
 	"strconv"
 	"sync"
 	"testing"
@@ -58,6 +59,37 @@ func Test_New(t *testing.T) {
 	listAssert(t, list, nil)
 }
 
+func Test_NewListOf(t *testing.T) {
+	listAssert(t, NewListOf[Data](), nil)
+	listAssert(t, NewListOf[Data](1, 2, 3), []Data{1, 2, 3})
+	if err := NewListOf[Data](3, 2, 1).CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_PlainValues(t *testing.T) {
+	numbers := NewList[int]()
+	numbers.Append(1)
+	numbers.Append(2)
+	if numbers.String() != "Length: 2, Data: 1 2" {
+		t.Error("unexpected int list", numbers.String())
+	}
+
+	type point struct{ X, Y int }
+	points := NewList[point]()
+	points.Append(point{1, 2})
+	if points.Find(point{1, 2}) == nil || points.String() != "Length: 1, Data: {1 2}" {
+		t.Error("unexpected struct list", points.String())
+	}
+
+	words := NewList[string]()
+	words.Insert("b")
+	words.Insert("a")
+	if words.View().String() != "Length: 2, Data: a b" {
+		t.Error("unexpected string list", words.View().String())
+	}
+}
+
 func Test_Insert(t *testing.T) {
 	list := NewList[Data]()
 	values := []Data{10}
@@ -70,11 +102,7 @@ func Test_Insert(t *testing.T) {
 }
 
 func Test_Delete(t *testing.T) {
-	list := NewList[Data]()
-	list.Insert(4)
-	list.Insert(3)
-	list.Insert(2)
-	list.Insert(1)
+	list := NewListOf[Data](1, 2, 3, 4)
 
 	before := []Data{1, 2, 3, 4}
 	listAssert(t, list, before)
@@ -154,3 +182,119 @@ func Test_Concurrency(t *testing.T) {
 
 	listAssert(t, list, listData)
 }
+
+func Test_ForEach(t *testing.T) {
+	list := NewListOf[Data](1, 2, 3, 4)
+	var visited []Data
+	list.ForEach(func(value Data) { visited = append(visited, value) })
+	if !slices.Equal(visited, []Data{1, 2, 3, 4}) {
+		t.Error("expected ForEach to visit every value in order", visited)
+	}
+
+	visited = nil
+	list.ForEachUntil(func(value Data) bool {
+		visited = append(visited, value)
+		return value < 2
+	})
+	if !slices.Equal(visited, []Data{1, 2}) {
+		t.Error("expected ForEachUntil to stop when f returns false", visited)
+	}
+
+	var unset *List[Data]
+	unset.ForEach(func(Data) { t.Error("expected no values in a nil list") })
+}
+
+func Test_Reverse(t *testing.T) {
+	list := NewList[Data]()
+	list.Reverse()
+	listAssert(t, list, nil)
+
+	list.Append(1)
+	list.Reverse()
+	listAssert(t, list, []Data{1})
+
+	list.Append(2)
+	list.Append(3)
+	list.Reverse()
+	listAssert(t, list, []Data{3, 2, 1})
+	list.Append(0)
+	listAssert(t, list, []Data{3, 2, 1, 0})
+}
+
+func Test_DeleteAll(t *testing.T) {
+	list := NewList[Data]()
+	list.AppendAll(1, 2, 1, 3, 1, 1)
+	if removed := list.DeleteAll(1); removed != 4 {
+		t.Error("expected to remove 4 values, removed", removed)
+	}
+	listAssert(t, list, []Data{2, 3})
+	if removed := list.DeleteAll(4); removed != 0 {
+		t.Error("expected to remove nothing, removed", removed)
+	}
+	list.DeleteAll(3)
+	list.Append(5)
+	listAssert(t, list, []Data{2, 5})
+	list.DeleteAll(2)
+	list.DeleteAll(5)
+	listAssert(t, list, nil)
+}
+
+func Test_Rotate(t *testing.T) {
+	list := NewList[Data]()
+	list.RotateLeft(3)
+	listAssert(t, list, nil)
+
+	list.AppendAll(1, 2, 3, 4, 5)
+	list.RotateLeft(2)
+	listAssert(t, list, []Data{3, 4, 5, 1, 2})
+	list.RotateRight(2)
+	listAssert(t, list, []Data{1, 2, 3, 4, 5})
+	list.RotateLeft(7)
+	listAssert(t, list, []Data{3, 4, 5, 1, 2})
+	list.RotateRight(-3)
+	listAssert(t, list, []Data{1, 2, 3, 4, 5})
+	list.RotateLeft(5)
+	listAssert(t, list, []Data{1, 2, 3, 4, 5})
+	list.RotateRight(1)
+	list.Append(6)
+	listAssert(t, list, []Data{5, 1, 2, 3, 4, 6})
+}
+
+func Test_Dedup(t *testing.T) {
+	list := NewList[Data]()
+	if list.Dedup() != 0 || list.DedupAll() != 0 {
+		t.Error("expected nothing to remove from an empty list")
+	}
+	list.AppendAll(1, 1, 2, 2, 2, 1, 3, 3)
+	if removed := list.Dedup(); removed != 4 {
+		t.Error("expected to remove 4 adjacent duplicates, removed", removed)
+	}
+	listAssert(t, list, []Data{1, 2, 1, 3})
+	if removed := list.DedupAll(); removed != 1 {
+		t.Error("expected to remove 1 duplicate, removed", removed)
+	}
+	listAssert(t, list, []Data{1, 2, 3})
+
+	list.AppendAll(3, 2, 1, 1)
+	list.DedupAll()
+	listAssert(t, list, []Data{1, 2, 3})
+}
+
+func Test_ReverseInGroups(t *testing.T) {
+	list := NewList[Data]()
+	list.ReverseInGroups(2)
+	listAssert(t, list, nil)
+
+	list.AppendAll(1, 2, 3, 4, 5, 6, 7)
+	list.ReverseInGroups(3)
+	listAssert(t, list, []Data{3, 2, 1, 6, 5, 4, 7})
+	list.ReverseInGroups(1)
+	listAssert(t, list, []Data{3, 2, 1, 6, 5, 4, 7})
+	list.ReverseInGroups(7)
+	listAssert(t, list, []Data{7, 4, 5, 6, 1, 2, 3})
+	list.DeleteTail()
+	list.ReverseInGroups(2)
+	listAssert(t, list, []Data{4, 7, 6, 5, 2, 1})
+	list.Append(8)
+	listAssert(t, list, []Data{4, 7, 6, 5, 2, 1, 8})
+}
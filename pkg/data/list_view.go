@@ -0,0 +1,93 @@
+package data
+
+import (
+	"fmt"
+	"iter"
+	"unsafe"
+)
+
+// ListView is an immutable copy of the contents of a List.
+type ListView[T ListData] struct {
+	values []T // Values in list order.
+}
+
+// View captures an immutable view of the list.
+func (list *List[T]) View() *ListView[T] {
+	if list == nil {
+		return &ListView[T]{}
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	defer list.tracer.end("List.View", list.tracer.begin(), list.length)
+	return list.view()
+}
+
+// view copies the list values, read lock must be held.
+func (list *List[T]) view() *ListView[T] {
+	values := make([]T, 0, list.length)
+	for currentNode := list.head; currentNode != nil; currentNode = currentNode.next {
+		values = append(values, currentNode.value)
+	}
+	return &ListView[T]{values}
+}
+
+func (list *List[T]) snapshotKey() uintptr {
+	return lockKey(unsafe.Pointer(list))
+}
+
+func (list *List[T]) snapshotLock() {
+	if list != nil {
+		list.mux.RLock()
+	}
+}
+
+func (list *List[T]) snapshotUnlock() {
+	if list != nil {
+		list.mux.RUnlock()
+	}
+}
+
+func (list *List[T]) snapshot() any {
+	if list == nil {
+		return &ListView[T]{}
+	}
+	return list.view()
+}
+
+// Length reports the number of elements in the view.
+func (view *ListView[T]) Length() int {
+	return len(view.values)
+}
+
+// At gets the value at an index of the view.
+func (view *ListView[T]) At(i int) (T, bool) {
+	var unset T
+	if i < 0 || i >= len(view.values) {
+		return unset, false
+	}
+	return view.values[i], true
+}
+
+// Values copies the values of the view into a slice.
+func (view *ListView[T]) Values() []T {
+	return append([]T(nil), view.values...)
+}
+
+// String converts ListView data into a string.
+func (view *ListView[T]) String() string {
+	s := fmt.Sprintf("Length: %d, Data:", len(view.values))
+	for _, v := range view.values {
+		s += " " + fmt.Sprint(v)
+	}
+	return s
+}
+
+// Iterator creates an iterator over the values of the view.
+func (view *ListView[T]) Iterator() Iterator[T] {
+	return FromSlice(view.values)
+}
+
+// All gets a sequence of the values of the view.
+func (view *ListView[T]) All() iter.Seq[T] {
+	return Seq(view.Iterator())
+}
@@ -0,0 +1,162 @@
+package data
+
+import (
+	"fmt"
+	"fun/pkg/constraints"
+	"iter"
+	"sync/atomic"
+)
+
+// lockFreeNode is a node of a LockFreeList.
+type lockFreeNode[T constraints.Ordered] struct {
+	value T                               // Value stored in the node.
+	next  atomic.Pointer[lockFreeLink[T]] // Successor and deletion mark.
+}
+
+// lockFreeLink is an immutable successor pointer with the deletion mark of
+// the node it belongs to, so both change in one compare-and-swap.
+type lockFreeLink[T constraints.Ordered] struct {
+	node   *lockFreeNode[T] // Next node, nil at the end.
+	marked bool             // Whether the owning node is logically deleted.
+}
+
+// LockFreeList is a sorted set of values that concurrent goroutines change
+// with compare-and-swap instead of a lock, using Harris's algorithm: Delete
+// marks a node before unlinking it, and any operation that meets a marked
+// node unlinks it. Contains never retries.
+type LockFreeList[T constraints.Ordered] struct {
+	head     lockFreeNode[T]         // Sentinel before the first node.
+	length   atomic.Int64            // Number of values, exact when the list is quiescent.
+	comparer constraints.Comparer[T] // Order of the values, equivalent values are the same.
+}
+
+// Create a new lock-free list ordered by <, or configured by WithComparator.
+func NewLockFreeList[T constraints.Ordered](opts ...Option) *LockFreeList[T] {
+	list := &LockFreeList[T]{comparer: comparerOr(newOptions(opts), constraints.OrderedComparer[T]())}
+	list.head.next.Store(&lockFreeLink[T]{})
+	return list
+}
+
+// Length reports the number of values in the list.
+func (list *LockFreeList[T]) Length() int {
+	if list == nil {
+		return 0
+	}
+	return int(list.length.Load())
+}
+
+// search finds the last node before value and the first node at or after
+// it, unlinking marked nodes on the way. The returned link is the observed
+// unmarked successor pointer of pred.
+func (list *LockFreeList[T]) search(value T) (pred *lockFreeNode[T], predLink *lockFreeLink[T], curr *lockFreeNode[T]) {
+retry:
+	pred = &list.head
+	predLink = pred.next.Load()
+	curr = predLink.node
+	for curr != nil {
+		currLink := curr.next.Load()
+		if currLink.marked {
+			unlinked := &lockFreeLink[T]{node: currLink.node}
+			if !pred.next.CompareAndSwap(predLink, unlinked) {
+				goto retry
+			}
+			predLink, curr = unlinked, currLink.node
+			continue
+		}
+		if list.comparer.Compare(curr.value, value) >= 0 {
+			return pred, predLink, curr
+		}
+		pred, predLink, curr = curr, currLink, currLink.node
+	}
+	return pred, predLink, nil
+}
+
+// Insert adds a value to the list, reporting false if it is already present.
+func (list *LockFreeList[T]) Insert(value T) bool {
+	if list == nil {
+		return false
+	}
+	for {
+		pred, predLink, curr := list.search(value)
+		if curr != nil && list.comparer.Compare(curr.value, value) == 0 {
+			return false
+		}
+		node := &lockFreeNode[T]{value: value}
+		node.next.Store(&lockFreeLink[T]{node: curr})
+		if pred.next.CompareAndSwap(predLink, &lockFreeLink[T]{node: node}) {
+			list.length.Add(1)
+			return true
+		}
+	}
+}
+
+// Delete removes a value from the list, reporting whether it was present.
+func (list *LockFreeList[T]) Delete(value T) bool {
+	if list == nil {
+		return false
+	}
+	for {
+		pred, predLink, curr := list.search(value)
+		if curr == nil || list.comparer.Compare(curr.value, value) != 0 {
+			return false
+		}
+		currLink := curr.next.Load()
+		if currLink.marked {
+			continue
+		}
+		// Marking the node is the deletion; unlinking it is cleanup that a
+		// later search does if this one loses a race.
+		if !curr.next.CompareAndSwap(currLink, &lockFreeLink[T]{node: currLink.node, marked: true}) {
+			continue
+		}
+		list.length.Add(-1)
+		pred.next.CompareAndSwap(predLink, &lockFreeLink[T]{node: currLink.node})
+		return true
+	}
+}
+
+// Contains reports whether a value is in the list.
+func (list *LockFreeList[T]) Contains(value T) bool {
+	if list == nil {
+		return false
+	}
+	curr := list.head.next.Load().node
+	for curr != nil && list.comparer.Compare(curr.value, value) < 0 {
+		curr = curr.next.Load().node
+	}
+	return curr != nil && list.comparer.Compare(curr.value, value) == 0 && !curr.next.Load().marked
+}
+
+// All gets a sequence of the values in ascending order. It is weakly
+// consistent: values inserted or deleted during iteration may or may not be
+// seen.
+func (list *LockFreeList[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if list == nil {
+			return
+		}
+		for curr := list.head.next.Load().node; curr != nil; {
+			link := curr.next.Load()
+			if !link.marked && !yield(curr.value) {
+				return
+			}
+			curr = link.node
+		}
+	}
+}
+
+// String converts LockFreeList data into a string.
+func (list *LockFreeList[T]) String() string {
+	if list == nil {
+		return ""
+	}
+	values := make([]T, 0, list.Length())
+	for value := range list.All() {
+		values = append(values, value)
+	}
+	s := fmt.Sprintf("Length: %d, Data:", len(values))
+	for _, value := range values {
+		s += fmt.Sprintf(" %v", value)
+	}
+	return s
+}
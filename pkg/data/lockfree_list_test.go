@@ -0,0 +1,74 @@
+package data_test
+
+import (
+	. "fun/pkg/data"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func Test_LockFreeList(t *testing.T) {
+	list := NewLockFreeList[int]()
+	for _, value := range []int{3, 1, 2} {
+		if !list.Insert(value) {
+			t.Error("expected to insert", value)
+		}
+	}
+	if list.Insert(2) {
+		t.Error("expected a duplicate insert to fail")
+	}
+	if list.String() != "Length: 3, Data: 1 2 3" {
+		t.Error("unexpected list", list.String())
+	}
+	if !list.Contains(2) || list.Contains(4) {
+		t.Error("unexpected membership")
+	}
+	if !list.Delete(2) || list.Delete(2) || list.Contains(2) {
+		t.Error("expected to delete 2 once")
+	}
+	if values := slices.Collect(list.All()); !slices.Equal(values, []int{1, 3}) || list.Length() != 2 {
+		t.Error("unexpected values", values)
+	}
+}
+
+func Test_LockFreeListStress(t *testing.T) {
+	const workers = 8
+	const perWorker = 500
+	list := NewLockFreeList[int]()
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			// Each worker owns the values congruent to w, and races the
+			// others through the shared links.
+			for i := 0; i < perWorker; i++ {
+				list.Insert(i*workers + w)
+			}
+			for i := 0; i < perWorker; i += 2 {
+				if !list.Delete(i*workers + w) {
+					t.Error("failed to delete", i*workers+w)
+				}
+			}
+			for i := 1; i < perWorker; i += 2 {
+				if !list.Contains(i*workers + w) {
+					t.Error("missing", i*workers+w)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	values := slices.Collect(list.All())
+	if len(values) != workers*perWorker/2 || list.Length() != len(values) {
+		t.Fatal("expected", workers*perWorker/2, "values, got", len(values), list.Length())
+	}
+	if !slices.IsSorted(values) {
+		t.Error("expected sorted values")
+	}
+	for _, value := range values {
+		if (value/workers)%2 == 0 {
+			t.Error("deleted value survived", value)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package data
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// Sizer estimates the heap bytes a value references beyond its own size, for
+// example the backing array of a string or slice.
+type Sizer[T any] func(value T) uintptr
+
+// MemStats is the approximate memory used by a structure.
+type MemStats struct {
+	Nodes         int     // Number of allocated nodes.
+	NodeBytes     uintptr // Bytes used by nodes, including values stored inline.
+	PayloadBytes  uintptr // Bytes referenced by values, as reported by a Sizer.
+	OverheadBytes uintptr // Bytes used by the structure itself and its lock.
+}
+
+// Total reports the approximate number of heap bytes used.
+func (stats MemStats) Total() uintptr {
+	return stats.NodeBytes + stats.PayloadBytes + stats.OverheadBytes
+}
+
+// SizeOf reports the approximate heap bytes used by the list, not counting
+// memory referenced by the values.
+func (list *List[T]) SizeOf() uintptr {
+	return list.MemStats(nil).Total()
+}
+
+// MemStats reports the approximate memory used by the list. The sizer is
+// called for each value to estimate referenced memory and may be nil.
+func (list *List[T]) MemStats(sizer Sizer[T]) MemStats {
+	if list == nil {
+		return MemStats{}
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	stats := MemStats{
+		Nodes:         list.length,
+		NodeBytes:     uintptr(list.length) * unsafe.Sizeof(ListNode[T]{}),
+		OverheadBytes: unsafe.Sizeof(*list),
+	}
+	if _, ok := list.mux.(*sync.RWMutex); ok {
+		stats.OverheadBytes += unsafe.Sizeof(sync.RWMutex{})
+	}
+	if sizer != nil {
+		for currentNode := list.head; currentNode != nil; currentNode = currentNode.next {
+			stats.PayloadBytes += sizer(currentNode.value)
+		}
+	}
+	return stats
+}
@@ -0,0 +1,48 @@
+package data_test
+
+import (
+	. "fun/pkg/data"
+	"testing"
+	"unsafe"
+)
+
+func Test_MemStats(t *testing.T) {
+	empty := NewList[Data]()
+	overhead := empty.SizeOf()
+	if overhead == 0 {
+		t.Error("expected list overhead to be non-zero")
+	}
+
+	list := NewList[Data]()
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+
+	nodeSize := unsafe.Sizeof(Data(0)) + 3*unsafe.Sizeof(uintptr(0))
+	stats := list.MemStats(nil)
+	if stats.Nodes != 3 {
+		t.Error("expected 3 nodes, got", stats.Nodes)
+	}
+	if stats.NodeBytes != 3*nodeSize {
+		t.Error("expected node bytes", 3*nodeSize, "got", stats.NodeBytes)
+	}
+	if stats.PayloadBytes != 0 {
+		t.Error("expected no payload bytes without a sizer, got", stats.PayloadBytes)
+	}
+	if list.SizeOf() != overhead+3*nodeSize {
+		t.Error("expected size", overhead+3*nodeSize, "got", list.SizeOf())
+	}
+
+	stats = list.MemStats(func(value Data) uintptr { return uintptr(value) * 10 })
+	if stats.PayloadBytes != 60 {
+		t.Error("expected payload bytes 60, got", stats.PayloadBytes)
+	}
+	if stats.Total() != stats.NodeBytes+stats.PayloadBytes+stats.OverheadBytes {
+		t.Error("total does not add up", stats)
+	}
+
+	var nilList *List[Data]
+	if nilList.SizeOf() != 0 {
+		t.Error("expected nil list size 0")
+	}
+}
@@ -0,0 +1,191 @@
+package data
+
+import (
+	"expvar"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the upper bounds of the latency histogram buckets, the
+// last bucket counts everything slower.
+var latencyBuckets = []time.Duration{
+	100 * time.Nanosecond,
+	time.Microsecond,
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// operationMetrics accumulates measurements of one operation.
+type operationMetrics struct {
+	count    uint64        // Number of calls.
+	latency  time.Duration // Total time spent in calls, including lock wait.
+	lockWait time.Duration // Total time spent waiting for the lock.
+	max      time.Duration // Slowest call.
+	buckets  []uint64      // Latency histogram, one more than latencyBuckets.
+}
+
+// Metrics records operation counts, latency histograms, and lock wait time
+// for the structures it is attached to. A nil *Metrics records nothing, so
+// uninstrumented structures pay only a nil check.
+type Metrics struct {
+	operations map[string]*operationMetrics // Measurements by operation name.
+	since      time.Time                    // When recording started or was reset.
+	mux        *sync.Mutex                  // Lock the measurements.
+}
+
+// Stats is a copy of the measurements recorded by Metrics.
+type Stats struct {
+	Since      time.Time                 // When recording started or was reset.
+	Operations map[string]OperationStats // Measurements by operation name.
+}
+
+// OperationStats are the measurements of one operation.
+type OperationStats struct {
+	Count     uint64            // Number of calls.
+	Latency   time.Duration     // Total time spent in calls, including lock wait.
+	LockWait  time.Duration     // Total time spent waiting for the lock.
+	Max       time.Duration     // Slowest call.
+	Histogram []HistogramBucket // Latency histogram.
+}
+
+// HistogramBucket counts calls at most as slow as its upper bound, and slower
+// than the previous bucket. The last bucket has an upper bound of 0 and
+// counts every slower call.
+type HistogramBucket struct {
+	UpperBound time.Duration // Slowest latency counted, 0 for unbounded.
+	Count      uint64        // Number of calls in the bucket.
+}
+
+// Create new metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{operations: map[string]*operationMetrics{}, since: time.Now(), mux: &sync.Mutex{}}
+}
+
+// Stats copies the current measurements.
+func (metrics *Metrics) Stats() Stats {
+	if metrics == nil {
+		return Stats{Operations: map[string]OperationStats{}}
+	}
+	metrics.mux.Lock()
+	defer metrics.mux.Unlock()
+	stats := Stats{Since: metrics.since, Operations: make(map[string]OperationStats, len(metrics.operations))}
+	for name, operation := range metrics.operations {
+		histogram := make([]HistogramBucket, len(operation.buckets))
+		for i, count := range operation.buckets {
+			histogram[i].Count = count
+			if i < len(latencyBuckets) {
+				histogram[i].UpperBound = latencyBuckets[i]
+			}
+		}
+		stats.Operations[name] = OperationStats{
+			Count:     operation.count,
+			Latency:   operation.latency,
+			LockWait:  operation.lockWait,
+			Max:       operation.max,
+			Histogram: histogram,
+		}
+	}
+	return stats
+}
+
+// Publish exposes the measurements as an expvar variable holding Stats. Like
+// expvar.Publish, it panics if name is already in use.
+func (metrics *Metrics) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return metrics.Stats()
+	}))
+}
+
+// Reset clears all measurements.
+func (metrics *Metrics) Reset() {
+	if metrics == nil {
+		return
+	}
+	metrics.mux.Lock()
+	defer metrics.mux.Unlock()
+	metrics.operations = map[string]*operationMetrics{}
+	metrics.since = time.Now()
+}
+
+// Names lists the operations with measurements, sorted.
+func (stats Stats) Names() []string {
+	names := make([]string, 0, len(stats.Operations))
+	for name := range stats.Operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Mean reports the average latency of a call.
+func (stats OperationStats) Mean() time.Duration {
+	if stats.Count == 0 {
+		return 0
+	}
+	return stats.Latency / time.Duration(stats.Count)
+}
+
+// begin starts timing an operation, before its lock is requested.
+func (metrics *Metrics) begin() time.Time {
+	if metrics == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// acquired notes when the lock of an operation was granted.
+func (metrics *Metrics) acquired() time.Time {
+	if metrics == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// end records an operation started at start whose lock was acquired at
+// acquired.
+func (metrics *Metrics) end(name string, start time.Time, acquired time.Time) {
+	if metrics == nil {
+		return
+	}
+	latency := time.Since(start)
+	lockWait := acquired.Sub(start)
+
+	metrics.mux.Lock()
+	defer metrics.mux.Unlock()
+	operation, ok := metrics.operations[name]
+	if !ok {
+		operation = &operationMetrics{buckets: make([]uint64, len(latencyBuckets)+1)}
+		metrics.operations[name] = operation
+	}
+	operation.count++
+	operation.latency += latency
+	operation.lockWait += lockWait
+	if latency > operation.max {
+		operation.max = latency
+	}
+	bucket := sort.Search(len(latencyBuckets), func(i int) bool {
+		return latency <= latencyBuckets[i]
+	})
+	operation.buckets[bucket]++
+}
+
+// Collect reports the call count, total latency, and total lock wait of each
+// operation, labeled by operation name.
+func (metrics *Metrics) Collect(emit func(Sample)) {
+	stats := metrics.Stats()
+	for _, name := range stats.Names() {
+		operation := stats.Operations[name]
+		labels := map[string]string{"operation": name}
+		emit(Sample{Name: "operations_total", Help: "Number of calls.", Kind: CounterSample, Labels: labels,
+			Value: float64(operation.Count)})
+		emit(Sample{Name: "latency_seconds_total", Help: "Time spent in calls.", Kind: CounterSample, Labels: labels,
+			Value: operation.Latency.Seconds()})
+		emit(Sample{Name: "lock_wait_seconds_total", Help: "Time spent waiting for locks.", Kind: CounterSample, Labels: labels,
+			Value: operation.LockWait.Seconds()})
+	}
+}
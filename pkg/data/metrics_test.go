@@ -0,0 +1,117 @@
+package data_test
+
+import (
+	"expvar"
+	. "fun/pkg/data"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_Metrics(t *testing.T) {
+	metrics := NewMetrics()
+	list := NewList[Data]()
+	list.SetMetrics(metrics)
+	list.Append(1)
+	list.Append(2)
+	list.Insert(0)
+	list.Find(2)
+	list.Delete(1)
+	list.AppendAll(3, 4)
+
+	stats := metrics.Stats()
+	expected := map[string]uint64{"Append": 2, "Insert": 1, "Find": 1, "Delete": 1, "AppendAll": 1}
+	if len(stats.Operations) != len(expected) {
+		t.Error("expected operations", expected, "got", stats.Names())
+	}
+	for name, count := range expected {
+		operation := stats.Operations[name]
+		if operation.Count != count {
+			t.Error("expected", name, "count", count, "got", operation.Count)
+		}
+		var histogramCount uint64
+		for _, bucket := range operation.Histogram {
+			histogramCount += bucket.Count
+		}
+		if histogramCount != count {
+			t.Error("expected", name, "histogram to count", count, "got", histogramCount)
+		}
+		if operation.LockWait > operation.Latency || operation.Max > operation.Latency {
+			t.Errorf("inconsistent %s latency %+v", name, operation)
+		}
+		if operation.Mean()*time.Duration(count) > operation.Latency {
+			t.Error("expected mean not to exceed total latency", name)
+		}
+	}
+	if names := stats.Names(); names[0] != "Append" || names[len(names)-1] != "Insert" {
+		t.Error("expected sorted names, got", names)
+	}
+	histogram := stats.Operations["Append"].Histogram
+	if histogram[len(histogram)-1].UpperBound != 0 || histogram[0].UpperBound == 0 {
+		t.Error("expected bounded buckets and a final unbounded bucket, got", histogram)
+	}
+
+	metrics.Reset()
+	if len(metrics.Stats().Operations) != 0 {
+		t.Error("expected reset to clear operations")
+	}
+	list.SetMetrics(nil)
+	list.Append(5)
+	if len(metrics.Stats().Operations) != 0 {
+		t.Error("expected detached metrics to record nothing")
+	}
+
+	var nilMetrics *Metrics
+	nilMetrics.Reset()
+	if len(nilMetrics.Stats().Operations) != 0 {
+		t.Error("expected nil metrics to have no operations")
+	}
+}
+
+func Test_MetricsConcurrency(t *testing.T) {
+	const threads = 10
+	const iterations = 100
+
+	metrics := NewMetrics()
+	list := NewList[Data]()
+	list.SetMetrics(metrics)
+	var wg sync.WaitGroup
+	wg.Add(threads)
+	for i := 0; i < threads; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				list.Append(Data(j))
+			}
+		}()
+	}
+	wg.Wait()
+	if count := metrics.Stats().Operations["Append"].Count; count != threads*iterations {
+		t.Error("expected", threads*iterations, "appends, got", count)
+	}
+}
+
+func Test_MetricsPublish(t *testing.T) {
+	metrics := NewMetrics()
+	list := NewList[Data](WithMetrics(metrics))
+	list.Append(1)
+	list.Find(1)
+	if stats := list.Stats(); stats.Operations["Append"].Count != 1 || stats.Operations["Find"].Count != 1 {
+		t.Error("unexpected list stats", stats.Names())
+	}
+	if len(NewList[Data]().Stats().Operations) != 0 {
+		t.Error("expected a list without metrics to have no operations")
+	}
+
+	expvarRuns++
+	name := "test_metrics_" + strconv.Itoa(expvarRuns)
+	metrics.Publish(name)
+	value := expvar.Get(name).String()
+	for _, expected := range []string{`"Append":{"Count":1`, `"Find":{"Count":1`, `"LockWait":`} {
+		if !strings.Contains(value, expected) {
+			t.Errorf("expected %s in %s", expected, value)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package data
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+)
+
+// MinMaxHeap is a double-ended priority queue ordered by a less function:
+// PopMin removes the least element and PopMax the greatest, both in
+// O(log n) time. It is a binary heap whose even levels, starting at the
+// root, hold elements no greater than their descendants, and whose odd
+// levels hold elements no less than their descendants.
+type MinMaxHeap[T any] struct {
+	values  []T               // Heap, alternating min and max levels.
+	less    func(a, b T) bool // Order of the elements.
+	metrics *Metrics          // Instrumentation, nil when disabled.
+	mux     locker            // Lock read and write operations.
+}
+
+// Create a new min-max heap ordered by less, configured by WithLocking and
+// WithMetrics.
+func NewMinMaxHeap[T any](less func(a, b T) bool, opts ...Option) *MinMaxHeap[T] {
+	return NewMinMaxHeapFrom(less, nil, opts...)
+}
+
+// Create a new min-max heap of values ordered by less, heapifying them in
+// O(n) time. The heap takes ownership of the slice.
+func NewMinMaxHeapFrom[T any](less func(a, b T) bool, values []T, opts ...Option) *MinMaxHeap[T] {
+	settings := newOptions(opts)
+	heap := &MinMaxHeap[T]{values: values, less: less, metrics: settings.metrics, mux: settings.newLocker()}
+	for i := len(values)/2 - 1; i >= 0; i-- {
+		heap.down(i)
+	}
+	return heap
+}
+
+// Length reports the number of elements in the heap.
+func (heap *MinMaxHeap[T]) Length() int {
+	if heap == nil {
+		return 0
+	}
+	heap.mux.RLock()
+	defer heap.mux.RUnlock()
+	return len(heap.values)
+}
+
+// Push adds an element.
+func (heap *MinMaxHeap[T]) Push(value T) error {
+	if heap == nil {
+		return nilError("min-max heap")
+	}
+	start := heap.metrics.begin()
+	heap.mux.Lock()
+	defer heap.mux.Unlock()
+	defer heap.metrics.end("Push", start, heap.metrics.acquired())
+	defer heap.debugCheck()
+	heap.values = append(heap.values, value)
+	heap.up(len(heap.values) - 1)
+	return nil
+}
+
+// PopMin removes the least element.
+func (heap *MinMaxHeap[T]) PopMin() (T, bool) {
+	var unset T
+	if heap == nil {
+		return unset, false
+	}
+	start := heap.metrics.begin()
+	heap.mux.Lock()
+	defer heap.mux.Unlock()
+	defer heap.metrics.end("PopMin", start, heap.metrics.acquired())
+	defer heap.debugCheck()
+	if len(heap.values) == 0 {
+		return unset, false
+	}
+	return heap.remove(0), true
+}
+
+// PopMax removes the greatest element.
+func (heap *MinMaxHeap[T]) PopMax() (T, bool) {
+	var unset T
+	if heap == nil {
+		return unset, false
+	}
+	start := heap.metrics.begin()
+	heap.mux.Lock()
+	defer heap.mux.Unlock()
+	defer heap.metrics.end("PopMax", start, heap.metrics.acquired())
+	defer heap.debugCheck()
+	if len(heap.values) == 0 {
+		return unset, false
+	}
+	return heap.remove(heap.maxIndex()), true
+}
+
+// PeekMin gets the least element without removing it.
+func (heap *MinMaxHeap[T]) PeekMin() (T, bool) {
+	var unset T
+	if heap == nil {
+		return unset, false
+	}
+	heap.mux.RLock()
+	defer heap.mux.RUnlock()
+	if len(heap.values) == 0 {
+		return unset, false
+	}
+	return heap.values[0], true
+}
+
+// PeekMax gets the greatest element without removing it.
+func (heap *MinMaxHeap[T]) PeekMax() (T, bool) {
+	var unset T
+	if heap == nil {
+		return unset, false
+	}
+	heap.mux.RLock()
+	defer heap.mux.RUnlock()
+	if len(heap.values) == 0 {
+		return unset, false
+	}
+	return heap.values[heap.maxIndex()], true
+}
+
+// maxIndex finds the greatest element, the root or one of its children, in
+// a heap that is not empty, lock must be held.
+func (heap *MinMaxHeap[T]) maxIndex() int {
+	switch len(heap.values) {
+	case 1:
+		return 0
+	case 2:
+		return 1
+	}
+	if heap.less(heap.values[1], heap.values[2]) {
+		return 2
+	}
+	return 1
+}
+
+// remove replaces the element at i, a root or a child of the root, with the
+// last element and restores the order, lock must be held.
+func (heap *MinMaxHeap[T]) remove(i int) T {
+	var unset T
+	last := len(heap.values) - 1
+	value := heap.values[i]
+	heap.values[i] = heap.values[last]
+	heap.values[last] = unset
+	heap.values = heap.values[:last]
+	if i < last {
+		heap.down(i)
+	}
+	return value
+}
+
+// minLevel reports whether i is on a min level, an even depth from the root.
+func minLevel(i int) bool {
+	return (bits.Len(uint(i+1))-1)%2 == 0
+}
+
+// before reports whether a belongs above b on a min level, or on a max level
+// when min is false.
+func (heap *MinMaxHeap[T]) before(min bool, a, b T) bool {
+	if min {
+		return heap.less(a, b)
+	}
+	return heap.less(b, a)
+}
+
+// up moves the element at i toward the root, first to the kind of level it
+// belongs on and then between grandparents, lock must be held.
+func (heap *MinMaxHeap[T]) up(i int) {
+	if i == 0 {
+		return
+	}
+	min := minLevel(i)
+	if parent := (i - 1) / 2; heap.before(!min, heap.values[i], heap.values[parent]) {
+		heap.values[i], heap.values[parent] = heap.values[parent], heap.values[i]
+		i, min = parent, !min
+	}
+	for i > 2 {
+		grandparent := ((i-1)/2 - 1) / 2
+		if !heap.before(min, heap.values[i], heap.values[grandparent]) {
+			return
+		}
+		heap.values[i], heap.values[grandparent] = heap.values[grandparent], heap.values[i]
+		i = grandparent
+	}
+}
+
+// down moves the element at i toward the leaves, between grandchildren and
+// then to the kind of level it belongs on, lock must be held.
+func (heap *MinMaxHeap[T]) down(i int) {
+	min := minLevel(i)
+	for {
+		// Find the first among the children and grandchildren.
+		first := -1
+		for _, child := range [2]int{2*i + 1, 2*i + 2} {
+			for _, j := range [3]int{child, 2*child + 1, 2*child + 2} {
+				if j < len(heap.values) && (first < 0 || heap.before(min, heap.values[j], heap.values[first])) {
+					first = j
+				}
+			}
+		}
+		if first < 0 || !heap.before(min, heap.values[first], heap.values[i]) {
+			return
+		}
+		heap.values[i], heap.values[first] = heap.values[first], heap.values[i]
+		if first <= 2*i+2 {
+			return
+		}
+		if parent := (first - 1) / 2; heap.before(!min, heap.values[first], heap.values[parent]) {
+			heap.values[first], heap.values[parent] = heap.values[parent], heap.values[first]
+		}
+		i = first
+	}
+}
+
+// Values copies the elements into a slice in heap order, which starts with
+// the least element but is otherwise unsorted.
+func (heap *MinMaxHeap[T]) Values() []T {
+	if heap == nil {
+		return nil
+	}
+	heap.mux.RLock()
+	defer heap.mux.RUnlock()
+	return append([]T(nil), heap.values...)
+}
+
+// All gets a sequence of the elements as of the call, in heap order.
+func (heap *MinMaxHeap[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range heap.Values() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// String converts MinMaxHeap data into a string, in heap order.
+func (heap *MinMaxHeap[T]) String() string {
+	if heap == nil {
+		return ""
+	}
+	values := heap.Values()
+	s := fmt.Sprintf("Length: %d, Data:", len(values))
+	for _, v := range values {
+		s += " " + fmt.Sprint(v)
+	}
+	return s
+}
+
+// CheckInvariants verifies that no element is less than an ancestor on a
+// min level, or greater than an ancestor on a max level.
+func (heap *MinMaxHeap[T]) CheckInvariants() error {
+	if heap == nil {
+		return nil
+	}
+	heap.mux.RLock()
+	defer heap.mux.RUnlock()
+	return heap.checkInvariants()
+}
+
+// checkInvariants verifies the heap, lock must be held.
+func (heap *MinMaxHeap[T]) checkInvariants() error {
+	for i := 1; i < len(heap.values); i++ {
+		for ancestor := (i - 1) / 2; ; ancestor = (ancestor - 1) / 2 {
+			if min := minLevel(ancestor); heap.before(min, heap.values[i], heap.values[ancestor]) {
+				return fmt.Errorf("min-max heap: element %d is out of order with its ancestor %d", i, ancestor)
+			}
+			if ancestor == 0 {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// debugCheck panics if the heap invariants are violated in debug builds,
+// lock must be held.
+func (heap *MinMaxHeap[T]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := heap.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,120 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func Test_MinMaxHeap(t *testing.T) {
+	heap := NewMinMaxHeap(func(a, b int) bool { return a < b })
+	if _, ok := heap.PeekMax(); ok {
+		t.Error("expected no greatest element of an empty heap")
+	}
+	for _, value := range []int{5, 1, 4, 1, 3, 9, 2} {
+		heap.Push(value)
+	}
+	if least, _ := heap.PeekMin(); least != 1 {
+		t.Error("unexpected least element", least)
+	}
+	if greatest, _ := heap.PeekMax(); greatest != 9 {
+		t.Error("unexpected greatest element", greatest)
+	}
+	var popped []int
+	for heap.Length() > 1 {
+		least, _ := heap.PopMin()
+		greatest, _ := heap.PopMax()
+		popped = append(popped, least, greatest)
+	}
+	if last, ok := heap.PopMax(); ok {
+		popped = append(popped, last)
+	}
+	if !slices.Equal(popped, []int{1, 9, 1, 5, 2, 4, 3}) {
+		t.Error("expected elements from both ends", popped)
+	}
+
+	random := rand.New(rand.NewSource(1))
+	heapified := NewMinMaxHeapFrom(func(a, b int) bool { return a < b }, random.Perm(100))
+	if err := heapified.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+	for expected := 99; expected >= 50; expected-- {
+		if value, _ := heapified.PopMax(); value != expected {
+			t.Fatal("expected", expected, "got", value)
+		}
+	}
+
+	var unset *MinMaxHeap[int]
+	if err := unset.Push(1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
+
+// Example of keeping the k greatest of a stream, evicting the least.
+func Test_MinMaxHeapTopK(t *testing.T) {
+	const k = 3
+	top := NewMinMaxHeap(func(a, b int) bool { return a < b })
+	for _, value := range []int{7, 2, 8, 1, 9, 3, 6} {
+		top.Push(value)
+		if top.Length() > k {
+			top.PopMin()
+		}
+	}
+	if least, _ := top.PeekMin(); least != 7 || top.Length() != k {
+		t.Error("unexpected top elements", top)
+	}
+}
+
+// minMaxHeapMachine checks a MinMaxHeap against a sorted slice model.
+var minMaxHeapMachine = datatest.Machine[*MinMaxHeap[int], *[]int]{
+	NewSystem: func() *MinMaxHeap[int] { return NewMinMaxHeap(func(a, b int) bool { return a < b }) },
+	NewModel:  func() *[]int { return &[]int{} },
+	Commands: []datatest.Command[*MinMaxHeap[int], *[]int]{
+		{Name: "Push", Run: func(heap *MinMaxHeap[int], model *[]int, arg int) error {
+			i, _ := slices.BinarySearch(*model, arg)
+			*model = slices.Insert(*model, i, arg)
+			return heap.Push(arg)
+		}},
+		{Name: "PopMin", Run: func(heap *MinMaxHeap[int], model *[]int, arg int) error {
+			value, ok := heap.PopMin()
+			if ok != (len(*model) > 0) {
+				return fmt.Errorf("PopMin returned %t", ok)
+			}
+			if ok {
+				if value != (*model)[0] {
+					return fmt.Errorf("PopMin returned %d, expected %d", value, (*model)[0])
+				}
+				*model = (*model)[1:]
+			}
+			return nil
+		}},
+		{Name: "PopMax", Run: func(heap *MinMaxHeap[int], model *[]int, arg int) error {
+			value, ok := heap.PopMax()
+			if ok != (len(*model) > 0) {
+				return fmt.Errorf("PopMax returned %t", ok)
+			}
+			if ok {
+				last := len(*model) - 1
+				if value != (*model)[last] {
+					return fmt.Errorf("PopMax returned %d, expected %d", value, (*model)[last])
+				}
+				*model = (*model)[:last]
+			}
+			return nil
+		}},
+	},
+	Check: func(heap *MinMaxHeap[int], model *[]int) error {
+		if heap.Length() != len(*model) {
+			return fmt.Errorf("expected length %d, got %d", len(*model), heap.Length())
+		}
+		return nil
+	},
+}
+
+func Test_MinMaxHeapModel(t *testing.T) {
+	minMaxHeapMachine.Test(t, datatest.Config{Runs: 200, Length: 100})
+}
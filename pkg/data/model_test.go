@@ -0,0 +1,235 @@
+package data_test
+
+import (
+	"fmt"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// listMachine checks List against a slice model.
+var listMachine = datatest.Machine[*List[Data], *[]Data]{
+	NewSystem: func() *List[Data] { return NewList[Data]() },
+	NewModel:  func() *[]Data { return &[]Data{} },
+	ArgRange:  8,
+	Commands: []datatest.Command[*List[Data], *[]Data]{
+		{Name: "Insert", Run: func(list *List[Data], model *[]Data, arg int) error {
+			*model = append([]Data{Data(arg)}, *model...)
+			return list.Insert(Data(arg))
+		}},
+		{Name: "Append", Run: func(list *List[Data], model *[]Data, arg int) error {
+			*model = append(*model, Data(arg))
+			return list.Append(Data(arg))
+		}},
+		{Name: "Delete", Run: func(list *List[Data], model *[]Data, arg int) error {
+			expected := false
+			for i, value := range *model {
+				if value == Data(arg) {
+					*model = append((*model)[:i], (*model)[i+1:]...)
+					expected = true
+					break
+				}
+			}
+			if deleted := list.Delete(Data(arg)); deleted != expected {
+				return fmt.Errorf("Delete returned %t", deleted)
+			}
+			return nil
+		}},
+		{Name: "DeleteHead", Run: func(list *List[Data], model *[]Data, arg int) error {
+			value, ok := list.DeleteHead()
+			if ok != (len(*model) > 0) {
+				return fmt.Errorf("DeleteHead returned %t", ok)
+			}
+			if ok {
+				if value != (*model)[0] {
+					return fmt.Errorf("DeleteHead returned %d, expected %d", value, (*model)[0])
+				}
+				*model = (*model)[1:]
+			}
+			return nil
+		}},
+		{Name: "DeleteTail", Run: func(list *List[Data], model *[]Data, arg int) error {
+			value, ok := list.DeleteTail()
+			if ok != (len(*model) > 0) {
+				return fmt.Errorf("DeleteTail returned %t", ok)
+			}
+			if ok {
+				last := len(*model) - 1
+				if value != (*model)[last] {
+					return fmt.Errorf("DeleteTail returned %d, expected %d", value, (*model)[last])
+				}
+				*model = (*model)[:last]
+			}
+			return nil
+		}},
+		{Name: "Find", Run: func(list *List[Data], model *[]Data, arg int) error {
+			expected := false
+			for _, value := range *model {
+				expected = expected || value == Data(arg)
+			}
+			if found := list.Find(Data(arg)); (found != nil) != expected {
+				return fmt.Errorf("Find returned %v", found)
+			}
+			return nil
+		}},
+	},
+	Check: func(list *List[Data], model *[]Data) error {
+		if values := list.View().Values(); !slices.Equal(values, *model) {
+			return fmt.Errorf("expected %v, got %v", *model, values)
+		}
+		return nil
+	},
+}
+
+// queueMachine checks a bounded BlockingQueue against a slice model.
+var queueMachine = datatest.Machine[*BlockingQueue[int], *[]int]{
+	NewSystem: func() *BlockingQueue[int] { return NewBlockingQueue[int](4) },
+	NewModel:  func() *[]int { return &[]int{} },
+	Commands: []datatest.Command[*BlockingQueue[int], *[]int]{
+		{Name: "TryPut", Run: func(queue *BlockingQueue[int], model *[]int, arg int) error {
+			expected := len(*model) < 4
+			if expected {
+				*model = append(*model, arg)
+			}
+			if ok := queue.TryPut(arg); ok != expected {
+				return fmt.Errorf("TryPut returned %t", ok)
+			}
+			return nil
+		}},
+		{Name: "TryTake", Run: func(queue *BlockingQueue[int], model *[]int, arg int) error {
+			value, ok := queue.TryTake()
+			if ok != (len(*model) > 0) {
+				return fmt.Errorf("TryTake returned %t", ok)
+			}
+			if ok {
+				if value != (*model)[0] {
+					return fmt.Errorf("TryTake returned %d, expected %d", value, (*model)[0])
+				}
+				*model = (*model)[1:]
+			}
+			return nil
+		}},
+	},
+	Check: func(queue *BlockingQueue[int], model *[]int) error {
+		if queue.Length() != len(*model) {
+			return fmt.Errorf("expected length %d, got %d", len(*model), queue.Length())
+		}
+		return nil
+	},
+}
+
+// semaphoreMachine checks a Semaphore against a count of held weight.
+var semaphoreMachine = datatest.Machine[*Semaphore, *int64]{
+	NewSystem: func() *Semaphore { return NewSemaphore(10) },
+	NewModel:  func() *int64 { return new(int64) },
+	ArgRange:  6,
+	Commands: []datatest.Command[*Semaphore, *int64]{
+		{Name: "TryAcquire", Run: func(semaphore *Semaphore, held *int64, arg int) error {
+			expected := *held+int64(arg) <= 10
+			if expected {
+				*held += int64(arg)
+			}
+			if ok := semaphore.TryAcquire(int64(arg)); ok != expected {
+				return fmt.Errorf("TryAcquire returned %t", ok)
+			}
+			return nil
+		}},
+		{Name: "Release", Run: func(semaphore *Semaphore, held *int64, arg int) error {
+			if int64(arg) <= *held {
+				*held -= int64(arg)
+				semaphore.Release(int64(arg))
+			}
+			return nil
+		}},
+	},
+	Check: func(semaphore *Semaphore, held *int64) error {
+		if semaphore.Available() != 10-*held {
+			return fmt.Errorf("expected %d available, got %d", 10-*held, semaphore.Available())
+		}
+		return nil
+	},
+}
+
+func Test_Models(t *testing.T) {
+	listMachine.Test(t, datatest.Config{Runs: 200})
+	queueMachine.Test(t, datatest.Config{Runs: 200})
+	semaphoreMachine.Test(t, datatest.Config{Runs: 200})
+}
+
+// queueInput is an operation on a FIFO: a put of Value, or a take.
+type queueInput struct {
+	Put   bool // Whether the operation is a put.
+	Value int  // Value put.
+}
+
+// queueOutput is the result of a FIFO operation.
+type queueOutput struct {
+	Value int  // Value taken.
+	OK    bool // Whether the put or take succeeded.
+}
+
+// fifoModel is the sequential specification of a FIFO bounded by capacity,
+// or unbounded if capacity is 0.
+func fifoModel(capacity int) datatest.Model[[]int, queueInput, queueOutput] {
+	return datatest.Model[[]int, queueInput, queueOutput]{
+		Init: func() []int { return nil },
+		Step: func(state []int, input queueInput) ([]int, queueOutput) {
+			if input.Put {
+				if capacity > 0 && len(state) >= capacity {
+					return state, queueOutput{}
+				}
+				return append(append([]int(nil), state...), input.Value), queueOutput{OK: true}
+			}
+			if len(state) == 0 {
+				return state, queueOutput{}
+			}
+			return state[1:], queueOutput{state[0], true}
+		},
+		Equal: func(a, b queueOutput) bool { return a == b },
+	}
+}
+
+// recordFIFO runs concurrent puts and takes and returns the history.
+func recordFIFO(run func(queueInput) queueOutput) []datatest.Operation[queueInput, queueOutput] {
+	recorder := datatest.NewRecorder[queueInput, queueOutput]()
+	var wg sync.WaitGroup
+	for client := 0; client < 3; client++ {
+		wg.Add(1)
+		go func(client int) {
+			defer wg.Done()
+			for i := 0; i < 6; i++ {
+				recorder.Record(client, queueInput{Put: i%2 == 0, Value: client*10 + i}, run)
+			}
+		}(client)
+	}
+	wg.Wait()
+	return recorder.History()
+}
+
+func Test_Linearizable(t *testing.T) {
+	list := NewList[Data]()
+	history := recordFIFO(func(input queueInput) queueOutput {
+		if input.Put {
+			return queueOutput{OK: list.Append(Data(input.Value)) == nil}
+		}
+		value, ok := list.DeleteHead()
+		return queueOutput{int(value), ok}
+	})
+	if !datatest.Linearizable(fifoModel(0), history) {
+		t.Error("expected List Append and DeleteHead to be linearizable", history)
+	}
+
+	queue := NewBlockingQueue[int](2)
+	history = recordFIFO(func(input queueInput) queueOutput {
+		if input.Put {
+			return queueOutput{OK: queue.TryPut(input.Value)}
+		}
+		value, ok := queue.TryTake()
+		return queueOutput{value, ok}
+	})
+	if !datatest.Linearizable(fifoModel(2), history) {
+		t.Error("expected BlockingQueue TryPut and TryTake to be linearizable", history)
+	}
+}
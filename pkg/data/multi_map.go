@@ -0,0 +1,225 @@
+package data
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// MultiMap maps each key to a list of values in the order they were added,
+// which may repeat. A key is present while it has at least one value.
+type MultiMap[K, V comparable] struct {
+	values  map[K][]V // Values of each key, never empty.
+	length  int       // Number of values stored in the map.
+	metrics *Metrics  // Instrumentation, nil when disabled.
+	mux     locker    // Lock read and write operations.
+}
+
+// Create a new multimap, configured by WithLocking and WithMetrics.
+func NewMultiMap[K, V comparable](opts ...Option) *MultiMap[K, V] {
+	settings := newOptions(opts)
+	return &MultiMap[K, V]{values: map[K][]V{}, metrics: settings.metrics, mux: settings.newLocker()}
+}
+
+// Length reports the number of values in the map, counting each value of
+// each key.
+func (multi *MultiMap[K, V]) Length() int {
+	if multi == nil {
+		return 0
+	}
+	multi.mux.RLock()
+	defer multi.mux.RUnlock()
+	return multi.length
+}
+
+// KeyCount reports the number of keys with values.
+func (multi *MultiMap[K, V]) KeyCount() int {
+	if multi == nil {
+		return 0
+	}
+	multi.mux.RLock()
+	defer multi.mux.RUnlock()
+	return len(multi.values)
+}
+
+// Add appends values to those of a key.
+func (multi *MultiMap[K, V]) Add(key K, values ...V) error {
+	if multi == nil {
+		return nilError("multimap")
+	}
+	start := multi.metrics.begin()
+	multi.mux.Lock()
+	defer multi.mux.Unlock()
+	defer multi.metrics.end("Add", start, multi.metrics.acquired())
+	defer multi.debugCheck()
+	if len(values) > 0 {
+		multi.values[key] = append(multi.values[key], values...)
+		multi.length += len(values)
+	}
+	return nil
+}
+
+// Get copies the values of a key into a slice, in the order they were
+// added, or nil if the key has none.
+func (multi *MultiMap[K, V]) Get(key K) []V {
+	if multi == nil {
+		return nil
+	}
+	multi.mux.RLock()
+	defer multi.mux.RUnlock()
+	return slices.Clone(multi.values[key])
+}
+
+// Count reports the number of values of a key.
+func (multi *MultiMap[K, V]) Count(key K) int {
+	if multi == nil {
+		return 0
+	}
+	multi.mux.RLock()
+	defer multi.mux.RUnlock()
+	return len(multi.values[key])
+}
+
+// Contains reports whether a key has any values.
+func (multi *MultiMap[K, V]) Contains(key K) bool {
+	return multi.Count(key) > 0
+}
+
+// ContainsValue reports whether a value is one of those of a key.
+func (multi *MultiMap[K, V]) ContainsValue(key K, value V) bool {
+	if multi == nil {
+		return false
+	}
+	multi.mux.RLock()
+	defer multi.mux.RUnlock()
+	return slices.Contains(multi.values[key], value)
+}
+
+// RemoveValue removes the first value of a key equal to value, reporting
+// whether there was one.
+func (multi *MultiMap[K, V]) RemoveValue(key K, value V) bool {
+	if multi == nil {
+		return false
+	}
+	start := multi.metrics.begin()
+	multi.mux.Lock()
+	defer multi.mux.Unlock()
+	defer multi.metrics.end("RemoveValue", start, multi.metrics.acquired())
+	defer multi.debugCheck()
+	values := multi.values[key]
+	i := slices.Index(values, value)
+	if i < 0 {
+		return false
+	}
+	if len(values) == 1 {
+		delete(multi.values, key)
+	} else {
+		multi.values[key] = slices.Delete(values, i, i+1)
+	}
+	multi.length--
+	return true
+}
+
+// Remove removes a key and all its values, returning the number removed.
+func (multi *MultiMap[K, V]) Remove(key K) int {
+	if multi == nil {
+		return 0
+	}
+	start := multi.metrics.begin()
+	multi.mux.Lock()
+	defer multi.mux.Unlock()
+	defer multi.metrics.end("Remove", start, multi.metrics.acquired())
+	defer multi.debugCheck()
+	count := len(multi.values[key])
+	delete(multi.values, key)
+	multi.length -= count
+	return count
+}
+
+// Keys copies the keys with values into a slice, in no particular order.
+func (multi *MultiMap[K, V]) Keys() []K {
+	if multi == nil {
+		return nil
+	}
+	multi.mux.RLock()
+	defer multi.mux.RUnlock()
+	keys := make([]K, 0, len(multi.values))
+	for key := range multi.values {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// All gets a sequence of each key and value as of the call, a key once for
+// each of its values. The keys are in no particular order, and the values
+// of a key are in the order they were added.
+func (multi *MultiMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if multi == nil {
+			return
+		}
+		multi.mux.RLock()
+		entries := make(map[K][]V, len(multi.values))
+		for key, values := range multi.values {
+			entries[key] = slices.Clone(values)
+		}
+		multi.mux.RUnlock()
+		for key, values := range entries {
+			for _, value := range values {
+				if !yield(key, value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// String converts MultiMap data into a string of key:value pairs, in no
+// particular order of the keys.
+func (multi *MultiMap[K, V]) String() string {
+	if multi == nil {
+		return ""
+	}
+	s := fmt.Sprintf("Length: %d, Data:", multi.Length())
+	for key, value := range multi.All() {
+		s += fmt.Sprintf(" %v:%v", key, value)
+	}
+	return s
+}
+
+// CheckInvariants verifies that no key is stored without values and that
+// the length is the number of values.
+func (multi *MultiMap[K, V]) CheckInvariants() error {
+	if multi == nil {
+		return nil
+	}
+	multi.mux.RLock()
+	defer multi.mux.RUnlock()
+	return multi.checkInvariants()
+}
+
+// checkInvariants verifies the multimap, lock must be held.
+func (multi *MultiMap[K, V]) checkInvariants() error {
+	count := 0
+	for key, values := range multi.values {
+		if len(values) == 0 {
+			return fmt.Errorf("multimap: key %v has no values", key)
+		}
+		count += len(values)
+	}
+	if count != multi.length {
+		return fmt.Errorf("multimap: length %d, counted %d values", multi.length, count)
+	}
+	return nil
+}
+
+// debugCheck panics if the multimap invariants are violated in debug builds,
+// lock must be held.
+func (multi *MultiMap[K, V]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := multi.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,91 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"slices"
+	"testing"
+)
+
+func Test_MultiMap(t *testing.T) {
+	multi := NewMultiMap[string, int]()
+	multi.Add("a", 1, 2)
+	multi.Add("b", 3)
+	multi.Add("a", 1)
+	if values := multi.Get("a"); !slices.Equal(values, []int{1, 2, 1}) {
+		t.Error("unexpected values", values)
+	}
+	if multi.Length() != 4 || multi.KeyCount() != 2 || multi.Count("a") != 3 {
+		t.Error("unexpected counts", multi.Length(), multi.KeyCount(), multi.Count("a"))
+	}
+	if !multi.RemoveValue("a", 1) || !slices.Equal(multi.Get("a"), []int{2, 1}) {
+		t.Error("expected to remove the first 1", multi.Get("a"))
+	}
+	if multi.RemoveValue("a", 3) || !multi.ContainsValue("b", 3) {
+		t.Error("expected 3 to be a value of b only")
+	}
+	if !multi.RemoveValue("b", 3) || multi.Contains("b") || multi.Get("b") != nil {
+		t.Error("expected b to go with its last value")
+	}
+	if multi.String() != "Length: 2, Data: a:2 a:1" {
+		t.Error("unexpected map", multi.String())
+	}
+	if multi.Remove("a") != 2 || multi.Length() != 0 {
+		t.Error("expected to remove a", multi)
+	}
+
+	var unset *MultiMap[string, int]
+	if err := unset.Add("a", 1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
+
+// multiMapMachine checks a MultiMap against a map model of value slices.
+var multiMapMachine = datatest.Machine[*MultiMap[int, int], map[int][]int]{
+	NewSystem: func() *MultiMap[int, int] { return NewMultiMap[int, int](WithLocking(false)) },
+	NewModel:  func() map[int][]int { return map[int][]int{} },
+	Commands: []datatest.Command[*MultiMap[int, int], map[int][]int]{
+		{Name: "Add", Run: func(multi *MultiMap[int, int], model map[int][]int, arg int) error {
+			model[arg%4] = append(model[arg%4], arg/4)
+			return multi.Add(arg%4, arg/4)
+		}},
+		{Name: "RemoveValue", Run: func(multi *MultiMap[int, int], model map[int][]int, arg int) error {
+			i := slices.Index(model[arg%4], arg/4)
+			if i >= 0 {
+				model[arg%4] = slices.Delete(model[arg%4], i, i+1)
+				if len(model[arg%4]) == 0 {
+					delete(model, arg%4)
+				}
+			}
+			if ok := multi.RemoveValue(arg%4, arg/4); ok != (i >= 0) {
+				return fmt.Errorf("RemoveValue returned %t", ok)
+			}
+			return nil
+		}},
+		{Name: "Remove", Run: func(multi *MultiMap[int, int], model map[int][]int, arg int) error {
+			expected := len(model[arg%4])
+			delete(model, arg%4)
+			if count := multi.Remove(arg % 4); count != expected {
+				return fmt.Errorf("Remove returned %d, expected %d", count, expected)
+			}
+			return nil
+		}},
+	},
+	Check: func(multi *MultiMap[int, int], model map[int][]int) error {
+		if multi.KeyCount() != len(model) {
+			return fmt.Errorf("expected %d keys, got %d", len(model), multi.KeyCount())
+		}
+		for key, expected := range model {
+			if values := multi.Get(key); !slices.Equal(values, expected) {
+				return fmt.Errorf("key %d has %v, expected %v", key, values, expected)
+			}
+		}
+		return nil
+	},
+}
+
+func Test_MultiMapModel(t *testing.T) {
+	multiMapMachine.Test(t, datatest.Config{Runs: 200, Length: 100})
+}
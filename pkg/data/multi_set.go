@@ -0,0 +1,306 @@
+package data
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+)
+
+// MultiSetEntry is a value of a MultiSet and the number of times it occurs.
+type MultiSetEntry[T any] struct {
+	Value T   // Value in the multiset.
+	Count int // Multiplicity of the value, at least 1.
+}
+
+// MultiSet, or bag, is an unordered collection of values that counts how
+// many times each occurs. Like Set, the methods combining multisets copy
+// each operand first and return a new multiset configured by the default
+// options.
+type MultiSet[T comparable] struct {
+	counts  map[T]int // Multiplicity of each value, never 0.
+	length  int       // Sum of the multiplicities.
+	metrics *Metrics  // Instrumentation, nil when disabled.
+	mux     locker    // Lock read and write operations.
+}
+
+// Create a new multiset, configured by WithLocking and WithMetrics.
+func NewMultiSet[T comparable](opts ...Option) *MultiSet[T] {
+	settings := newOptions(opts)
+	return &MultiSet[T]{counts: map[T]int{}, metrics: settings.metrics, mux: settings.newLocker()}
+}
+
+// Create a new multiset of values, counting repeats, configured by the
+// default options.
+func NewMultiSetOf[T comparable](values ...T) *MultiSet[T] {
+	multi := NewMultiSet[T]()
+	multi.Add(values...)
+	return multi
+}
+
+// newMultiSetFrom creates a multiset owning the counts.
+func newMultiSetFrom[T comparable](counts map[T]int) *MultiSet[T] {
+	multi := NewMultiSet[T]()
+	multi.counts = counts
+	for _, count := range counts {
+		multi.length += count
+	}
+	return multi
+}
+
+// Length reports the number of values in the multiset, counting each
+// occurrence.
+func (multi *MultiSet[T]) Length() int {
+	if multi == nil {
+		return 0
+	}
+	multi.mux.RLock()
+	defer multi.mux.RUnlock()
+	return multi.length
+}
+
+// Distinct reports the number of different values in the multiset.
+func (multi *MultiSet[T]) Distinct() int {
+	if multi == nil {
+		return 0
+	}
+	multi.mux.RLock()
+	defer multi.mux.RUnlock()
+	return len(multi.counts)
+}
+
+// Add adds an occurrence of each value.
+func (multi *MultiSet[T]) Add(values ...T) error {
+	if multi == nil {
+		return nilError("multiset")
+	}
+	start := multi.metrics.begin()
+	multi.mux.Lock()
+	defer multi.mux.Unlock()
+	defer multi.metrics.end("Add", start, multi.metrics.acquired())
+	defer multi.debugCheck()
+	for _, value := range values {
+		multi.counts[value]++
+	}
+	multi.length += len(values)
+	return nil
+}
+
+// AddCount adds count occurrences of a value, returning ErrOutOfRange if
+// count is negative.
+func (multi *MultiSet[T]) AddCount(value T, count int) error {
+	if multi == nil {
+		return nilError("multiset")
+	}
+	if count < 0 {
+		return fmt.Errorf("%w: multiset count %d is negative", ErrOutOfRange, count)
+	}
+	start := multi.metrics.begin()
+	multi.mux.Lock()
+	defer multi.mux.Unlock()
+	defer multi.metrics.end("AddCount", start, multi.metrics.acquired())
+	defer multi.debugCheck()
+	if count > 0 {
+		multi.counts[value] += count
+		multi.length += count
+	}
+	return nil
+}
+
+// Remove removes an occurrence of a value, reporting whether there was one.
+func (multi *MultiSet[T]) Remove(value T) bool {
+	return multi.RemoveCount(value, 1) == 1
+}
+
+// RemoveCount removes up to count occurrences of a value, returning the
+// number removed.
+func (multi *MultiSet[T]) RemoveCount(value T, count int) int {
+	if multi == nil || count <= 0 {
+		return 0
+	}
+	start := multi.metrics.begin()
+	multi.mux.Lock()
+	defer multi.mux.Unlock()
+	defer multi.metrics.end("RemoveCount", start, multi.metrics.acquired())
+	defer multi.debugCheck()
+	removed := min(count, multi.counts[value])
+	if removed == multi.counts[value] {
+		delete(multi.counts, value)
+	} else {
+		multi.counts[value] -= removed
+	}
+	multi.length -= removed
+	return removed
+}
+
+// Count reports the number of occurrences of a value.
+func (multi *MultiSet[T]) Count(value T) int {
+	if multi == nil {
+		return 0
+	}
+	multi.mux.RLock()
+	defer multi.mux.RUnlock()
+	return multi.counts[value]
+}
+
+// Contains reports whether a value occurs in the multiset.
+func (multi *MultiSet[T]) Contains(value T) bool {
+	return multi.Count(value) > 0
+}
+
+// snapshot copies the counts, none for a nil multiset.
+func (multi *MultiSet[T]) snapshot() map[T]int {
+	if multi == nil {
+		return map[T]int{}
+	}
+	multi.mux.RLock()
+	defer multi.mux.RUnlock()
+	return maps.Clone(multi.counts)
+}
+
+// Union gets a multiset counting each value as many times as the greater
+// count of the two.
+func (multi *MultiSet[T]) Union(other *MultiSet[T]) *MultiSet[T] {
+	counts := multi.snapshot()
+	for value, count := range other.snapshot() {
+		counts[value] = max(counts[value], count)
+	}
+	return newMultiSetFrom(counts)
+}
+
+// Intersection gets a multiset counting each value as many times as the
+// lesser count of the two.
+func (multi *MultiSet[T]) Intersection(other *MultiSet[T]) *MultiSet[T] {
+	counts, others := multi.snapshot(), other.snapshot()
+	for value, count := range counts {
+		if count = min(count, others[value]); count > 0 {
+			counts[value] = count
+		} else {
+			delete(counts, value)
+		}
+	}
+	return newMultiSetFrom(counts)
+}
+
+// Sum gets a multiset adding the counts of the two.
+func (multi *MultiSet[T]) Sum(other *MultiSet[T]) *MultiSet[T] {
+	counts := multi.snapshot()
+	for value, count := range other.snapshot() {
+		counts[value] += count
+	}
+	return newMultiSetFrom(counts)
+}
+
+// Difference gets a multiset subtracting the counts of the other from this
+// one, dropping values that do not remain.
+func (multi *MultiSet[T]) Difference(other *MultiSet[T]) *MultiSet[T] {
+	counts := multi.snapshot()
+	for value, count := range other.snapshot() {
+		if counts[value] > count {
+			counts[value] -= count
+		} else {
+			delete(counts, value)
+		}
+	}
+	return newMultiSetFrom(counts)
+}
+
+// MostCommon gets the n values with the greatest counts, or all values if n
+// is negative, from the most common. Values with equal counts are in no
+// particular order. It takes O(d log n) time for d distinct values.
+func (multi *MultiSet[T]) MostCommon(n int) []MultiSetEntry[T] {
+	counts := multi.snapshot()
+	if n < 0 || n > len(counts) {
+		n = len(counts)
+	}
+	if n == 0 {
+		return nil
+	}
+	// Keep the n most common in a heap with the least common on top.
+	top := NewPriorityQueue(func(a, b MultiSetEntry[T]) bool { return a.Count < b.Count }, WithLocking(false))
+	for value, count := range counts {
+		if top.Length() < n {
+			top.Push(MultiSetEntry[T]{value, count})
+		} else if least, _ := top.Peek(); count > least.Count {
+			top.Pop()
+			top.Push(MultiSetEntry[T]{value, count})
+		}
+	}
+	entries := make([]MultiSetEntry[T], n)
+	for i := n - 1; i >= 0; i-- {
+		entries[i], _ = top.Pop()
+	}
+	return entries
+}
+
+// All gets a sequence of the distinct values and their counts as of the
+// call, in no particular order.
+func (multi *MultiSet[T]) All() iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		for value, count := range multi.snapshot() {
+			if !yield(value, count) {
+				return
+			}
+		}
+	}
+}
+
+// Values copies the values into a slice, each as many times as it occurs,
+// in no particular order of the distinct values.
+func (multi *MultiSet[T]) Values() []T {
+	var values []T
+	for value, count := range multi.All() {
+		values = append(values, slices.Repeat([]T{value}, count)...)
+	}
+	return values
+}
+
+// String converts MultiSet data into a string of value:count pairs, in no
+// particular order.
+func (multi *MultiSet[T]) String() string {
+	if multi == nil {
+		return ""
+	}
+	s := fmt.Sprintf("Length: %d, Data:", multi.Length())
+	for value, count := range multi.All() {
+		s += fmt.Sprintf(" %v:%d", value, count)
+	}
+	return s
+}
+
+// CheckInvariants verifies that every stored count is positive and that the
+// length is their sum.
+func (multi *MultiSet[T]) CheckInvariants() error {
+	if multi == nil {
+		return nil
+	}
+	multi.mux.RLock()
+	defer multi.mux.RUnlock()
+	return multi.checkInvariants()
+}
+
+// checkInvariants verifies the multiset, lock must be held.
+func (multi *MultiSet[T]) checkInvariants() error {
+	sum := 0
+	for value, count := range multi.counts {
+		if count <= 0 {
+			return fmt.Errorf("multiset: value %v has count %d", value, count)
+		}
+		sum += count
+	}
+	if sum != multi.length {
+		return fmt.Errorf("multiset: length %d, counts sum to %d", multi.length, sum)
+	}
+	return nil
+}
+
+// debugCheck panics if the multiset invariants are violated in debug builds,
+// lock must be held.
+func (multi *MultiSet[T]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := multi.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,115 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func Test_MultiSet(t *testing.T) {
+	words := NewMultiSetOf(strings.Fields("the cat and the dog and the bird")...)
+	if words.Length() != 8 || words.Distinct() != 5 || words.Count("the") != 3 {
+		t.Error("unexpected counts", words)
+	}
+	if common := words.MostCommon(2); !slices.Equal(common, []MultiSetEntry[string]{{"the", 3}, {"and", 2}}) {
+		t.Error("unexpected most common", common)
+	}
+	if all := words.MostCommon(-1); len(all) != 5 || all[4].Count != 1 {
+		t.Error("expected every value by count", all)
+	}
+	if err := words.AddCount("cat", -1); !errors.Is(err, ErrOutOfRange) {
+		t.Error("expected out of range error, got", err)
+	}
+	if words.RemoveCount("the", 5) != 3 || words.Contains("the") || words.Length() != 5 {
+		t.Error("expected to remove every the", words)
+	}
+
+	a, b := NewMultiSetOf("x", "x", "x", "y"), NewMultiSetOf("x", "y", "y", "z")
+	counts := map[string]map[string]int{
+		"Union":        maps.Collect(a.Union(b).All()),
+		"Intersection": maps.Collect(a.Intersection(b).All()),
+		"Sum":          maps.Collect(a.Sum(b).All()),
+		"Difference":   maps.Collect(a.Difference(b).All()),
+	}
+	expected := map[string]map[string]int{
+		"Union":        {"x": 3, "y": 2, "z": 1},
+		"Intersection": {"x": 1, "y": 1},
+		"Sum":          {"x": 4, "y": 3, "z": 1},
+		"Difference":   {"x": 2},
+	}
+	for name, want := range expected {
+		if !maps.Equal(counts[name], want) {
+			t.Errorf("%s: expected %v, got %v", name, want, counts[name])
+		}
+	}
+	if length := a.Sum(b).Length(); length != 8 {
+		t.Error("unexpected length of the sum", length)
+	}
+
+	var unset *MultiSet[string]
+	if err := unset.Add("a"); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+	if unset.MostCommon(1) != nil || a.Union(unset).Length() != a.Length() {
+		t.Error("expected a nil multiset to be empty")
+	}
+}
+
+// multiSetMachine checks a MultiSet against a map model of counts.
+var multiSetMachine = datatest.Machine[*MultiSet[int], map[int]int]{
+	NewSystem: func() *MultiSet[int] { return NewMultiSet[int](WithLocking(false)) },
+	NewModel:  func() map[int]int { return map[int]int{} },
+	ArgRange:  64,
+	Commands: []datatest.Command[*MultiSet[int], map[int]int]{
+		{Name: "AddCount", Run: func(multi *MultiSet[int], model map[int]int, arg int) error {
+			value, count := arg%8, arg/8
+			if count > 0 {
+				model[value] += count
+			}
+			return multi.AddCount(value, count)
+		}},
+		{Name: "RemoveCount", Run: func(multi *MultiSet[int], model map[int]int, arg int) error {
+			value, count := arg%8, arg/16
+			expected := min(count, model[value])
+			if model[value] -= expected; model[value] == 0 {
+				delete(model, value)
+			}
+			if removed := multi.RemoveCount(value, count); removed != expected {
+				return fmt.Errorf("RemoveCount returned %d, expected %d", removed, expected)
+			}
+			return nil
+		}},
+		{Name: "MostCommon", Run: func(multi *MultiSet[int], model map[int]int, arg int) error {
+			n := arg % 4
+			counts := slices.Sorted(maps.Values(model))
+			slices.Reverse(counts)
+			counts = counts[:min(n, len(counts))]
+			var got []int
+			for _, entry := range multi.MostCommon(n) {
+				if model[entry.Value] != entry.Count {
+					return fmt.Errorf("MostCommon counted %d %d times, expected %d", entry.Value, entry.Count, model[entry.Value])
+				}
+				got = append(got, entry.Count)
+			}
+			if !slices.Equal(got, counts) {
+				return fmt.Errorf("MostCommon(%d) counted %v, expected %v", n, got, counts)
+			}
+			return nil
+		}},
+	},
+	Check: func(multi *MultiSet[int], model map[int]int) error {
+		if counts := maps.Collect(multi.All()); !maps.Equal(counts, model) {
+			return fmt.Errorf("expected %v, got %v", model, counts)
+		}
+		return nil
+	},
+}
+
+func Test_MultiSetModel(t *testing.T) {
+	multiSetMachine.Test(t, datatest.Config{Runs: 200, Length: 100})
+}
@@ -0,0 +1,277 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ObjectPoolConfig configures an ObjectPool.
+type ObjectPoolConfig[T any] struct {
+	New         func(ctx context.Context) (T, error) // Creates an object, required.
+	Validate    func(object T) bool                  // Health check before reuse, optional.
+	Destroy     func(object T)                       // Releases a discarded object, optional.
+	MaxSize     int                                  // Maximum objects idle or in use, 0 for unlimited.
+	MinIdle     int                                  // Idle objects kept ready after eviction.
+	IdleTimeout time.Duration                        // Idle time before an object is evicted, 0 to keep forever.
+}
+
+// idleObject is an object waiting in the pool.
+type idleObject[T any] struct {
+	object T         // Pooled object.
+	since  time.Time // When the object was returned to the pool.
+}
+
+// ObjectPool keeps a bounded set of reusable objects, such as connections,
+// validating them before reuse and evicting those idle for too long.
+type ObjectPool[T any] struct {
+	config    ObjectPoolConfig[T] // Pool configuration.
+	idle      []idleObject[T]     // Idle objects, most recently returned last.
+	total     int                 // Objects idle, in use, or being created.
+	closed    bool                // Whether Close has been called.
+	hits      uint64              // Gets served by an idle object.
+	misses    uint64              // Gets that created an object.
+	evictions uint64              // Objects evicted for being idle too long.
+	available chan struct{}       // Closed and replaced when an object may be available.
+	stop      chan struct{}       // Closed to stop idle eviction.
+	mux       *sync.Mutex         // Lock read and write operations.
+}
+
+// Create a new object pool, creating MinIdle objects up front and evicting
+// idle objects in the background if IdleTimeout is set. WithCapacity
+// replaces MaxSize.
+func NewObjectPool[T any](config ObjectPoolConfig[T], opts ...Option) (*ObjectPool[T], error) {
+	config.MaxSize = newOptions(opts).capacityOr(config.MaxSize)
+	if config.New == nil {
+		return nil, fmt.Errorf("%w: object pool New is nil", ErrInvalid)
+	}
+	if config.MaxSize > 0 && config.MinIdle > config.MaxSize {
+		return nil, fmt.Errorf("%w: object pool MinIdle exceeds MaxSize", ErrInvalid)
+	}
+	pool := &ObjectPool[T]{
+		config:    config,
+		available: make(chan struct{}),
+		stop:      make(chan struct{}),
+		mux:       &sync.Mutex{},
+	}
+	if err := pool.fill(context.Background()); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	if config.IdleTimeout > 0 {
+		go pool.evictLoop()
+	}
+	return pool, nil
+}
+
+// Idle reports the number of idle objects.
+func (pool *ObjectPool[T]) Idle() int {
+	pool.mux.Lock()
+	defer pool.mux.Unlock()
+	return len(pool.idle)
+}
+
+// Size reports the number of objects idle or in use.
+func (pool *ObjectPool[T]) Size() int {
+	pool.mux.Lock()
+	defer pool.mux.Unlock()
+	return pool.total
+}
+
+// Get an object from the pool, creating one if none is idle. If the pool is
+// at MaxSize, Get blocks until an object is returned or ctx is done.
+func (pool *ObjectPool[T]) Get(ctx context.Context) (T, error) {
+	var unset T
+	for {
+		if ctx.Err() != nil {
+			return unset, canceled(ctx)
+		}
+		pool.mux.Lock()
+		if pool.closed {
+			pool.mux.Unlock()
+			return unset, ErrClosed
+		}
+		if last := len(pool.idle) - 1; last >= 0 {
+			object := pool.idle[last].object
+			pool.idle[last] = idleObject[T]{}
+			pool.idle = pool.idle[:last]
+			pool.hits++
+			pool.mux.Unlock()
+			if pool.config.Validate != nil && !pool.config.Validate(object) {
+				pool.Discard(object)
+				continue
+			}
+			return object, nil
+		}
+		if pool.config.MaxSize == 0 || pool.total < pool.config.MaxSize {
+			pool.total++
+			pool.misses++
+			pool.mux.Unlock()
+			object, err := pool.config.New(ctx)
+			if err != nil {
+				pool.mux.Lock()
+				pool.total--
+				pool.signal()
+				pool.mux.Unlock()
+				return unset, err
+			}
+			return object, nil
+		}
+		available := pool.available
+		pool.mux.Unlock()
+
+		select {
+		case <-available:
+		case <-ctx.Done():
+			return unset, canceled(ctx)
+		}
+	}
+}
+
+// Put returns an object to the pool for reuse.
+func (pool *ObjectPool[T]) Put(object T) {
+	pool.mux.Lock()
+	if pool.closed {
+		pool.total--
+		pool.mux.Unlock()
+		pool.destroy(object)
+		return
+	}
+	pool.idle = append(pool.idle, idleObject[T]{object, time.Now()})
+	pool.signal()
+	pool.mux.Unlock()
+}
+
+// Discard destroys an object taken from the pool instead of returning it,
+// making room for a new one.
+func (pool *ObjectPool[T]) Discard(object T) {
+	pool.mux.Lock()
+	pool.total--
+	pool.signal()
+	pool.mux.Unlock()
+	pool.destroy(object)
+}
+
+// EvictIdle destroys objects idle for longer than IdleTimeout, then creates
+// objects until MinIdle are idle. It runs periodically when IdleTimeout is set.
+func (pool *ObjectPool[T]) EvictIdle() error {
+	pool.mux.Lock()
+	var evicted []T
+	if pool.config.IdleTimeout > 0 {
+		deadline := time.Now().Add(-pool.config.IdleTimeout)
+		kept := pool.idle[:0]
+		for _, idle := range pool.idle {
+			if idle.since.Before(deadline) {
+				evicted = append(evicted, idle.object)
+			} else {
+				kept = append(kept, idle)
+			}
+		}
+		for i := len(kept); i < len(pool.idle); i++ {
+			pool.idle[i] = idleObject[T]{}
+		}
+		pool.idle = kept
+		pool.total -= len(evicted)
+		pool.evictions += uint64(len(evicted))
+		if len(evicted) > 0 {
+			pool.signal()
+		}
+	}
+	pool.mux.Unlock()
+
+	for _, object := range evicted {
+		pool.destroy(object)
+	}
+	return pool.fill(context.Background())
+}
+
+// Close destroys the idle objects and stops the pool. Objects in use are
+// destroyed when they are returned.
+func (pool *ObjectPool[T]) Close() {
+	pool.mux.Lock()
+	if pool.closed {
+		pool.mux.Unlock()
+		return
+	}
+	pool.closed = true
+	idle := pool.idle
+	pool.idle = nil
+	pool.total -= len(idle)
+	close(pool.stop)
+	pool.signal()
+	pool.mux.Unlock()
+
+	for _, idle := range idle {
+		pool.destroy(idle.object)
+	}
+}
+
+// fill creates idle objects until MinIdle are idle or MaxSize is reached.
+func (pool *ObjectPool[T]) fill(ctx context.Context) error {
+	for {
+		pool.mux.Lock()
+		if pool.closed || len(pool.idle) >= pool.config.MinIdle ||
+			(pool.config.MaxSize > 0 && pool.total >= pool.config.MaxSize) {
+			pool.mux.Unlock()
+			return nil
+		}
+		pool.total++
+		pool.mux.Unlock()
+
+		object, err := pool.config.New(ctx)
+		if err != nil {
+			pool.mux.Lock()
+			pool.total--
+			pool.mux.Unlock()
+			return err
+		}
+		pool.Put(object)
+	}
+}
+
+// evictLoop runs EvictIdle periodically until the pool is closed.
+func (pool *ObjectPool[T]) evictLoop() {
+	interval := pool.config.IdleTimeout / 2
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			pool.EvictIdle()
+		case <-pool.stop:
+			return
+		}
+	}
+}
+
+// destroy releases an object if a Destroy callback is configured.
+func (pool *ObjectPool[T]) destroy(object T) {
+	if pool.config.Destroy != nil {
+		pool.config.Destroy(object)
+	}
+}
+
+// signal wakes callers waiting in Get, lock must be held.
+func (pool *ObjectPool[T]) signal() {
+	close(pool.available)
+	pool.available = make(chan struct{})
+}
+
+// Collect reports the idle and total objects of the pool, how many gets
+// reused or created an object, and how many objects were evicted.
+func (pool *ObjectPool[T]) Collect(emit func(Sample)) {
+	pool.mux.Lock()
+	idle, total := len(pool.idle), pool.total
+	hits, misses, evictions := pool.hits, pool.misses, pool.evictions
+	pool.mux.Unlock()
+	emit(Sample{Name: "idle", Help: "Number of idle objects.", Value: float64(idle)})
+	emit(Sample{Name: "size", Help: "Number of objects idle or in use.", Value: float64(total)})
+	emit(Sample{Name: "hits_total", Help: "Gets served by an idle object.", Kind: CounterSample, Value: float64(hits)})
+	emit(Sample{Name: "misses_total", Help: "Gets that created an object.", Kind: CounterSample, Value: float64(misses)})
+	emit(Sample{Name: "evictions_total", Help: "Objects evicted for being idle too long.", Kind: CounterSample,
+		Value: float64(evictions)})
+}
@@ -0,0 +1,134 @@
+package data_test
+
+import (
+	"context"
+	"errors"
+	. "fun/pkg/data"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// resource is a pooled object for testing.
+type resource struct {
+	id        int   // Creation order.
+	healthy   bool  // Whether the resource passes validation.
+	destroyed int32 // Set when the pool destroys the resource.
+}
+
+// isDestroyed reports whether the pool destroyed the resource.
+func (r *resource) isDestroyed() bool {
+	return atomic.LoadInt32(&r.destroyed) == 1
+}
+
+// resourceConfig creates resources, counting them.
+func resourceConfig() (ObjectPoolConfig[*resource], *int32) {
+	var created int32
+	return ObjectPoolConfig[*resource]{
+		New: func(ctx context.Context) (*resource, error) {
+			return &resource{id: int(atomic.AddInt32(&created, 1)), healthy: true}, nil
+		},
+		Validate: func(r *resource) bool { return r.healthy },
+		Destroy:  func(r *resource) { atomic.StoreInt32(&r.destroyed, 1) },
+	}, &created
+}
+
+func Test_ObjectPool(t *testing.T) {
+	ctx := context.Background()
+	config, created := resourceConfig()
+	config.MaxSize = 2
+	config.MinIdle = 1
+	pool, err := NewObjectPool(config)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if pool.Idle() != 1 || atomic.LoadInt32(created) != 1 {
+		t.Error("expected 1 idle object up front, got", pool.Idle())
+	}
+
+	a, _ := pool.Get(ctx)
+	b, _ := pool.Get(ctx)
+	if a.id != 1 || b.id != 2 || pool.Size() != 2 {
+		t.Error("expected to reuse the idle object and create one more, got", a.id, b.id)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := pool.Get(timeout); !errors.Is(err, ErrCanceled) {
+		t.Error("expected Get at MaxSize to block until canceled, got", err)
+	}
+
+	done := make(chan *resource)
+	go func() {
+		r, _ := pool.Get(ctx)
+		done <- r
+	}()
+	pool.Put(a)
+	if r := <-done; r != a {
+		t.Error("expected blocked Get to receive the returned object")
+	}
+
+	b.healthy = false
+	pool.Put(b)
+	c, _ := pool.Get(ctx)
+	if !b.isDestroyed() || c.id != 3 {
+		t.Error("expected unhealthy object to be destroyed and replaced, got", c.id)
+	}
+
+	pool.Discard(c)
+	if !c.isDestroyed() || pool.Size() != 1 {
+		t.Error("expected discarded object to be destroyed, size", pool.Size())
+	}
+
+	pool.Close()
+	if _, err := pool.Get(ctx); !errors.Is(err, ErrClosed) {
+		t.Error("expected Get on closed pool to fail, got", err)
+	}
+	pool.Put(a)
+	if !a.isDestroyed() {
+		t.Error("expected object returned to closed pool to be destroyed")
+	}
+}
+
+func Test_ObjectPoolEviction(t *testing.T) {
+	config, created := resourceConfig()
+	config.MinIdle = 1
+	config.IdleTimeout = time.Hour
+	pool, _ := NewObjectPool(config)
+	defer pool.Close()
+
+	ctx := context.Background()
+	a, _ := pool.Get(ctx)
+	b, _ := pool.Get(ctx)
+	pool.Put(a)
+	pool.Put(b)
+	pool.EvictIdle()
+	if pool.Idle() != 2 || a.isDestroyed() {
+		t.Error("expected recently idle objects to be kept, idle", pool.Idle())
+	}
+
+	config.IdleTimeout = time.Millisecond
+	short, _ := NewObjectPool(config)
+	defer short.Close()
+	first, _ := short.Get(ctx)
+	short.Put(first)
+	time.Sleep(20 * time.Millisecond)
+	if !first.isDestroyed() {
+		t.Error("expected idle object to be evicted in the background")
+	}
+	if atomic.LoadInt32(created) < 4 {
+		t.Error("expected eviction to replenish MinIdle, created", atomic.LoadInt32(created))
+	}
+}
+
+func Test_ObjectPoolConfig(t *testing.T) {
+	if _, err := NewObjectPool(ObjectPoolConfig[int]{}); err == nil {
+		t.Error("expected error without New")
+	}
+	config, _ := resourceConfig()
+	config.MaxSize = 1
+	config.MinIdle = 2
+	if _, err := NewObjectPool(config); err == nil {
+		t.Error("expected error with MinIdle over MaxSize")
+	}
+}
@@ -0,0 +1,172 @@
+package data
+
+import (
+	"fun/pkg/constraints"
+	"sync"
+)
+
+// Option configures a structure when it is created. Options that do not
+// apply to a structure are ignored.
+type Option func(*options)
+
+// options are the settings collected from Option values.
+type options struct {
+	locking   bool           // Whether operations take a lock.
+	metrics   *Metrics       // Instrumentation, nil when disabled.
+	tracer    Tracer         // Receives spans of scans, nil when disabled.
+	threshold TraceThreshold // Scans to trace.
+	nodePool  bool           // Whether list nodes are recycled.
+	pool      *sync.Pool     // Pool of recycled list nodes, nil for a pool per list.
+	arena     any            // Arena for nodes, nil to allocate each node.
+	capacity  int            // Capacity from WithCapacity, -1 when not set.
+	comparer  any            // Comparer from WithComparator, nil when not set.
+	hasher    any            // Hasher from WithHasher, nil when not set.
+}
+
+// newOptions applies opts over the defaults.
+func newOptions(opts []Option) options {
+	settings := options{locking: true, capacity: -1}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&settings)
+		}
+	}
+	return settings
+}
+
+// WithLocking selects whether operations take a lock, which is the default.
+// Without locking a structure is faster but must not be used by more than
+// one goroutine at a time.
+func WithLocking(locking bool) Option {
+	return func(settings *options) {
+		settings.locking = locking
+	}
+}
+
+// WithMetrics records the operations of a structure in metrics.
+func WithMetrics(metrics *Metrics) Option {
+	return func(settings *options) {
+		settings.metrics = metrics
+	}
+}
+
+// WithTracer reports scans of a structure that meet threshold to tracer.
+func WithTracer(tracer Tracer, threshold TraceThreshold) Option {
+	return func(settings *options) {
+		settings.tracer = tracer
+		settings.threshold = threshold
+	}
+}
+
+// WithNodePool recycles the nodes of deleted list elements through a
+// sync.Pool, so churn allocates less. A node returned by Head, Tail, or Find,
+// or held by an iterator, must not be used once its element is deleted,
+// because it may already hold another element.
+func WithNodePool() Option {
+	return func(settings *options) {
+		settings.nodePool = true
+	}
+}
+
+// WithPool recycles the nodes of deleted list elements through pool, like
+// WithNodePool, so lists of the same element type can share one pool. Values
+// in the pool that are not nodes of the list are dropped, and if pool.New is
+// nil, nodes are allocated when the pool is empty.
+func WithPool(pool *sync.Pool) Option {
+	return func(settings *options) {
+		settings.nodePool = true
+		settings.pool = pool
+	}
+}
+
+// WithCapacity sets the capacity of a structure, replacing the capacity
+// passed to its constructor: the maximum number of elements of a
+// BoundedList, RingBuffer, or BlockingQueue, of each subscriber queue of a
+// Topic, and of the objects of an ObjectPool, the number of values per node
+// of an UnrolledList, the total weight of a Semaphore, and the number of
+// keys a HashMap holds before growing.
+func WithCapacity(capacity int) Option {
+	return func(settings *options) {
+		settings.capacity = capacity
+	}
+}
+
+// WithComparator orders the keys of a sorted structure by comparer,
+// replacing the comparer passed to its constructor. It applies to
+// SortedList, SortedMap, SortedSet, BST, BTree, BPlusTree, and
+// LockFreeList, and is ignored by structures of other key types.
+func WithComparator[T any](comparer constraints.Comparer[T]) Option {
+	return func(settings *options) {
+		settings.comparer = comparer
+	}
+}
+
+// WithHasher hashes and compares the keys of a HashMap with hasher instead
+// of ==, so keys such as case-insensitive strings can be equivalent without
+// being equal. It is ignored by structures of other key types.
+func WithHasher[K any](hasher constraints.Hasher[K]) Option {
+	return func(settings *options) {
+		settings.hasher = hasher
+	}
+}
+
+// capacityOr gets the capacity from WithCapacity, or fallback if it is not
+// set.
+func (settings options) capacityOr(fallback int) int {
+	if settings.capacity < 0 {
+		return fallback
+	}
+	return settings.capacity
+}
+
+// comparerOr gets the comparer from WithComparator, or fallback if it is not
+// set or orders another type.
+func comparerOr[T any](settings options, fallback constraints.Comparer[T]) constraints.Comparer[T] {
+	if comparer, ok := settings.comparer.(constraints.Comparer[T]); ok {
+		return comparer
+	}
+	return fallback
+}
+
+// hasherOr gets the hasher from WithHasher, or fallback if it is not set or
+// hashes another type.
+func hasherOr[K any](settings options, fallback constraints.Hasher[K]) constraints.Hasher[K] {
+	if hasher, ok := settings.hasher.(constraints.Hasher[K]); ok {
+		return hasher
+	}
+	return fallback
+}
+
+// WithArena allocates the nodes of a structure from arena, which must be an
+// *Arena of the structure's node type, such as *Arena[ListNode[T]] for a
+// List[T]. Arenas of other types are ignored.
+func WithArena(arena any) Option {
+	return func(settings *options) {
+		settings.arena = arena
+	}
+}
+
+// locker is the lock of a structure.
+type locker interface {
+	Lock()
+	Unlock()
+	RLock()
+	RUnlock()
+}
+
+// newLocker creates a read-write lock, or a lock that does nothing if
+// locking is disabled.
+func (settings options) newLocker() locker {
+	if !settings.locking {
+		return noLock{}
+	}
+	return &sync.RWMutex{}
+}
+
+// noLock is the lock of unsynchronized structures.
+type noLock struct{}
+
+func (noLock) Lock()    {}
+func (noLock) Unlock()  {}
+func (noLock) RLock()   {}
+func (noLock) RUnlock() {}
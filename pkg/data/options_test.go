@@ -0,0 +1,151 @@
+package data_test
+
+import (
+	"context"
+	"errors"
+	"fun/pkg/constraints"
+	. "fun/pkg/data"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_ListOptions(t *testing.T) {
+	metrics := NewMetrics()
+	var spans []Span
+	tracer := TracerFunc(func(span Span) {
+		spans = append(spans, span)
+	})
+	list := NewList[Data](WithMetrics(metrics), WithTracer(tracer, TraceThreshold{}), nil)
+	list.Append(1)
+	list.Find(1)
+	if metrics.Stats().Operations["Append"].Count != 1 {
+		t.Error("expected Append to be recorded")
+	}
+	if len(spans) != 1 || spans[0].Name != "List.Find" {
+		t.Error("expected Find to be traced, got", spans)
+	}
+
+	unlocked := NewList[Data](WithLocking(false))
+	unlocked.AppendAll(1, 2, 3)
+	unlocked.DeleteHead()
+	listAssert(t, unlocked, []Data{2, 3})
+	if err := unlocked.Batch(func(tx *ListTx[Data]) { tx.Append(4) }); err != nil {
+		t.Error("unexpected error", err)
+	}
+	listAssert(t, unlocked, []Data{2, 3, 4})
+	if unlocked.MemStats(nil).OverheadBytes >= NewList[Data]().MemStats(nil).OverheadBytes {
+		t.Error("expected an unlocked list to have less overhead")
+	}
+}
+
+func Test_NodePool(t *testing.T) {
+	list := NewList[Data](WithNodePool())
+	list.AppendAll(1, 2, 3, 2)
+	list.DeleteHead()
+	list.DeleteTail()
+	list.Delete(3)
+	list.Insert(5)
+	list.Append(6)
+	listAssert(t, list, []Data{5, 2, 6})
+	list.DeleteAll(2)
+	list.RemoveAt(1)
+	list.InsertAt(1, 7)
+	listAssert(t, list, []Data{5, 7})
+
+	snapshot := list.Snapshot()
+	list.DeleteHead()
+	list.Append(8)
+	listAssert(t, snapshot, []Data{5, 7})
+	listAssert(t, list, []Data{7, 8})
+
+	front, back := list.SplitAt(1)
+	front.DeleteHead()
+	back.Append(9)
+	listAssert(t, back, []Data{8, 9})
+}
+
+func Test_WithPool(t *testing.T) {
+	pool := &sync.Pool{}
+	first := NewList[Data](WithPool(pool))
+	second := NewList[Data](WithPool(pool))
+	first.AppendAll(1, 2, 3)
+	first.DeleteHead()
+	pool.Put("not a node")
+	second.AppendAll(4, 5)
+	listAssert(t, first, []Data{2, 3})
+	listAssert(t, second, []Data{4, 5})
+	if err := second.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_WithCapacity(t *testing.T) {
+	if ring := NewRingBuffer[int](1, EvictHead, WithCapacity(3)); ring.Capacity() != 3 {
+		t.Error("expected ring buffer capacity 3, got", ring.Capacity())
+	}
+	if bounded := NewBoundedList[Data](1, EvictHead, WithCapacity(4)); bounded.Capacity() != 4 {
+		t.Error("expected bounded list capacity 4, got", bounded.Capacity())
+	}
+	if queue := NewBlockingQueue[int](0, WithCapacity(2)); queue.Capacity() != 2 {
+		t.Error("expected blocking queue capacity 2, got", queue.Capacity())
+	}
+	if semaphore := NewSemaphore(1, WithCapacity(5)); semaphore.Size() != 5 {
+		t.Error("expected semaphore size 5, got", semaphore.Size())
+	}
+	if hash := NewHashMap[int, int](WithCapacity(100)); hash.Capacity() < 100 {
+		t.Error("expected room for 100 keys, got", hash.Capacity())
+	}
+	topic := NewTopic[int](1, BackpressureError, WithCapacity(2))
+	queue, _ := topic.Subscribe()
+	if queue.Capacity() != 2 {
+		t.Error("expected subscriber capacity 2, got", queue.Capacity())
+	}
+	pool, err := NewObjectPool(ObjectPoolConfig[int]{
+		New: func(ctx context.Context) (int, error) { return 0, nil },
+	}, WithCapacity(1))
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	defer pool.Close()
+	pool.Get(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := pool.Get(ctx); !errors.Is(err, ErrCanceled) {
+		t.Error("expected the pool to be limited to 1 object, got", err)
+	}
+}
+
+func Test_WithComparator(t *testing.T) {
+	descending := WithComparator(constraints.Reverse(constraints.OrderedComparer[int]()))
+	tree := NewBTree[int, string](2, constraints.OrderedComparer[int](), descending)
+	sorted := NewSortedSet(constraints.OrderedComparer[int](), descending)
+	lockFree := NewLockFreeList[int](descending)
+	for _, key := range []int{2, 3, 1} {
+		tree.Insert(key, "")
+		sorted.Add(key)
+		lockFree.Insert(key)
+	}
+	var keys []int
+	for key := range tree.All() {
+		keys = append(keys, key)
+	}
+	if !slices.Equal(keys, []int{3, 2, 1}) {
+		t.Error("expected descending tree keys, got", keys)
+	}
+	if values := slices.Collect(sorted.All()); !slices.Equal(values, []int{3, 2, 1}) {
+		t.Error("expected descending set values, got", values)
+	}
+	if values := slices.Collect(lockFree.All()); !slices.Equal(values, []int{3, 2, 1}) || !lockFree.Contains(2) {
+		t.Error("expected descending lock-free values, got", values)
+	}
+
+	// A comparer of another type is ignored.
+	list := NewSortedList[Data](constraints.OrderedComparer[Data](), WithComparator(constraints.FoldComparer()))
+	list.Insert(2)
+	list.Insert(1)
+	if values := slices.Collect(list.All()); !slices.Equal(values, []Data{1, 2}) {
+		t.Error("expected ascending values, got", values)
+	}
+}
@@ -0,0 +1,320 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"unsafe"
+)
+
+// PairingNode is an element of a PairingHeap, returned by Push as a handle
+// for DecreaseKey.
+type PairingNode[T any] struct {
+	value   T               // Value of the element.
+	child   *PairingNode[T] // Leftmost child, no less than the node.
+	sibling *PairingNode[T] // Next child of the same parent.
+	prev    *PairingNode[T] // Parent of a leftmost child, otherwise previous sibling.
+	removed bool            // Popped from its heap.
+}
+
+// Value gets the value of the element.
+func (node *PairingNode[T]) Value() T {
+	return node.value
+}
+
+// PairingHeap is a priority queue ordered by a less function, stored as a
+// tree whose nodes are no less than their parents. Push, Meld, and Peek take
+// constant time, DecreaseKey takes amortized O(log n) time or better, and
+// Pop amortized O(log n) time.
+type PairingHeap[T any] struct {
+	root    *PairingNode[T]   // Least element, nil when empty.
+	length  int               // Number of elements stored in the heap.
+	less    func(a, b T) bool // Order of the elements.
+	metrics *Metrics          // Instrumentation, nil when disabled.
+	mux     locker            // Lock read and write operations.
+}
+
+// Create a new pairing heap ordered by less, configured by WithLocking and
+// WithMetrics.
+func NewPairingHeap[T any](less func(a, b T) bool, opts ...Option) *PairingHeap[T] {
+	settings := newOptions(opts)
+	return &PairingHeap[T]{less: less, metrics: settings.metrics, mux: settings.newLocker()}
+}
+
+// Length reports the number of elements in the heap.
+func (heap *PairingHeap[T]) Length() int {
+	if heap == nil {
+		return 0
+	}
+	heap.mux.RLock()
+	defer heap.mux.RUnlock()
+	return heap.length
+}
+
+// Push adds an element, returning its node.
+func (heap *PairingHeap[T]) Push(value T) (*PairingNode[T], error) {
+	if heap == nil {
+		return nil, nilError("pairing heap")
+	}
+	start := heap.metrics.begin()
+	heap.mux.Lock()
+	defer heap.mux.Unlock()
+	defer heap.metrics.end("Push", start, heap.metrics.acquired())
+	defer heap.debugCheck()
+	node := &PairingNode[T]{value: value}
+	heap.root = heap.link(heap.root, node)
+	heap.length++
+	return node, nil
+}
+
+// Pop removes the least element.
+func (heap *PairingHeap[T]) Pop() (T, bool) {
+	var unset T
+	if heap == nil {
+		return unset, false
+	}
+	start := heap.metrics.begin()
+	heap.mux.Lock()
+	defer heap.mux.Unlock()
+	defer heap.metrics.end("Pop", start, heap.metrics.acquired())
+	defer heap.debugCheck()
+	root := heap.root
+	if root == nil {
+		return unset, false
+	}
+	heap.root = heap.pair(root.child)
+	heap.length--
+	root.child, root.removed = nil, true
+	return root.value, true
+}
+
+// Peek gets the least element without removing it.
+func (heap *PairingHeap[T]) Peek() (T, bool) {
+	var unset T
+	if heap == nil {
+		return unset, false
+	}
+	heap.mux.RLock()
+	defer heap.mux.RUnlock()
+	if heap.root == nil {
+		return unset, false
+	}
+	return heap.root.value, true
+}
+
+// DecreaseKey replaces the value of a node in the heap with one that is not
+// greater, returning ErrNotFound for a node that was popped and
+// ErrOutOfRange for a greater value. The node must have been pushed to this
+// heap or to one melded into it.
+func (heap *PairingHeap[T]) DecreaseKey(node *PairingNode[T], value T) error {
+	if heap == nil {
+		return nilError("pairing heap")
+	}
+	start := heap.metrics.begin()
+	heap.mux.Lock()
+	defer heap.mux.Unlock()
+	defer heap.metrics.end("DecreaseKey", start, heap.metrics.acquired())
+	defer heap.debugCheck()
+	if node == nil || node.removed {
+		return ErrNotFound
+	}
+	if heap.less(node.value, value) {
+		return ErrOutOfRange
+	}
+	node.value = value
+	if node != heap.root {
+		heap.cut(node)
+		heap.root = heap.link(heap.root, node)
+	}
+	return nil
+}
+
+// Meld moves every element of other into the heap in constant time, leaving
+// other empty. Nodes of other stay valid for DecreaseKey on the heap.
+func (heap *PairingHeap[T]) Meld(other *PairingHeap[T]) error {
+	if heap == nil {
+		return nilError("pairing heap")
+	}
+	if other == nil {
+		return nil
+	}
+	if other == heap {
+		return fmt.Errorf("%w: cannot meld a heap into itself", ErrInvalid)
+	}
+	start := heap.metrics.begin()
+	unlock := lockInOrder(unsafe.Pointer(heap), heap.mux, unsafe.Pointer(other), other.mux)
+	defer unlock()
+	defer heap.metrics.end("Meld", start, heap.metrics.acquired())
+	defer other.debugCheck()
+	defer heap.debugCheck()
+	heap.root = heap.link(heap.root, other.root)
+	heap.length += other.length
+	other.root, other.length = nil, 0
+	return nil
+}
+
+// link makes the greater of two roots, either of which may be nil, the
+// leftmost child of the other and returns the new root, lock must be held.
+func (heap *PairingHeap[T]) link(a, b *PairingNode[T]) *PairingNode[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if heap.less(b.value, a.value) {
+		a, b = b, a
+	}
+	b.prev, b.sibling = a, a.child
+	if a.child != nil {
+		a.child.prev = b
+	}
+	a.child = b
+	a.prev, a.sibling = nil, nil
+	return a
+}
+
+// pair links a list of siblings into one tree, first in pairs from left to
+// right and then from right to left, and returns its root, lock must be
+// held.
+func (heap *PairingHeap[T]) pair(first *PairingNode[T]) *PairingNode[T] {
+	// Pair the siblings, stacking the pairs through their sibling links.
+	var pairs *PairingNode[T]
+	for first != nil {
+		a, b := first, first.sibling
+		first = nil
+		if b != nil {
+			first = b.sibling
+		}
+		a.prev, a.sibling = nil, nil
+		if b != nil {
+			b.prev, b.sibling = nil, nil
+		}
+		pair := heap.link(a, b)
+		pair.sibling, pairs = pairs, pair
+	}
+	var root *PairingNode[T]
+	for pairs != nil {
+		next := pairs.sibling
+		pairs.sibling = nil
+		root = heap.link(root, pairs)
+		pairs = next
+	}
+	return root
+}
+
+// cut detaches a node that is not the root, with its children, from its
+// parent, lock must be held.
+func (heap *PairingHeap[T]) cut(node *PairingNode[T]) {
+	if node.prev.child == node {
+		node.prev.child = node.sibling
+	} else {
+		node.prev.sibling = node.sibling
+	}
+	if node.sibling != nil {
+		node.sibling.prev = node.prev
+	}
+	node.prev, node.sibling = nil, nil
+}
+
+// Values copies the elements into a slice in heap order, which starts with
+// the least element but is otherwise unsorted.
+func (heap *PairingHeap[T]) Values() []T {
+	if heap == nil {
+		return nil
+	}
+	heap.mux.RLock()
+	defer heap.mux.RUnlock()
+	values := make([]T, 0, heap.length)
+	stack := []*PairingNode[T]{}
+	if heap.root != nil {
+		stack = append(stack, heap.root)
+	}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		values = append(values, node.value)
+		for child := node.child; child != nil; child = child.sibling {
+			stack = append(stack, child)
+		}
+	}
+	return values
+}
+
+// All gets a sequence of the elements as of the call, in heap order.
+func (heap *PairingHeap[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range heap.Values() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// String converts PairingHeap data into a string, in heap order.
+func (heap *PairingHeap[T]) String() string {
+	if heap == nil {
+		return ""
+	}
+	values := heap.Values()
+	s := fmt.Sprintf("Length: %d, Data:", len(values))
+	for _, v := range values {
+		s += " " + fmt.Sprint(v)
+	}
+	return s
+}
+
+// CheckInvariants verifies that no node is less than its parent, that the
+// back links match the forward links, and that the length is the number of
+// nodes.
+func (heap *PairingHeap[T]) CheckInvariants() error {
+	if heap == nil {
+		return nil
+	}
+	heap.mux.RLock()
+	defer heap.mux.RUnlock()
+	return heap.checkInvariants()
+}
+
+// checkInvariants verifies the heap, lock must be held.
+func (heap *PairingHeap[T]) checkInvariants() error {
+	count := 0
+	if heap.root != nil {
+		if heap.root.prev != nil || heap.root.sibling != nil {
+			return errors.New("pairing heap: root has a parent or sibling")
+		}
+		stack := []*PairingNode[T]{heap.root}
+		for len(stack) > 0 {
+			node := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			count++
+			prev := node
+			for child := node.child; child != nil; child = child.sibling {
+				if child.prev != prev {
+					return fmt.Errorf("pairing heap: node %v has a wrong back link", child.value)
+				}
+				if heap.less(child.value, node.value) {
+					return fmt.Errorf("pairing heap: node %v is less than its parent %v", child.value, node.value)
+				}
+				stack = append(stack, child)
+				prev = child
+			}
+		}
+	}
+	if count != heap.length {
+		return fmt.Errorf("pairing heap: length %d, counted %d nodes", heap.length, count)
+	}
+	return nil
+}
+
+// debugCheck panics if the heap invariants are violated in debug builds,
+// lock must be held.
+func (heap *PairingHeap[T]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := heap.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,125 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"slices"
+	"testing"
+)
+
+func Test_PairingHeap(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	heap := NewPairingHeap(less)
+	nodes := map[int]*PairingNode[int]{}
+	for _, value := range []int{5, 8, 3, 9, 7} {
+		nodes[value], _ = heap.Push(value)
+	}
+	if value, ok := heap.Peek(); !ok || value != 3 {
+		t.Error("unexpected least element", value, ok)
+	}
+	if value, _ := heap.Pop(); value != 3 {
+		t.Error("expected to pop 3, got", value)
+	}
+	if err := heap.DecreaseKey(nodes[9], 1); err != nil {
+		t.Error(err)
+	}
+	if err := heap.DecreaseKey(nodes[8], 10); !errors.Is(err, ErrOutOfRange) {
+		t.Error("expected an error increasing a key, got", err)
+	}
+	if err := heap.DecreaseKey(nodes[3], 0); !errors.Is(err, ErrNotFound) {
+		t.Error("expected an error decreasing a popped key, got", err)
+	}
+	if err := heap.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+
+	shard := NewPairingHeap(less)
+	four, _ := shard.Push(4)
+	shard.Push(6)
+	if err := heap.Meld(shard); err != nil || shard.Length() != 0 || heap.Length() != 6 {
+		t.Error("unexpected meld", err, shard.Length(), heap.Length())
+	}
+	if err := heap.DecreaseKey(four, 2); err != nil || four.Value() != 2 {
+		t.Error("expected a melded node to stay valid", err)
+	}
+	if err := heap.Meld(heap); err == nil {
+		t.Error("expected an error melding a heap into itself")
+	}
+	var popped []int
+	for value, ok := heap.Pop(); ok; value, ok = heap.Pop() {
+		popped = append(popped, value)
+	}
+	if !slices.Equal(popped, []int{1, 2, 5, 6, 7, 8}) {
+		t.Error("expected elements in order", popped)
+	}
+
+	var unset *PairingHeap[int]
+	if _, err := unset.Push(1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
+
+// pairingHeapState is a pairing heap with the nodes pushed to it.
+type pairingHeapState struct {
+	*PairingHeap[int]
+	nodes []*PairingNode[int]
+}
+
+// pairingHeapMachine checks a PairingHeap against a slice model, in the
+// order of pushes.
+var pairingHeapMachine = datatest.Machine[*pairingHeapState, *[]int]{
+	NewSystem: func() *pairingHeapState {
+		return &pairingHeapState{PairingHeap: NewPairingHeap(func(a, b int) bool { return a < b })}
+	},
+	NewModel: func() *[]int { return &[]int{} },
+	Commands: []datatest.Command[*pairingHeapState, *[]int]{
+		{Name: "Push", Run: func(heap *pairingHeapState, model *[]int, arg int) error {
+			node, err := heap.Push(arg)
+			heap.nodes = append(heap.nodes, node)
+			*model = append(*model, arg)
+			return err
+		}},
+		{Name: "Pop", Run: func(heap *pairingHeapState, model *[]int, arg int) error {
+			value, ok := heap.Pop()
+			if ok != (len(*model) > 0) {
+				return fmt.Errorf("Pop returned %t", ok)
+			}
+			if !ok {
+				return nil
+			}
+			if least := slices.Min(*model); value != least {
+				return fmt.Errorf("Pop returned %d, expected %d", value, least)
+			}
+			// Find the popped node, which DecreaseKey no longer accepts.
+			for i, node := range heap.nodes {
+				if node.Value() == value && heap.DecreaseKey(node, value) != nil {
+					heap.nodes = slices.Delete(heap.nodes, i, i+1)
+					*model = slices.Delete(*model, i, i+1)
+					return nil
+				}
+			}
+			return fmt.Errorf("no popped node of %d", value)
+		}},
+		{Name: "DecreaseKey", Run: func(heap *pairingHeapState, model *[]int, arg int) error {
+			if len(heap.nodes) == 0 {
+				return nil
+			}
+			i := (arg%len(heap.nodes) + len(heap.nodes)) % len(heap.nodes)
+			value := min((*model)[i], (*model)[i]-1-arg%10)
+			(*model)[i] = value
+			return heap.DecreaseKey(heap.nodes[i], value)
+		}},
+	},
+	Check: func(heap *pairingHeapState, model *[]int) error {
+		if heap.Length() != len(*model) {
+			return fmt.Errorf("expected length %d, got %d", len(*model), heap.Length())
+		}
+		return nil
+	},
+}
+
+func Test_PairingHeapModel(t *testing.T) {
+	pairingHeapMachine.Test(t, datatest.Config{Runs: 200, Length: 100})
+}
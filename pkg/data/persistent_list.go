@@ -0,0 +1,175 @@
+package data
+
+import (
+	"fmt"
+	"iter"
+)
+
+// persistentNode is an element of a PersistentList. Nodes are never changed
+// once linked, so any number of lists can share them.
+type persistentNode[T ListData] struct {
+	value T                  // Value is storage for data in the list.
+	next  *persistentNode[T] // Pointer to the next element in the list.
+}
+
+// PersistentList is an immutable list. Insert, Append, and Delete return a
+// new list that shares the unchanged nodes of the original, which stays as
+// it was, so every version can be kept for undo and read from any number of
+// goroutines without locks. The zero value and nil are empty lists.
+type PersistentList[T ListData] struct {
+	head   *persistentNode[T] // Head of the list.
+	length int                // Number of elements stored in the list.
+}
+
+// Create a new persistent list of values, in order.
+func NewPersistentList[T ListData](values ...T) *PersistentList[T] {
+	var head *persistentNode[T]
+	for i := len(values) - 1; i >= 0; i-- {
+		head = &persistentNode[T]{values[i], head}
+	}
+	return &PersistentList[T]{head, len(values)}
+}
+
+// Length reports the number of elements in the list.
+func (list *PersistentList[T]) Length() int {
+	if list == nil {
+		return 0
+	}
+	return list.length
+}
+
+// Head gets the first value of the list.
+func (list *PersistentList[T]) Head() (T, bool) {
+	var unset T
+	if list == nil || list.head == nil {
+		return unset, false
+	}
+	return list.head.value, true
+}
+
+// Insert creates a list with value added at the beginning, sharing every
+// node of the list, in constant time.
+func (list *PersistentList[T]) Insert(value T) *PersistentList[T] {
+	head, length := list.nodes()
+	return &PersistentList[T]{&persistentNode[T]{value, head}, length + 1}
+}
+
+// Append creates a list with value added at the end. Nothing can be shared,
+// because every node leads to the new one, so it copies the list in linear
+// time.
+func (list *PersistentList[T]) Append(value T) *PersistentList[T] {
+	head, length := list.nodes()
+	copied, last := copyPersistent(head, nil)
+	appended := &persistentNode[T]{value: value}
+	if last == nil {
+		copied = appended
+	} else {
+		last.next = appended
+	}
+	return &PersistentList[T]{copied, length + 1}
+}
+
+// Delete creates a list without the first element equal to value, copying
+// the nodes before it and sharing the nodes after it. If there is no such
+// element, it returns the list itself and false.
+func (list *PersistentList[T]) Delete(value T) (*PersistentList[T], bool) {
+	head, length := list.nodes()
+	found := head
+	for found != nil && found.value != value {
+		found = found.next
+	}
+	if found == nil {
+		return list, false
+	}
+	copied, last := copyPersistent(head, found)
+	if last == nil {
+		copied = found.next
+	} else {
+		last.next = found.next
+	}
+	return &PersistentList[T]{copied, length - 1}, true
+}
+
+// DeleteHead creates a list without its first element, sharing every other
+// node, in constant time, and returns the element.
+func (list *PersistentList[T]) DeleteHead() (T, *PersistentList[T], bool) {
+	var unset T
+	head, length := list.nodes()
+	if head == nil {
+		return unset, list, false
+	}
+	return head.value, &PersistentList[T]{head.next, length - 1}, true
+}
+
+// nodes gets the head and length of the list, nil and 0 for a nil list.
+func (list *PersistentList[T]) nodes() (*persistentNode[T], int) {
+	if list == nil {
+		return nil, 0
+	}
+	return list.head, list.length
+}
+
+// copyPersistent copies the nodes from head up to, not including, end, and
+// returns the first and last copies. The last copy links to nothing.
+func copyPersistent[T ListData](head, end *persistentNode[T]) (first, last *persistentNode[T]) {
+	for node := head; node != end; node = node.next {
+		copied := &persistentNode[T]{value: node.value}
+		if last == nil {
+			first = copied
+		} else {
+			last.next = copied
+		}
+		last = copied
+	}
+	return first, last
+}
+
+// Contains reports whether a value is an element of the list.
+func (list *PersistentList[T]) Contains(value T) bool {
+	for element := range list.All() {
+		if element == value {
+			return true
+		}
+	}
+	return false
+}
+
+// All gets a sequence of the values of the list from head to tail.
+func (list *PersistentList[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		head, _ := list.nodes()
+		for node := head; node != nil; node = node.next {
+			if !yield(node.value) {
+				return
+			}
+		}
+	}
+}
+
+// Values copies the values of the list into a slice, from head to tail.
+func (list *PersistentList[T]) Values() []T {
+	values := make([]T, 0, list.Length())
+	for value := range list.All() {
+		values = append(values, value)
+	}
+	return values
+}
+
+// ToList creates a mutable list of the values, configured by the options of
+// NewList.
+func (list *PersistentList[T]) ToList(opts ...Option) *List[T] {
+	mutable := NewList[T](opts...)
+	for value := range list.All() {
+		mutable.append(value)
+	}
+	return mutable
+}
+
+// String converts PersistentList data into a string.
+func (list *PersistentList[T]) String() string {
+	s := fmt.Sprintf("Length: %d, Data:", list.Length())
+	for value := range list.All() {
+		s += " " + fmt.Sprint(value)
+	}
+	return s
+}
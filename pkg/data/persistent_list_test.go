@@ -0,0 +1,80 @@
+package data_test
+
+import (
+	. "fun/pkg/data"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func Test_PersistentList(t *testing.T) {
+	empty := NewPersistentList[Data]()
+	one := empty.Insert(2)
+	two := one.Insert(1)
+	three := two.Append(3)
+	if empty.Length() != 0 || one.String() != "Length: 1, Data: 2" || two.String() != "Length: 2, Data: 1 2" {
+		t.Error("expected earlier versions to be unchanged", empty, one, two)
+	}
+	if three.String() != "Length: 3, Data: 1 2 3" {
+		t.Error("unexpected appended list", three)
+	}
+
+	deleted, ok := three.Delete(2)
+	if !ok || !slices.Equal(deleted.Values(), []Data{1, 3}) {
+		t.Error("unexpected deletion", deleted, ok)
+	}
+	if !slices.Equal(three.Values(), []Data{1, 2, 3}) {
+		t.Error("expected the original to keep the deleted value", three)
+	}
+	if same, ok := three.Delete(9); ok || same != three {
+		t.Error("expected deleting a missing value to return the list")
+	}
+	head, rest, ok := three.DeleteHead()
+	if !ok || head != 1 || !slices.Equal(rest.Values(), []Data{2, 3}) {
+		t.Error("unexpected head deletion", head, rest, ok)
+	}
+	if _, _, ok := empty.DeleteHead(); ok {
+		t.Error("expected no head in an empty list")
+	}
+
+	if !three.Contains(3) || three.Contains(4) {
+		t.Error("unexpected membership")
+	}
+	if value, ok := three.Head(); !ok || value != 1 {
+		t.Error("unexpected head", value, ok)
+	}
+	listAssert(t, three.ToList(), []Data{1, 2, 3})
+
+	var unset *PersistentList[Data]
+	if unset.Insert(1).String() != "Length: 1, Data: 1" || unset.Length() != 0 {
+		t.Error("expected a nil list to be empty")
+	}
+	if values := NewPersistentList[Data](1, 2, 3).Values(); !slices.Equal(values, []Data{1, 2, 3}) {
+		t.Error("unexpected constructed list", values)
+	}
+}
+
+func Test_PersistentListConcurrency(t *testing.T) {
+	base := NewPersistentList[Data](1, 2, 3)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			version := base
+			for j := 0; j < 100; j++ {
+				version = version.Insert(Data(i))
+				if j%2 == 0 {
+					version, _ = version.Delete(Data(i))
+				}
+			}
+			if version.Length() != 53 {
+				t.Error("unexpected length", version.Length())
+			}
+		}(i)
+	}
+	wg.Wait()
+	if !slices.Equal(base.Values(), []Data{1, 2, 3}) {
+		t.Error("expected the shared base to be unchanged", base)
+	}
+}
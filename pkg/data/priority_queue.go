@@ -0,0 +1,194 @@
+package data
+
+import (
+	"fmt"
+	"iter"
+)
+
+// PriorityQueue is a binary heap ordered by a less function: Pop removes the
+// element that is less than all others. Push and Pop take O(log n) time, and
+// Peek constant time. Elements that are equal are popped in no particular
+// order.
+type PriorityQueue[T any] struct {
+	values  []T               // Heap, each element no greater than its children.
+	less    func(a, b T) bool // Order of the elements.
+	metrics *Metrics          // Instrumentation, nil when disabled.
+	mux     locker            // Lock read and write operations.
+}
+
+// Create a new priority queue ordered by less, configured by WithLocking
+// and WithMetrics.
+func NewPriorityQueue[T any](less func(a, b T) bool, opts ...Option) *PriorityQueue[T] {
+	return NewPriorityQueueFrom(less, nil, opts...)
+}
+
+// Create a new priority queue of values ordered by less, heapifying them in
+// O(n) time. The queue takes ownership of the slice.
+func NewPriorityQueueFrom[T any](less func(a, b T) bool, values []T, opts ...Option) *PriorityQueue[T] {
+	settings := newOptions(opts)
+	queue := &PriorityQueue[T]{values: values, less: less, metrics: settings.metrics, mux: settings.newLocker()}
+	for i := len(values)/2 - 1; i >= 0; i-- {
+		queue.down(i)
+	}
+	return queue
+}
+
+// Length reports the number of elements in the queue.
+func (queue *PriorityQueue[T]) Length() int {
+	if queue == nil {
+		return 0
+	}
+	queue.mux.RLock()
+	defer queue.mux.RUnlock()
+	return len(queue.values)
+}
+
+// Push adds an element.
+func (queue *PriorityQueue[T]) Push(value T) error {
+	if queue == nil {
+		return nilError("priority queue")
+	}
+	start := queue.metrics.begin()
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	defer queue.metrics.end("Push", start, queue.metrics.acquired())
+	defer queue.debugCheck()
+	queue.values = append(queue.values, value)
+	queue.up(len(queue.values) - 1)
+	return nil
+}
+
+// Pop removes the least element.
+func (queue *PriorityQueue[T]) Pop() (T, bool) {
+	var unset T
+	if queue == nil {
+		return unset, false
+	}
+	start := queue.metrics.begin()
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	defer queue.metrics.end("Pop", start, queue.metrics.acquired())
+	defer queue.debugCheck()
+	if len(queue.values) == 0 {
+		return unset, false
+	}
+	last := len(queue.values) - 1
+	value := queue.values[0]
+	queue.values[0] = queue.values[last]
+	queue.values[last] = unset
+	queue.values = queue.values[:last]
+	queue.down(0)
+	return value, true
+}
+
+// Peek gets the least element without removing it.
+func (queue *PriorityQueue[T]) Peek() (T, bool) {
+	var unset T
+	if queue == nil {
+		return unset, false
+	}
+	queue.mux.RLock()
+	defer queue.mux.RUnlock()
+	if len(queue.values) == 0 {
+		return unset, false
+	}
+	return queue.values[0], true
+}
+
+// up moves the element at i toward the root until its parent is not
+// greater, lock must be held.
+func (queue *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !queue.less(queue.values[i], queue.values[parent]) {
+			return
+		}
+		queue.values[i], queue.values[parent] = queue.values[parent], queue.values[i]
+		i = parent
+	}
+}
+
+// down moves the element at i toward the leaves until no child is less,
+// lock must be held.
+func (queue *PriorityQueue[T]) down(i int) {
+	for {
+		least := i
+		if left := 2*i + 1; left < len(queue.values) && queue.less(queue.values[left], queue.values[least]) {
+			least = left
+		}
+		if right := 2*i + 2; right < len(queue.values) && queue.less(queue.values[right], queue.values[least]) {
+			least = right
+		}
+		if least == i {
+			return
+		}
+		queue.values[i], queue.values[least] = queue.values[least], queue.values[i]
+		i = least
+	}
+}
+
+// Values copies the elements into a slice in heap order, which starts with
+// the least element but is otherwise unsorted.
+func (queue *PriorityQueue[T]) Values() []T {
+	if queue == nil {
+		return nil
+	}
+	queue.mux.RLock()
+	defer queue.mux.RUnlock()
+	return append([]T(nil), queue.values...)
+}
+
+// All gets a sequence of the elements as of the call, in heap order.
+func (queue *PriorityQueue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range queue.Values() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// String converts PriorityQueue data into a string, in heap order.
+func (queue *PriorityQueue[T]) String() string {
+	if queue == nil {
+		return ""
+	}
+	values := queue.Values()
+	s := fmt.Sprintf("Length: %d, Data:", len(values))
+	for _, v := range values {
+		s += " " + fmt.Sprint(v)
+	}
+	return s
+}
+
+// CheckInvariants verifies that no element is less than its parent.
+func (queue *PriorityQueue[T]) CheckInvariants() error {
+	if queue == nil {
+		return nil
+	}
+	queue.mux.RLock()
+	defer queue.mux.RUnlock()
+	return queue.checkInvariants()
+}
+
+// checkInvariants verifies the queue, lock must be held.
+func (queue *PriorityQueue[T]) checkInvariants() error {
+	for i := 1; i < len(queue.values); i++ {
+		if parent := (i - 1) / 2; queue.less(queue.values[i], queue.values[parent]) {
+			return fmt.Errorf("priority queue: element %d is less than its parent %d", i, parent)
+		}
+	}
+	return nil
+}
+
+// debugCheck panics if the queue invariants are violated in debug builds,
+// lock must be held.
+func (queue *PriorityQueue[T]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := queue.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,84 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func Test_PriorityQueue(t *testing.T) {
+	queue := NewPriorityQueue(func(a, b int) bool { return a < b })
+	for _, value := range []int{5, 1, 4, 1, 3} {
+		queue.Push(value)
+	}
+	if value, ok := queue.Peek(); !ok || value != 1 || queue.Length() != 5 {
+		t.Error("unexpected least element", value, ok, queue.Length())
+	}
+	var popped []int
+	for value, ok := queue.Pop(); ok; value, ok = queue.Pop() {
+		popped = append(popped, value)
+	}
+	if !slices.Equal(popped, []int{1, 1, 3, 4, 5}) {
+		t.Error("expected elements in order", popped)
+	}
+	if _, ok := queue.Peek(); ok {
+		t.Error("expected an empty queue")
+	}
+
+	random := rand.New(rand.NewSource(1))
+	values := random.Perm(100)
+	maxQueue := NewPriorityQueueFrom(func(a, b int) bool { return a > b }, values)
+	if err := maxQueue.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+	for expected := 99; expected >= 0; expected-- {
+		if value, _ := maxQueue.Pop(); value != expected {
+			t.Fatal("expected", expected, "got", value)
+		}
+	}
+
+	var unset *PriorityQueue[int]
+	if err := unset.Push(1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
+
+// priorityQueueMachine checks a PriorityQueue against a sorted slice model.
+var priorityQueueMachine = datatest.Machine[*PriorityQueue[int], *[]int]{
+	NewSystem: func() *PriorityQueue[int] { return NewPriorityQueue(func(a, b int) bool { return a < b }) },
+	NewModel:  func() *[]int { return &[]int{} },
+	Commands: []datatest.Command[*PriorityQueue[int], *[]int]{
+		{Name: "Push", Run: func(queue *PriorityQueue[int], model *[]int, arg int) error {
+			i, _ := slices.BinarySearch(*model, arg)
+			*model = slices.Insert(*model, i, arg)
+			return queue.Push(arg)
+		}},
+		{Name: "Pop", Run: func(queue *PriorityQueue[int], model *[]int, arg int) error {
+			value, ok := queue.Pop()
+			if ok != (len(*model) > 0) {
+				return fmt.Errorf("Pop returned %t", ok)
+			}
+			if ok {
+				if value != (*model)[0] {
+					return fmt.Errorf("Pop returned %d, expected %d", value, (*model)[0])
+				}
+				*model = (*model)[1:]
+			}
+			return nil
+		}},
+	},
+	Check: func(queue *PriorityQueue[int], model *[]int) error {
+		if queue.Length() != len(*model) {
+			return fmt.Errorf("expected length %d, got %d", len(*model), queue.Length())
+		}
+		return nil
+	},
+}
+
+func Test_PriorityQueueModel(t *testing.T) {
+	priorityQueueMachine.Test(t, datatest.Config{Runs: 200})
+}
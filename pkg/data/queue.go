@@ -0,0 +1,139 @@
+package data
+
+import (
+	"fmt"
+	"iter"
+)
+
+// queueMinCapacity is the number of elements a Queue first makes room for.
+const queueMinCapacity = 8
+
+// Queue is a FIFO queue stored in a circular buffer that doubles when full,
+// so Enqueue, Dequeue, and Peek take amortized constant time. Unlike
+// BlockingQueue it never blocks, and with WithLocking(false) it skips
+// locking for use by a single goroutine.
+type Queue[T any] struct {
+	values  []T      // Circular buffer, len is the capacity.
+	head    int      // Index of the oldest element.
+	length  int      // Number of elements stored in the queue.
+	metrics *Metrics // Instrumentation, nil when disabled.
+	mux     locker   // Lock read and write operations.
+}
+
+// Create a new queue, configured by WithLocking and WithMetrics.
+func NewQueue[T any](opts ...Option) *Queue[T] {
+	settings := newOptions(opts)
+	return &Queue[T]{metrics: settings.metrics, mux: settings.newLocker()}
+}
+
+// Length reports the number of elements in the queue.
+func (queue *Queue[T]) Length() int {
+	if queue == nil {
+		return 0
+	}
+	queue.mux.RLock()
+	defer queue.mux.RUnlock()
+	return queue.length
+}
+
+// Enqueue adds an element at the back of the queue.
+func (queue *Queue[T]) Enqueue(value T) error {
+	if queue == nil {
+		return nilError("queue")
+	}
+	start := queue.metrics.begin()
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	defer queue.metrics.end("Enqueue", start, queue.metrics.acquired())
+	if queue.length == len(queue.values) {
+		queue.grow()
+	}
+	queue.values[(queue.head+queue.length)%len(queue.values)] = value
+	queue.length++
+	return nil
+}
+
+// grow doubles the buffer, moving the elements to its start, lock must be
+// held.
+func (queue *Queue[T]) grow() {
+	values := make([]T, max(queueMinCapacity, 2*len(queue.values)))
+	queue.copyTo(values)
+	queue.values, queue.head = values, 0
+}
+
+// copyTo copies the elements, oldest first, to the start of values, lock
+// must be held.
+func (queue *Queue[T]) copyTo(values []T) {
+	n := copy(values, queue.values[queue.head:min(queue.head+queue.length, len(queue.values))])
+	copy(values[n:], queue.values[:queue.length-n])
+}
+
+// Dequeue removes the element at the front of the queue.
+func (queue *Queue[T]) Dequeue() (T, bool) {
+	var unset T
+	if queue == nil {
+		return unset, false
+	}
+	start := queue.metrics.begin()
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	defer queue.metrics.end("Dequeue", start, queue.metrics.acquired())
+	if queue.length == 0 {
+		return unset, false
+	}
+	value := queue.values[queue.head]
+	queue.values[queue.head] = unset
+	queue.head = (queue.head + 1) % len(queue.values)
+	queue.length--
+	return value, true
+}
+
+// Peek gets the element at the front of the queue without removing it.
+func (queue *Queue[T]) Peek() (T, bool) {
+	var unset T
+	if queue == nil {
+		return unset, false
+	}
+	queue.mux.RLock()
+	defer queue.mux.RUnlock()
+	if queue.length == 0 {
+		return unset, false
+	}
+	return queue.values[queue.head], true
+}
+
+// Values copies the elements into a slice, oldest first.
+func (queue *Queue[T]) Values() []T {
+	if queue == nil {
+		return nil
+	}
+	queue.mux.RLock()
+	defer queue.mux.RUnlock()
+	values := make([]T, queue.length)
+	queue.copyTo(values)
+	return values
+}
+
+// All gets a sequence of the elements as of the call, oldest first.
+func (queue *Queue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range queue.Values() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// String converts Queue data into a string.
+func (queue *Queue[T]) String() string {
+	if queue == nil {
+		return ""
+	}
+	values := queue.Values()
+	s := fmt.Sprintf("Length: %d, Data:", len(values))
+	for _, v := range values {
+		s += " " + fmt.Sprint(v)
+	}
+	return s
+}
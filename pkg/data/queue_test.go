@@ -0,0 +1,94 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func Test_Queue(t *testing.T) {
+	queue := NewQueue[string]()
+	if _, ok := queue.Peek(); ok {
+		t.Error("expected no front of an empty queue")
+	}
+	for _, value := range []string{"a", "b", "c"} {
+		queue.Enqueue(value)
+	}
+	if value, ok := queue.Peek(); !ok || value != "a" || queue.Length() != 3 {
+		t.Error("unexpected front", value, ok, queue.Length())
+	}
+	if value, ok := queue.Dequeue(); !ok || value != "a" {
+		t.Error("expected to dequeue a, got", value, ok)
+	}
+	// Wrap around the end of the buffer while it grows.
+	for i := 0; i < 20; i++ {
+		queue.Enqueue(fmt.Sprint(i))
+		queue.Dequeue()
+	}
+	if queue.String() != "Length: 2, Data: 18 19" {
+		t.Error("unexpected queue", queue.String())
+	}
+
+	var unset *Queue[int]
+	if err := unset.Enqueue(1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
+
+// queueModelMachine checks an unsynchronized Queue against a slice model.
+var queueModelMachine = datatest.Machine[*Queue[int], *[]int]{
+	NewSystem: func() *Queue[int] { return NewQueue[int](WithLocking(false)) },
+	NewModel:  func() *[]int { return &[]int{} },
+	Commands: []datatest.Command[*Queue[int], *[]int]{
+		{Name: "Enqueue", Run: func(queue *Queue[int], model *[]int, arg int) error {
+			*model = append(*model, arg)
+			return queue.Enqueue(arg)
+		}},
+		{Name: "Dequeue", Run: func(queue *Queue[int], model *[]int, arg int) error {
+			value, ok := queue.Dequeue()
+			if ok != (len(*model) > 0) {
+				return fmt.Errorf("Dequeue returned %t", ok)
+			}
+			if ok {
+				if value != (*model)[0] {
+					return fmt.Errorf("Dequeue returned %d, expected %d", value, (*model)[0])
+				}
+				*model = (*model)[1:]
+			}
+			return nil
+		}},
+	},
+	Check: func(queue *Queue[int], model *[]int) error {
+		if values := queue.Values(); !slices.Equal(values, *model) {
+			return fmt.Errorf("expected %v, got %v", *model, values)
+		}
+		return nil
+	},
+}
+
+func Test_QueueModel(t *testing.T) {
+	queueModelMachine.Test(t, datatest.Config{Runs: 200, Length: 100})
+}
+
+func Test_QueueConcurrency(t *testing.T) {
+	queue := NewQueue[int]()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				queue.Enqueue(j)
+				queue.Dequeue()
+			}
+		}()
+	}
+	wg.Wait()
+	if queue.Length() != 0 {
+		t.Error("expected an empty queue, got", queue.Length())
+	}
+}
@@ -0,0 +1,277 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"fun/pkg/constraints"
+	"sync"
+	"time"
+)
+
+// limiterAlgorithm decides when events are allowed, called with the limiter
+// lock held.
+type limiterAlgorithm interface {
+	// take consumes capacity for an event at now. If the event is not allowed
+	// now and reserve is false, nothing is consumed and the delay until it
+	// would be is returned. If reserve is true, capacity is consumed at the
+	// returned delay. ok is false if the event can never be allowed.
+	take(now time.Time, reserve bool) (delay time.Duration, allowed bool, ok bool)
+}
+
+// RateLimiter limits the rate of events using a token bucket, leaky bucket,
+// or sliding window log algorithm.
+type RateLimiter struct {
+	algorithm limiterAlgorithm // Algorithm deciding when events are allowed.
+	now       func() time.Time // Clock used to time events.
+	mux       *sync.Mutex      // Lock the algorithm state.
+}
+
+// Reservation of an event by a RateLimiter.
+type Reservation struct {
+	ok    bool          // Whether the event could be reserved.
+	delay time.Duration // How long to wait before the event.
+}
+
+// OK reports whether the event was reserved.
+func (reservation Reservation) OK() bool {
+	return reservation.ok
+}
+
+// Delay reports how long to wait before acting on the reservation.
+func (reservation Reservation) Delay() time.Duration {
+	return reservation.delay
+}
+
+// newRateLimiter wraps an algorithm.
+func newRateLimiter(algorithm limiterAlgorithm) *RateLimiter {
+	return &RateLimiter{algorithm: algorithm, now: time.Now, mux: &sync.Mutex{}}
+}
+
+// Create a token bucket limiter allowing rate events per second on average,
+// and bursts of up to burst events.
+func NewTokenBucket(rate float64, burst int) *RateLimiter {
+	return newRateLimiter(&tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)})
+}
+
+// Create a leaky bucket limiter spacing events evenly at rate events per
+// second, with up to capacity events waiting for a slot.
+func NewLeakyBucket(rate float64, capacity int) *RateLimiter {
+	var interval time.Duration
+	if rate > 0 {
+		interval = time.Duration(float64(time.Second) / rate)
+	}
+	return newRateLimiter(&leakyBucket{interval: interval, capacity: capacity})
+}
+
+// Create a sliding window log limiter allowing at most limit events in any
+// window of time.
+func NewSlidingWindowLog(limit int, window time.Duration) *RateLimiter {
+	if limit < 0 {
+		limit = 0
+	}
+	return newRateLimiter(&slidingWindowLog{window: window, log: make([]time.Time, 0, limit)})
+}
+
+// Allow reports whether an event may happen now, consuming capacity if so.
+func (limiter *RateLimiter) Allow() bool {
+	limiter.mux.Lock()
+	defer limiter.mux.Unlock()
+	_, allowed, _ := limiter.algorithm.take(limiter.now(), false)
+	return allowed
+}
+
+// Reserve consumes capacity for an event in the future, reporting how long
+// the caller must wait before acting.
+func (limiter *RateLimiter) Reserve() Reservation {
+	limiter.mux.Lock()
+	defer limiter.mux.Unlock()
+	delay, _, ok := limiter.algorithm.take(limiter.now(), true)
+	return Reservation{ok: ok, delay: delay}
+}
+
+// Wait blocks until an event is allowed or ctx is done. No capacity is
+// consumed if ctx is done first.
+func (limiter *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		if ctx.Err() != nil {
+			return canceled(ctx)
+		}
+		limiter.mux.Lock()
+		delay, allowed, ok := limiter.algorithm.take(limiter.now(), false)
+		limiter.mux.Unlock()
+		if allowed {
+			return nil
+		}
+		if !ok {
+			return fmt.Errorf("%w: rate limit can never allow the event", ErrOutOfRange)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return canceled(ctx)
+		}
+	}
+}
+
+// tokenBucket refills tokens at a constant rate up to a burst size.
+type tokenBucket struct {
+	rate   float64   // Tokens added per second.
+	burst  float64   // Maximum number of tokens.
+	tokens float64   // Available tokens, negative when reserved ahead.
+	last   time.Time // When tokens were last refilled.
+}
+
+func (bucket *tokenBucket) take(now time.Time, reserve bool) (time.Duration, bool, bool) {
+	if bucket.burst < 1 || bucket.rate <= 0 {
+		if bucket.tokens >= 1 {
+			bucket.tokens--
+			return 0, true, true
+		}
+		return 0, false, false
+	}
+	if !bucket.last.IsZero() && now.After(bucket.last) {
+		bucket.tokens += now.Sub(bucket.last).Seconds() * bucket.rate
+		if bucket.tokens > bucket.burst {
+			bucket.tokens = bucket.burst
+		}
+	}
+	bucket.last = now
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return 0, true, true
+	}
+	delay := time.Duration((1 - bucket.tokens) / bucket.rate * float64(time.Second))
+	if reserve {
+		bucket.tokens--
+	}
+	return delay, false, true
+}
+
+// leakyBucket gives events evenly spaced slots, with a bounded queue of
+// events waiting for their slot.
+type leakyBucket struct {
+	interval time.Duration // Time between slots.
+	capacity int           // Maximum number of events waiting.
+	next     time.Time     // Next free slot.
+}
+
+func (bucket *leakyBucket) take(now time.Time, reserve bool) (time.Duration, bool, bool) {
+	if bucket.interval <= 0 {
+		return 0, false, false
+	}
+	slot := bucket.next
+	if slot.Before(now) {
+		slot = now
+	}
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		bucket.next = slot.Add(bucket.interval)
+		return 0, true, true
+	}
+	if !reserve {
+		return delay, false, true
+	}
+	if delay > time.Duration(bucket.capacity)*bucket.interval {
+		return 0, false, false
+	}
+	bucket.next = slot.Add(bucket.interval)
+	return delay, false, true
+}
+
+// slidingWindowLog records the times of the most recent events in a ring of
+// limit entries, allowing an event once the oldest falls out of the window.
+type slidingWindowLog struct {
+	window time.Duration // Length of the window.
+	log    []time.Time   // Ring of event times, at most limit entries.
+	oldest int           // Index of the oldest entry once the ring is full.
+}
+
+func (log *slidingWindowLog) take(now time.Time, reserve bool) (time.Duration, bool, bool) {
+	if cap(log.log) == 0 {
+		return 0, false, false
+	}
+	if len(log.log) < cap(log.log) {
+		log.log = append(log.log, now)
+		return 0, true, true
+	}
+	available := log.log[log.oldest].Add(log.window)
+	delay := available.Sub(now)
+	if delay <= 0 {
+		log.record(now)
+		return 0, true, true
+	}
+	if reserve {
+		log.record(available)
+	}
+	return delay, false, true
+}
+
+// record replaces the oldest entry with an event time.
+func (log *slidingWindowLog) record(at time.Time) {
+	log.log[log.oldest] = at
+	log.oldest = (log.oldest + 1) % len(log.log)
+}
+
+// KeyedRateLimiter keeps a separate RateLimiter for each key, created on
+// first use.
+type KeyedRateLimiter[K constraints.Hashable] struct {
+	create   func() *RateLimiter // Creates the limiter for a new key.
+	limiters map[K]*RateLimiter  // Limiters by key.
+	mux      *sync.RWMutex       // Lock the limiters map.
+}
+
+// Create a keyed rate limiter using create for each new key.
+func NewKeyedRateLimiter[K constraints.Hashable](create func() *RateLimiter) *KeyedRateLimiter[K] {
+	return &KeyedRateLimiter[K]{create: create, limiters: map[K]*RateLimiter{}, mux: &sync.RWMutex{}}
+}
+
+// Limiter gets the limiter for a key, creating it if needed.
+func (keyed *KeyedRateLimiter[K]) Limiter(key K) *RateLimiter {
+	keyed.mux.RLock()
+	limiter, ok := keyed.limiters[key]
+	keyed.mux.RUnlock()
+	if ok {
+		return limiter
+	}
+	keyed.mux.Lock()
+	defer keyed.mux.Unlock()
+	if limiter, ok = keyed.limiters[key]; !ok {
+		limiter = keyed.create()
+		keyed.limiters[key] = limiter
+	}
+	return limiter
+}
+
+// Allow reports whether an event for a key may happen now.
+func (keyed *KeyedRateLimiter[K]) Allow(key K) bool {
+	return keyed.Limiter(key).Allow()
+}
+
+// Reserve reserves an event for a key.
+func (keyed *KeyedRateLimiter[K]) Reserve(key K) Reservation {
+	return keyed.Limiter(key).Reserve()
+}
+
+// Wait blocks until an event for a key is allowed or ctx is done.
+func (keyed *KeyedRateLimiter[K]) Wait(ctx context.Context, key K) error {
+	return keyed.Limiter(key).Wait(ctx)
+}
+
+// Remove forgets the limiter for a key.
+func (keyed *KeyedRateLimiter[K]) Remove(key K) bool {
+	keyed.mux.Lock()
+	defer keyed.mux.Unlock()
+	_, ok := keyed.limiters[key]
+	delete(keyed.limiters, key)
+	return ok
+}
+
+// Length reports the number of keys with a limiter.
+func (keyed *KeyedRateLimiter[K]) Length() int {
+	keyed.mux.RLock()
+	defer keyed.mux.RUnlock()
+	return len(keyed.limiters)
+}
@@ -0,0 +1,108 @@
+package data_test
+
+import (
+	"context"
+	"errors"
+	. "fun/pkg/data"
+	"testing"
+	"time"
+)
+
+func Test_TokenBucket(t *testing.T) {
+	limiter := NewTokenBucket(10, 2)
+	if !limiter.Allow() || !limiter.Allow() {
+		t.Error("expected burst of 2 to be allowed")
+	}
+	if limiter.Allow() {
+		t.Error("expected third event to be limited")
+	}
+	reservation := limiter.Reserve()
+	if !reservation.OK() || reservation.Delay() <= 0 || reservation.Delay() > 100*time.Millisecond {
+		t.Error("expected reservation within 100ms, got", reservation.Delay())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, ErrCanceled) {
+		t.Error("expected wait to be canceled, got", err)
+	}
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Error("unexpected error", err)
+	}
+
+	never := NewTokenBucket(0, 0)
+	if never.Allow() || never.Reserve().OK() {
+		t.Error("expected empty bucket to never allow")
+	}
+	if err := never.Wait(context.Background()); err == nil {
+		t.Error("expected error waiting on empty bucket")
+	}
+}
+
+func Test_LeakyBucket(t *testing.T) {
+	limiter := NewLeakyBucket(100, 1)
+	if !limiter.Allow() {
+		t.Error("expected first event to be allowed")
+	}
+	if limiter.Allow() {
+		t.Error("expected second event to wait for its slot")
+	}
+	reservation := limiter.Reserve()
+	if !reservation.OK() || reservation.Delay() <= 0 || reservation.Delay() > 10*time.Millisecond {
+		t.Error("expected reservation within 10ms, got", reservation.Delay())
+	}
+	if limiter.Reserve().OK() {
+		t.Error("expected reservation to fail with a full queue")
+	}
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Error("unexpected error", err)
+	}
+}
+
+func Test_SlidingWindowLog(t *testing.T) {
+	limiter := NewSlidingWindowLog(2, 50*time.Millisecond)
+	if !limiter.Allow() || !limiter.Allow() {
+		t.Error("expected 2 events in the window to be allowed")
+	}
+	if limiter.Allow() {
+		t.Error("expected third event in the window to be limited")
+	}
+	reservation := limiter.Reserve()
+	if !reservation.OK() || reservation.Delay() <= 0 || reservation.Delay() > 50*time.Millisecond {
+		t.Error("expected reservation within the window, got", reservation.Delay())
+	}
+	time.Sleep(60 * time.Millisecond)
+	if !limiter.Allow() {
+		t.Error("expected event to be allowed once the window slides")
+	}
+}
+
+func Test_KeyedRateLimiter(t *testing.T) {
+	keyed := NewKeyedRateLimiter[string](func() *RateLimiter {
+		return NewTokenBucket(1, 1)
+	})
+	if !keyed.Allow("a") || keyed.Allow("a") {
+		t.Error("expected a single event for key a")
+	}
+	if !keyed.Allow("b") {
+		t.Error("expected keys to be limited independently")
+	}
+	if keyed.Length() != 2 {
+		t.Error("expected 2 keys, got", keyed.Length())
+	}
+	if keyed.Limiter("a") != keyed.Limiter("a") {
+		t.Error("expected the same limiter for a key")
+	}
+	if !keyed.Remove("a") || keyed.Remove("a") {
+		t.Error("expected to remove key a once")
+	}
+	if !keyed.Allow("a") {
+		t.Error("expected a new limiter after removing key a")
+	}
+	if !keyed.Reserve("c").OK() {
+		t.Error("expected reservation for key c")
+	}
+	if err := keyed.Wait(context.Background(), "d"); err != nil {
+		t.Error("unexpected error", err)
+	}
+}
@@ -0,0 +1,163 @@
+package data
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SampleKind tells how a sample changes over time.
+type SampleKind int
+
+const (
+	GaugeSample   SampleKind = iota // Value can go up and down, like a size.
+	CounterSample                   // Value only increases, like an eviction count.
+)
+
+// Sample is one measurement reported by a Collector.
+type Sample struct {
+	Name   string            // Metric name, prefixed with the registered name by a Registry.
+	Help   string            // Description of the metric.
+	Kind   SampleKind        // Gauge or counter.
+	Labels map[string]string // Dimensions of the metric, may be nil.
+	Value  float64           // Measured value.
+}
+
+// Collector reports samples when a Registry is gathered. It has the shape of
+// a Prometheus collector without depending on the client library, so an
+// adapter can forward samples to a prometheus.Collector. Structures pay
+// nothing for being collectable until they are registered and gathered.
+type Collector interface {
+	Collect(emit func(Sample))
+}
+
+// Registry gathers samples from named collectors, and exposes them as expvar
+// variables or in the Prometheus text format.
+type Registry struct {
+	collectors map[string]Collector // Registered collectors by name.
+	mux        *sync.RWMutex        // Lock registration and gather operations.
+}
+
+// metricName is the syntax of Prometheus metric names.
+var metricName = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// Create a new registry.
+func NewRegistry() *Registry {
+	return &Registry{collectors: map[string]Collector{}, mux: &sync.RWMutex{}}
+}
+
+// Register adds a named collector, whose sample names are prefixed with
+// name and an underscore.
+func (registry *Registry) Register(name string, collector Collector) error {
+	if registry == nil {
+		return nilError("registry")
+	}
+	if !metricName.MatchString(name) {
+		return fmt.Errorf("%w: metric name %q", ErrInvalid, name)
+	}
+	if collector == nil {
+		return fmt.Errorf("%w: collector is nil", ErrInvalid)
+	}
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+	if _, ok := registry.collectors[name]; ok {
+		return fmt.Errorf("%w: collector already registered as %s", ErrDuplicate, name)
+	}
+	registry.collectors[name] = collector
+	return nil
+}
+
+// Unregister removes a named collector, reporting whether it was registered.
+func (registry *Registry) Unregister(name string) bool {
+	if registry == nil {
+		return false
+	}
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+	_, ok := registry.collectors[name]
+	delete(registry.collectors, name)
+	return ok
+}
+
+// Gather collects the samples of every registered collector, sorted by name
+// and then labels.
+func (registry *Registry) Gather() []Sample {
+	if registry == nil {
+		return nil
+	}
+	registry.mux.RLock()
+	defer registry.mux.RUnlock()
+	var samples []Sample
+	for name, collector := range registry.collectors {
+		collector.Collect(func(sample Sample) {
+			sample.Name = name + "_" + sample.Name
+			samples = append(samples, sample)
+		})
+	}
+	sort.SliceStable(samples, func(i, j int) bool {
+		if samples[i].Name != samples[j].Name {
+			return samples[i].Name < samples[j].Name
+		}
+		return formatLabels(samples[i].Labels) < formatLabels(samples[j].Labels)
+	})
+	return samples
+}
+
+// Publish exposes the registry as an expvar variable holding each sample by
+// name and labels. Like expvar.Publish, it panics if name is already in use.
+func (registry *Registry) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		values := map[string]float64{}
+		for _, sample := range registry.Gather() {
+			values[sample.Name+formatLabels(sample.Labels)] = sample.Value
+		}
+		return values
+	}))
+}
+
+// WritePrometheus writes the samples in the Prometheus text exposition
+// format.
+func (registry *Registry) WritePrometheus(w io.Writer) error {
+	var b strings.Builder
+	previous := ""
+	for _, sample := range registry.Gather() {
+		if sample.Name != previous {
+			previous = sample.Name
+			kind := "gauge"
+			if sample.Kind == CounterSample {
+				kind = "counter"
+			}
+			if sample.Help != "" {
+				fmt.Fprintf(&b, "# HELP %s %s\n", sample.Name, sample.Help)
+			}
+			fmt.Fprintf(&b, "# TYPE %s %s\n", sample.Name, kind)
+		}
+		fmt.Fprintf(&b, "%s%s %s\n", sample.Name, formatLabels(sample.Labels),
+			strconv.FormatFloat(sample.Value, 'g', -1, 64))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatLabels formats labels as {key="value",...} sorted by key, or an
+// empty string if there are none.
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + strconv.Quote(labels[key])
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
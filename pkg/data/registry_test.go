@@ -0,0 +1,111 @@
+package data_test
+
+import (
+	"bytes"
+	"context"
+	"expvar"
+	. "fun/pkg/data"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func Test_Registry(t *testing.T) {
+	registry := NewRegistry()
+	list := NewList[Data]()
+	list.AppendAll(1, 2, 3)
+	queue := NewBlockingQueue[int](4)
+	queue.TryPut(1)
+	if err := registry.Register("list", list); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if err := registry.Register("queue", queue); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if err := registry.Register("list", queue); err == nil {
+		t.Error("expected error registering a duplicate name")
+	}
+	if err := registry.Register("bad name", queue); err == nil {
+		t.Error("expected error registering an invalid name")
+	}
+	if err := registry.Register("nothing", nil); err == nil {
+		t.Error("expected error registering a nil collector")
+	}
+
+	samples := registry.Gather()
+	names := make([]string, len(samples))
+	for i, sample := range samples {
+		names[i] = sample.Name
+	}
+	if strings.Join(names, " ") != "list_length queue_capacity queue_length" {
+		t.Error("unexpected samples", names)
+	}
+	if samples[0].Value != 3 || samples[2].Value != 1 {
+		t.Error("unexpected values", samples)
+	}
+
+	if !registry.Unregister("queue") || registry.Unregister("queue") {
+		t.Error("expected queue to be unregistered once")
+	}
+	if len(registry.Gather()) != 1 {
+		t.Error("expected only list samples after unregistering")
+	}
+
+	var nilRegistry *Registry
+	if nilRegistry.Register("list", list) == nil || nilRegistry.Gather() != nil || nilRegistry.Unregister("list") {
+		t.Error("expected nil registry to do nothing")
+	}
+}
+
+func Test_RegistryPrometheus(t *testing.T) {
+	registry := NewRegistry()
+	metrics := NewMetrics()
+	list := NewList[Data]()
+	list.SetMetrics(metrics)
+	list.Append(1)
+	list.Append(2)
+	list.DeleteHead()
+	registry.Register("list", list)
+	registry.Register("list_metrics", metrics)
+
+	var buffer bytes.Buffer
+	if err := registry.WritePrometheus(&buffer); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	text := buffer.String()
+	for _, expected := range []string{
+		"# HELP list_length Number of elements in the list.\n# TYPE list_length gauge\nlist_length 1\n",
+		"# TYPE list_metrics_operations_total counter\n" +
+			"list_metrics_operations_total{operation=\"Append\"} 2\n" +
+			"list_metrics_operations_total{operation=\"DeleteHead\"} 1\n",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("expected %q in:\n%s", expected, text)
+		}
+	}
+}
+
+// expvarRuns makes expvar names unique when tests are run more than once.
+var expvarRuns int
+
+func Test_RegistryExpvar(t *testing.T) {
+	registry := NewRegistry()
+	pool, _ := NewObjectPool(ObjectPoolConfig[int]{
+		New: func(ctx context.Context) (int, error) { return 1, nil },
+	})
+	defer pool.Close()
+	object, _ := pool.Get(context.Background())
+	pool.Put(object)
+	pool.Get(context.Background())
+	registry.Register("pool", pool)
+	expvarRuns++
+	name := "test_registry_" + strconv.Itoa(expvarRuns)
+	registry.Publish(name)
+
+	value := expvar.Get(name).String()
+	for _, expected := range []string{`"pool_hits_total":1`, `"pool_misses_total":1`, `"pool_idle":0`, `"pool_size":1`} {
+		if !strings.Contains(value, expected) {
+			t.Errorf("expected %s in %s", expected, value)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package data
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RenderFormat selects the output of Render.
+type RenderFormat int
+
+const (
+	RenderASCII   RenderFormat = iota // Plain text for terminals and logs.
+	RenderMermaid                     // Mermaid flowchart markup.
+	RenderDOT                         // Graphviz digraph.
+)
+
+// String names the render format.
+func (format RenderFormat) String() string {
+	switch format {
+	case RenderASCII:
+		return "ascii"
+	case RenderMermaid:
+		return "mermaid"
+	case RenderDOT:
+		return "dot"
+	}
+	return fmt.Sprintf("RenderFormat(%d)", int(format))
+}
+
+// ParseRenderFormat gets the render format with a name from String.
+func ParseRenderFormat(name string) (RenderFormat, error) {
+	for _, format := range []RenderFormat{RenderASCII, RenderMermaid, RenderDOT} {
+		if format.String() == name {
+			return format, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown render format %q", name)
+}
+
+// Renderer is a structure that can draw itself for debugging.
+type Renderer interface {
+	Render(w io.Writer, format RenderFormat) error
+}
+
+// Render draws the list as a chain of nodes.
+func (list *List[T]) Render(w io.Writer, format RenderFormat) error {
+	if list == nil {
+		return nilError("list")
+	}
+	return list.View().Render(w, format)
+}
+
+// Render draws the view as a chain of nodes.
+func (view *ListView[T]) Render(w io.Writer, format RenderFormat) error {
+	var b strings.Builder
+	switch format {
+	case RenderASCII:
+		fmt.Fprintf(&b, "length %d\n", len(view.values))
+		for _, value := range view.values {
+			fmt.Fprintf(&b, "[%s] -> ", fmt.Sprint(value))
+		}
+		b.WriteString("nil\n")
+	case RenderMermaid:
+		b.WriteString("flowchart LR\n\thead([head])\n\tnil((nil))\n")
+		for i, value := range view.values {
+			fmt.Fprintf(&b, "\tn%d[\"%s\"]\n", i, mermaidEscape(fmt.Sprint(value)))
+		}
+		renderChain(&b, len(view.values), "-->", "-.->")
+	case RenderDOT:
+		b.WriteString("digraph list {\n\trankdir=LR;\n\tnode [shape=box];\n")
+		b.WriteString("\thead [shape=plaintext];\n\ttail [shape=plaintext];\n\tnil [shape=point];\n")
+		for i, value := range view.values {
+			fmt.Fprintf(&b, "\tn%d [label=%q];\n", i, fmt.Sprint(value))
+		}
+		if len(view.values) == 0 {
+			b.WriteString("\thead -> nil;\n\ttail -> nil;\n")
+		} else {
+			for i := 1; i < len(view.values); i++ {
+				fmt.Fprintf(&b, "\tn%d -> n%d;\n", i-1, i)
+			}
+			last := len(view.values) - 1
+			fmt.Fprintf(&b, "\tn%d -> nil;\n\thead -> n0 [style=dashed];\n\ttail -> n%d [style=dashed];\n", last, last)
+		}
+		b.WriteString("}\n")
+	default:
+		return fmt.Errorf("unknown render format %s", format)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ToDOT writes the nodes of the list and their links as a Graphviz digraph,
+// with head and tail markers. Unlike Render, it follows the actual next and
+// previous pointers, so a corrupted list is drawn as it is: a cycle is drawn
+// back to the node it repeats, and a tail or previous pointer to a node not
+// reachable from the head points at a node labeled "?". Previous pointers
+// are omitted while the nodes are shared with a snapshot.
+func (list *List[T]) ToDOT(w io.Writer) error {
+	if list == nil {
+		return nilError("list")
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	defer list.tracer.end("List.ToDOT", list.tracer.begin(), list.length)
+
+	var b strings.Builder
+	b.WriteString("digraph list {\n\trankdir=LR;\n\tnode [shape=box];\n")
+	b.WriteString("\thead [shape=plaintext];\n\ttail [shape=plaintext];\n\tnil [shape=point];\n")
+	ids := map[*ListNode[T]]int{}
+	var order []*ListNode[T]
+	for node := list.head; node != nil; node = node.next {
+		if _, seen := ids[node]; seen {
+			break
+		}
+		ids[node] = len(order)
+		order = append(order, node)
+		fmt.Fprintf(&b, "\tn%d [label=%q];\n", ids[node], fmt.Sprint(node.value))
+	}
+	unknown := false
+	name := func(node *ListNode[T]) string {
+		if node == nil {
+			return "nil"
+		}
+		if id, ok := ids[node]; ok {
+			return fmt.Sprintf("n%d", id)
+		}
+		unknown = true
+		return "unknown"
+	}
+	for _, node := range order {
+		fmt.Fprintf(&b, "\t%s -> %s;\n", name(node), name(node.next))
+		if !list.shared && node.prev != nil {
+			fmt.Fprintf(&b, "\t%s -> %s [style=dotted];\n", name(node), name(node.prev))
+		}
+	}
+	fmt.Fprintf(&b, "\thead -> %s [style=dashed];\n", name(list.head))
+	fmt.Fprintf(&b, "\ttail -> %s [style=dashed];\n", name(list.tail))
+	if unknown {
+		b.WriteString("\tunknown [label=\"?\", shape=circle];\n")
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// renderChain writes the Mermaid edges linking head, n0..n(length-1), and
+// nil.
+func renderChain(b *strings.Builder, length int, edge, pointer string) {
+	if length == 0 {
+		fmt.Fprintf(b, "\thead %s nil\n", pointer)
+		return
+	}
+	fmt.Fprintf(b, "\thead %s n0\n", pointer)
+	for i := 1; i < length; i++ {
+		fmt.Fprintf(b, "\tn%d %s n%d\n", i-1, edge, i)
+	}
+	fmt.Fprintf(b, "\tn%d %s nil\n", length-1, edge)
+}
+
+// mermaidEscape replaces characters that end a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s)
+}
@@ -0,0 +1,150 @@
+package data_test
+
+import (
+	"bytes"
+	"fun/pkg/constraints"
+	. "fun/pkg/data"
+	"strings"
+	"testing"
+)
+
+func Test_Render(t *testing.T) {
+	list := NewList[Data]()
+	list.AppendAll(1, 2)
+
+	var b bytes.Buffer
+	if err := list.Render(&b, RenderASCII); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if b.String() != "length 2\n[1] -> [2] -> nil\n" {
+		t.Errorf("unexpected ascii %q", b.String())
+	}
+
+	b.Reset()
+	list.Render(&b, RenderMermaid)
+	expected := "flowchart LR\n\thead([head])\n\tnil((nil))\n\tn0[\"1\"]\n\tn1[\"2\"]\n\thead -.-> n0\n\tn0 --> n1\n\tn1 --> nil\n"
+	if b.String() != expected {
+		t.Errorf("unexpected mermaid %q", b.String())
+	}
+
+	b.Reset()
+	list.Render(&b, RenderDOT)
+	if !strings.HasPrefix(b.String(), "digraph list {") || !strings.Contains(b.String(), "n0 -> n1;") {
+		t.Errorf("unexpected dot %q", b.String())
+	}
+
+	b.Reset()
+	NewList[Data]().Render(&b, RenderMermaid)
+	if !strings.Contains(b.String(), "head -.-> nil") {
+		t.Errorf("unexpected empty mermaid %q", b.String())
+	}
+
+	if err := list.Render(&b, RenderFormat(9)); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	if format, err := ParseRenderFormat("mermaid"); err != nil || format != RenderMermaid {
+		t.Error("unexpected format", format, err)
+	}
+	if _, err := ParseRenderFormat("png"); err == nil {
+		t.Error("expected an error for an unknown format name")
+	}
+}
+
+func Test_ToDOT(t *testing.T) {
+	var b bytes.Buffer
+	if err := NewListOf[Data](1, 2).ToDOT(&b); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	expected := "digraph list {\n\trankdir=LR;\n\tnode [shape=box];\n" +
+		"\thead [shape=plaintext];\n\ttail [shape=plaintext];\n\tnil [shape=point];\n" +
+		"\tn0 [label=\"1\"];\n\tn1 [label=\"2\"];\n" +
+		"\tn0 -> n1;\n\tn1 -> nil;\n\tn1 -> n0 [style=dotted];\n" +
+		"\thead -> n0 [style=dashed];\n\ttail -> n1 [style=dashed];\n}\n"
+	if b.String() != expected {
+		t.Errorf("unexpected dot %q", b.String())
+	}
+
+	b.Reset()
+	NewList[Data]().ToDOT(&b)
+	if !strings.Contains(b.String(), "head -> nil [style=dashed];") {
+		t.Errorf("unexpected empty dot %q", b.String())
+	}
+	var unset *List[Data]
+	if err := unset.ToDOT(&b); err == nil {
+		t.Error("expected an error for a nil list")
+	}
+}
+
+func Test_RenderTrees(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	queue := NewPriorityQueue(less)
+	tree := NewBST[int, string](constraints.OrderedComparer[int]())
+	for _, value := range []int{4, 2, 6, 5} {
+		queue.Push(value)
+		tree.Insert(value, "")
+	}
+
+	var b bytes.Buffer
+	if err := queue.Render(&b, RenderASCII); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if b.String() != "2\n├── 4\n│   └── 5\n└── 6\n" {
+		t.Errorf("unexpected ascii %q", b.String())
+	}
+	b.Reset()
+	tree.Render(&b, RenderASCII)
+	if b.String() != "4\n├── L: 2\n└── R: 6\n    └── L: 5\n" {
+		t.Errorf("unexpected ascii %q", b.String())
+	}
+	b.Reset()
+	tree.Render(&b, RenderMermaid)
+	expected := "flowchart TD\n\tn0[\"4\"]\n\tn1[\"2\"]\n\tn0 -->|L| n1\n\tn2[\"6\"]\n\tn3[\"5\"]\n\tn2 -->|L| n3\n\tn0 -->|R| n2\n"
+	if b.String() != expected {
+		t.Errorf("unexpected mermaid %q", b.String())
+	}
+	b.Reset()
+	queue.Render(&b, RenderDOT)
+	if !strings.HasPrefix(b.String(), "digraph heap {") || !strings.Contains(b.String(), "n0 -> n1;") {
+		t.Errorf("unexpected dot %q", b.String())
+	}
+
+	btree := NewBTree[int, string](2, constraints.OrderedComparer[int]())
+	bplus := NewBPlusTree[int, string](2, constraints.OrderedComparer[int]())
+	for i := 1; i <= 4; i++ {
+		btree.Insert(i, "")
+		bplus.Insert(i, "")
+	}
+	pairing := NewPairingHeap(less)
+	fibonacci := NewFibonacciHeap(less)
+	minMax := NewMinMaxHeap(less)
+	for _, value := range []int{3, 1, 2} {
+		pairing.Push(value)
+		fibonacci.Push(value)
+		minMax.Push(value)
+	}
+	renderers := map[string]Renderer{
+		"btree": btree, "bplus": bplus, "pairing": pairing, "fibonacci": fibonacci, "minmax": minMax,
+	}
+	for name, renderer := range renderers {
+		for _, format := range []RenderFormat{RenderASCII, RenderMermaid, RenderDOT} {
+			b.Reset()
+			if err := renderer.Render(&b, format); err != nil || b.Len() == 0 {
+				t.Error("expected", name, "to render as", format, err)
+			}
+		}
+		if err := renderer.Render(&b, RenderFormat(9)); err == nil {
+			t.Error("expected an error for an unknown format")
+		}
+	}
+	b.Reset()
+	btree.Render(&b, RenderASCII)
+	if !strings.Contains(b.String(), "3 | 4") {
+		t.Errorf("expected a node of several keys, got %q", b.String())
+	}
+
+	b.Reset()
+	NewBST[int, string](constraints.OrderedComparer[int]()).Render(&b, RenderDOT)
+	if b.String() != "digraph tree {\n\tnode [shape=box];\n\tnil [shape=point];\n}\n" {
+		t.Errorf("unexpected empty dot %q", b.String())
+	}
+}
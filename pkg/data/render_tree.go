@@ -0,0 +1,275 @@
+package data
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// renderNode is a node of a tree drawn by renderTree, built while the lock
+// of the structure is held so it can be drawn after it is released.
+type renderNode struct {
+	label    string        // Values of the node.
+	edge     string        // Label of the link from the parent, may be empty.
+	children []*renderNode // Subtrees, in order.
+}
+
+// Render draws the heap as the binary tree its slice encodes.
+func (queue *PriorityQueue[T]) Render(w io.Writer, format RenderFormat) error {
+	if queue == nil {
+		return nilError("priority queue")
+	}
+	queue.mux.RLock()
+	roots := renderHeap(queue.values)
+	queue.mux.RUnlock()
+	return renderTree(w, format, "heap", roots)
+}
+
+// Render draws the heap as the binary tree its slice encodes, the root on a
+// min level.
+func (heap *MinMaxHeap[T]) Render(w io.Writer, format RenderFormat) error {
+	if heap == nil {
+		return nilError("min-max heap")
+	}
+	heap.mux.RLock()
+	roots := renderHeap(heap.values)
+	heap.mux.RUnlock()
+	return renderTree(w, format, "heap", roots)
+}
+
+// renderHeap builds the tree of a binary heap stored in a slice.
+func renderHeap[T any](values []T) []*renderNode {
+	if len(values) == 0 {
+		return nil
+	}
+	nodes := make([]*renderNode, len(values))
+	for i, value := range values {
+		nodes[i] = &renderNode{label: fmt.Sprint(value)}
+		if i > 0 {
+			parent := nodes[(i-1)/2]
+			parent.children = append(parent.children, nodes[i])
+		}
+	}
+	return nodes[:1]
+}
+
+// Render draws the heap as its tree, each node above its children.
+func (heap *PairingHeap[T]) Render(w io.Writer, format RenderFormat) error {
+	if heap == nil {
+		return nilError("pairing heap")
+	}
+	heap.mux.RLock()
+	var roots []*renderNode
+	if heap.root != nil {
+		roots = append(roots, renderPairing(heap.root))
+	}
+	heap.mux.RUnlock()
+	return renderTree(w, format, "heap", roots)
+}
+
+// renderPairing builds the tree of a pairing heap node.
+func renderPairing[T any](node *PairingNode[T]) *renderNode {
+	rendered := &renderNode{label: fmt.Sprint(node.value)}
+	for child := node.child; child != nil; child = child.sibling {
+		rendered.children = append(rendered.children, renderPairing(child))
+	}
+	return rendered
+}
+
+// Render draws the heap as its forest of trees, starting at the least root.
+// Marked nodes are labeled with a *.
+func (heap *FibonacciHeap[T]) Render(w io.Writer, format RenderFormat) error {
+	if heap == nil {
+		return nilError("fibonacci heap")
+	}
+	heap.mux.RLock()
+	roots := renderFibonacci(heap.min)
+	heap.mux.RUnlock()
+	return renderTree(w, format, "heap", roots)
+}
+
+// renderFibonacci builds the trees of a circular list of siblings.
+func renderFibonacci[T any](first *FibonacciNode[T]) []*renderNode {
+	if first == nil {
+		return nil
+	}
+	var nodes []*renderNode
+	node := first
+	for {
+		rendered := &renderNode{label: fmt.Sprint(node.value), children: renderFibonacci(node.child)}
+		if node.marked {
+			rendered.label += "*"
+		}
+		nodes = append(nodes, rendered)
+		if node = node.right; node == first {
+			return nodes
+		}
+	}
+}
+
+// Render draws the keys of the tree, labeling links to left and right
+// subtrees L and R.
+func (tree *BST[K, V]) Render(w io.Writer, format RenderFormat) error {
+	if tree == nil {
+		return nilError("binary search tree")
+	}
+	tree.mux.RLock()
+	var roots []*renderNode
+	if tree.root != nil {
+		roots = append(roots, renderBST(tree.root, ""))
+	}
+	tree.mux.RUnlock()
+	return renderTree(w, format, "tree", roots)
+}
+
+// renderBST builds the tree of a binary search tree node.
+func renderBST[K, V any](node *bstNode[K, V], edge string) *renderNode {
+	rendered := &renderNode{label: fmt.Sprint(node.key), edge: edge}
+	if node.left != nil {
+		rendered.children = append(rendered.children, renderBST(node.left, "L"))
+	}
+	if node.right != nil {
+		rendered.children = append(rendered.children, renderBST(node.right, "R"))
+	}
+	return rendered
+}
+
+// Render draws the keys of each node of the tree.
+func (tree *BTree[K, V]) Render(w io.Writer, format RenderFormat) error {
+	if tree == nil {
+		return nilError("btree")
+	}
+	tree.mux.RLock()
+	var roots []*renderNode
+	if tree.root != nil && len(tree.root.keys) > 0 {
+		roots = append(roots, renderBTree(tree.root))
+	}
+	tree.mux.RUnlock()
+	return renderTree(w, format, "tree", roots)
+}
+
+// renderBTree builds the tree of a B-tree node.
+func renderBTree[K, V any](node *btreeNode[K, V]) *renderNode {
+	rendered := &renderNode{label: renderKeys(node.keys)}
+	for _, child := range node.children {
+		rendered.children = append(rendered.children, renderBTree(child))
+	}
+	return rendered
+}
+
+// Render draws the keys of each node of the tree, with the separators of
+// the internal nodes above the keys of the leaves.
+func (tree *BPlusTree[K, V]) Render(w io.Writer, format RenderFormat) error {
+	if tree == nil {
+		return nilError("b+ tree")
+	}
+	tree.mux.RLock()
+	var roots []*renderNode
+	if tree.root != nil && tree.length > 0 {
+		roots = append(roots, renderBPlus(tree.root))
+	}
+	tree.mux.RUnlock()
+	return renderTree(w, format, "tree", roots)
+}
+
+// renderBPlus builds the tree of a B+ tree node.
+func renderBPlus[K, V any](node *bplusNode[K, V]) *renderNode {
+	rendered := &renderNode{label: renderKeys(node.keys)}
+	for _, child := range node.children {
+		rendered.children = append(rendered.children, renderBPlus(child))
+	}
+	return rendered
+}
+
+// renderKeys labels a node holding several keys.
+func renderKeys[K any](keys []K) string {
+	labels := make([]string, len(keys))
+	for i, key := range keys {
+		labels[i] = fmt.Sprint(key)
+	}
+	return strings.Join(labels, " | ")
+}
+
+// renderTree draws a forest, each node above its children: as an indented
+// outline for ASCII, and as a top-down graph named name for Mermaid and DOT.
+func renderTree(w io.Writer, format RenderFormat, name string, roots []*renderNode) error {
+	var b strings.Builder
+	switch format {
+	case RenderASCII:
+		if len(roots) == 0 {
+			b.WriteString("nil\n")
+		}
+		for _, root := range roots {
+			renderOutline(&b, root, "", "")
+		}
+	case RenderMermaid:
+		b.WriteString("flowchart TD\n")
+		if len(roots) == 0 {
+			b.WriteString("\tnil((nil))\n")
+		}
+		renderGraph(roots, func(id int, node *renderNode) {
+			fmt.Fprintf(&b, "\tn%d[\"%s\"]\n", id, mermaidEscape(node.label))
+		}, func(parent, child int, edge string) {
+			if edge == "" {
+				fmt.Fprintf(&b, "\tn%d --> n%d\n", parent, child)
+			} else {
+				fmt.Fprintf(&b, "\tn%d -->|%s| n%d\n", parent, edge, child)
+			}
+		})
+	case RenderDOT:
+		fmt.Fprintf(&b, "digraph %s {\n\tnode [shape=box];\n", name)
+		if len(roots) == 0 {
+			b.WriteString("\tnil [shape=point];\n")
+		}
+		renderGraph(roots, func(id int, node *renderNode) {
+			fmt.Fprintf(&b, "\tn%d [label=%q];\n", id, node.label)
+		}, func(parent, child int, edge string) {
+			if edge == "" {
+				fmt.Fprintf(&b, "\tn%d -> n%d;\n", parent, child)
+			} else {
+				fmt.Fprintf(&b, "\tn%d -> n%d [label=%q];\n", parent, child, edge)
+			}
+		})
+		b.WriteString("}\n")
+	default:
+		return fmt.Errorf("unknown render format %s", format)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// renderGraph numbers the nodes of a forest in preorder, calling node for
+// each node and then link for each link to a child.
+func renderGraph(roots []*renderNode, node func(id int, node *renderNode), link func(parent, child int, edge string)) {
+	next := 0
+	var walk func(rendered *renderNode) int
+	walk = func(rendered *renderNode) int {
+		id := next
+		next++
+		node(id, rendered)
+		for _, child := range rendered.children {
+			link(id, walk(child), child.edge)
+		}
+		return id
+	}
+	for _, root := range roots {
+		walk(root)
+	}
+}
+
+// renderOutline writes a node on a line after prefix, then its children
+// indented below it after indent.
+func renderOutline(b *strings.Builder, node *renderNode, prefix, indent string) {
+	b.WriteString(prefix)
+	if node.edge != "" {
+		b.WriteString(node.edge + ": ")
+	}
+	b.WriteString(node.label + "\n")
+	for i, child := range node.children {
+		if i == len(node.children)-1 {
+			renderOutline(b, child, indent+"└── ", indent+"    ")
+		} else {
+			renderOutline(b, child, indent+"├── ", indent+"│   ")
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package data
+
+import (
+	"fmt"
+	"iter"
+)
+
+// RingBuffer is a FIFO buffer of fixed capacity, such as a window of recent
+// history. Pushing to a full buffer overwrites the oldest element with the
+// EvictHead policy, and returns ErrFull with EvictError. A ring buffer never
+// blocks, so EvictBlock rejects like EvictError.
+type RingBuffer[T any] struct {
+	values  []T         // Circular buffer, len is the capacity.
+	head    int         // Index of the oldest element.
+	length  int         // Number of elements stored in the buffer.
+	policy  EvictPolicy // Handling of pushes while full.
+	metrics *Metrics    // Instrumentation, nil when disabled.
+	mux     locker      // Lock read and write operations.
+}
+
+// Create a new ring buffer holding at most capacity elements, at least 1,
+// configured by WithLocking, WithMetrics, and WithCapacity.
+func NewRingBuffer[T any](capacity int, policy EvictPolicy, opts ...Option) *RingBuffer[T] {
+	settings := newOptions(opts)
+	capacity = settings.capacityOr(capacity)
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &RingBuffer[T]{
+		values:  make([]T, capacity),
+		policy:  policy,
+		metrics: settings.metrics,
+		mux:     settings.newLocker(),
+	}
+}
+
+// Length reports the number of elements in the buffer.
+func (ring *RingBuffer[T]) Length() int {
+	if ring == nil {
+		return 0
+	}
+	ring.mux.RLock()
+	defer ring.mux.RUnlock()
+	return ring.length
+}
+
+// Capacity reports the maximum number of elements.
+func (ring *RingBuffer[T]) Capacity() int {
+	if ring == nil {
+		return 0
+	}
+	return len(ring.values)
+}
+
+// Push adds an element after the newest, following the policy if the buffer
+// is full.
+func (ring *RingBuffer[T]) Push(value T) error {
+	if ring == nil {
+		return nilError("ring buffer")
+	}
+	start := ring.metrics.begin()
+	ring.mux.Lock()
+	defer ring.mux.Unlock()
+	defer ring.metrics.end("Push", start, ring.metrics.acquired())
+	if ring.length == len(ring.values) {
+		if ring.policy != EvictHead {
+			return ErrFull
+		}
+		ring.values[ring.head] = value
+		ring.head = (ring.head + 1) % len(ring.values)
+		return nil
+	}
+	ring.values[(ring.head+ring.length)%len(ring.values)] = value
+	ring.length++
+	return nil
+}
+
+// Pop removes the oldest element.
+func (ring *RingBuffer[T]) Pop() (T, bool) {
+	var unset T
+	if ring == nil {
+		return unset, false
+	}
+	start := ring.metrics.begin()
+	ring.mux.Lock()
+	defer ring.mux.Unlock()
+	defer ring.metrics.end("Pop", start, ring.metrics.acquired())
+	if ring.length == 0 {
+		return unset, false
+	}
+	value := ring.values[ring.head]
+	ring.values[ring.head] = unset
+	ring.head = (ring.head + 1) % len(ring.values)
+	ring.length--
+	return value, true
+}
+
+// Peek gets the oldest element without removing it.
+func (ring *RingBuffer[T]) Peek() (T, bool) {
+	return ring.At(0)
+}
+
+// PeekNewest gets the newest element without removing it.
+func (ring *RingBuffer[T]) PeekNewest() (T, bool) {
+	var unset T
+	if ring == nil {
+		return unset, false
+	}
+	ring.mux.RLock()
+	defer ring.mux.RUnlock()
+	if ring.length == 0 {
+		return unset, false
+	}
+	return ring.values[(ring.head+ring.length-1)%len(ring.values)], true
+}
+
+// At gets the element at an index, counting from the oldest.
+func (ring *RingBuffer[T]) At(i int) (T, bool) {
+	var unset T
+	if ring == nil {
+		return unset, false
+	}
+	ring.mux.RLock()
+	defer ring.mux.RUnlock()
+	if i < 0 || i >= ring.length {
+		return unset, false
+	}
+	return ring.values[(ring.head+i)%len(ring.values)], true
+}
+
+// Values copies the elements into a slice, oldest first.
+func (ring *RingBuffer[T]) Values() []T {
+	if ring == nil {
+		return nil
+	}
+	ring.mux.RLock()
+	defer ring.mux.RUnlock()
+	values := make([]T, ring.length)
+	for i := range values {
+		values[i] = ring.values[(ring.head+i)%len(ring.values)]
+	}
+	return values
+}
+
+// All gets a sequence of the elements as of the call, oldest first.
+func (ring *RingBuffer[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range ring.Values() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// String converts RingBuffer data into a string.
+func (ring *RingBuffer[T]) String() string {
+	if ring == nil {
+		return ""
+	}
+	values := ring.Values()
+	s := fmt.Sprintf("Length: %d, Data:", len(values))
+	for _, v := range values {
+		s += " " + fmt.Sprint(v)
+	}
+	return s
+}
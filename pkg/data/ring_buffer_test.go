@@ -0,0 +1,62 @@
+package data_test
+
+import (
+	"errors"
+	. "fun/pkg/data"
+	"slices"
+	"testing"
+)
+
+func Test_RingBuffer(t *testing.T) {
+	window := NewRingBuffer[int](3, EvictHead)
+	for i := 1; i <= 5; i++ {
+		if err := window.Push(i); err != nil {
+			t.Error("expected push to overwrite the oldest, got", err)
+		}
+	}
+	if values := window.Values(); !slices.Equal(values, []int{3, 4, 5}) {
+		t.Error("expected the newest values", values)
+	}
+	if value, ok := window.Peek(); !ok || value != 3 {
+		t.Error("unexpected oldest", value, ok)
+	}
+	if value, ok := window.PeekNewest(); !ok || value != 5 {
+		t.Error("unexpected newest", value, ok)
+	}
+	if value, ok := window.At(1); !ok || value != 4 {
+		t.Error("unexpected value at 1", value, ok)
+	}
+	if window.Length() != 3 || window.Capacity() != 3 {
+		t.Error("unexpected length or capacity", window.Length(), window.Capacity())
+	}
+	if value, ok := window.Pop(); !ok || value != 3 {
+		t.Error("expected to pop 3, got", value, ok)
+	}
+	window.Push(6)
+	if window.String() != "Length: 3, Data: 4 5 6" {
+		t.Error("unexpected ring buffer", window.String())
+	}
+
+	rejecting := NewRingBuffer[int](2, EvictError)
+	rejecting.Push(1)
+	rejecting.Push(2)
+	if err := rejecting.Push(3); !errors.Is(err, ErrFull) {
+		t.Error("expected ErrFull, got", err)
+	}
+	if values := slices.Collect(rejecting.All()); !slices.Equal(values, []int{1, 2}) {
+		t.Error("expected the oldest values to be kept", values)
+	}
+	rejecting.Pop()
+	rejecting.Pop()
+	if _, ok := rejecting.Pop(); ok {
+		t.Error("expected an empty buffer")
+	}
+	if _, ok := rejecting.PeekNewest(); ok {
+		t.Error("expected no newest element")
+	}
+
+	var unset *RingBuffer[int]
+	if err := unset.Push(1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
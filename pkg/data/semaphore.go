@@ -0,0 +1,138 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// semaphoreWaiter is a caller blocked in Acquire.
+type semaphoreWaiter struct {
+	n     int64         // Weight requested.
+	ready chan struct{} // Closed when the weight is granted.
+}
+
+// Semaphore limits access to a resource of a fixed size. Callers acquire a
+// weight of it and are served in FIFO order, so a large request is not
+// starved by a stream of smaller ones.
+type Semaphore struct {
+	size    int64              // Total weight available.
+	used    int64              // Weight currently held.
+	waiters []*semaphoreWaiter // Blocked callers, oldest first.
+	mux     *sync.Mutex        // Lock read and write operations.
+}
+
+// Create a new semaphore of a total weight, configured by WithCapacity. A
+// counting semaphore is one where every caller acquires a weight of 1.
+func NewSemaphore(size int64, opts ...Option) *Semaphore {
+	size = int64(newOptions(opts).capacityOr(int(size)))
+	return &Semaphore{size: size, mux: &sync.Mutex{}}
+}
+
+// Size reports the total weight of the semaphore.
+func (semaphore *Semaphore) Size() int64 {
+	return semaphore.size
+}
+
+// Available reports the weight that is not held.
+func (semaphore *Semaphore) Available() int64 {
+	semaphore.mux.Lock()
+	defer semaphore.mux.Unlock()
+	return semaphore.size - semaphore.used
+}
+
+// Acquire a weight of n, blocking until it is available or ctx is done. On
+// failure nothing is held.
+func (semaphore *Semaphore) Acquire(ctx context.Context, n int64) error {
+	if n < 0 {
+		return fmt.Errorf("%w: semaphore weight %d is negative", ErrOutOfRange, n)
+	}
+	if n > semaphore.size {
+		return fmt.Errorf("%w: semaphore weight %d exceeds size %d", ErrOutOfRange, n, semaphore.size)
+	}
+	semaphore.mux.Lock()
+	if semaphore.size-semaphore.used >= n && len(semaphore.waiters) == 0 {
+		semaphore.used += n
+		semaphore.mux.Unlock()
+		return nil
+	}
+	if ctx.Err() != nil {
+		semaphore.mux.Unlock()
+		return canceled(ctx)
+	}
+	waiter := &semaphoreWaiter{n: n, ready: make(chan struct{})}
+	semaphore.waiters = append(semaphore.waiters, waiter)
+	semaphore.mux.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+		semaphore.mux.Lock()
+		defer semaphore.mux.Unlock()
+		select {
+		case <-waiter.ready:
+			// Granted while canceling, the caller holds the weight.
+			return nil
+		default:
+		}
+		for i, w := range semaphore.waiters {
+			if w == waiter {
+				semaphore.waiters = append(semaphore.waiters[:i], semaphore.waiters[i+1:]...)
+				break
+			}
+		}
+		// Waiters behind this one may now fit.
+		semaphore.notify()
+		return canceled(ctx)
+	}
+}
+
+// TryAcquire a weight of n without blocking, reporting whether it was acquired.
+func (semaphore *Semaphore) TryAcquire(n int64) bool {
+	if n < 0 {
+		return false
+	}
+	semaphore.mux.Lock()
+	defer semaphore.mux.Unlock()
+	if semaphore.size-semaphore.used < n || len(semaphore.waiters) > 0 {
+		return false
+	}
+	semaphore.used += n
+	return true
+}
+
+// Release a weight of n previously acquired.
+func (semaphore *Semaphore) Release(n int64) {
+	semaphore.mux.Lock()
+	defer semaphore.mux.Unlock()
+	semaphore.used -= n
+	if semaphore.used < 0 {
+		semaphore.used += n
+		panic("semaphore released more than held")
+	}
+	semaphore.notify()
+}
+
+// Do runs f holding a weight of n, releasing it when f returns.
+func (semaphore *Semaphore) Do(ctx context.Context, n int64, f func() error) error {
+	if err := semaphore.Acquire(ctx, n); err != nil {
+		return err
+	}
+	defer semaphore.Release(n)
+	return f()
+}
+
+// notify grants waiters in order while their weight fits, lock must be held.
+func (semaphore *Semaphore) notify() {
+	for len(semaphore.waiters) > 0 {
+		waiter := semaphore.waiters[0]
+		if semaphore.size-semaphore.used < waiter.n {
+			return
+		}
+		semaphore.used += waiter.n
+		semaphore.waiters[0] = nil
+		semaphore.waiters = semaphore.waiters[1:]
+		close(waiter.ready)
+	}
+}
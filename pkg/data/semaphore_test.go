@@ -0,0 +1,111 @@
+package data_test
+
+import (
+	"context"
+	"errors"
+	. "fun/pkg/data"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_Semaphore(t *testing.T) {
+	ctx := context.Background()
+	semaphore := NewSemaphore(3)
+	if err := semaphore.Acquire(ctx, 2); err != nil {
+		t.Error("unexpected error", err)
+	}
+	if semaphore.Available() != 1 {
+		t.Error("expected 1 available, got", semaphore.Available())
+	}
+	if semaphore.TryAcquire(2) {
+		t.Error("expected TryAcquire over the available weight to fail")
+	}
+	if !semaphore.TryAcquire(1) {
+		t.Error("expected TryAcquire of the available weight to succeed")
+	}
+	semaphore.Release(3)
+	if semaphore.Available() != 3 {
+		t.Error("expected 3 available, got", semaphore.Available())
+	}
+	if err := semaphore.Acquire(ctx, 4); err == nil {
+		t.Error("expected error acquiring more than the size")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic releasing more than held")
+			}
+		}()
+		semaphore.Release(1)
+	}()
+}
+
+func Test_SemaphoreCancel(t *testing.T) {
+	semaphore := NewSemaphore(1)
+	semaphore.TryAcquire(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := semaphore.Acquire(ctx, 1); !errors.Is(err, ErrCanceled) {
+		t.Error("expected acquire to be canceled, got", err)
+	}
+	semaphore.Release(1)
+	if semaphore.Available() != 1 {
+		t.Error("expected canceled acquire to hold nothing, available", semaphore.Available())
+	}
+}
+
+func Test_SemaphoreFairness(t *testing.T) {
+	ctx := context.Background()
+	semaphore := NewSemaphore(2)
+	semaphore.TryAcquire(1)
+
+	acquired := make(chan struct{})
+	go func() {
+		semaphore.Acquire(ctx, 2)
+		close(acquired)
+	}()
+	for semaphore.TryAcquire(0) {
+		// Wait for the large request to queue.
+		time.Sleep(time.Millisecond)
+	}
+	if semaphore.TryAcquire(1) {
+		t.Error("expected small request not to barge ahead of a waiter")
+	}
+	semaphore.Release(1)
+	<-acquired
+	if semaphore.Available() != 0 {
+		t.Error("expected waiter to hold the full weight, available", semaphore.Available())
+	}
+}
+
+func Test_SemaphoreConcurrency(t *testing.T) {
+	const threads = 20
+	ctx := context.Background()
+	semaphore := NewSemaphore(3)
+	var running, maxRunning int32
+	var wg sync.WaitGroup
+	wg.Add(threads)
+	for i := 0; i < threads; i++ {
+		go func() {
+			defer wg.Done()
+			semaphore.Do(ctx, 1, func() error {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					max := atomic.LoadInt32(&maxRunning)
+					if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	if maxRunning > 3 {
+		t.Error("expected at most 3 concurrent holders, got", maxRunning)
+	}
+}
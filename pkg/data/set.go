@@ -0,0 +1,194 @@
+package data
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+)
+
+// Set is an unordered collection of distinct values. The algebra methods
+// copy each operand before combining them, so two sets are never locked
+// together, and they return a new set configured by the default options.
+type Set[T comparable] struct {
+	members map[T]struct{} // Values in the set.
+	metrics *Metrics       // Instrumentation, nil when disabled.
+	mux     locker         // Lock read and write operations.
+}
+
+// Create a new set, configured by WithLocking and WithMetrics.
+func NewSet[T comparable](opts ...Option) *Set[T] {
+	settings := newOptions(opts)
+	return &Set[T]{members: map[T]struct{}{}, metrics: settings.metrics, mux: settings.newLocker()}
+}
+
+// Create a new set of values, configured by the default options.
+func NewSetOf[T comparable](values ...T) *Set[T] {
+	set := NewSet[T]()
+	set.Add(values...)
+	return set
+}
+
+// newSetFrom creates a set owning the members.
+func newSetFrom[T comparable](members map[T]struct{}) *Set[T] {
+	set := NewSet[T]()
+	set.members = members
+	return set
+}
+
+// Length reports the number of values in the set.
+func (set *Set[T]) Length() int {
+	if set == nil {
+		return 0
+	}
+	set.mux.RLock()
+	defer set.mux.RUnlock()
+	return len(set.members)
+}
+
+// Add adds values that are not already in the set.
+func (set *Set[T]) Add(values ...T) error {
+	if set == nil {
+		return nilError("set")
+	}
+	start := set.metrics.begin()
+	set.mux.Lock()
+	defer set.mux.Unlock()
+	defer set.metrics.end("Add", start, set.metrics.acquired())
+	for _, value := range values {
+		set.members[value] = struct{}{}
+	}
+	return nil
+}
+
+// Remove removes a value, reporting whether it was in the set.
+func (set *Set[T]) Remove(value T) bool {
+	if set == nil {
+		return false
+	}
+	start := set.metrics.begin()
+	set.mux.Lock()
+	defer set.mux.Unlock()
+	defer set.metrics.end("Remove", start, set.metrics.acquired())
+	_, ok := set.members[value]
+	delete(set.members, value)
+	return ok
+}
+
+// Contains reports whether a value is in the set.
+func (set *Set[T]) Contains(value T) bool {
+	if set == nil {
+		return false
+	}
+	set.mux.RLock()
+	defer set.mux.RUnlock()
+	_, ok := set.members[value]
+	return ok
+}
+
+// copyMembers copies the members, none for a nil set.
+func (set *Set[T]) copyMembers() map[T]struct{} {
+	if set == nil {
+		return map[T]struct{}{}
+	}
+	set.mux.RLock()
+	defer set.mux.RUnlock()
+	return maps.Clone(set.members)
+}
+
+// Union gets a set of the values in either set.
+func (set *Set[T]) Union(other *Set[T]) *Set[T] {
+	members := set.copyMembers()
+	maps.Copy(members, other.copyMembers())
+	return newSetFrom(members)
+}
+
+// Intersection gets a set of the values in both sets.
+func (set *Set[T]) Intersection(other *Set[T]) *Set[T] {
+	members, others := set.copyMembers(), other.copyMembers()
+	maps.DeleteFunc(members, func(value T, _ struct{}) bool {
+		_, ok := others[value]
+		return !ok
+	})
+	return newSetFrom(members)
+}
+
+// Difference gets a set of the values in this set but not the other.
+func (set *Set[T]) Difference(other *Set[T]) *Set[T] {
+	members, others := set.copyMembers(), other.copyMembers()
+	maps.DeleteFunc(members, func(value T, _ struct{}) bool {
+		_, ok := others[value]
+		return ok
+	})
+	return newSetFrom(members)
+}
+
+// SymmetricDifference gets a set of the values in exactly one of the sets.
+func (set *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	members, others := set.copyMembers(), other.copyMembers()
+	for value := range others {
+		if _, ok := members[value]; ok {
+			delete(members, value)
+		} else {
+			members[value] = struct{}{}
+		}
+	}
+	return newSetFrom(members)
+}
+
+// IsSubset reports whether every value in this set is in the other.
+func (set *Set[T]) IsSubset(other *Set[T]) bool {
+	members, others := set.copyMembers(), other.copyMembers()
+	if len(members) > len(others) {
+		return false
+	}
+	for value := range members {
+		if _, ok := others[value]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset reports whether every value in the other set is in this one.
+func (set *Set[T]) IsSuperset(other *Set[T]) bool {
+	return other.IsSubset(set)
+}
+
+// Equal reports whether the sets have the same values.
+func (set *Set[T]) Equal(other *Set[T]) bool {
+	return maps.Equal(set.copyMembers(), other.copyMembers())
+}
+
+// Values copies the values into a slice, in no particular order.
+func (set *Set[T]) Values() []T {
+	members := set.copyMembers()
+	values := make([]T, 0, len(members))
+	for value := range members {
+		values = append(values, value)
+	}
+	return values
+}
+
+// All gets a sequence of the values as of the call, in no particular order.
+func (set *Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for value := range set.copyMembers() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// String converts Set data into a string, in no particular order.
+func (set *Set[T]) String() string {
+	if set == nil {
+		return ""
+	}
+	values := set.Values()
+	s := fmt.Sprintf("Length: %d, Data:", len(values))
+	for _, v := range values {
+		s += " " + fmt.Sprint(v)
+	}
+	return s
+}
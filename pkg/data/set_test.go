@@ -0,0 +1,120 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"slices"
+	"testing"
+)
+
+// sortedValues gets the values of a set in order.
+func sortedValues(set *Set[int]) []int {
+	return slices.Sorted(set.All())
+}
+
+func Test_Set(t *testing.T) {
+	a, b := NewSetOf(1, 2, 3, 4), NewSetOf(3, 4, 5)
+	if a.Length() != 4 || !a.Contains(2) || a.Contains(5) {
+		t.Error("unexpected set", a)
+	}
+	if union := sortedValues(a.Union(b)); !slices.Equal(union, []int{1, 2, 3, 4, 5}) {
+		t.Error("unexpected union", union)
+	}
+	if intersection := sortedValues(a.Intersection(b)); !slices.Equal(intersection, []int{3, 4}) {
+		t.Error("unexpected intersection", intersection)
+	}
+	if difference := sortedValues(a.Difference(b)); !slices.Equal(difference, []int{1, 2}) {
+		t.Error("unexpected difference", difference)
+	}
+	if symmetric := sortedValues(a.SymmetricDifference(b)); !slices.Equal(symmetric, []int{1, 2, 5}) {
+		t.Error("unexpected symmetric difference", symmetric)
+	}
+	if !a.Intersection(b).IsSubset(b) || a.IsSubset(b) || !a.IsSuperset(NewSetOf(1, 4)) {
+		t.Error("unexpected subsets")
+	}
+	if !a.Equal(NewSetOf(4, 3, 2, 1)) || a.Equal(b) {
+		t.Error("unexpected equality")
+	}
+	if !a.Union(a).Equal(a) || a.Difference(a).Length() != 0 {
+		t.Error("unexpected algebra of a set with itself")
+	}
+	if !b.Remove(5) || b.Remove(5) || b.Length() != 2 {
+		t.Error("expected to remove 5 once", b)
+	}
+
+	var unset *Set[int]
+	if err := unset.Add(1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+	if !unset.IsSubset(a) || !a.Union(unset).Equal(a) {
+		t.Error("expected a nil set to be empty")
+	}
+}
+
+// setPair is a pair of sets to combine.
+type setPair struct {
+	a, b *Set[int]
+}
+
+// setMachine checks set algebra against maps, adding and removing values of
+// two sets.
+var setMachine = datatest.Machine[*setPair, *[2]map[int]bool]{
+	NewSystem: func() *setPair {
+		return &setPair{NewSet[int](WithLocking(false)), NewSet[int](WithLocking(false))}
+	},
+	NewModel: func() *[2]map[int]bool { return &[2]map[int]bool{{}, {}} },
+	Commands: []datatest.Command[*setPair, *[2]map[int]bool]{
+		{Name: "Add", Run: func(sets *setPair, model *[2]map[int]bool, arg int) error {
+			model[arg%2][arg/2] = true
+			return []*Set[int]{sets.a, sets.b}[arg%2].Add(arg / 2)
+		}},
+		{Name: "Remove", Run: func(sets *setPair, model *[2]map[int]bool, arg int) error {
+			found := model[arg%2][arg/2]
+			delete(model[arg%2], arg/2)
+			if ok := []*Set[int]{sets.a, sets.b}[arg%2].Remove(arg / 2); ok != found {
+				return fmt.Errorf("Remove returned %t, expected %t", ok, found)
+			}
+			return nil
+		}},
+	},
+	Check: func(sets *setPair, model *[2]map[int]bool) error {
+		a, b := model[0], model[1]
+		expected := map[string]func(int) bool{
+			"union":        func(v int) bool { return a[v] || b[v] },
+			"intersection": func(v int) bool { return a[v] && b[v] },
+			"difference":   func(v int) bool { return a[v] && !b[v] },
+			"symmetric":    func(v int) bool { return a[v] != b[v] },
+		}
+		results := map[string]*Set[int]{
+			"union":        sets.a.Union(sets.b),
+			"intersection": sets.a.Intersection(sets.b),
+			"difference":   sets.a.Difference(sets.b),
+			"symmetric":    sets.a.SymmetricDifference(sets.b),
+		}
+		for name, result := range results {
+			var values []int
+			for v := range 8 {
+				if expected[name](v) {
+					values = append(values, v)
+				}
+			}
+			if got := sortedValues(result); !slices.Equal(got, values) {
+				return fmt.Errorf("%s: expected %v, got %v", name, values, got)
+			}
+		}
+		subset := true
+		for v := range a {
+			subset = subset && b[v]
+		}
+		if sets.a.IsSubset(sets.b) != subset {
+			return fmt.Errorf("IsSubset returned %t, expected %t", !subset, subset)
+		}
+		return nil
+	},
+}
+
+func Test_SetModel(t *testing.T) {
+	setMachine.Test(t, datatest.Config{Runs: 200, Length: 50})
+}
@@ -0,0 +1,151 @@
+package data
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Snapshottable is a structure that can be captured by a Snapshotter. It is
+// implemented by List, captured as a *ListView, HashMap and SortedMap,
+// captured as a *MapView, and Set and SortedSet, captured as a *SetView.
+type Snapshottable interface {
+	snapshotKey() uintptr // Order of the structure's lock, see lockKey.
+	snapshotLock()        // Acquire the structure's read lock.
+	snapshotUnlock()      // Release the structure's read lock.
+	snapshot() any        // Build an immutable view, read lock must be held.
+}
+
+// Snapshotter atomically captures a group of registered structures.
+//
+// All registered structures are read-locked together before any is copied,
+// so the views in a Snapshot are mutually consistent. The locks are taken in
+// the same global order as Concat and Meld take theirs, so snapshots cannot
+// deadlock with those operations or with each other.
+type Snapshotter struct {
+	names      []string                 // Registration order of structures.
+	structures map[string]Snapshottable // Registered structures by name.
+	mux        *sync.Mutex              // Lock registration and snapshot operations.
+}
+
+// Snapshot is a consistent, immutable capture of a group of structures.
+type Snapshot struct {
+	time  time.Time      // When the snapshot was taken.
+	names []string       // Names of the captured structures.
+	views map[string]any // Immutable views by name.
+}
+
+// Create a new snapshotter.
+func NewSnapshotter() *Snapshotter {
+	return &Snapshotter{structures: map[string]Snapshottable{}, mux: &sync.Mutex{}}
+}
+
+// Register adds a named structure to the snapshotter.
+func (snapshotter *Snapshotter) Register(name string, structure Snapshottable) error {
+	if snapshotter == nil {
+		return nilError("snapshotter")
+	}
+	if name == "" {
+		return fmt.Errorf("%w: snapshot name is empty", ErrInvalid)
+	}
+	if structure == nil {
+		return fmt.Errorf("%w: structure is nil", ErrInvalid)
+	}
+	snapshotter.mux.Lock()
+	defer snapshotter.mux.Unlock()
+	if _, ok := snapshotter.structures[name]; ok {
+		return fmt.Errorf("%w: structure already registered as %s", ErrDuplicate, name)
+	}
+	for _, existing := range snapshotter.structures {
+		if existing == structure {
+			return fmt.Errorf("%w: structure already registered", ErrDuplicate)
+		}
+	}
+	snapshotter.names = append(snapshotter.names, name)
+	snapshotter.structures[name] = structure
+	return nil
+}
+
+// Unregister removes a named structure from the snapshotter.
+func (snapshotter *Snapshotter) Unregister(name string) bool {
+	if snapshotter == nil {
+		return false
+	}
+	snapshotter.mux.Lock()
+	defer snapshotter.mux.Unlock()
+	if _, ok := snapshotter.structures[name]; !ok {
+		return false
+	}
+	delete(snapshotter.structures, name)
+	for i, n := range snapshotter.names {
+		if n == name {
+			snapshotter.names = append(snapshotter.names[:i], snapshotter.names[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Snapshot captures every registered structure at a single point in time.
+func (snapshotter *Snapshotter) Snapshot() *Snapshot {
+	if snapshotter == nil {
+		return nil
+	}
+	snapshotter.mux.Lock()
+	defer snapshotter.mux.Unlock()
+
+	locked := make([]Snapshottable, 0, len(snapshotter.names))
+	for _, name := range snapshotter.names {
+		locked = append(locked, snapshotter.structures[name])
+	}
+	slices.SortFunc(locked, func(a, b Snapshottable) int {
+		return cmp.Compare(a.snapshotKey(), b.snapshotKey())
+	})
+	for _, structure := range locked {
+		structure.snapshotLock()
+	}
+	snap := &Snapshot{
+		time:  time.Now(),
+		names: append([]string(nil), snapshotter.names...),
+		views: make(map[string]any, len(snapshotter.names)),
+	}
+	for _, name := range snapshotter.names {
+		snap.views[name] = snapshotter.structures[name].snapshot()
+	}
+	for _, structure := range slices.Backward(locked) {
+		structure.snapshotUnlock()
+	}
+	return snap
+}
+
+// Time reports when the snapshot was taken.
+func (snap *Snapshot) Time() time.Time {
+	return snap.time
+}
+
+// Names lists the captured structures in registration order.
+func (snap *Snapshot) Names() []string {
+	return append([]string(nil), snap.names...)
+}
+
+// View gets the immutable view of a captured structure.
+func (snap *Snapshot) View(name string) (any, bool) {
+	view, ok := snap.views[name]
+	return view, ok
+}
+
+// SnapshotView gets the view of a captured structure as a concrete type,
+// for example SnapshotView[*ListView[int]](snap, "name").
+func SnapshotView[V any](snap *Snapshot, name string) (V, bool) {
+	var unset V
+	if snap == nil {
+		return unset, false
+	}
+	view, ok := snap.views[name].(V)
+	if !ok {
+		return unset, false
+	}
+	return view, true
+}
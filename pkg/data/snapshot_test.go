@@ -0,0 +1,193 @@
+package data_test
+
+import (
+	"fun/pkg/constraints"
+	. "fun/pkg/data"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_SnapshotRegister(t *testing.T) {
+	snapshotter := NewSnapshotter()
+	list := NewList[Data]()
+	if err := snapshotter.Register("", list); err == nil {
+		t.Error("expected error registering empty name")
+	}
+	if err := snapshotter.Register("list", nil); err == nil {
+		t.Error("expected error registering nil structure")
+	}
+	if err := snapshotter.Register("list", list); err != nil {
+		t.Error("unexpected error", err)
+	}
+	if err := snapshotter.Register("list", NewList[Data]()); err == nil {
+		t.Error("expected error registering duplicate name")
+	}
+	if err := snapshotter.Register("other", list); err == nil {
+		t.Error("expected error registering duplicate structure")
+	}
+	if !snapshotter.Unregister("list") {
+		t.Error("failed to unregister list")
+	}
+	if snapshotter.Unregister("list") {
+		t.Error("unregistered list twice")
+	}
+	if names := snapshotter.Snapshot().Names(); len(names) != 0 {
+		t.Error("expected no names, got", names)
+	}
+}
+
+func Test_SnapshotViews(t *testing.T) {
+	snapshotter := NewSnapshotter()
+	a := NewList[Data]()
+	b := NewList[Data]()
+	a.Append(1)
+	a.Append(2)
+	b.Append(3)
+	snapshotter.Register("a", a)
+	snapshotter.Register("b", b)
+
+	snap := snapshotter.Snapshot()
+	a.Append(4)
+	b.Delete(3)
+
+	if names := snap.Names(); len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Error("expected names [a b], got", names)
+	}
+	viewA, ok := SnapshotView[*ListView[Data]](snap, "a")
+	if !ok {
+		t.Fatal("missing view a")
+	}
+	if viewA.String() != "Length: 2, Data: 1 2" {
+		t.Error("unexpected view a", viewA.String())
+	}
+	viewB, ok := SnapshotView[*ListView[Data]](snap, "b")
+	if !ok {
+		t.Fatal("missing view b")
+	}
+	if value, ok := viewB.At(0); !ok || value != 3 {
+		t.Error("expected view b to hold 3, got", viewB.String())
+	}
+	if _, ok := viewB.At(1); ok {
+		t.Error("expected out of range view access to fail")
+	}
+	if _, ok := SnapshotView[*ListView[Data]](snap, "c"); ok {
+		t.Error("expected missing view c")
+	}
+	if _, ok := SnapshotView[string](snap, "a"); ok {
+		t.Error("expected view of the wrong type to fail")
+	}
+}
+
+func Test_SnapshotConsistency(t *testing.T) {
+	const iterations = 1000
+
+	snapshotter := NewSnapshotter()
+	a := NewList[Data]()
+	b := NewList[Data]()
+	snapshotter.Register("a", a)
+	snapshotter.Register("b", b)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			a.Append(Data(i))
+			b.Append(Data(i))
+		}
+	}()
+
+	for i := 0; i < iterations; i++ {
+		snap := snapshotter.Snapshot()
+		viewA, _ := SnapshotView[*ListView[Data]](snap, "a")
+		viewB, _ := SnapshotView[*ListView[Data]](snap, "b")
+		if diff := viewA.Length() - viewB.Length(); diff < 0 || diff > 1 {
+			t.Fatal("inconsistent snapshot", viewA.Length(), viewB.Length())
+		}
+	}
+	wg.Wait()
+}
+
+func Test_SnapshotLockOrder(t *testing.T) {
+	const iterations = 20000
+
+	// Register the lists in both orders, so one snapshotter lists them
+	// against the order Concat locks them in.
+	a, b := NewListOf[Data](1), NewListOf[Data](2)
+	forward, backward := NewSnapshotter(), NewSnapshotter()
+	forward.Register("a", a)
+	forward.Register("b", b)
+	backward.Register("b", b)
+	backward.Register("a", a)
+
+	var wg sync.WaitGroup
+	for _, concat := range []func(){
+		func() { a.Concat(b) },
+		func() { b.Concat(a) },
+		func() { forward.Snapshot() },
+		func() { backward.Snapshot() },
+	} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				concat()
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("snapshots deadlocked with Concat")
+	}
+}
+
+func Test_SnapshotContainers(t *testing.T) {
+	hash := NewHashMap[string, int](WithHasher(constraints.FoldHasher()))
+	sortedMap := NewSortedMap[int, string](constraints.OrderedComparer[int]())
+	set := NewSetOf(1, 2)
+	sortedSet := NewSortedSet(constraints.OrderedComparer[int]())
+	hash.Put("a", 1)
+	sortedMap.Put(2, "b")
+	sortedMap.Put(1, "a")
+	sortedSet.Add(3, 1, 2)
+
+	snapshotter := NewSnapshotter()
+	snapshotter.Register("hash", hash)
+	snapshotter.Register("sorted map", sortedMap)
+	snapshotter.Register("set", set)
+	snapshotter.Register("sorted set", sortedSet)
+	snap := snapshotter.Snapshot()
+	hash.Put("b", 2)
+	sortedMap.Delete(1)
+	set.Remove(1)
+	sortedSet.Add(4)
+
+	hashView, _ := SnapshotView[*MapView[string, int]](snap, "hash")
+	if value, ok := hashView.Get("A"); !ok || value != 1 || hashView.Contains("b") || hashView.Length() != 1 {
+		t.Error("unexpected hash map view", value, ok, hashView.Length())
+	}
+	sortedMapView, _ := SnapshotView[*MapView[int, string]](snap, "sorted map")
+	var keys []int
+	for key := range sortedMapView.All() {
+		keys = append(keys, key)
+	}
+	if value, ok := sortedMapView.Get(1); !ok || value != "a" || !slices.Equal(keys, []int{1, 2}) {
+		t.Error("unexpected sorted map view", keys, value, ok)
+	}
+	setView, _ := SnapshotView[*SetView[int]](snap, "set")
+	if !setView.Contains(1) || setView.Length() != 2 {
+		t.Error("unexpected set view", slices.Collect(setView.All()))
+	}
+	sortedSetView, _ := SnapshotView[*SetView[int]](snap, "sorted set")
+	if values := slices.Collect(sortedSetView.All()); !slices.Equal(values, []int{1, 2, 3}) || sortedSetView.Contains(4) || !sortedSetView.Contains(2) {
+		t.Error("unexpected sorted set view", values)
+	}
+}
@@ -0,0 +1,206 @@
+package data
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"unsafe"
+)
+
+// MapView is an immutable copy of the keys and values of a HashMap or
+// SortedMap, captured by a Snapshotter.
+type MapView[K, V any] struct {
+	length int                   // Number of keys.
+	get    func(key K) (V, bool) // Finds the value of a key.
+	all    iter.Seq2[K, V]       // Keys and values in the order of the map.
+}
+
+// Length reports the number of keys in the view.
+func (view *MapView[K, V]) Length() int {
+	return view.length
+}
+
+// Get gets the value stored under a key.
+func (view *MapView[K, V]) Get(key K) (V, bool) {
+	return view.get(key)
+}
+
+// Contains reports whether a key is in the view.
+func (view *MapView[K, V]) Contains(key K) bool {
+	_, ok := view.get(key)
+	return ok
+}
+
+// All gets a sequence of the keys and values, in the order of the map they
+// were copied from.
+func (view *MapView[K, V]) All() iter.Seq2[K, V] {
+	return view.all
+}
+
+// SetView is an immutable copy of the values of a Set or SortedSet,
+// captured by a Snapshotter.
+type SetView[T any] struct {
+	length   int                // Number of values.
+	contains func(value T) bool // Reports whether a value is in the view.
+	all      iter.Seq[T]        // Values in the order of the set.
+}
+
+// Length reports the number of values in the view.
+func (view *SetView[T]) Length() int {
+	return view.length
+}
+
+// Contains reports whether a value is in the view.
+func (view *SetView[T]) Contains(value T) bool {
+	return view.contains(value)
+}
+
+// All gets a sequence of the values, in the order of the set they were
+// copied from.
+func (view *SetView[T]) All() iter.Seq[T] {
+	return view.all
+}
+
+func (hash *HashMap[K, V]) snapshotKey() uintptr {
+	return lockKey(unsafe.Pointer(hash))
+}
+
+func (hash *HashMap[K, V]) snapshotLock() {
+	if hash != nil {
+		hash.mux.RLock()
+	}
+}
+
+func (hash *HashMap[K, V]) snapshotUnlock() {
+	if hash != nil {
+		hash.mux.RUnlock()
+	}
+}
+
+// snapshot copies the slots into an unlocked map read through a MapView.
+func (hash *HashMap[K, V]) snapshot() any {
+	if hash == nil {
+		hash = NewHashMap[K, V]()
+	}
+	copied := &HashMap[K, V]{slots: slices.Clone(hash.slots), length: hash.length, hasher: hash.hasher, mux: noLock{}}
+	return &MapView[K, V]{length: copied.length, get: copied.Get, all: copied.All()}
+}
+
+func (sorted *SortedMap[K, V]) snapshotKey() uintptr {
+	return lockKey(unsafe.Pointer(sorted.btree()))
+}
+
+func (sorted *SortedMap[K, V]) snapshotLock() {
+	if tree := sorted.btree(); tree != nil {
+		tree.mux.RLock()
+	}
+}
+
+func (sorted *SortedMap[K, V]) snapshotUnlock() {
+	if tree := sorted.btree(); tree != nil {
+		tree.mux.RUnlock()
+	}
+}
+
+// snapshot copies the keys and values in order into a MapView searched by
+// binary search.
+func (sorted *SortedMap[K, V]) snapshot() any {
+	var keys []K
+	var values []V
+	tree := sorted.btree()
+	if tree != nil && tree.root != nil {
+		var lo K
+		tree.ascend(tree.root, lo, false, func(key K, value V) bool {
+			keys = append(keys, key)
+			values = append(values, value)
+			return true
+		})
+	}
+	return &MapView[K, V]{
+		length: len(keys),
+		get: func(key K) (V, bool) {
+			var unset V
+			if tree == nil {
+				return unset, false
+			}
+			i, found := slices.BinarySearchFunc(keys, key, tree.comparer.Compare)
+			if !found {
+				return unset, false
+			}
+			return values[i], true
+		},
+		all: func(yield func(K, V) bool) {
+			for i, key := range keys {
+				if !yield(key, values[i]) {
+					return
+				}
+			}
+		},
+	}
+}
+
+func (set *Set[T]) snapshotKey() uintptr {
+	return lockKey(unsafe.Pointer(set))
+}
+
+func (set *Set[T]) snapshotLock() {
+	if set != nil {
+		set.mux.RLock()
+	}
+}
+
+func (set *Set[T]) snapshotUnlock() {
+	if set != nil {
+		set.mux.RUnlock()
+	}
+}
+
+// snapshot copies the members into a SetView.
+func (set *Set[T]) snapshot() any {
+	members := map[T]struct{}{}
+	if set != nil {
+		members = maps.Clone(set.members)
+	}
+	return &SetView[T]{
+		length: len(members),
+		contains: func(value T) bool {
+			_, ok := members[value]
+			return ok
+		},
+		all: maps.Keys(members),
+	}
+}
+
+func (set *SortedSet[T]) snapshotKey() uintptr {
+	return lockKey(unsafe.Pointer(set))
+}
+
+func (set *SortedSet[T]) snapshotLock() {
+	if set != nil {
+		set.mux.RLock()
+	}
+}
+
+func (set *SortedSet[T]) snapshotUnlock() {
+	if set != nil {
+		set.mux.RUnlock()
+	}
+}
+
+// snapshot copies the values in order into a SetView searched by binary
+// search.
+func (set *SortedSet[T]) snapshot() any {
+	if set == nil {
+		return &SetView[T]{contains: func(T) bool { return false }, all: slices.Values([]T(nil))}
+	}
+	values := set.collect(set.head.next[0].node, set.length, nil)
+	comparer := set.comparer
+	return &SetView[T]{
+		length: len(values),
+		contains: func(value T) bool {
+			_, found := slices.BinarySearchFunc(values, value, comparer.Compare)
+			return found
+		},
+		all: slices.Values(values),
+	}
+}
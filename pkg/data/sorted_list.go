@@ -0,0 +1,182 @@
+package data
+
+import (
+	"fmt"
+	"fun/pkg/constraints"
+	"iter"
+)
+
+// SortedList is a list kept in the order of a comparer. Insert places each
+// value after the values that compare equal to it, so equal values keep
+// their insertion order.
+type SortedList[T ListData] struct {
+	list     *List[T]                // Values in order.
+	comparer constraints.Comparer[T] // Order of the values.
+}
+
+// Create a new sorted list ordered by comparer, configured by WithComparator
+// and the options of NewList.
+func NewSortedList[T ListData](comparer constraints.Comparer[T], opts ...Option) *SortedList[T] {
+	return &SortedList[T]{list: NewList[T](opts...), comparer: comparerOr(newOptions(opts), comparer)}
+}
+
+// Length reports the number of elements in the list.
+func (sorted *SortedList[T]) Length() int {
+	if sorted == nil {
+		return 0
+	}
+	return sorted.list.Length()
+}
+
+// Insert adds an element at its sorted position.
+func (sorted *SortedList[T]) Insert(value T) error {
+	if sorted == nil {
+		return nilError("sorted list")
+	}
+	list := sorted.list
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("Insert", start, list.metrics.acquired())
+	defer list.tracer.end("SortedList.Insert", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	list.unshare()
+	var parent *ListNode[T]
+	for node := list.head; node != nil && sorted.comparer.Compare(node.value, value) <= 0; node = node.next {
+		parent = node
+	}
+	if parent == nil {
+		list.insert(value)
+	} else {
+		list.insertAfter(parent, value)
+	}
+	return nil
+}
+
+// Delete the first element equal to value, reporting whether there was one.
+func (sorted *SortedList[T]) Delete(value T) bool {
+	if sorted == nil {
+		return false
+	}
+	return sorted.list.Delete(value)
+}
+
+// DeleteHead removes the smallest element.
+func (sorted *SortedList[T]) DeleteHead() (T, bool) {
+	if sorted == nil {
+		var unset T
+		return unset, false
+	}
+	return sorted.list.DeleteHead()
+}
+
+// DeleteTail removes the largest element.
+func (sorted *SortedList[T]) DeleteTail() (T, bool) {
+	if sorted == nil {
+		var unset T
+		return unset, false
+	}
+	return sorted.list.DeleteTail()
+}
+
+// Contains reports whether a value compares equal to an element.
+func (sorted *SortedList[T]) Contains(value T) bool {
+	ceiling, ok := sorted.Ceiling(value)
+	return ok && sorted.comparer.Compare(ceiling, value) == 0
+}
+
+// Floor gets the largest element less than or equal to value.
+func (sorted *SortedList[T]) Floor(value T) (T, bool) {
+	var floor T
+	if sorted == nil {
+		return floor, false
+	}
+	list := sorted.list
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	defer list.tracer.end("SortedList.Floor", list.tracer.begin(), list.length)
+	found := false
+	for node := list.head; node != nil && sorted.comparer.Compare(node.value, value) <= 0; node = node.next {
+		floor, found = node.value, true
+	}
+	return floor, found
+}
+
+// Ceiling gets the smallest element greater than or equal to value.
+func (sorted *SortedList[T]) Ceiling(value T) (T, bool) {
+	var unset T
+	if sorted == nil {
+		return unset, false
+	}
+	list := sorted.list
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	defer list.tracer.end("SortedList.Ceiling", list.tracer.begin(), list.length)
+	for node := list.head; node != nil; node = node.next {
+		if sorted.comparer.Compare(node.value, value) >= 0 {
+			return node.value, true
+		}
+	}
+	return unset, false
+}
+
+// Range gets a sequence of the elements from low to high inclusive, in
+// order, with the consistency of List.Iterator.
+func (sorted *SortedList[T]) Range(low, high T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if sorted == nil {
+			return
+		}
+		for value := range sorted.list.All() {
+			if sorted.comparer.Compare(value, low) < 0 {
+				continue
+			}
+			if sorted.comparer.Compare(value, high) > 0 || !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// All gets a sequence of the elements in order, with the consistency of
+// List.Iterator.
+func (sorted *SortedList[T]) All() iter.Seq[T] {
+	if sorted == nil {
+		return func(func(T) bool) {}
+	}
+	return sorted.list.All()
+}
+
+// View captures an immutable view of the list.
+func (sorted *SortedList[T]) View() *ListView[T] {
+	if sorted == nil {
+		return &ListView[T]{}
+	}
+	return sorted.list.View()
+}
+
+// String converts SortedList data into a string.
+func (sorted *SortedList[T]) String() string {
+	if sorted == nil {
+		return ""
+	}
+	return sorted.list.String()
+}
+
+// CheckInvariants verifies the underlying list and that its elements are in
+// order.
+func (sorted *SortedList[T]) CheckInvariants() error {
+	if sorted == nil {
+		return nil
+	}
+	if err := sorted.list.CheckInvariants(); err != nil {
+		return err
+	}
+	values := sorted.list.View().values
+	for i := 1; i < len(values); i++ {
+		if sorted.comparer.Compare(values[i-1], values[i]) > 0 {
+			return fmt.Errorf("sorted list: values %d and %d are out of order", i-1, i)
+		}
+	}
+	return nil
+}
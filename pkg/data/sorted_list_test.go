@@ -0,0 +1,82 @@
+package data_test
+
+import (
+	"fun/pkg/constraints"
+	. "fun/pkg/data"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func Test_SortedList(t *testing.T) {
+	sorted := NewSortedList[Data](constraints.OrderedComparer[Data]())
+	for _, value := range []Data{5, 1, 4, 1, 3, 9} {
+		sorted.Insert(value)
+	}
+	if sorted.String() != "Length: 6, Data: 1 1 3 4 5 9" {
+		t.Error("unexpected sorted list", sorted.String())
+	}
+	if value, ok := sorted.Floor(6); !ok || value != 5 {
+		t.Error("unexpected floor", value, ok)
+	}
+	if value, ok := sorted.Floor(4); !ok || value != 4 {
+		t.Error("expected floor of an element to be itself", value, ok)
+	}
+	if _, ok := sorted.Floor(0); ok {
+		t.Error("expected no floor below the smallest element")
+	}
+	if value, ok := sorted.Ceiling(6); !ok || value != 9 {
+		t.Error("unexpected ceiling", value, ok)
+	}
+	if _, ok := sorted.Ceiling(10); ok {
+		t.Error("expected no ceiling above the largest element")
+	}
+	if values := slices.Collect(sorted.Range(2, 5)); !slices.Equal(values, []Data{3, 4, 5}) {
+		t.Error("unexpected range", values)
+	}
+	if !sorted.Contains(3) || sorted.Contains(2) {
+		t.Error("unexpected membership")
+	}
+	if value, _ := sorted.DeleteHead(); value != 1 {
+		t.Error("expected to delete the smallest element, got", value)
+	}
+	if value, _ := sorted.DeleteTail(); value != 9 {
+		t.Error("expected to delete the largest element, got", value)
+	}
+
+	reversed := NewSortedList[Data](constraints.Reverse(constraints.OrderedComparer[Data]()))
+	reversed.Insert(1)
+	reversed.Insert(3)
+	reversed.Insert(2)
+	if values := slices.Collect(reversed.All()); !slices.Equal(values, []Data{3, 2, 1}) {
+		t.Error("unexpected reversed order", values)
+	}
+}
+
+func Test_SortedListStable(t *testing.T) {
+	byKey := constraints.ComparerFunc[pair](func(a, b pair) int { return a.Key - b.Key })
+	sorted := NewSortedList[pair](byKey)
+	random := rand.New(rand.NewSource(1))
+	var expected []pair
+	for i := 0; i < 200; i++ {
+		value := pair{random.Intn(5), i}
+		expected = append(expected, value)
+		sorted.Insert(value)
+		if err := sorted.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	slices.SortStableFunc(expected, byKey)
+	if values := sorted.View().Values(); !slices.Equal(values, expected) {
+		t.Error("expected equal keys in insertion order")
+	}
+
+	snapshot := NewSortedList[Data](constraints.OrderedComparer[Data]())
+	snapshot.Insert(1)
+	snapshot.Insert(3)
+	view := snapshot.View()
+	snapshot.Insert(2)
+	if view.Length() != 2 || snapshot.Length() != 3 {
+		t.Error("unexpected lengths", view.Length(), snapshot.Length())
+	}
+}
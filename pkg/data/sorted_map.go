@@ -0,0 +1,108 @@
+package data
+
+import (
+	"fun/pkg/constraints"
+	"iter"
+)
+
+// sortedMapDegree is the minimum degree of the B-tree of a SortedMap.
+const sortedMapDegree = 16
+
+// SortedMap is a map kept in the order of a comparer, stored in a BTree, so
+// Get, Put, and Delete take O(log n) time and the keys can be walked in
+// order or searched for the nearest key. Use constraints.OrderedComparer
+// for keys with the built-in order.
+type SortedMap[K, V any] struct {
+	tree *BTree[K, V] // Keys and values in order.
+}
+
+// Create a new sorted map ordered by comparer, configured by the options of
+// NewBTree.
+func NewSortedMap[K, V any](comparer constraints.Comparer[K], opts ...Option) *SortedMap[K, V] {
+	return &SortedMap[K, V]{tree: NewBTree[K, V](sortedMapDegree, comparer, opts...)}
+}
+
+// Length reports the number of keys in the map.
+func (sorted *SortedMap[K, V]) Length() int {
+	return sorted.btree().Length()
+}
+
+// Put stores a value under a key, replacing any value already there.
+func (sorted *SortedMap[K, V]) Put(key K, value V) error {
+	if sorted == nil {
+		return nilError("sorted map")
+	}
+	return sorted.tree.Insert(key, value)
+}
+
+// Get gets the value stored under a key.
+func (sorted *SortedMap[K, V]) Get(key K) (V, bool) {
+	return sorted.btree().Find(key)
+}
+
+// Contains reports whether a key is in the map.
+func (sorted *SortedMap[K, V]) Contains(key K) bool {
+	_, ok := sorted.Get(key)
+	return ok
+}
+
+// Delete removes a key, reporting whether there was one.
+func (sorted *SortedMap[K, V]) Delete(key K) bool {
+	return sorted.btree().Delete(key)
+}
+
+// Min gets the least key and its value.
+func (sorted *SortedMap[K, V]) Min() (K, V, bool) {
+	return sorted.btree().Min()
+}
+
+// Max gets the greatest key and its value.
+func (sorted *SortedMap[K, V]) Max() (K, V, bool) {
+	return sorted.btree().Max()
+}
+
+// Floor gets the greatest key not greater than key, and its value.
+func (sorted *SortedMap[K, V]) Floor(key K) (K, V, bool) {
+	return sorted.btree().Floor(key)
+}
+
+// Ceiling gets the least key not less than key, and its value.
+func (sorted *SortedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	return sorted.btree().Ceiling(key)
+}
+
+// btree gets the tree of the map, or nil for a nil map, which the methods
+// of BTree treat as empty.
+func (sorted *SortedMap[K, V]) btree() *BTree[K, V] {
+	if sorted == nil {
+		return nil
+	}
+	return sorted.tree
+}
+
+// Keys copies the keys into a slice, in order.
+func (sorted *SortedMap[K, V]) Keys() []K {
+	return sorted.btree().Keys()
+}
+
+// All gets a sequence of the keys and values as of the call, in key order.
+func (sorted *SortedMap[K, V]) All() iter.Seq2[K, V] {
+	return sorted.btree().All()
+}
+
+// Range gets a sequence of the keys from lo up to but not including hi, and
+// their values, as of the call, in key order.
+func (sorted *SortedMap[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return sorted.btree().Range(lo, hi)
+}
+
+// String converts SortedMap data into a string of key:value pairs, in key
+// order.
+func (sorted *SortedMap[K, V]) String() string {
+	return sorted.btree().String()
+}
+
+// CheckInvariants verifies the invariants of the tree.
+func (sorted *SortedMap[K, V]) CheckInvariants() error {
+	return sorted.btree().CheckInvariants()
+}
@@ -0,0 +1,108 @@
+package data
+
+import (
+	"fmt"
+	"fun/pkg/codec"
+	"slices"
+)
+
+// Field numbers of the messages in sorted_map.proto.
+const (
+	protoSortedMapEntries = 1 // SortedMap.entries
+	protoEntryKey         = 1 // SortedMapEntry.key
+	protoEntryValue       = 2 // SortedMapEntry.value
+)
+
+// MarshalProto encodes the map as a SortedMap protocol buffer message, as
+// defined in sorted_map.proto, with each key and value encoded by keys and
+// values.
+func (sorted *SortedMap[K, V]) MarshalProto(keys codec.Codec[K], values codec.Codec[V]) ([]byte, error) {
+	if sorted == nil {
+		return nil, nilError("sorted map")
+	}
+	var lo K
+	mapKeys, mapValues := sorted.tree.collect(lo, false, nil)
+	var data []byte
+	for i, key := range mapKeys {
+		encodedKey, err := keys.Encode(key)
+		if err != nil {
+			return nil, err
+		}
+		encodedValue, err := values.Encode(mapValues[i])
+		if err != nil {
+			return nil, err
+		}
+		entry := codec.AppendProtoBytes(nil, protoEntryKey, encodedKey)
+		entry = codec.AppendProtoBytes(entry, protoEntryValue, encodedValue)
+		data = codec.AppendProtoBytes(data, protoSortedMapEntries, entry)
+	}
+	return data, nil
+}
+
+// UnmarshalProto replaces the contents of the map with a SortedMap protocol
+// buffer message, decoding each key and value with keys and values. The
+// entries may be in any order, and the last entry of a key wins, as for a
+// protocol buffer map. The map is unchanged if decoding fails.
+func (sorted *SortedMap[K, V]) UnmarshalProto(data []byte, keys codec.Codec[K], values codec.Codec[V]) error {
+	if sorted == nil {
+		return nilError("sorted map")
+	}
+	type entry struct {
+		key   K
+		value V
+	}
+	var entries []entry
+	err := codec.ReadProtoBytes(data, func(field int, message []byte) error {
+		if field != protoSortedMapEntries {
+			return nil
+		}
+		var encodedKey, encodedValue []byte
+		err := codec.ReadProtoBytes(message, func(field int, value []byte) error {
+			switch field {
+			case protoEntryKey:
+				encodedKey = value
+			case protoEntryValue:
+				encodedValue = value
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		var decoded entry
+		if decoded.key, err = keys.Decode(encodedKey); err != nil {
+			return fmt.Errorf("sorted map entry %d key: %w", len(entries), err)
+		}
+		if decoded.value, err = values.Decode(encodedValue); err != nil {
+			return fmt.Errorf("sorted map entry %d value: %w", len(entries), err)
+		}
+		entries = append(entries, decoded)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	tree := sorted.tree
+	slices.SortStableFunc(entries, func(a, b entry) int {
+		return tree.comparer.Compare(a.key, b.key)
+	})
+	mapKeys := make([]K, 0, len(entries))
+	mapValues := make([]V, 0, len(entries))
+	for _, decoded := range entries {
+		if n := len(mapKeys); n > 0 && tree.comparer.Compare(mapKeys[n-1], decoded.key) == 0 {
+			mapValues[n-1] = decoded.value
+			continue
+		}
+		mapKeys = append(mapKeys, decoded.key)
+		mapValues = append(mapValues, decoded.value)
+	}
+	start := tree.metrics.begin()
+	tree.mux.Lock()
+	defer tree.mux.Unlock()
+	defer tree.metrics.end("UnmarshalProto", start, tree.metrics.acquired())
+	defer tree.debugCheck()
+	tree.root = nil
+	tree.load(mapKeys, mapValues)
+	return nil
+}
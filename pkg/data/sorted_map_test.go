@@ -0,0 +1,185 @@
+package data_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"fun/pkg/codec"
+	"fun/pkg/constraints"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func Test_SortedMap(t *testing.T) {
+	sorted := NewSortedMap[string, int](constraints.OrderedComparer[string]())
+	for i, key := range []string{"pear", "apple", "fig", "plum", "date"} {
+		sorted.Put(key, i)
+	}
+	if key, _, ok := sorted.Min(); !ok || key != "apple" {
+		t.Error("unexpected least key", key, ok)
+	}
+	if key, _, ok := sorted.Max(); !ok || key != "plum" {
+		t.Error("unexpected greatest key", key, ok)
+	}
+	if key, value, ok := sorted.Floor("grape"); !ok || key != "fig" || value != 2 {
+		t.Error("unexpected floor", key, value, ok)
+	}
+	if key, _, ok := sorted.Ceiling("grape"); !ok || key != "pear" {
+		t.Error("unexpected ceiling", key, ok)
+	}
+	if _, _, ok := sorted.Floor("a"); ok {
+		t.Error("expected no floor before the least key")
+	}
+	var keys []string
+	for key := range sorted.Range("b", "p") {
+		keys = append(keys, key)
+	}
+	if !slices.Equal(keys, []string{"date", "fig"}) {
+		t.Error("unexpected range", keys)
+	}
+	if !sorted.Delete("fig") || sorted.Contains("fig") {
+		t.Error("expected to delete fig")
+	}
+	if sorted.String() != "Length: 4, Data: apple:1 date:4 pear:0 plum:3" {
+		t.Error("unexpected map", sorted.String())
+	}
+
+	var unset *SortedMap[string, int]
+	if err := unset.Put("a", 1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+	if _, _, ok := unset.Min(); ok || unset.Length() != 0 {
+		t.Error("expected a nil map to be empty")
+	}
+}
+
+// sortedMapMachine checks a SortedMap against a map model, finding floors
+// and ceilings by scanning the sorted keys.
+var sortedMapMachine = datatest.Machine[*SortedMap[int, int], map[int]int]{
+	NewSystem: func() *SortedMap[int, int] {
+		return NewSortedMap[int, int](constraints.OrderedComparer[int](), WithLocking(false))
+	},
+	NewModel: func() map[int]int { return map[int]int{} },
+	ArgRange: 128,
+	Commands: []datatest.Command[*SortedMap[int, int], map[int]int]{
+		{Name: "Put", Run: func(sorted *SortedMap[int, int], model map[int]int, arg int) error {
+			model[arg] = -arg
+			return sorted.Put(arg, -arg)
+		}},
+		{Name: "Delete", Run: func(sorted *SortedMap[int, int], model map[int]int, arg int) error {
+			_, found := model[arg]
+			delete(model, arg)
+			if ok := sorted.Delete(arg); ok != found {
+				return fmt.Errorf("Delete returned %t, expected %t", ok, found)
+			}
+			return nil
+		}},
+		{Name: "Floor", Run: func(sorted *SortedMap[int, int], model map[int]int, arg int) error {
+			expected, found := -1, false
+			for _, key := range slices.Sorted(maps.Keys(model)) {
+				if key <= arg {
+					expected, found = key, true
+				}
+			}
+			if key, _, ok := sorted.Floor(arg); ok != found || (ok && key != expected) {
+				return fmt.Errorf("Floor(%d) returned %d %t, expected %d %t", arg, key, ok, expected, found)
+			}
+			return nil
+		}},
+		{Name: "Ceiling", Run: func(sorted *SortedMap[int, int], model map[int]int, arg int) error {
+			expected, found := -1, false
+			for _, key := range slices.Backward(slices.Sorted(maps.Keys(model))) {
+				if key >= arg {
+					expected, found = key, true
+				}
+			}
+			if key, _, ok := sorted.Ceiling(arg); ok != found || (ok && key != expected) {
+				return fmt.Errorf("Ceiling(%d) returned %d %t, expected %d %t", arg, key, ok, expected, found)
+			}
+			return nil
+		}},
+		{Name: "Range", Run: func(sorted *SortedMap[int, int], model map[int]int, arg int) error {
+			var expected []int
+			for _, key := range slices.Sorted(maps.Keys(model)) {
+				if key >= arg && key < arg+16 {
+					expected = append(expected, key)
+				}
+			}
+			var keys []int
+			for key := range sorted.Range(arg, arg+16) {
+				keys = append(keys, key)
+			}
+			if !slices.Equal(keys, expected) {
+				return fmt.Errorf("Range(%d, %d) returned %v, expected %v", arg, arg+16, keys, expected)
+			}
+			return nil
+		}},
+	},
+	Check: func(sorted *SortedMap[int, int], model map[int]int) error {
+		if keys := sorted.Keys(); !slices.Equal(keys, slices.Sorted(maps.Keys(model))) {
+			return fmt.Errorf("unexpected keys %v", keys)
+		}
+		return nil
+	},
+}
+
+func Test_SortedMapModel(t *testing.T) {
+	sortedMapMachine.Test(t, datatest.Config{Runs: 200, Length: 200})
+}
+
+func Test_SortedMapProto(t *testing.T) {
+	keys, values := codec.JSON[string](), codec.JSON[int]()
+	sorted := NewSortedMap[string, int](constraints.OrderedComparer[string]())
+	for i, key := range []string{"pear", "apple", "fig"} {
+		sorted.Put(key, i)
+	}
+	data, err := sorted.MarshalProto(keys, values)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	entry := codec.AppendProtoBytes(codec.AppendProtoBytes(nil, 1, []byte(`"apple"`)), 2, []byte("1"))
+	if expected := codec.AppendProtoBytes(nil, 1, entry); !bytes.HasPrefix(data, expected) {
+		t.Errorf("expected the first entry % x, got % x", expected, data)
+	}
+
+	decoded := NewSortedMap[string, int](constraints.OrderedComparer[string]())
+	decoded.Put("plum", 9)
+	if err := decoded.UnmarshalProto(data, keys, values); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if !maps.Equal(maps.Collect(decoded.All()), maps.Collect(sorted.All())) {
+		t.Error("expected the decoded map to match, got", decoded)
+	}
+
+	// Entries out of order with a repeated key, as another writer may send.
+	unordered := codec.AppendProtoBytes(nil, 1, codec.AppendProtoBytes(codec.AppendProtoBytes(nil, 1, []byte(`"b"`)), 2, []byte("1")))
+	unordered = codec.AppendProtoBytes(unordered, 1, codec.AppendProtoBytes(codec.AppendProtoBytes(nil, 1, []byte(`"a"`)), 2, []byte("2")))
+	unordered = codec.AppendProtoBytes(unordered, 1, codec.AppendProtoBytes(codec.AppendProtoBytes(nil, 1, []byte(`"b"`)), 2, []byte("3")))
+	if err := decoded.UnmarshalProto(unordered, keys, values); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if !slices.Equal(decoded.Keys(), []string{"a", "b"}) || !decoded.Contains("a") {
+		t.Error("expected keys a and b, got", decoded)
+	}
+	if value, _ := decoded.Get("b"); value != 3 {
+		t.Error("expected the last value of b, got", value)
+	}
+	if err := decoded.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+
+	bad := codec.AppendProtoBytes(nil, 1, codec.AppendProtoBytes(nil, 1, []byte("x")))
+	if err := decoded.UnmarshalProto(bad, keys, values); err == nil {
+		t.Error("expected an error decoding a malformed key")
+	}
+	if decoded.Length() != 2 {
+		t.Error("expected a failed decode to leave the map unchanged, got", decoded)
+	}
+	var unset *SortedMap[string, int]
+	if _, err := unset.MarshalProto(keys, values); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
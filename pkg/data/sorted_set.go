@@ -0,0 +1,394 @@
+package data
+
+import (
+	"fmt"
+	"fun/pkg/constraints"
+	"iter"
+	"math/bits"
+	"math/rand/v2"
+)
+
+// sortedSetMaxLevel is the number of levels of the skip list of a
+// SortedSet, enough for 4^32 values.
+const sortedSetMaxLevel = 32
+
+// skipLink is a link from a node of a skip list to the next node at a
+// level.
+type skipLink[T any] struct {
+	node *skipNode[T] // Next node at the level, nil at the end.
+	span int          // Number of ranks the link skips, to the end if last.
+}
+
+// skipNode is a node of a skip list, linked at each of its levels.
+type skipNode[T any] struct {
+	value T             // Value of the node, unset in the head.
+	next  []skipLink[T] // Links from the lowest level up.
+}
+
+// SortedSet is a set kept in the order of a comparer, stored in a skip list
+// whose links record how many values they skip. Add, Remove, and Contains
+// take expected O(log n) time, and so do Rank and Select, which convert
+// between values and their positions in order, as for a leaderboard.
+type SortedSet[T any] struct {
+	head     *skipNode[T]            // Sentinel linked to the first node at each level.
+	level    int                     // Number of levels in use, at least 1.
+	length   int                     // Number of values stored in the set.
+	comparer constraints.Comparer[T] // Order of the values.
+	metrics  *Metrics                // Instrumentation, nil when disabled.
+	mux      locker                  // Lock read and write operations.
+}
+
+// Create a new sorted set ordered by comparer, configured by WithLocking,
+// WithMetrics, and WithComparator.
+func NewSortedSet[T any](comparer constraints.Comparer[T], opts ...Option) *SortedSet[T] {
+	settings := newOptions(opts)
+	return &SortedSet[T]{
+		head:     &skipNode[T]{next: make([]skipLink[T], sortedSetMaxLevel)},
+		level:    1,
+		comparer: comparerOr(settings, comparer),
+		metrics:  settings.metrics,
+		mux:      settings.newLocker(),
+	}
+}
+
+// Length reports the number of values in the set.
+func (set *SortedSet[T]) Length() int {
+	if set == nil {
+		return 0
+	}
+	set.mux.RLock()
+	defer set.mux.RUnlock()
+	return set.length
+}
+
+// before reports whether the node a link leads to is before value.
+func (set *SortedSet[T]) before(link skipLink[T], value T) bool {
+	return link.node != nil && set.comparer.Compare(link.node.value, value) < 0
+}
+
+// Add adds values that are not already in the set.
+func (set *SortedSet[T]) Add(values ...T) error {
+	if set == nil {
+		return nilError("sorted set")
+	}
+	start := set.metrics.begin()
+	set.mux.Lock()
+	defer set.mux.Unlock()
+	defer set.metrics.end("Add", start, set.metrics.acquired())
+	defer set.debugCheck()
+	for _, value := range values {
+		set.add(value)
+	}
+	return nil
+}
+
+// add adds a value if it is not already in the set, lock must be held.
+func (set *SortedSet[T]) add(value T) {
+	// Find the last node before value at each level, and its rank.
+	var update [sortedSetMaxLevel]*skipNode[T]
+	var rank [sortedSetMaxLevel]int
+	node := set.head
+	for i := set.level - 1; i >= 0; i-- {
+		if i < set.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for set.before(node.next[i], value) {
+			rank[i] += node.next[i].span
+			node = node.next[i].node
+		}
+		update[i] = node
+	}
+	if next := node.next[0].node; next != nil && set.comparer.Compare(next.value, value) == 0 {
+		return
+	}
+	level := randomLevel()
+	for i := set.level; i < level; i++ {
+		update[i], rank[i] = set.head, 0
+		set.head.next[i].span = set.length
+	}
+	set.level = max(set.level, level)
+	added := &skipNode[T]{value: value, next: make([]skipLink[T], level)}
+	for i := range level {
+		link := &update[i].next[i]
+		skipped := rank[0] - rank[i]
+		added.next[i] = skipLink[T]{node: link.node, span: link.span - skipped}
+		*link = skipLink[T]{node: added, span: skipped + 1}
+	}
+	for i := level; i < set.level; i++ {
+		update[i].next[i].span++
+	}
+	set.length++
+}
+
+// randomLevel picks the number of levels of a new node, each level after
+// the first with probability 1/4.
+func randomLevel() int {
+	return min(1+bits.TrailingZeros64(rand.Uint64())/2, sortedSetMaxLevel)
+}
+
+// Remove removes a value, reporting whether it was in the set.
+func (set *SortedSet[T]) Remove(value T) bool {
+	if set == nil {
+		return false
+	}
+	start := set.metrics.begin()
+	set.mux.Lock()
+	defer set.mux.Unlock()
+	defer set.metrics.end("Remove", start, set.metrics.acquired())
+	defer set.debugCheck()
+	var update [sortedSetMaxLevel]*skipNode[T]
+	node := set.head
+	for i := set.level - 1; i >= 0; i-- {
+		for set.before(node.next[i], value) {
+			node = node.next[i].node
+		}
+		update[i] = node
+	}
+	removed := node.next[0].node
+	if removed == nil || set.comparer.Compare(removed.value, value) != 0 {
+		return false
+	}
+	for i := range set.level {
+		link := &update[i].next[i]
+		if link.node == removed {
+			*link = skipLink[T]{node: removed.next[i].node, span: link.span + removed.next[i].span - 1}
+		} else {
+			link.span--
+		}
+	}
+	for set.level > 1 && set.head.next[set.level-1].node == nil {
+		set.level--
+	}
+	set.length--
+	return true
+}
+
+// seek finds the last node before value and the number of nodes up to it,
+// lock must be held.
+func (set *SortedSet[T]) seek(value T) (*skipNode[T], int) {
+	node, rank := set.head, 0
+	for i := set.level - 1; i >= 0; i-- {
+		for set.before(node.next[i], value) {
+			rank += node.next[i].span
+			node = node.next[i].node
+		}
+	}
+	return node, rank
+}
+
+// Contains reports whether a value is in the set.
+func (set *SortedSet[T]) Contains(value T) bool {
+	_, ok := set.Rank(value)
+	return ok
+}
+
+// Rank reports the number of values in the set before value, its index if
+// it is in the set, and whether it is.
+func (set *SortedSet[T]) Rank(value T) (int, bool) {
+	if set == nil {
+		return 0, false
+	}
+	set.mux.RLock()
+	defer set.mux.RUnlock()
+	node, rank := set.seek(value)
+	next := node.next[0].node
+	return rank, next != nil && set.comparer.Compare(next.value, value) == 0
+}
+
+// at finds the node at an index, or nil, lock must be held.
+func (set *SortedSet[T]) at(i int) *skipNode[T] {
+	if i < 0 || i >= set.length {
+		return nil
+	}
+	// The head has rank 0, so the node at index i has rank i+1.
+	node, rank := set.head, 0
+	for level := set.level - 1; level >= 0; level-- {
+		for link := node.next[level]; link.node != nil && rank+link.span <= i+1; link = node.next[level] {
+			rank += link.span
+			node = link.node
+		}
+		if rank == i+1 {
+			break
+		}
+	}
+	return node
+}
+
+// Select gets the value at an index, counting from the least.
+func (set *SortedSet[T]) Select(i int) (T, bool) {
+	var unset T
+	if set == nil {
+		return unset, false
+	}
+	set.mux.RLock()
+	defer set.mux.RUnlock()
+	node := set.at(i)
+	if node == nil {
+		return unset, false
+	}
+	return node.value, true
+}
+
+// Min gets the least value.
+func (set *SortedSet[T]) Min() (T, bool) {
+	return set.Select(0)
+}
+
+// Max gets the greatest value.
+func (set *SortedSet[T]) Max() (T, bool) {
+	var unset T
+	if set == nil {
+		return unset, false
+	}
+	set.mux.RLock()
+	defer set.mux.RUnlock()
+	node := set.at(set.length - 1)
+	if node == nil {
+		return unset, false
+	}
+	return node.value, true
+}
+
+// collect copies the values from a node until stop reports true of one or
+// count values are copied, lock must be held.
+func (set *SortedSet[T]) collect(node *skipNode[T], count int, stop func(T) bool) []T {
+	var values []T
+	for ; node != nil && len(values) < count; node = node.next[0].node {
+		if stop != nil && stop(node.value) {
+			break
+		}
+		values = append(values, node.value)
+	}
+	return values
+}
+
+// Range gets a sequence of the values from lo up to but not including hi,
+// as of the call, in order.
+func (set *SortedSet[T]) Range(lo, hi T) iter.Seq[T] {
+	return set.sequence(func() []T {
+		node, _ := set.seek(lo)
+		return set.collect(node.next[0].node, set.length, func(value T) bool {
+			return set.comparer.Compare(value, hi) >= 0
+		})
+	})
+}
+
+// RangeByRank gets a sequence of the values from index start up to but not
+// including end, as of the call, in order.
+func (set *SortedSet[T]) RangeByRank(start, end int) iter.Seq[T] {
+	start = max(start, 0)
+	return set.sequence(func() []T {
+		return set.collect(set.at(start), end-start, nil)
+	})
+}
+
+// Values copies the values into a slice, in order.
+func (set *SortedSet[T]) Values() []T {
+	if set == nil {
+		return nil
+	}
+	set.mux.RLock()
+	defer set.mux.RUnlock()
+	return set.collect(set.head.next[0].node, set.length, nil)
+}
+
+// All gets a sequence of the values as of the call, in order.
+func (set *SortedSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range set.Values() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// sequence yields the values collected under the read lock.
+func (set *SortedSet[T]) sequence(collect func() []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if set == nil {
+			return
+		}
+		set.mux.RLock()
+		values := collect()
+		set.mux.RUnlock()
+		for _, value := range values {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// String converts SortedSet data into a string, in order.
+func (set *SortedSet[T]) String() string {
+	if set == nil {
+		return ""
+	}
+	values := set.Values()
+	s := fmt.Sprintf("Length: %d, Data:", len(values))
+	for _, v := range values {
+		s += " " + fmt.Sprint(v)
+	}
+	return s
+}
+
+// CheckInvariants verifies that the values are in strictly increasing
+// order, that each level links a subsequence of the level below, that each
+// link spans the ranks it skips, and that the length is the number of
+// values.
+func (set *SortedSet[T]) CheckInvariants() error {
+	if set == nil {
+		return nil
+	}
+	set.mux.RLock()
+	defer set.mux.RUnlock()
+	return set.checkInvariants()
+}
+
+// checkInvariants verifies the set, lock must be held.
+func (set *SortedSet[T]) checkInvariants() error {
+	ranks := map[*skipNode[T]]int{set.head: 0}
+	count := 0
+	for node := set.head.next[0].node; node != nil; node = node.next[0].node {
+		count++
+		ranks[node] = count
+		if next := node.next[0].node; next != nil && set.comparer.Compare(node.value, next.value) >= 0 {
+			return fmt.Errorf("sorted set: %v at index %d is not before %v", node.value, count-1, next.value)
+		}
+	}
+	if count != set.length {
+		return fmt.Errorf("sorted set: length %d, counted %d values", set.length, count)
+	}
+	for level := range set.level {
+		for node := set.head; node != nil; node = node.next[level].node {
+			link := node.next[level]
+			expected := set.length - ranks[node]
+			if link.node != nil {
+				rank, ok := ranks[link.node]
+				if !ok || len(link.node.next) <= level {
+					return fmt.Errorf("sorted set: level %d links a node not in the list at that level", level)
+				}
+				expected = rank - ranks[node]
+			}
+			if link.span != expected {
+				return fmt.Errorf("sorted set: link at level %d from index %d spans %d, expected %d", level, ranks[node]-1, link.span, expected)
+			}
+		}
+	}
+	if set.level < sortedSetMaxLevel && set.head.next[set.level].node != nil {
+		return fmt.Errorf("sorted set: nodes above level %d", set.level)
+	}
+	return nil
+}
+
+// debugCheck panics if the set invariants are violated in debug builds,
+// lock must be held.
+func (set *SortedSet[T]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := set.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,137 @@
+package data_test
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"fun/pkg/constraints"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"slices"
+	"testing"
+)
+
+// player is a leaderboard entry, ordered by descending score, then name.
+type player struct {
+	name  string
+	score int
+}
+
+func Test_SortedSet(t *testing.T) {
+	board := NewSortedSet(constraints.ComparerFunc[player](func(a, b player) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), cmp.Compare(a.name, b.name))
+	}))
+	board.Add(player{"ann", 30}, player{"bob", 50}, player{"cat", 40}, player{"dan", 40}, player{"eve", 10})
+	board.Add(player{"bob", 50})
+	if board.Length() != 5 {
+		t.Error("expected a duplicate to be ignored", board)
+	}
+	if rank, ok := board.Rank(player{"dan", 40}); !ok || rank != 2 {
+		t.Error("unexpected rank", rank, ok)
+	}
+	// A value not in the set ranks where it would be added.
+	if rank, ok := board.Rank(player{"fay", 35}); ok || rank != 3 {
+		t.Error("unexpected rank of a missing value", rank, ok)
+	}
+	if top, ok := board.Select(0); !ok || top.name != "bob" {
+		t.Error("unexpected leader", top, ok)
+	}
+	if _, ok := board.Select(5); ok {
+		t.Error("expected no value past the end")
+	}
+	var names []string
+	for p := range board.RangeByRank(1, 3) {
+		names = append(names, p.name)
+	}
+	if !slices.Equal(names, []string{"cat", "dan"}) {
+		t.Error("unexpected second and third places", names)
+	}
+	names = nil
+	for p := range board.Range(player{"", 40}, player{"", 20}) {
+		names = append(names, p.name)
+	}
+	if !slices.Equal(names, []string{"cat", "dan", "ann"}) {
+		t.Error("unexpected scores from 40 down to 20", names)
+	}
+	if !board.Remove(player{"bob", 50}) || board.Contains(player{"bob", 50}) {
+		t.Error("expected to remove bob")
+	}
+	if last, ok := board.Max(); !ok || last.name != "eve" {
+		t.Error("unexpected last place", last, ok)
+	}
+
+	var unset *SortedSet[int]
+	if err := unset.Add(1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+	if _, ok := unset.Max(); ok || unset.Length() != 0 {
+		t.Error("expected a nil set to be empty")
+	}
+}
+
+// sortedSetMachine checks a SortedSet against a sorted slice model.
+var sortedSetMachine = datatest.Machine[*SortedSet[int], *[]int]{
+	NewSystem: func() *SortedSet[int] {
+		return NewSortedSet(constraints.OrderedComparer[int](), WithLocking(false))
+	},
+	NewModel: func() *[]int { return &[]int{} },
+	ArgRange: 128,
+	Commands: []datatest.Command[*SortedSet[int], *[]int]{
+		{Name: "Add", Run: func(set *SortedSet[int], model *[]int, arg int) error {
+			if i, found := slices.BinarySearch(*model, arg); !found {
+				*model = slices.Insert(*model, i, arg)
+			}
+			return set.Add(arg)
+		}},
+		{Name: "Remove", Run: func(set *SortedSet[int], model *[]int, arg int) error {
+			i, found := slices.BinarySearch(*model, arg)
+			if found {
+				*model = slices.Delete(*model, i, i+1)
+			}
+			if ok := set.Remove(arg); ok != found {
+				return fmt.Errorf("Remove returned %t, expected %t", ok, found)
+			}
+			return nil
+		}},
+		{Name: "Rank", Run: func(set *SortedSet[int], model *[]int, arg int) error {
+			i, found := slices.BinarySearch(*model, arg)
+			if rank, ok := set.Rank(arg); rank != i || ok != found {
+				return fmt.Errorf("Rank(%d) returned %d %t, expected %d %t", arg, rank, ok, i, found)
+			}
+			return nil
+		}},
+		{Name: "Select", Run: func(set *SortedSet[int], model *[]int, arg int) error {
+			i := arg % 32
+			value, ok := set.Select(i)
+			if ok != (i < len(*model)) || (ok && value != (*model)[i]) {
+				return fmt.Errorf("Select(%d) returned %d %t", i, value, ok)
+			}
+			return nil
+		}},
+		{Name: "Range", Run: func(set *SortedSet[int], model *[]int, arg int) error {
+			lo, _ := slices.BinarySearch(*model, arg)
+			hi, _ := slices.BinarySearch(*model, arg+20)
+			if values := slices.Collect(set.Range(arg, arg+20)); !slices.Equal(values, (*model)[lo:hi]) {
+				return fmt.Errorf("Range(%d, %d) returned %v, expected %v", arg, arg+20, values, (*model)[lo:hi])
+			}
+			return nil
+		}},
+		{Name: "RangeByRank", Run: func(set *SortedSet[int], model *[]int, arg int) error {
+			start, end := min(arg%16, len(*model)), min(arg%16+arg%5, len(*model))
+			if values := slices.Collect(set.RangeByRank(arg%16, arg%16+arg%5)); !slices.Equal(values, (*model)[start:end]) {
+				return fmt.Errorf("RangeByRank returned %v, expected %v", values, (*model)[start:end])
+			}
+			return nil
+		}},
+	},
+	Check: func(set *SortedSet[int], model *[]int) error {
+		if values := set.Values(); !slices.Equal(values, *model) {
+			return fmt.Errorf("expected %v, got %v", *model, values)
+		}
+		return nil
+	},
+}
+
+func Test_SortedSetModel(t *testing.T) {
+	sortedSetMachine.Test(t, datatest.Config{Runs: 200, Length: 200})
+}
@@ -0,0 +1,298 @@
+package data
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// SuffixArray indexes a text by its suffixes in lexicographic order, with
+// the length of the longest common prefix of each adjacent pair, so the
+// occurrences of a pattern can be found in O(m log n) time for a pattern of
+// length m. A suffix array is immutable and safe for concurrent use.
+type SuffixArray struct {
+	text     []byte // Indexed text.
+	suffixes []int  // Start of each suffix, in lexicographic order.
+	lcp      []int  // Common prefix length of each suffix and the one before.
+}
+
+// BuildSuffixArray indexes a copy of text, sorting its suffixes with SA-IS
+// in O(n) time and computing the common prefixes with Kasai's algorithm in
+// O(n) time.
+func BuildSuffixArray(text []byte) *SuffixArray {
+	text = slices.Clone(text)
+	// Shift the bytes up to make room for a sentinel less than all of them.
+	symbols := make([]int, len(text)+1)
+	for i, b := range text {
+		symbols[i] = int(b) + 1
+	}
+	suffixes := sais(symbols, 257)[1:]
+	return &SuffixArray{text: text, suffixes: suffixes, lcp: kasai(text, suffixes)}
+}
+
+// sais sorts the suffixes of symbols, whose values are less than size and
+// whose last value is a 0 that appears nowhere else, by induced sorting: it
+// sorts the leftmost S-type (LMS) substrings, recursing on their names if
+// they are not distinct, then induces the order of the rest from them.
+func sais(symbols []int, size int) []int {
+	n := len(symbols)
+	suffixes := make([]int, n)
+	if n == 1 {
+		return suffixes
+	}
+	// A suffix is S-type if it is less than the next, otherwise L-type.
+	stype := make([]bool, n)
+	stype[n-1] = true
+	for i := n - 2; i >= 0; i-- {
+		stype[i] = symbols[i] < symbols[i+1] || (symbols[i] == symbols[i+1] && stype[i+1])
+	}
+	lms := func(i int) bool { return i > 0 && stype[i] && !stype[i-1] }
+
+	counts := make([]int, size)
+	for _, symbol := range symbols {
+		counts[symbol]++
+	}
+	// buckets gets the start of each symbol's bucket, or the end if end is
+	// true.
+	buckets := func(end bool) []int {
+		bounds := make([]int, size)
+		sum := 0
+		for symbol, count := range counts {
+			sum += count
+			if end {
+				bounds[symbol] = sum
+			} else {
+				bounds[symbol] = sum - count
+			}
+		}
+		return bounds
+	}
+	induce := func() {
+		heads := buckets(false)
+		for i := 0; i < n; i++ {
+			if j := suffixes[i] - 1; j >= 0 && !stype[j] {
+				suffixes[heads[symbols[j]]] = j
+				heads[symbols[j]]++
+			}
+		}
+		tails := buckets(true)
+		for i := n - 1; i >= 0; i-- {
+			if j := suffixes[i] - 1; j >= 0 && stype[j] {
+				tails[symbols[j]]--
+				suffixes[tails[symbols[j]]] = j
+			}
+		}
+	}
+
+	// Sort the LMS substrings by inducing from the LMS suffixes in any order.
+	for i := range suffixes {
+		suffixes[i] = -1
+	}
+	tails := buckets(true)
+	for i := 1; i < n; i++ {
+		if lms(i) {
+			tails[symbols[i]]--
+			suffixes[tails[symbols[i]]] = i
+		}
+	}
+	induce()
+
+	// Name the LMS substrings by rank, equal substrings sharing a name.
+	sorted := make([]int, 0, n/2+1)
+	for _, i := range suffixes {
+		if lms(i) {
+			sorted = append(sorted, i)
+		}
+	}
+	names := make([]int, n)
+	for i := range names {
+		names[i] = -1
+	}
+	name, prev := 0, -1
+	for _, i := range sorted {
+		if prev < 0 || !equalLMS(symbols, stype, lms, prev, i) {
+			name++
+		}
+		names[i], prev = name-1, i
+	}
+	positions := make([]int, 0, len(sorted))
+	reduced := make([]int, 0, len(sorted))
+	for i, name := range names {
+		if name >= 0 {
+			positions = append(positions, i)
+			reduced = append(reduced, name)
+		}
+	}
+
+	// Order the LMS suffixes, recursing while names repeat.
+	var order []int
+	if name < len(reduced) {
+		order = sais(reduced, name)
+	} else {
+		order = make([]int, len(reduced))
+		for i, name := range reduced {
+			order[name] = i
+		}
+	}
+
+	// Induce the order of all suffixes from the sorted LMS suffixes.
+	for i := range suffixes {
+		suffixes[i] = -1
+	}
+	tails = buckets(true)
+	for i := len(order) - 1; i >= 0; i-- {
+		j := positions[order[i]]
+		tails[symbols[j]]--
+		suffixes[tails[symbols[j]]] = j
+	}
+	induce()
+	return suffixes
+}
+
+// equalLMS reports whether the LMS substrings starting at a and b, up to and
+// including the next LMS position, have the same symbols and types.
+func equalLMS(symbols []int, stype []bool, lms func(int) bool, a, b int) bool {
+	for d := 0; ; d++ {
+		if symbols[a+d] != symbols[b+d] || stype[a+d] != stype[b+d] {
+			return false
+		}
+		if d > 0 && (lms(a+d) || lms(b+d)) {
+			return lms(a+d) && lms(b+d)
+		}
+	}
+}
+
+// kasai computes the length of the longest common prefix of each suffix and
+// the one before it in order, 0 for the first, in O(n) time: the prefix in
+// common with the preceding suffix shrinks by at most 1 from one text
+// position to the next.
+func kasai(text []byte, suffixes []int) []int {
+	n := len(text)
+	rank := make([]int, n)
+	for i, suffix := range suffixes {
+		rank[suffix] = i
+	}
+	lcp := make([]int, n)
+	common := 0
+	for i := 0; i < n; i++ {
+		if rank[i] == 0 {
+			common = 0
+			continue
+		}
+		j := suffixes[rank[i]-1]
+		for i+common < n && j+common < n && text[i+common] == text[j+common] {
+			common++
+		}
+		lcp[rank[i]] = common
+		if common > 0 {
+			common--
+		}
+	}
+	return lcp
+}
+
+// Length reports the number of suffixes, the length of the text.
+func (array *SuffixArray) Length() int {
+	if array == nil {
+		return 0
+	}
+	return len(array.text)
+}
+
+// Suffixes copies the starts of the suffixes, in lexicographic order.
+func (array *SuffixArray) Suffixes() []int {
+	if array == nil {
+		return nil
+	}
+	return slices.Clone(array.suffixes)
+}
+
+// LCP copies the lengths of the longest common prefix of each suffix in
+// order and the one before it, 0 for the first.
+func (array *SuffixArray) LCP() []int {
+	if array == nil {
+		return nil
+	}
+	return slices.Clone(array.lcp)
+}
+
+// bounds finds the range of suffixes that start with pattern.
+func (array *SuffixArray) bounds(pattern []byte) (int, int) {
+	lo := sort.Search(len(array.suffixes), func(i int) bool {
+		return bytes.Compare(array.text[array.suffixes[i]:], pattern) >= 0
+	})
+	hi := lo + sort.Search(len(array.suffixes)-lo, func(i int) bool {
+		return !bytes.HasPrefix(array.text[array.suffixes[lo+i]:], pattern)
+	})
+	return lo, hi
+}
+
+// Search finds the positions where pattern occurs in the text, in
+// increasing order. An empty pattern occurs at every position.
+func (array *SuffixArray) Search(pattern []byte) []int {
+	if array == nil {
+		return nil
+	}
+	lo, hi := array.bounds(pattern)
+	positions := slices.Clone(array.suffixes[lo:hi])
+	slices.Sort(positions)
+	return positions
+}
+
+// Count reports the number of times pattern occurs in the text, without
+// listing the positions.
+func (array *SuffixArray) Count(pattern []byte) int {
+	if array == nil {
+		return 0
+	}
+	lo, hi := array.bounds(pattern)
+	return hi - lo
+}
+
+// String converts SuffixArray data into a string, of suffix starts in
+// order.
+func (array *SuffixArray) String() string {
+	if array == nil {
+		return ""
+	}
+	s := fmt.Sprintf("Length: %d, Data:", len(array.suffixes))
+	for _, suffix := range array.suffixes {
+		s += " " + fmt.Sprint(suffix)
+	}
+	return s
+}
+
+// CheckInvariants verifies that the suffixes are a permutation of the
+// positions in strictly increasing order, and that LCP has the length of
+// each common prefix.
+func (array *SuffixArray) CheckInvariants() error {
+	if array == nil {
+		return nil
+	}
+	if len(array.suffixes) != len(array.text) || len(array.lcp) != len(array.text) {
+		return fmt.Errorf("suffix array: %d suffixes and %d prefixes for %d bytes", len(array.suffixes), len(array.lcp), len(array.text))
+	}
+	seen := make([]bool, len(array.text))
+	for i, suffix := range array.suffixes {
+		if suffix < 0 || suffix >= len(array.text) || seen[suffix] {
+			return fmt.Errorf("suffix array: suffix %d at %d is repeated or out of range", suffix, i)
+		}
+		seen[suffix] = true
+		if i == 0 {
+			continue
+		}
+		prev, next := array.text[array.suffixes[i-1]:], array.text[suffix:]
+		if bytes.Compare(prev, next) >= 0 {
+			return fmt.Errorf("suffix array: suffixes %d and %d are out of order", array.suffixes[i-1], suffix)
+		}
+		common := 0
+		for common < len(prev) && common < len(next) && prev[common] == next[common] {
+			common++
+		}
+		if array.lcp[i] != common {
+			return fmt.Errorf("suffix array: common prefix %d at %d, expected %d", array.lcp[i], i, common)
+		}
+	}
+	return nil
+}
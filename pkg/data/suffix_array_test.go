@@ -0,0 +1,53 @@
+package data_test
+
+import (
+	"bytes"
+	. "fun/pkg/data"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func Test_SuffixArray(t *testing.T) {
+	array := BuildSuffixArray([]byte("banana"))
+	if suffixes := array.Suffixes(); !slices.Equal(suffixes, []int{5, 3, 1, 0, 4, 2}) {
+		t.Error("unexpected suffixes", suffixes)
+	}
+	if lcp := array.LCP(); !slices.Equal(lcp, []int{0, 1, 3, 0, 0, 2}) {
+		t.Error("unexpected common prefixes", lcp)
+	}
+	if positions := array.Search([]byte("ana")); !slices.Equal(positions, []int{1, 3}) {
+		t.Error("unexpected positions", positions)
+	}
+	if array.Count([]byte("a")) != 3 || array.Count([]byte("nab")) != 0 || array.Count(nil) != 6 {
+		t.Error("unexpected counts")
+	}
+	if empty := BuildSuffixArray(nil); empty.Length() != 0 || len(empty.Search([]byte("a"))) != 0 {
+		t.Error("expected an empty suffix array")
+	}
+
+	// Compare with sorting and scanning, over small alphabets that make
+	// repeats and recursion likely.
+	random := rand.New(rand.NewSource(1))
+	for run := 0; run < 200; run++ {
+		text := make([]byte, random.Intn(200))
+		alphabet := 1 + random.Intn(4)
+		for i := range text {
+			text[i] = byte('a' + random.Intn(alphabet))
+		}
+		array := BuildSuffixArray(text)
+		if err := array.CheckInvariants(); err != nil {
+			t.Fatalf("%q: %v", text, err)
+		}
+		pattern := []byte("ab")[:random.Intn(3)]
+		var expected []int
+		for i := range text {
+			if bytes.HasPrefix(text[i:], pattern) {
+				expected = append(expected, i)
+			}
+		}
+		if positions := array.Search(pattern); !slices.Equal(positions, expected) {
+			t.Fatalf("%q: Search(%q) returned %v, expected %v", text, pattern, positions, expected)
+		}
+	}
+}
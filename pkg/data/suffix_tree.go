@@ -0,0 +1,401 @@
+package data
+
+import (
+	"fmt"
+	"slices"
+)
+
+// suffixTreeRoot is the index of the root node of a suffix tree.
+const suffixTreeRoot = 0
+
+// suffixLeafEnd is the end of every leaf edge, which grows with the text.
+const suffixLeafEnd = -1
+
+// suffixTreeNode is a node of a SuffixTree, at the end of an edge labeled
+// with a range of the text.
+type suffixTreeNode struct {
+	start    int           // Start of the edge label in the text.
+	end      int           // End of the edge label, suffixLeafEnd for a leaf.
+	link     int           // Node of the label without its first symbol.
+	suffix   int           // Start of the suffix a leaf ends.
+	children map[int32]int // Child for the first symbol of each edge.
+}
+
+// SuffixTree is a compressed trie of every suffix of a text, built online by
+// Ukkonen's algorithm in O(n) time, so text can be extended while the tree is
+// queried. Substring queries take time in proportion to the pattern. The
+// tree is implicit: suffixes that also occur earlier in the text end inside
+// the tree rather than at leaves.
+type SuffixTree struct {
+	text         []int32          // Symbols of the text, bytes or terminators.
+	nodes        []suffixTreeNode // Nodes by index, the root first.
+	activeNode   int              // Node where the next insertion starts.
+	activeEdge   int              // Text position of the first symbol of the active edge.
+	activeLength int              // Symbols along the active edge.
+	remainder    int              // Suffixes that are still implicit.
+	metrics      *Metrics         // Instrumentation, nil when disabled.
+	mux          locker           // Lock read and write operations.
+}
+
+// Create a new suffix tree of text, configured by WithLocking and
+// WithMetrics.
+func NewSuffixTree(text []byte, opts ...Option) *SuffixTree {
+	settings := newOptions(opts)
+	tree := &SuffixTree{metrics: settings.metrics, mux: settings.newLocker()}
+	tree.nodes = append(tree.nodes, suffixTreeNode{suffix: -1, children: map[int32]int{}})
+	for _, b := range text {
+		tree.extend(int32(b))
+	}
+	return tree
+}
+
+// Length reports the length of the text.
+func (tree *SuffixTree) Length() int {
+	if tree == nil {
+		return 0
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	return len(tree.text)
+}
+
+// Extend appends text, adding its suffixes to the tree in amortized
+// constant time a byte.
+func (tree *SuffixTree) Extend(text []byte) error {
+	if tree == nil {
+		return nilError("suffix tree")
+	}
+	start := tree.metrics.begin()
+	tree.mux.Lock()
+	defer tree.mux.Unlock()
+	defer tree.metrics.end("Extend", start, tree.metrics.acquired())
+	defer tree.debugCheck()
+	for _, b := range text {
+		tree.extend(int32(b))
+	}
+	return nil
+}
+
+// edgeLength reports the length of the label of the edge to a node, lock
+// must be held.
+func (tree *SuffixTree) edgeLength(node int) int {
+	return tree.edgeEnd(node) - tree.nodes[node].start
+}
+
+// edgeEnd gets the end of the label of the edge to a node, lock must be
+// held.
+func (tree *SuffixTree) edgeEnd(node int) int {
+	if end := tree.nodes[node].end; end != suffixLeafEnd {
+		return end
+	}
+	return len(tree.text)
+}
+
+// newNode adds a node with an edge label from start to end, lock must be
+// held.
+func (tree *SuffixTree) newNode(start, end int) int {
+	node := suffixTreeNode{start: start, end: end, suffix: -1}
+	if end != suffixLeafEnd {
+		node.children = map[int32]int{}
+	}
+	tree.nodes = append(tree.nodes, node)
+	return len(tree.nodes) - 1
+}
+
+// extend appends a symbol, making explicit each implicit suffix that it does
+// not continue, from the longest, lock must be held.
+func (tree *SuffixTree) extend(symbol int32) {
+	tree.text = append(tree.text, symbol)
+	pos := len(tree.text) - 1
+	tree.remainder++
+	linkFrom := -1
+	// link points the last internal node created at node.
+	link := func(node int) {
+		if linkFrom > 0 {
+			tree.nodes[linkFrom].link = node
+		}
+		linkFrom = node
+	}
+	for tree.remainder > 0 {
+		if tree.activeLength == 0 {
+			tree.activeEdge = pos
+		}
+		next, ok := tree.nodes[tree.activeNode].children[tree.text[tree.activeEdge]]
+		if !ok {
+			leaf := tree.newNode(pos, suffixLeafEnd)
+			tree.nodes[leaf].suffix = pos - tree.remainder + 1
+			tree.nodes[tree.activeNode].children[tree.text[tree.activeEdge]] = leaf
+			link(tree.activeNode)
+		} else {
+			// Walk down past edges no longer than the active length.
+			if length := tree.edgeLength(next); tree.activeLength >= length {
+				tree.activeEdge += length
+				tree.activeLength -= length
+				tree.activeNode = next
+				continue
+			}
+			if tree.text[tree.nodes[next].start+tree.activeLength] == symbol {
+				// The suffix is already in the tree, and so are the shorter ones.
+				tree.activeLength++
+				link(tree.activeNode)
+				return
+			}
+			split := tree.newNode(tree.nodes[next].start, tree.nodes[next].start+tree.activeLength)
+			tree.nodes[tree.activeNode].children[tree.text[tree.activeEdge]] = split
+			leaf := tree.newNode(pos, suffixLeafEnd)
+			tree.nodes[leaf].suffix = pos - tree.remainder + 1
+			tree.nodes[split].children[symbol] = leaf
+			tree.nodes[next].start += tree.activeLength
+			tree.nodes[split].children[tree.text[tree.nodes[next].start]] = next
+			link(split)
+		}
+		tree.remainder--
+		if tree.activeNode == suffixTreeRoot && tree.activeLength > 0 {
+			tree.activeLength--
+			tree.activeEdge = pos - tree.remainder + 1
+		} else {
+			tree.activeNode = tree.nodes[tree.activeNode].link
+		}
+	}
+}
+
+// locate finds the node at or below the end of the path spelling pattern,
+// lock must be held.
+func (tree *SuffixTree) locate(pattern []byte) (int, bool) {
+	node := suffixTreeRoot
+	for i := 0; i < len(pattern); {
+		child, ok := tree.nodes[node].children[int32(pattern[i])]
+		if !ok {
+			return 0, false
+		}
+		for j := tree.nodes[child].start; j < tree.edgeEnd(child) && i < len(pattern); i, j = i+1, j+1 {
+			if tree.text[j] != int32(pattern[i]) {
+				return 0, false
+			}
+		}
+		node = child
+	}
+	return node, true
+}
+
+// Contains reports whether pattern occurs in the text.
+func (tree *SuffixTree) Contains(pattern []byte) bool {
+	if tree == nil {
+		return false
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	_, ok := tree.locate(pattern)
+	return ok
+}
+
+// Search finds the positions where pattern occurs in the text, in
+// increasing order. An empty pattern occurs at every position.
+func (tree *SuffixTree) Search(pattern []byte) []int {
+	if tree == nil {
+		return nil
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	n := len(tree.text)
+	var positions []int
+	if len(pattern) == 0 {
+		for i := range n {
+			positions = append(positions, i)
+		}
+		return positions
+	}
+	node, ok := tree.locate(pattern)
+	if !ok {
+		return nil
+	}
+	tree.walk(node, func(node, depth int) {
+		if suffix := tree.nodes[node].suffix; suffix >= 0 {
+			positions = append(positions, suffix)
+		}
+	})
+	// The implicit suffixes have no leaves, so check them directly.
+	for p := n - tree.remainder; p+len(pattern) <= n; p++ {
+		if tree.matches(p, pattern) {
+			positions = append(positions, p)
+		}
+	}
+	slices.Sort(positions)
+	return positions
+}
+
+// matches reports whether pattern occurs at position p, lock must be held.
+func (tree *SuffixTree) matches(p int, pattern []byte) bool {
+	for i, b := range pattern {
+		if tree.text[p+i] != int32(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// walk calls f on each node of the subtree of node in depth-first preorder,
+// with the length of the path to the node from the start of its edge, lock
+// must be held. The walk uses a stack rather than recursion, since a path
+// may be as long as the text.
+func (tree *SuffixTree) walk(node int, f func(node, depth int)) {
+	type visit struct{ node, depth int }
+	stack := []visit{{node, tree.edgeLength(node)}}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		f(top.node, top.depth)
+		for _, child := range tree.nodes[top.node].children {
+			stack = append(stack, visit{child, top.depth + tree.edgeLength(child)})
+		}
+	}
+}
+
+// LongestRepeatedSubstring finds the longest substring that occurs at least
+// twice in the text, possibly overlapping, and the first of them in
+// lexicographic order if several are longest.
+func (tree *SuffixTree) LongestRepeatedSubstring() []byte {
+	if tree == nil {
+		return nil
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	// A repeated substring that cannot be extended ends at an internal node,
+	// or is a suffix occurring earlier, which is implicit.
+	var best []int32
+	if tree.remainder > 0 {
+		best = tree.text[len(tree.text)-tree.remainder:]
+	}
+	tree.walk(suffixTreeRoot, func(node, depth int) {
+		if tree.nodes[node].end == suffixLeafEnd || node == suffixTreeRoot {
+			return
+		}
+		end := tree.edgeEnd(node)
+		if label := tree.text[end-depth : end]; len(label) > len(best) || (len(label) == len(best) && slices.Compare(label, best) < 0) {
+			best = label
+		}
+	})
+	return symbolBytes(best)
+}
+
+// symbolBytes converts symbols that are bytes to a byte slice.
+func symbolBytes(symbols []int32) []byte {
+	text := make([]byte, len(symbols))
+	for i, symbol := range symbols {
+		text[i] = byte(symbol)
+	}
+	return text
+}
+
+// LongestCommonSubstring finds the longest substring of both a and b, and
+// the first in lexicographic order if several are longest, with a suffix tree
+// of both texts in O(len(a) + len(b)) time.
+func LongestCommonSubstring(a, b []byte) []byte {
+	// End each text with a unique terminator, so every suffix is a leaf and
+	// no internal path crosses from one text to the other.
+	tree := NewSuffixTree(a, WithLocking(false))
+	tree.extend(256)
+	for _, symbol := range b {
+		tree.extend(int32(symbol))
+	}
+	tree.extend(257)
+
+	// Mark the subtrees with leaves of a with 1 and of b with 2, children
+	// after parents in reverse of preorder.
+	type visit struct{ node, parent, depth int }
+	var order []visit
+	stack := []visit{{suffixTreeRoot, -1, 0}}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		order = append(order, top)
+		for _, child := range tree.nodes[top.node].children {
+			stack = append(stack, visit{child, top.node, top.depth + tree.edgeLength(child)})
+		}
+	}
+	marks := make([]int, len(tree.nodes))
+	var best []int32
+	for i := len(order) - 1; i >= 0; i-- {
+		visit := order[i]
+		if suffix := tree.nodes[visit.node].suffix; suffix >= 0 {
+			if suffix <= len(a) {
+				marks[visit.node] = 1
+			} else {
+				marks[visit.node] = 2
+			}
+		} else if marks[visit.node] == 3 && visit.node != suffixTreeRoot {
+			end := tree.edgeEnd(visit.node)
+			if label := tree.text[end-visit.depth : end]; len(label) > len(best) || (len(label) == len(best) && slices.Compare(label, best) < 0) {
+				best = label
+			}
+		}
+		if visit.parent >= 0 {
+			marks[visit.parent] |= marks[visit.node]
+		}
+	}
+	return symbolBytes(best)
+}
+
+// String converts SuffixTree data into a string of the text and the number
+// of nodes.
+func (tree *SuffixTree) String() string {
+	if tree == nil {
+		return ""
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	return fmt.Sprintf("Length: %d, Nodes: %d, Data: %s", len(tree.text), len(tree.nodes), symbolBytes(tree.text))
+}
+
+// CheckInvariants verifies that every edge starts with the symbol it is
+// keyed by, that internal nodes other than the root branch, and that each
+// leaf spells the rest of the text from its suffix, with one leaf for each
+// suffix that is not implicit.
+func (tree *SuffixTree) CheckInvariants() error {
+	if tree == nil {
+		return nil
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	return tree.checkInvariants()
+}
+
+// checkInvariants verifies the suffix tree, lock must be held.
+func (tree *SuffixTree) checkInvariants() error {
+	var err error
+	leaves := 0
+	tree.walk(suffixTreeRoot, func(node, depth int) {
+		if err != nil {
+			return
+		}
+		for symbol, child := range tree.nodes[node].children {
+			if tree.edgeLength(child) <= 0 || tree.text[tree.nodes[child].start] != symbol {
+				err = fmt.Errorf("suffix tree: edge keyed %d starts with %d", symbol, tree.text[tree.nodes[child].start])
+			}
+		}
+		switch {
+		case tree.nodes[node].end == suffixLeafEnd:
+			leaves++
+			if suffix := tree.nodes[node].suffix; suffix+depth != len(tree.text) {
+				err = fmt.Errorf("suffix tree: leaf of suffix %d has depth %d", suffix, depth)
+			}
+		case node != suffixTreeRoot && len(tree.nodes[node].children) < 2:
+			err = fmt.Errorf("suffix tree: internal node %d has %d children", node, len(tree.nodes[node].children))
+		}
+	})
+	if err == nil && leaves != len(tree.text)-tree.remainder {
+		err = fmt.Errorf("suffix tree: %d leaves for %d explicit suffixes", leaves, len(tree.text)-tree.remainder)
+	}
+	return err
+}
+
+// debugCheck panics if the suffix tree invariants are violated in debug builds,
+// lock must be held.
+func (tree *SuffixTree) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := tree.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,111 @@
+package data_test
+
+import (
+	"bytes"
+	. "fun/pkg/data"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func Test_SuffixTree(t *testing.T) {
+	tree := NewSuffixTree([]byte("bananas"))
+	if !tree.Contains([]byte("nana")) || tree.Contains([]byte("nab")) || !tree.Contains(nil) {
+		t.Error("unexpected substrings")
+	}
+	if positions := tree.Search([]byte("an")); !slices.Equal(positions, []int{1, 3}) {
+		t.Error("unexpected positions", positions)
+	}
+	if repeated := tree.LongestRepeatedSubstring(); string(repeated) != "ana" {
+		t.Errorf("unexpected longest repeated substring %q", repeated)
+	}
+	// Extend online, making the end of the text repeat.
+	tree.Extend([]byte(" bananas"))
+	if repeated := tree.LongestRepeatedSubstring(); string(repeated) != "bananas" {
+		t.Errorf("unexpected longest repeated substring %q", repeated)
+	}
+	if positions := tree.Search([]byte("nas")); !slices.Equal(positions, []int{4, 12}) {
+		t.Error("unexpected positions", positions)
+	}
+	if err := tree.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+	if common := LongestCommonSubstring([]byte("xabcdey"), []byte("zbcdeabw")); string(common) != "bcde" {
+		t.Errorf("unexpected longest common substring %q", common)
+	}
+	if common := LongestCommonSubstring([]byte("abc"), []byte("xyz")); len(common) != 0 {
+		t.Errorf("expected no common substring, got %q", common)
+	}
+}
+
+// longestRepeated finds the longest repeated substring by brute force, the
+// least in lexicographic order if several are longest.
+func longestRepeated(text []byte) []byte {
+	for length := len(text) - 1; length > 0; length-- {
+		var found []byte
+		for i := 0; i+length <= len(text); i++ {
+			candidate := text[i : i+length]
+			if bytes.Index(text[i+1:], candidate) >= 0 && (found == nil || bytes.Compare(candidate, found) < 0) {
+				found = candidate
+			}
+		}
+		if found != nil {
+			return found
+		}
+	}
+	return []byte{}
+}
+
+// longestCommon finds the longest common substring by brute force, the
+// least in lexicographic order if several are longest.
+func longestCommon(a, b []byte) []byte {
+	for length := min(len(a), len(b)); length > 0; length-- {
+		var found []byte
+		for i := 0; i+length <= len(a); i++ {
+			candidate := a[i : i+length]
+			if bytes.Contains(b, candidate) && (found == nil || bytes.Compare(candidate, found) < 0) {
+				found = candidate
+			}
+		}
+		if found != nil {
+			return found
+		}
+	}
+	return []byte{}
+}
+
+func Test_SuffixTreeRandom(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	text := func() []byte {
+		text := make([]byte, random.Intn(40))
+		alphabet := 1 + random.Intn(3)
+		for i := range text {
+			text[i] = byte('a' + random.Intn(alphabet))
+		}
+		return text
+	}
+	for run := 0; run < 300; run++ {
+		a, b := text(), text()
+		tree := NewSuffixTree(a[:len(a)/2])
+		tree.Extend(a[len(a)/2:])
+		if err := tree.CheckInvariants(); err != nil {
+			t.Fatalf("%q: %v", a, err)
+		}
+		pattern := b[:min(len(b), 1+random.Intn(3))]
+		var expected []int
+		for i := range a {
+			if bytes.HasPrefix(a[i:], pattern) {
+				expected = append(expected, i)
+			}
+		}
+		if positions := tree.Search(pattern); !slices.Equal(positions, expected) {
+			t.Fatalf("%q: Search(%q) returned %v, expected %v", a, pattern, positions, expected)
+		}
+		if repeated := tree.LongestRepeatedSubstring(); !bytes.Equal(repeated, longestRepeated(a)) {
+			t.Fatalf("%q: longest repeated substring %q, expected %q", a, repeated, longestRepeated(a))
+		}
+		if common := LongestCommonSubstring(a, b); !bytes.Equal(common, longestCommon(a, b)) {
+			t.Fatalf("%q %q: longest common substring %q, expected %q", a, b, common, longestCommon(a, b))
+		}
+	}
+}
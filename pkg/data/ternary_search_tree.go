@@ -0,0 +1,446 @@
+package data
+
+import (
+	"fmt"
+	"iter"
+)
+
+// tstNode is a node of a TernarySearchTree, for one byte of a key.
+type tstNode[V any] struct {
+	label    byte        // Byte of the key at the depth of the node.
+	lo       *tstNode[V] // Nodes for lesser bytes at the same depth.
+	eq       *tstNode[V] // Nodes for the next byte of keys with this one.
+	hi       *tstNode[V] // Nodes for greater bytes at the same depth.
+	value    V           // Value of the key ending at the node.
+	terminal bool        // A key ends at the node.
+}
+
+// TernarySearchTree is a map from strings to values stored in a ternary
+// search tree: a trie whose children at each depth form a binary search
+// tree, so nodes need three links rather than one per possible byte. Lookups
+// take time in proportion to the length of the key plus the logarithm of the
+// number of keys. Keys are visited in lexicographic byte order.
+type TernarySearchTree[V any] struct {
+	root     *tstNode[V] // Root of the first bytes, nil when no key is empty.
+	empty    V           // Value of the empty key.
+	hasEmpty bool        // The empty key is stored.
+	length   int         // Number of keys stored in the tree.
+	metrics  *Metrics    // Instrumentation, nil when disabled.
+	mux      locker      // Lock read and write operations.
+}
+
+// Create a new ternary search tree, configured by WithLocking and
+// WithMetrics.
+func NewTernarySearchTree[V any](opts ...Option) *TernarySearchTree[V] {
+	settings := newOptions(opts)
+	return &TernarySearchTree[V]{metrics: settings.metrics, mux: settings.newLocker()}
+}
+
+// Length reports the number of keys in the tree.
+func (tree *TernarySearchTree[V]) Length() int {
+	if tree == nil {
+		return 0
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	return tree.length
+}
+
+// Insert stores a value under a key, replacing any value already there.
+func (tree *TernarySearchTree[V]) Insert(key string, value V) error {
+	if tree == nil {
+		return nilError("ternary search tree")
+	}
+	start := tree.metrics.begin()
+	tree.mux.Lock()
+	defer tree.mux.Unlock()
+	defer tree.metrics.end("Insert", start, tree.metrics.acquired())
+	defer tree.debugCheck()
+	if key == "" {
+		if !tree.hasEmpty {
+			tree.hasEmpty = true
+			tree.length++
+		}
+		tree.empty = value
+		return nil
+	}
+	link := &tree.root
+	for i := 0; ; {
+		if *link == nil {
+			*link = &tstNode[V]{label: key[i]}
+		}
+		node := *link
+		switch {
+		case key[i] < node.label:
+			link = &node.lo
+		case key[i] > node.label:
+			link = &node.hi
+		case i < len(key)-1:
+			link = &node.eq
+			i++
+		default:
+			if !node.terminal {
+				node.terminal = true
+				tree.length++
+			}
+			node.value = value
+			return nil
+		}
+	}
+}
+
+// find gets the node of the last byte of a key that is not empty, or nil,
+// lock must be held.
+func (tree *TernarySearchTree[V]) find(key string) *tstNode[V] {
+	node := tree.root
+	for i := 0; node != nil; {
+		switch {
+		case key[i] < node.label:
+			node = node.lo
+		case key[i] > node.label:
+			node = node.hi
+		case i < len(key)-1:
+			node = node.eq
+			i++
+		default:
+			return node
+		}
+	}
+	return nil
+}
+
+// Get gets the value stored under a key.
+func (tree *TernarySearchTree[V]) Get(key string) (V, bool) {
+	var unset V
+	if tree == nil {
+		return unset, false
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	if key == "" {
+		return tree.empty, tree.hasEmpty
+	}
+	if node := tree.find(key); node != nil && node.terminal {
+		return node.value, true
+	}
+	return unset, false
+}
+
+// Contains reports whether a key is in the tree.
+func (tree *TernarySearchTree[V]) Contains(key string) bool {
+	_, ok := tree.Get(key)
+	return ok
+}
+
+// Delete removes a key, reporting whether there was one, and prunes the
+// nodes left without keys.
+func (tree *TernarySearchTree[V]) Delete(key string) bool {
+	if tree == nil {
+		return false
+	}
+	start := tree.metrics.begin()
+	tree.mux.Lock()
+	defer tree.mux.Unlock()
+	defer tree.metrics.end("Delete", start, tree.metrics.acquired())
+	defer tree.debugCheck()
+	var unset V
+	if key == "" {
+		deleted := tree.hasEmpty
+		if deleted {
+			tree.hasEmpty, tree.empty = false, unset
+			tree.length--
+		}
+		return deleted
+	}
+	var deleted bool
+	tree.root, deleted = tree.delete(tree.root, key, 0)
+	if deleted {
+		tree.length--
+	}
+	return deleted
+}
+
+// delete removes the key from byte i on from the subtree of node, returning
+// the subtree that replaces it, lock must be held.
+func (tree *TernarySearchTree[V]) delete(node *tstNode[V], key string, i int) (*tstNode[V], bool) {
+	if node == nil {
+		return nil, false
+	}
+	var deleted bool
+	switch {
+	case key[i] < node.label:
+		node.lo, deleted = tree.delete(node.lo, key, i)
+	case key[i] > node.label:
+		node.hi, deleted = tree.delete(node.hi, key, i)
+	case i < len(key)-1:
+		node.eq, deleted = tree.delete(node.eq, key, i+1)
+	case node.terminal:
+		var unset V
+		node.terminal, node.value, deleted = false, unset, true
+	}
+	if node.terminal || node.eq != nil {
+		return node, deleted
+	}
+	// Without keys of its own, replace the node by its lesser and greater
+	// siblings, hanging the greater below the greatest of the lesser.
+	if node.lo == nil {
+		return node.hi, deleted
+	}
+	last := node.lo
+	for last.hi != nil {
+		last = last.hi
+	}
+	last.hi = node.hi
+	return node.lo, deleted
+}
+
+// HasPrefix reports whether any key starts with prefix.
+func (tree *TernarySearchTree[V]) HasPrefix(prefix string) bool {
+	if tree == nil {
+		return false
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	if prefix == "" {
+		return tree.length > 0
+	}
+	return tree.find(prefix) != nil
+}
+
+// WalkPrefix calls fn on each key that starts with prefix, and its value, in
+// lexicographic order until fn returns false. The read lock is held
+// throughout, so fn must not modify the tree.
+func (tree *TernarySearchTree[V]) WalkPrefix(prefix string, fn func(key string, value V) bool) {
+	if tree == nil {
+		return
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	if prefix == "" {
+		if tree.hasEmpty && !fn("", tree.empty) {
+			return
+		}
+		walkTST(tree.root, nil, fn)
+		return
+	}
+	node := tree.find(prefix)
+	if node == nil {
+		return
+	}
+	if node.terminal && !fn(prefix, node.value) {
+		return
+	}
+	walkTST(node.eq, []byte(prefix), fn)
+}
+
+// walkTST calls fn on the keys of the subtree of node in order, each
+// starting with prefix, until fn returns false, reporting whether it did
+// not.
+func walkTST[V any](node *tstNode[V], prefix []byte, fn func(string, V) bool) bool {
+	if node == nil {
+		return true
+	}
+	if !walkTST(node.lo, prefix, fn) {
+		return false
+	}
+	key := append(prefix, node.label)
+	if node.terminal && !fn(string(key), node.value) {
+		return false
+	}
+	return walkTST(node.eq, key, fn) && walkTST(node.hi, prefix, fn)
+}
+
+// NearSearch finds the keys of the same length as key that differ from it
+// in at most distance bytes, the Hamming distance, in lexicographic order.
+func (tree *TernarySearchTree[V]) NearSearch(key string, distance int) []string {
+	if tree == nil {
+		return nil
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	var keys []string
+	if key == "" {
+		if tree.hasEmpty && distance >= 0 {
+			keys = append(keys, "")
+		}
+		return keys
+	}
+	var near func(node *tstNode[V], prefix []byte, distance int)
+	near = func(node *tstNode[V], prefix []byte, distance int) {
+		if node == nil || distance < 0 {
+			return
+		}
+		// Lesser and greater bytes only match by spending distance.
+		i := len(prefix)
+		if distance > 0 || key[i] < node.label {
+			near(node.lo, prefix, distance)
+		}
+		spent := distance
+		if node.label != key[i] {
+			spent--
+		}
+		next := append(prefix, node.label)
+		if i == len(key)-1 {
+			if node.terminal && spent >= 0 {
+				keys = append(keys, string(next))
+			}
+		} else {
+			near(node.eq, next, spent)
+		}
+		if distance > 0 || key[i] > node.label {
+			near(node.hi, prefix, distance)
+		}
+	}
+	near(tree.root, nil, distance)
+	return keys
+}
+
+// Wildcard matches any byte in a Match pattern.
+const Wildcard = '.'
+
+// Match finds the keys matching a pattern of the same length in which
+// Wildcard matches any byte and other bytes match themselves, in
+// lexicographic order.
+func (tree *TernarySearchTree[V]) Match(pattern string) []string {
+	if tree == nil {
+		return nil
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	var keys []string
+	if pattern == "" {
+		if tree.hasEmpty {
+			keys = append(keys, "")
+		}
+		return keys
+	}
+	var match func(node *tstNode[V], prefix []byte)
+	match = func(node *tstNode[V], prefix []byte) {
+		if node == nil {
+			return
+		}
+		i := len(prefix)
+		wild := pattern[i] == Wildcard
+		if wild || pattern[i] < node.label {
+			match(node.lo, prefix)
+		}
+		if wild || pattern[i] == node.label {
+			next := append(prefix, node.label)
+			if i == len(pattern)-1 {
+				if node.terminal {
+					keys = append(keys, string(next))
+				}
+			} else {
+				match(node.eq, next)
+			}
+		}
+		if wild || pattern[i] > node.label {
+			match(node.hi, prefix)
+		}
+	}
+	match(tree.root, nil)
+	return keys
+}
+
+// Keys copies the keys into a slice, in lexicographic order.
+func (tree *TernarySearchTree[V]) Keys() []string {
+	var keys []string
+	tree.WalkPrefix("", func(key string, value V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// All gets a sequence of the keys and values as of the call, in
+// lexicographic order.
+func (tree *TernarySearchTree[V]) All() iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		var keys []string
+		var values []V
+		tree.WalkPrefix("", func(key string, value V) bool {
+			keys = append(keys, key)
+			values = append(values, value)
+			return true
+		})
+		for i, key := range keys {
+			if !yield(key, values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// String converts TernarySearchTree data into a string of key:value pairs,
+// in lexicographic order.
+func (tree *TernarySearchTree[V]) String() string {
+	if tree == nil {
+		return ""
+	}
+	s := fmt.Sprintf("Length: %d, Data:", tree.Length())
+	for key, value := range tree.All() {
+		s += fmt.Sprintf(" %s:%v", key, value)
+	}
+	return s
+}
+
+// CheckInvariants verifies that the bytes at each depth are in binary search
+// tree order, that every node leads to a key, and that the length is the
+// number of keys.
+func (tree *TernarySearchTree[V]) CheckInvariants() error {
+	if tree == nil {
+		return nil
+	}
+	tree.mux.RLock()
+	defer tree.mux.RUnlock()
+	return tree.checkInvariants()
+}
+
+// checkInvariants verifies the tree, lock must be held.
+func (tree *TernarySearchTree[V]) checkInvariants() error {
+	count := 0
+	if tree.hasEmpty {
+		count++
+	}
+	// Bounds are exclusive, widened to int for the whole range of bytes.
+	var check func(node *tstNode[V], prefix []byte, lo, hi int) error
+	check = func(node *tstNode[V], prefix []byte, lo, hi int) error {
+		if node == nil {
+			return nil
+		}
+		if int(node.label) <= lo || int(node.label) >= hi {
+			return fmt.Errorf("ternary search tree: byte %q after %q is out of order", node.label, prefix)
+		}
+		if !node.terminal && node.eq == nil {
+			return fmt.Errorf("ternary search tree: node %q leads to no key", append(prefix, node.label))
+		}
+		if node.terminal {
+			count++
+		}
+		if err := check(node.lo, prefix, lo, int(node.label)); err != nil {
+			return err
+		}
+		if err := check(node.eq, append(prefix, node.label), -1, 256); err != nil {
+			return err
+		}
+		return check(node.hi, prefix, int(node.label), hi)
+	}
+	if err := check(tree.root, nil, -1, 256); err != nil {
+		return err
+	}
+	if count != tree.length {
+		return fmt.Errorf("ternary search tree: length %d, counted %d keys", tree.length, count)
+	}
+	return nil
+}
+
+// debugCheck panics if the tree invariants are violated in debug builds,
+// lock must be held.
+func (tree *TernarySearchTree[V]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := tree.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
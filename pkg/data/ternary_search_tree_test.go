@@ -0,0 +1,158 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func Test_TernarySearchTree(t *testing.T) {
+	tree := NewTernarySearchTree[int]()
+	for i, key := range []string{"cat", "cap", "car", "cut", "dog", "ca", "cat"} {
+		tree.Insert(key, i)
+	}
+	if value, ok := tree.Get("cat"); !ok || value != 6 || tree.Length() != 6 {
+		t.Error("expected Insert to replace the value", value, ok, tree.Length())
+	}
+	if _, ok := tree.Get("c"); ok || tree.Contains("") {
+		t.Error("expected prefixes not to be keys")
+	}
+	if keys := tree.Keys(); !slices.Equal(keys, []string{"ca", "cap", "car", "cat", "cut", "dog"}) {
+		t.Error("expected keys in lexicographic order", keys)
+	}
+	var prefixed []string
+	tree.WalkPrefix("ca", func(key string, value int) bool {
+		prefixed = append(prefixed, key)
+		return true
+	})
+	if !slices.Equal(prefixed, []string{"ca", "cap", "car", "cat"}) || !tree.HasPrefix("cu") || tree.HasPrefix("cz") {
+		t.Error("unexpected prefixes", prefixed)
+	}
+
+	if near := tree.NearSearch("cot", 1); !slices.Equal(near, []string{"cat", "cut"}) {
+		t.Error("unexpected near neighbors", near)
+	}
+	if near := tree.NearSearch("cot", 2); !slices.Equal(near, []string{"cap", "car", "cat", "cut", "dog"}) {
+		t.Error("unexpected near neighbors", near)
+	}
+	if matches := tree.Match("ca."); !slices.Equal(matches, []string{"cap", "car", "cat"}) {
+		t.Error("unexpected matches", matches)
+	}
+	if matches := tree.Match(".o."); !slices.Equal(matches, []string{"dog"}) {
+		t.Error("unexpected matches", matches)
+	}
+
+	tree.Insert("", -1)
+	if value, ok := tree.Get(""); !ok || value != -1 || tree.Length() != 7 {
+		t.Error("expected an empty key", value, ok)
+	}
+	if !tree.Delete("ca") || tree.Delete("ca") || tree.Delete("c") || !tree.Delete("") {
+		t.Error("unexpected deletions")
+	}
+	if !tree.Contains("cap") || tree.Length() != 5 {
+		t.Error("expected only the deleted keys to be gone")
+	}
+	if err := tree.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+
+	var unset *TernarySearchTree[int]
+	if err := unset.Insert("a", 1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
+
+// hamming counts the bytes that differ between strings of equal length.
+func hamming(a, b string) int {
+	distance := 0
+	for i := range a {
+		if a[i] != b[i] {
+			distance++
+		}
+	}
+	return distance
+}
+
+// tstMachine checks a TernarySearchTree against a map model, reusing the
+// keys of trieMachine.
+var tstMachine = datatest.Machine[*TernarySearchTree[int], map[string]int]{
+	NewSystem: func() *TernarySearchTree[int] { return NewTernarySearchTree[int]() },
+	NewModel:  func() map[string]int { return map[string]int{} },
+	ArgRange:  81,
+	Commands: []datatest.Command[*TernarySearchTree[int], map[string]int]{
+		{Name: "Insert", Run: func(tree *TernarySearchTree[int], model map[string]int, arg int) error {
+			model[trieKey(arg)] = arg
+			return tree.Insert(trieKey(arg), arg)
+		}},
+		{Name: "Delete", Run: func(tree *TernarySearchTree[int], model map[string]int, arg int) error {
+			_, found := model[trieKey(arg)]
+			delete(model, trieKey(arg))
+			if ok := tree.Delete(trieKey(arg)); ok != found {
+				return fmt.Errorf("Delete returned %t", ok)
+			}
+			return nil
+		}},
+		{Name: "NearSearch", Run: func(tree *TernarySearchTree[int], model map[string]int, arg int) error {
+			key, distance := trieKey(arg), arg%3
+			var expected []string
+			for _, other := range slices.Sorted(maps.Keys(model)) {
+				if len(other) == len(key) && hamming(key, other) <= distance {
+					expected = append(expected, other)
+				}
+			}
+			if near := tree.NearSearch(key, distance); !slices.Equal(near, expected) {
+				return fmt.Errorf("NearSearch(%q, %d) returned %v, expected %v", key, distance, near, expected)
+			}
+			return nil
+		}},
+		{Name: "Match", Run: func(tree *TernarySearchTree[int], model map[string]int, arg int) error {
+			// Every other byte of the key is a wildcard.
+			pattern := []byte(trieKey(arg))
+			for i := arg % 2; i < len(pattern); i += 2 {
+				pattern[i] = Wildcard
+			}
+			var expected []string
+			for _, key := range slices.Sorted(maps.Keys(model)) {
+				if matchesPattern(key, string(pattern)) {
+					expected = append(expected, key)
+				}
+			}
+			if matches := tree.Match(string(pattern)); !slices.Equal(matches, expected) {
+				return fmt.Errorf("Match(%q) returned %v, expected %v", pattern, matches, expected)
+			}
+			return nil
+		}},
+	},
+	Check: func(tree *TernarySearchTree[int], model map[string]int) error {
+		if keys := tree.Keys(); !slices.Equal(keys, slices.Sorted(maps.Keys(model))) {
+			return fmt.Errorf("unexpected keys %v", keys)
+		}
+		for key, expected := range model {
+			if value, ok := tree.Get(key); !ok || value != expected {
+				return fmt.Errorf("key %q has value %d %t, expected %d", key, value, ok, expected)
+			}
+		}
+		return nil
+	},
+}
+
+// matchesPattern reports whether a key matches a pattern with wildcards.
+func matchesPattern(key, pattern string) bool {
+	if len(key) != len(pattern) {
+		return false
+	}
+	for i := range key {
+		if pattern[i] != Wildcard && pattern[i] != key[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func Test_TernarySearchTreeModel(t *testing.T) {
+	tstMachine.Test(t, datatest.Config{Runs: 200, Length: 100})
+}
@@ -0,0 +1,134 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Backpressure selects what Publish does when a subscriber queue is full.
+type Backpressure int
+
+const (
+	BackpressureBlock      Backpressure = iota // Wait for room in the queue.
+	BackpressureDropOldest                     // Drop the oldest queued value.
+	BackpressureError                          // Skip the subscriber and report ErrFull.
+)
+
+// Topic is an in-process publish/subscribe channel. Every subscriber gets its
+// own bounded queue of published values.
+type Topic[T any] struct {
+	capacity    int                 // Capacity of each subscriber queue.
+	policy      Backpressure        // What to do when a queue is full.
+	subscribers []*BlockingQueue[T] // Subscriber queues in subscription order.
+	closed      bool                // Whether Close has been called.
+	mux         *sync.RWMutex       // Lock subscription operations.
+}
+
+// Create a new topic whose subscriber queues hold capacity values,
+// configured by WithCapacity.
+func NewTopic[T any](capacity int, policy Backpressure, opts ...Option) *Topic[T] {
+	capacity = newOptions(opts).capacityOr(capacity)
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Topic[T]{capacity: capacity, policy: policy, mux: &sync.RWMutex{}}
+}
+
+// Subscribers reports the number of subscribers.
+func (topic *Topic[T]) Subscribers() int {
+	topic.mux.RLock()
+	defer topic.mux.RUnlock()
+	return len(topic.subscribers)
+}
+
+// Subscribe adds a subscriber and returns the queue its values are sent to.
+// The queue is closed when the subscriber is removed or the topic is closed.
+func (topic *Topic[T]) Subscribe() (*BlockingQueue[T], error) {
+	if topic == nil {
+		return nil, nilError("topic")
+	}
+	topic.mux.Lock()
+	defer topic.mux.Unlock()
+	if topic.closed {
+		return nil, ErrClosed
+	}
+	queue := NewBlockingQueue[T](topic.capacity)
+	topic.subscribers = append(topic.subscribers, queue)
+	return queue, nil
+}
+
+// Unsubscribe removes a subscriber and closes its queue, values already
+// queued can still be taken.
+func (topic *Topic[T]) Unsubscribe(queue *BlockingQueue[T]) bool {
+	if topic == nil {
+		return false
+	}
+	topic.mux.Lock()
+	defer topic.mux.Unlock()
+	for i, subscriber := range topic.subscribers {
+		if subscriber == queue {
+			topic.subscribers = append(topic.subscribers[:i], topic.subscribers[i+1:]...)
+			queue.Close()
+			return true
+		}
+	}
+	return false
+}
+
+// Publish sends a value to every subscriber, applying the topic backpressure
+// policy to full queues. With BackpressureError the value is delivered to the
+// subscribers with room and an error wrapping ErrFull is returned.
+func (topic *Topic[T]) Publish(ctx context.Context, value T) error {
+	if topic == nil {
+		return nilError("topic")
+	}
+	topic.mux.RLock()
+	if topic.closed {
+		topic.mux.RUnlock()
+		return ErrClosed
+	}
+	subscribers := append([]*BlockingQueue[T](nil), topic.subscribers...)
+	topic.mux.RUnlock()
+
+	full := 0
+	for _, queue := range subscribers {
+		switch topic.policy {
+		case BackpressureDropOldest:
+			for !queue.TryPut(value) {
+				if queue.isClosed() {
+					break
+				}
+				queue.TryTake()
+			}
+		case BackpressureError:
+			if !queue.TryPut(value) && !queue.isClosed() {
+				full++
+			}
+		default:
+			err := queue.Put(ctx, value)
+			if err != nil && !errors.Is(err, ErrClosed) {
+				return err
+			}
+		}
+	}
+	if full > 0 {
+		return fmt.Errorf("%w: %d of %d subscribers skipped", ErrFull, full, len(subscribers))
+	}
+	return nil
+}
+
+// Close removes all subscribers, closing their queues.
+func (topic *Topic[T]) Close() {
+	if topic == nil {
+		return
+	}
+	topic.mux.Lock()
+	defer topic.mux.Unlock()
+	topic.closed = true
+	for _, queue := range topic.subscribers {
+		queue.Close()
+	}
+	topic.subscribers = nil
+}
@@ -0,0 +1,121 @@
+package data_test
+
+import (
+	"context"
+	"errors"
+	. "fun/pkg/data"
+	"testing"
+	"time"
+)
+
+// drain takes every value currently queued.
+func drain[T any](queue *BlockingQueue[T]) []T {
+	var values []T
+	for {
+		value, ok := queue.TryTake()
+		if !ok {
+			return values
+		}
+		values = append(values, value)
+	}
+}
+
+func Test_TopicPublish(t *testing.T) {
+	ctx := context.Background()
+	topic := NewTopic[int](10, BackpressureBlock)
+	a, _ := topic.Subscribe()
+	b, _ := topic.Subscribe()
+	if topic.Subscribers() != 2 {
+		t.Error("expected 2 subscribers, got", topic.Subscribers())
+	}
+	topic.Publish(ctx, 1)
+	topic.Publish(ctx, 2)
+	if values := drain(a); len(values) != 2 || values[0] != 1 || values[1] != 2 {
+		t.Error("expected a to receive [1 2], got", values)
+	}
+	if values := drain(b); len(values) != 2 {
+		t.Error("expected b to receive 2 values, got", values)
+	}
+
+	if !topic.Unsubscribe(a) {
+		t.Error("failed to unsubscribe a")
+	}
+	if topic.Unsubscribe(a) {
+		t.Error("unsubscribed a twice")
+	}
+	topic.Publish(ctx, 3)
+	if _, err := a.Take(ctx); !errors.Is(err, ErrClosed) {
+		t.Error("expected unsubscribed queue to be closed, got", err)
+	}
+	if value, _ := b.Take(ctx); value != 3 {
+		t.Error("expected b to receive 3, got", value)
+	}
+
+	topic.Close()
+	if err := topic.Publish(ctx, 4); !errors.Is(err, ErrClosed) {
+		t.Error("expected publish on closed topic to fail, got", err)
+	}
+	if _, err := topic.Subscribe(); !errors.Is(err, ErrClosed) {
+		t.Error("expected subscribe on closed topic to fail, got", err)
+	}
+	if _, err := b.Take(ctx); !errors.Is(err, ErrClosed) {
+		t.Error("expected subscriber queue closed with topic, got", err)
+	}
+}
+
+func Test_TopicDropOldest(t *testing.T) {
+	ctx := context.Background()
+	topic := NewTopic[int](2, BackpressureDropOldest)
+	queue, _ := topic.Subscribe()
+	for i := 1; i <= 5; i++ {
+		if err := topic.Publish(ctx, i); err != nil {
+			t.Error("unexpected error", err)
+		}
+	}
+	if values := drain(queue); len(values) != 2 || values[0] != 4 || values[1] != 5 {
+		t.Error("expected newest values [4 5], got", values)
+	}
+}
+
+func Test_TopicError(t *testing.T) {
+	ctx := context.Background()
+	topic := NewTopic[int](1, BackpressureError)
+	full, _ := topic.Subscribe()
+	topic.Publish(ctx, 1)
+	empty, _ := topic.Subscribe()
+	if err := topic.Publish(ctx, 2); !errors.Is(err, ErrFull) {
+		t.Error("expected ErrFull, got", err)
+	}
+	if values := drain(full); len(values) != 1 || values[0] != 1 {
+		t.Error("expected full subscriber to keep [1], got", values)
+	}
+	if values := drain(empty); len(values) != 1 || values[0] != 2 {
+		t.Error("expected other subscriber to receive [2], got", values)
+	}
+}
+
+func Test_TopicBlock(t *testing.T) {
+	topic := NewTopic[int](1, BackpressureBlock)
+	queue, _ := topic.Subscribe()
+	topic.Publish(context.Background(), 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := topic.Publish(ctx, 2); !errors.Is(err, ErrCanceled) {
+		t.Error("expected blocked publish to be canceled, got", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- topic.Publish(context.Background(), 3)
+	}()
+	if value, _ := queue.Take(context.Background()); value != 1 {
+		t.Error("expected 1, got", value)
+	}
+	if err := <-done; err != nil {
+		t.Error("unexpected error", err)
+	}
+	if value, _ := queue.Take(context.Background()); value != 3 {
+		t.Error("expected 3, got", value)
+	}
+}
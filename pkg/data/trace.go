@@ -0,0 +1,80 @@
+package data
+
+import "time"
+
+// Span describes a completed expensive operation.
+type Span struct {
+	Name       string           // Operation name, such as "List.Find".
+	Start      time.Time        // When the operation started.
+	Duration   time.Duration    // How long the operation took.
+	Attributes map[string]int64 // Cardinality of the operation, such as the length scanned.
+}
+
+// Tracer receives spans of expensive operations. Spans are reported after
+// the operation completes, so an OpenTelemetry adapter should start its span
+// at Span.Start and end it at Span.Start plus Span.Duration.
+type Tracer interface {
+	Trace(span Span)
+}
+
+// TracerFunc adapts a function to a Tracer.
+type TracerFunc func(span Span)
+
+// Trace calls f.
+func (f TracerFunc) Trace(span Span) {
+	f(span)
+}
+
+// TraceThreshold selects the operations that are traced: those over a
+// structure at least Length long, or that take at least Duration. A zero
+// field is ignored, so the zero TraceThreshold traces every operation.
+type TraceThreshold struct {
+	Length   int           // Minimum length of the structure.
+	Duration time.Duration // Minimum duration of the operation.
+}
+
+// traceHook reports spans to a tracer. A nil *traceHook reports nothing, so
+// untraced structures pay only a nil check.
+type traceHook struct {
+	tracer    Tracer         // Receives spans.
+	threshold TraceThreshold // Operations to trace.
+}
+
+// newTraceHook creates a hook, or nil if tracer is nil.
+func newTraceHook(tracer Tracer, threshold TraceThreshold) *traceHook {
+	if tracer == nil {
+		return nil
+	}
+	return &traceHook{tracer, threshold}
+}
+
+// begin starts timing an operation.
+func (hook *traceHook) begin() time.Time {
+	if hook == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// end reports an operation started at start over a structure of length
+// elements, if it meets the threshold.
+func (hook *traceHook) end(name string, start time.Time, length int) {
+	if hook == nil {
+		return
+	}
+	duration := time.Since(start)
+	threshold := hook.threshold
+	lengthMet := threshold.Length > 0 && length >= threshold.Length
+	durationMet := threshold.Duration > 0 && duration >= threshold.Duration
+	if threshold.Length > 0 || threshold.Duration > 0 {
+		if !lengthMet && !durationMet {
+			return
+		}
+	}
+	hook.tracer.Trace(Span{
+		Name:       name,
+		Start:      start,
+		Duration:   duration,
+		Attributes: map[string]int64{"length": int64(length)},
+	})
+}
@@ -0,0 +1,46 @@
+package data_test
+
+import (
+	. "fun/pkg/data"
+	"testing"
+	"time"
+)
+
+func Test_ListTracer(t *testing.T) {
+	var spans []Span
+	tracer := TracerFunc(func(span Span) {
+		spans = append(spans, span)
+	})
+	list := NewList[Data]().WithTracer(tracer, TraceThreshold{Length: 3})
+	list.AppendAll(1, 2)
+	list.Find(2)
+	if len(spans) != 0 {
+		t.Error("expected short scans not to be traced, got", spans)
+	}
+	list.Append(3)
+	list.Find(3)
+	list.Delete(3)
+	if len(spans) != 2 || spans[0].Name != "List.Find" || spans[1].Name != "List.Delete" {
+		t.Fatal("expected Find and Delete spans, got", spans)
+	}
+	if spans[0].Attributes["length"] != 3 || spans[0].Start.IsZero() || spans[0].Duration < 0 {
+		t.Error("unexpected span", spans[0])
+	}
+
+	spans = nil
+	list.WithTracer(tracer, TraceThreshold{})
+	_ = list.String()
+	list.View()
+	if len(spans) != 2 || spans[0].Name != "List.String" || spans[1].Name != "List.View" {
+		t.Error("expected every scan to be traced with a zero threshold, got", spans)
+	}
+
+	spans = nil
+	list.WithTracer(tracer, TraceThreshold{Duration: time.Hour})
+	list.Find(1)
+	list.WithTracer(nil, TraceThreshold{})
+	list.Find(1)
+	if len(spans) != 0 {
+		t.Error("expected no spans, got", spans)
+	}
+}
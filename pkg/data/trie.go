@@ -0,0 +1,300 @@
+package data
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// trieNode is a node of a Trie, reached by the bytes of a key prefix.
+type trieNode[V any] struct {
+	value    V              // Value of the key ending at the node.
+	terminal bool           // A key ends at the node.
+	labels   []byte         // Byte leading to each child, in increasing order.
+	children []*trieNode[V] // Child for each label.
+}
+
+// child gets the child reached by a byte, or nil.
+func (node *trieNode[V]) child(label byte) *trieNode[V] {
+	if i, found := slices.BinarySearch(node.labels, label); found {
+		return node.children[i]
+	}
+	return nil
+}
+
+// Trie is a map from strings to values stored in a prefix tree, one node per
+// byte of each distinct prefix, so lookups take time in proportion to the
+// length of the key and keys sharing a prefix can be found together. Keys
+// are visited in lexicographic byte order.
+type Trie[V any] struct {
+	root    *trieNode[V] // Node of the empty prefix.
+	length  int          // Number of keys stored in the trie.
+	metrics *Metrics     // Instrumentation, nil when disabled.
+	mux     locker       // Lock read and write operations.
+}
+
+// Create a new trie, configured by WithLocking and WithMetrics.
+func NewTrie[V any](opts ...Option) *Trie[V] {
+	settings := newOptions(opts)
+	return &Trie[V]{root: &trieNode[V]{}, metrics: settings.metrics, mux: settings.newLocker()}
+}
+
+// Length reports the number of keys in the trie.
+func (trie *Trie[V]) Length() int {
+	if trie == nil {
+		return 0
+	}
+	trie.mux.RLock()
+	defer trie.mux.RUnlock()
+	return trie.length
+}
+
+// Insert stores a value under a key, replacing any value already there.
+func (trie *Trie[V]) Insert(key string, value V) error {
+	if trie == nil {
+		return nilError("trie")
+	}
+	start := trie.metrics.begin()
+	trie.mux.Lock()
+	defer trie.mux.Unlock()
+	defer trie.metrics.end("Insert", start, trie.metrics.acquired())
+	defer trie.debugCheck()
+	node := trie.root
+	for i := 0; i < len(key); i++ {
+		j, found := slices.BinarySearch(node.labels, key[i])
+		if !found {
+			node.labels = slices.Insert(node.labels, j, key[i])
+			node.children = slices.Insert(node.children, j, &trieNode[V]{})
+		}
+		node = node.children[j]
+	}
+	if !node.terminal {
+		node.terminal = true
+		trie.length++
+	}
+	node.value = value
+	return nil
+}
+
+// find gets the node of a prefix, or nil, lock must be held.
+func (trie *Trie[V]) find(prefix string) *trieNode[V] {
+	node := trie.root
+	for i := 0; i < len(prefix) && node != nil; i++ {
+		node = node.child(prefix[i])
+	}
+	return node
+}
+
+// Get gets the value stored under a key.
+func (trie *Trie[V]) Get(key string) (V, bool) {
+	var unset V
+	if trie == nil {
+		return unset, false
+	}
+	trie.mux.RLock()
+	defer trie.mux.RUnlock()
+	if node := trie.find(key); node != nil && node.terminal {
+		return node.value, true
+	}
+	return unset, false
+}
+
+// Contains reports whether a key is in the trie.
+func (trie *Trie[V]) Contains(key string) bool {
+	_, ok := trie.Get(key)
+	return ok
+}
+
+// Delete removes a key, reporting whether there was one, and prunes the
+// nodes left without keys.
+func (trie *Trie[V]) Delete(key string) bool {
+	if trie == nil {
+		return false
+	}
+	start := trie.metrics.begin()
+	trie.mux.Lock()
+	defer trie.mux.Unlock()
+	defer trie.metrics.end("Delete", start, trie.metrics.acquired())
+	defer trie.debugCheck()
+	path := make([]*trieNode[V], 0, len(key)+1)
+	node := trie.root
+	for i := 0; i < len(key) && node != nil; i++ {
+		path = append(path, node)
+		node = node.child(key[i])
+	}
+	if node == nil || !node.terminal {
+		return false
+	}
+	var unset V
+	node.terminal, node.value = false, unset
+	trie.length--
+	// Remove the nodes with no key and no children, from the deepest up.
+	for i := len(path) - 1; i >= 0 && !node.terminal && len(node.children) == 0; i-- {
+		parent := path[i]
+		j, _ := slices.BinarySearch(parent.labels, key[i])
+		parent.labels = slices.Delete(parent.labels, j, j+1)
+		parent.children = slices.Delete(parent.children, j, j+1)
+		node = parent
+	}
+	return true
+}
+
+// HasPrefix reports whether any key starts with prefix.
+func (trie *Trie[V]) HasPrefix(prefix string) bool {
+	if trie == nil {
+		return false
+	}
+	trie.mux.RLock()
+	defer trie.mux.RUnlock()
+	node := trie.find(prefix)
+	return node != nil && (node.terminal || len(node.children) > 0)
+}
+
+// LongestPrefix finds the longest key that is a prefix of s, as for a
+// routing table, and its value.
+func (trie *Trie[V]) LongestPrefix(s string) (string, V, bool) {
+	var unset V
+	if trie == nil {
+		return "", unset, false
+	}
+	trie.mux.RLock()
+	defer trie.mux.RUnlock()
+	length, value, ok := 0, unset, false
+	node := trie.root
+	for i := 0; node != nil; i++ {
+		if node.terminal {
+			length, value, ok = i, node.value, true
+		}
+		if i == len(s) {
+			break
+		}
+		node = node.child(s[i])
+	}
+	return s[:length], value, ok
+}
+
+// WalkPrefix calls fn on each key that starts with prefix, and its value, in
+// lexicographic order until fn returns false. The read lock is held
+// throughout, so fn must not modify the trie.
+func (trie *Trie[V]) WalkPrefix(prefix string, fn func(key string, value V) bool) {
+	if trie == nil {
+		return
+	}
+	trie.mux.RLock()
+	defer trie.mux.RUnlock()
+	if node := trie.find(prefix); node != nil {
+		walkTrie(node, []byte(prefix), fn)
+	}
+}
+
+// walkTrie calls fn on the keys of the subtree of node, whose prefix is key,
+// until fn returns false, reporting whether it did not.
+func walkTrie[V any](node *trieNode[V], key []byte, fn func(string, V) bool) bool {
+	if node.terminal && !fn(string(key), node.value) {
+		return false
+	}
+	for i, child := range node.children {
+		if !walkTrie(child, append(key, node.labels[i]), fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Keys copies the keys into a slice, in lexicographic order.
+func (trie *Trie[V]) Keys() []string {
+	var keys []string
+	trie.WalkPrefix("", func(key string, value V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// All gets a sequence of the keys and values as of the call, in
+// lexicographic order.
+func (trie *Trie[V]) All() iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		var keys []string
+		var values []V
+		trie.WalkPrefix("", func(key string, value V) bool {
+			keys = append(keys, key)
+			values = append(values, value)
+			return true
+		})
+		for i, key := range keys {
+			if !yield(key, values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// String converts Trie data into a string of key:value pairs, in
+// lexicographic order.
+func (trie *Trie[V]) String() string {
+	if trie == nil {
+		return ""
+	}
+	s := fmt.Sprintf("Length: %d, Data:", trie.Length())
+	for key, value := range trie.All() {
+		s += fmt.Sprintf(" %s:%v", key, value)
+	}
+	return s
+}
+
+// CheckInvariants verifies that the labels of each node are strictly
+// increasing, that every node but the root leads to a key, and that the
+// length is the number of keys.
+func (trie *Trie[V]) CheckInvariants() error {
+	if trie == nil {
+		return nil
+	}
+	trie.mux.RLock()
+	defer trie.mux.RUnlock()
+	return trie.checkInvariants()
+}
+
+// checkInvariants verifies the trie, lock must be held.
+func (trie *Trie[V]) checkInvariants() error {
+	count := 0
+	var check func(node *trieNode[V], key []byte) error
+	check = func(node *trieNode[V], key []byte) error {
+		if len(node.labels) != len(node.children) {
+			return fmt.Errorf("trie: node %q has %d labels and %d children", key, len(node.labels), len(node.children))
+		}
+		if node != trie.root && !node.terminal && len(node.children) == 0 {
+			return fmt.Errorf("trie: node %q leads to no key", key)
+		}
+		if node.terminal {
+			count++
+		}
+		for i, child := range node.children {
+			if i > 0 && node.labels[i-1] >= node.labels[i] {
+				return fmt.Errorf("trie: labels of node %q are out of order", key)
+			}
+			if err := check(child, append(key, node.labels[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := check(trie.root, nil); err != nil {
+		return err
+	}
+	if count != trie.length {
+		return fmt.Errorf("trie: length %d, counted %d keys", trie.length, count)
+	}
+	return nil
+}
+
+// debugCheck panics if the trie invariants are violated in debug builds,
+// lock must be held.
+func (trie *Trie[V]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := trie.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
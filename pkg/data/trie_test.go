@@ -0,0 +1,132 @@
+package data_test
+
+import (
+	"errors"
+	"fmt"
+	. "fun/pkg/data"
+	"fun/pkg/datatest"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func Test_Trie(t *testing.T) {
+	trie := NewTrie[int]()
+	for i, key := range []string{"tea", "ten", "to", "inn", "in", "tea", ""} {
+		trie.Insert(key, i)
+	}
+	if value, ok := trie.Get("tea"); !ok || value != 5 || trie.Length() != 6 {
+		t.Error("expected Insert to replace the value", value, ok, trie.Length())
+	}
+	if _, ok := trie.Get("te"); ok {
+		t.Error("expected a prefix not to be a key")
+	}
+	if !trie.HasPrefix("te") || trie.HasPrefix("tx") || !trie.HasPrefix("") {
+		t.Error("unexpected prefixes")
+	}
+	if keys := trie.Keys(); !slices.Equal(keys, []string{"", "in", "inn", "tea", "ten", "to"}) {
+		t.Error("expected keys in lexicographic order", keys)
+	}
+
+	// Autocomplete stops after the first few matches.
+	var completions []string
+	trie.WalkPrefix("t", func(key string, value int) bool {
+		completions = append(completions, key)
+		return len(completions) < 2
+	})
+	if !slices.Equal(completions, []string{"tea", "ten"}) {
+		t.Error("unexpected completions", completions)
+	}
+
+	routes := NewTrie[string]()
+	routes.Insert("/api/", "api")
+	routes.Insert("/api/users/", "users")
+	if prefix, value, ok := routes.LongestPrefix("/api/users/42"); !ok || prefix != "/api/users/" || value != "users" {
+		t.Error("unexpected route", prefix, value, ok)
+	}
+	if prefix, value, _ := routes.LongestPrefix("/api/teams"); prefix != "/api/" || value != "api" {
+		t.Error("unexpected route", prefix, value)
+	}
+	if _, _, ok := routes.LongestPrefix("/static"); ok {
+		t.Error("expected no route")
+	}
+
+	if !trie.Delete("inn") || trie.Delete("inn") || trie.Delete("te") {
+		t.Error("unexpected deletions")
+	}
+	if trie.HasPrefix("inn") || !trie.Contains("in") {
+		t.Error("expected only the deleted key to be gone")
+	}
+	if err := trie.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+
+	var unset *Trie[int]
+	if err := unset.Insert("a", 1); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
+
+// trieKey converts a command argument to a short key over a small alphabet,
+// so keys share prefixes.
+func trieKey(arg int) string {
+	var key strings.Builder
+	for ; arg > 0; arg /= 3 {
+		key.WriteByte("abc"[arg%3])
+	}
+	return key.String()
+}
+
+// trieMachine checks a Trie against a map model.
+var trieMachine = datatest.Machine[*Trie[int], map[string]int]{
+	NewSystem: func() *Trie[int] { return NewTrie[int]() },
+	NewModel:  func() map[string]int { return map[string]int{} },
+	ArgRange:  81,
+	Commands: []datatest.Command[*Trie[int], map[string]int]{
+		{Name: "Insert", Run: func(trie *Trie[int], model map[string]int, arg int) error {
+			model[trieKey(arg)] = arg
+			return trie.Insert(trieKey(arg), arg)
+		}},
+		{Name: "Delete", Run: func(trie *Trie[int], model map[string]int, arg int) error {
+			_, found := model[trieKey(arg)]
+			delete(model, trieKey(arg))
+			if ok := trie.Delete(trieKey(arg)); ok != found {
+				return fmt.Errorf("Delete returned %t", ok)
+			}
+			return nil
+		}},
+		{Name: "WalkPrefix", Run: func(trie *Trie[int], model map[string]int, arg int) error {
+			prefix := trieKey(arg % 9)
+			var keys, expected []string
+			trie.WalkPrefix(prefix, func(key string, value int) bool {
+				keys = append(keys, key)
+				return true
+			})
+			for _, key := range slices.Sorted(maps.Keys(model)) {
+				if strings.HasPrefix(key, prefix) {
+					expected = append(expected, key)
+				}
+			}
+			if !slices.Equal(keys, expected) || trie.HasPrefix(prefix) != (len(expected) > 0) {
+				return fmt.Errorf("WalkPrefix(%q) returned %v, expected %v", prefix, keys, expected)
+			}
+			return nil
+		}},
+	},
+	Check: func(trie *Trie[int], model map[string]int) error {
+		if trie.Length() != len(model) {
+			return fmt.Errorf("expected length %d, got %d", len(model), trie.Length())
+		}
+		for key, expected := range model {
+			if value, ok := trie.Get(key); !ok || value != expected {
+				return fmt.Errorf("key %q has value %d %t, expected %d", key, value, ok, expected)
+			}
+		}
+		return nil
+	},
+}
+
+func Test_TrieModel(t *testing.T) {
+	trieMachine.Test(t, datatest.Config{Runs: 200, Length: 100})
+}
@@ -0,0 +1,340 @@
+package data
+
+import (
+	"fmt"
+	"iter"
+)
+
+// DefaultUnrolledCapacity is the number of values per node of an
+// UnrolledList created with a node capacity of 0.
+const DefaultUnrolledCapacity = 16
+
+// unrolledNode holds a run of consecutive values of an UnrolledList.
+type unrolledNode[T ListData] struct {
+	values []T              // Values in list order, at most the node capacity.
+	next   *unrolledNode[T] // Pointer to the next node in the list.
+}
+
+// UnrolledList is a linked list that stores several values per node, so
+// traversal touches fewer pointers and cache lines than List. Nodes are
+// merged when deletes leave them less than half full.
+type UnrolledList[T ListData] struct {
+	head     *unrolledNode[T] // Head of the list.
+	tail     *unrolledNode[T] // Tail of the list.
+	length   int              // Number of elements stored in the list.
+	capacity int              // Maximum values per node.
+	metrics  *Metrics         // Instrumentation, nil when disabled.
+	tracer   *traceHook       // Tracing of scans, nil when disabled.
+	mux      locker           // Lock read and write operations.
+}
+
+// Create a new unrolled list of up to nodeCapacity values per node, or
+// DefaultUnrolledCapacity if nodeCapacity is 0, configured by WithLocking,
+// WithMetrics, WithTracer, and WithCapacity.
+func NewUnrolledList[T ListData](nodeCapacity int, opts ...Option) *UnrolledList[T] {
+	settings := newOptions(opts)
+	nodeCapacity = settings.capacityOr(nodeCapacity)
+	if nodeCapacity <= 0 {
+		nodeCapacity = DefaultUnrolledCapacity
+	}
+	return &UnrolledList[T]{
+		capacity: nodeCapacity,
+		metrics:  settings.metrics,
+		tracer:   newTraceHook(settings.tracer, settings.threshold),
+		mux:      settings.newLocker(),
+	}
+}
+
+// newNode creates an empty node with room for the node capacity.
+func (list *UnrolledList[T]) newNode() *unrolledNode[T] {
+	return &unrolledNode[T]{values: make([]T, 0, list.capacity)}
+}
+
+// Length reports the number of elements in the list.
+func (list *UnrolledList[T]) Length() int {
+	if list == nil {
+		return 0
+	}
+	return list.length
+}
+
+// Insert adds an element at the beginning of a list.
+func (list *UnrolledList[T]) Insert(value T) error {
+	if list == nil {
+		return nilError("list")
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("Insert", start, list.metrics.acquired())
+	defer list.debugCheck()
+	if list.head == nil || len(list.head.values) == list.capacity {
+		node := list.newNode()
+		node.next = list.head
+		list.head = node
+		if list.tail == nil {
+			list.tail = node
+		}
+	}
+	list.head.values = append(list.head.values, value)
+	copy(list.head.values[1:], list.head.values)
+	list.head.values[0] = value
+	list.length++
+	return nil
+}
+
+// Append adds an element at the end of a list.
+func (list *UnrolledList[T]) Append(value T) error {
+	if list == nil {
+		return nilError("list")
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("Append", start, list.metrics.acquired())
+	defer list.debugCheck()
+	if list.tail == nil || len(list.tail.values) == list.capacity {
+		node := list.newNode()
+		if list.tail == nil {
+			list.head = node
+		} else {
+			list.tail.next = node
+		}
+		list.tail = node
+	}
+	list.tail.values = append(list.tail.values, value)
+	list.length++
+	return nil
+}
+
+// Get gets the value at an index, counting from the head.
+func (list *UnrolledList[T]) Get(i int) (T, bool) {
+	var unset T
+	if list == nil {
+		return unset, false
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	if i < 0 || i >= list.length {
+		return unset, false
+	}
+	node := list.head
+	for i >= len(node.values) {
+		i -= len(node.values)
+		node = node.next
+	}
+	return node.values[i], true
+}
+
+// Contains reports whether a value is in the list.
+func (list *UnrolledList[T]) Contains(value T) bool {
+	if list == nil {
+		return false
+	}
+	start := list.metrics.begin()
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	defer list.metrics.end("Contains", start, list.metrics.acquired())
+	defer list.tracer.end("UnrolledList.Contains", list.tracer.begin(), list.length)
+	_, node, _ := list.find(value)
+	return node != nil
+}
+
+// find finds the node holding the first occurrence of value, its parent, and
+// its index in the node, lock must be held.
+func (list *UnrolledList[T]) find(value T) (parent, node *unrolledNode[T], index int) {
+	for node = list.head; node != nil; parent, node = node, node.next {
+		for i, v := range node.values {
+			if v == value {
+				return parent, node, i
+			}
+		}
+	}
+	return nil, nil, -1
+}
+
+// Delete the first element holding value from the list.
+func (list *UnrolledList[T]) Delete(value T) bool {
+	if list == nil {
+		return false
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("Delete", start, list.metrics.acquired())
+	defer list.tracer.end("UnrolledList.Delete", list.tracer.begin(), list.length)
+	defer list.debugCheck()
+	parent, node, index := list.find(value)
+	if node == nil {
+		return false
+	}
+	list.remove(parent, node, index)
+	return true
+}
+
+// Delete the head node in the list.
+func (list *UnrolledList[T]) DeleteHead() (T, bool) {
+	var unset T
+	if list == nil {
+		return unset, false
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("DeleteHead", start, list.metrics.acquired())
+	defer list.debugCheck()
+	if list.head == nil {
+		return unset, false
+	}
+	value := list.head.values[0]
+	list.remove(nil, list.head, 0)
+	return value, true
+}
+
+// Delete the tail node in the list.
+func (list *UnrolledList[T]) DeleteTail() (T, bool) {
+	var unset T
+	if list == nil {
+		return unset, false
+	}
+	start := list.metrics.begin()
+	list.mux.Lock()
+	defer list.mux.Unlock()
+	defer list.metrics.end("DeleteTail", start, list.metrics.acquired())
+	defer list.debugCheck()
+	if list.tail == nil {
+		return unset, false
+	}
+	last := len(list.tail.values) - 1
+	value := list.tail.values[last]
+	if last > 0 {
+		// The tail keeps values, so its parent is not needed.
+		list.tail.values[last] = unset
+		list.tail.values = list.tail.values[:last]
+		list.length--
+		return value, true
+	}
+	var parent *unrolledNode[T]
+	for node := list.head; node != list.tail; node = node.next {
+		parent = node
+	}
+	list.remove(parent, list.tail, 0)
+	return value, true
+}
+
+// remove deletes the value at an index of a node, unlinking the node if it
+// empties and merging it with its successor if both fit in one node, lock
+// must be held.
+func (list *UnrolledList[T]) remove(parent, node *unrolledNode[T], index int) {
+	var unset T
+	copy(node.values[index:], node.values[index+1:])
+	node.values[len(node.values)-1] = unset
+	node.values = node.values[:len(node.values)-1]
+	list.length--
+	switch {
+	case len(node.values) == 0:
+		if parent == nil {
+			list.head = node.next
+		} else {
+			parent.next = node.next
+		}
+		if list.tail == node {
+			list.tail = parent
+		}
+	case node.next != nil && len(node.values) < list.capacity/2 &&
+		len(node.values)+len(node.next.values) <= list.capacity:
+		next := node.next
+		node.values = append(node.values, next.values...)
+		node.next = next.next
+		if list.tail == next {
+			list.tail = node
+		}
+	}
+}
+
+// Values copies the values of the list into a slice.
+func (list *UnrolledList[T]) Values() []T {
+	if list == nil {
+		return nil
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	values := make([]T, 0, list.length)
+	for node := list.head; node != nil; node = node.next {
+		values = append(values, node.values...)
+	}
+	return values
+}
+
+// All gets a sequence of the values of a snapshot of the list, taken when
+// iteration starts.
+func (list *UnrolledList[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range list.Values() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// String converts UnrolledList data into a string.
+func (list *UnrolledList[T]) String() string {
+	if list == nil {
+		return ""
+	}
+	values := list.Values()
+	s := fmt.Sprintf("Length: %d, Data:", len(values))
+	for _, v := range values {
+		s += " " + fmt.Sprint(v)
+	}
+	return s
+}
+
+// CheckInvariants verifies the list length matches the values in the nodes
+// and that no node is empty or over capacity.
+func (list *UnrolledList[T]) CheckInvariants() error {
+	if list == nil {
+		return nil
+	}
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	return list.checkInvariants()
+}
+
+// checkInvariants verifies the list, lock must be held.
+func (list *UnrolledList[T]) checkInvariants() error {
+	if (list.head == nil) != (list.tail == nil) {
+		return fmt.Errorf("unrolled list: head %p and tail %p must both be nil or both be set", list.head, list.tail)
+	}
+	count := 0
+	var last *unrolledNode[T]
+	for node := list.head; node != nil; node = node.next {
+		if len(node.values) == 0 || len(node.values) > list.capacity {
+			return fmt.Errorf("unrolled list: node %p holds %d values, capacity %d", node, len(node.values), list.capacity)
+		}
+		count += len(node.values)
+		if count > list.length {
+			return fmt.Errorf("unrolled list: nodes hold more than length %d values (cycle or stale length)", list.length)
+		}
+		last = node
+	}
+	if count != list.length {
+		return fmt.Errorf("unrolled list: length is %d but nodes hold %d values", list.length, count)
+	}
+	if last != list.tail {
+		return fmt.Errorf("unrolled list: tail %p is not the last node %p", list.tail, last)
+	}
+	return nil
+}
+
+// debugCheck panics if the list invariants are violated in debug builds, lock
+// must be held.
+func (list *UnrolledList[T]) debugCheck() {
+	if !debugInvariants {
+		return
+	}
+	if err := list.checkInvariants(); err != nil {
+		panic(err)
+	}
+}
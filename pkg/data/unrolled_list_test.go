@@ -0,0 +1,84 @@
+package data_test
+
+import (
+	. "fun/pkg/data"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func Test_UnrolledList(t *testing.T) {
+	list := NewUnrolledList[Data](4)
+	for i := 5; i < 12; i++ {
+		list.Append(Data(i))
+	}
+	for i := 4; i >= 0; i-- {
+		list.Insert(Data(i))
+	}
+	expected := []Data{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+	if values := list.Values(); !slices.Equal(values, expected) {
+		t.Fatal("expected", expected, "got", values)
+	}
+	for i, value := range expected {
+		if got, ok := list.Get(i); !ok || got != value {
+			t.Error("unexpected value at", i, got)
+		}
+	}
+	if _, ok := list.Get(12); ok {
+		t.Error("expected Get past the end to fail")
+	}
+	if !list.Contains(7) || list.Contains(12) {
+		t.Error("unexpected membership")
+	}
+	if !list.Delete(7) || list.Delete(7) {
+		t.Error("expected to delete 7 once")
+	}
+	if value, ok := list.DeleteTail(); !ok || value != 11 {
+		t.Error("unexpected tail", value)
+	}
+	if value, ok := list.DeleteHead(); !ok || value != 0 {
+		t.Error("unexpected head", value)
+	}
+	if list.String() != "Length: 9, Data: 1 2 3 4 5 6 8 9 10" {
+		t.Error("unexpected list", list.String())
+	}
+	if err := list.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_UnrolledListModel(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	list := NewUnrolledList[Data](3)
+	var model []Data
+	for i := 0; i < 2000; i++ {
+		value := Data(random.Intn(20))
+		switch random.Intn(5) {
+		case 0:
+			list.Insert(value)
+			model = append([]Data{value}, model...)
+		case 1:
+			list.Append(value)
+			model = append(model, value)
+		case 2:
+			if index := slices.Index(model, value); index >= 0 {
+				model = slices.Delete(model, index, index+1)
+			}
+			list.Delete(value)
+		case 3:
+			if _, ok := list.DeleteHead(); ok {
+				model = model[1:]
+			}
+		case 4:
+			if _, ok := list.DeleteTail(); ok {
+				model = model[:len(model)-1]
+			}
+		}
+		if err := list.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+		if values := slices.Collect(list.All()); !slices.Equal(values, model) {
+			t.Fatal("expected", model, "got", values)
+		}
+	}
+}
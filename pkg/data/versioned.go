@@ -0,0 +1,257 @@
+package data
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Version describes a committed version of a Versioned list.
+type Version struct {
+	Number int       // Position in the history, starting at 1.
+	Tag    string    // Name given at commit, may be empty.
+	Time   time.Time // When the version was committed.
+}
+
+// ChangeKind is the kind of a Change between versions.
+type ChangeKind int
+
+const (
+	Removed  ChangeKind = iota + 1 // The value is only in the older version.
+	Inserted                       // The value is only in the newer version.
+)
+
+// String names the change kind.
+func (kind ChangeKind) String() string {
+	switch kind {
+	case Removed:
+		return "-"
+	case Inserted:
+		return "+"
+	}
+	return "?"
+}
+
+// Change is one step of a diff between versions. The index of a removed
+// value is in the older version, of an inserted value in the newer one.
+type Change[T ListData] struct {
+	Kind  ChangeKind // Whether the value was removed or inserted.
+	Index int        // Position of the value in its version.
+	Value T          // Changed value.
+}
+
+// String formats the change as kind, index, and value.
+func (change Change[T]) String() string {
+	return fmt.Sprintf("%s%d %v", change.Kind, change.Index, change.Value)
+}
+
+// Versioned keeps a history of committed versions of a List, for audit logs
+// and inspecting past states. Each version is a PersistentList that shares
+// the nodes after the last change with the version before it, so a commit
+// stores only the values up to its last change, and reading a version never
+// blocks the list.
+type Versioned[T ListData] struct {
+	list     *List[T]             // List being versioned.
+	versions []Version            // Committed versions, oldest first.
+	contents []*PersistentList[T] // Contents of each version.
+	tags     map[string]int       // Version number of each tag.
+	mux      *sync.Mutex          // Lock the history.
+}
+
+// Create a new version history of a list, with no versions committed.
+func NewVersioned[T ListData](list *List[T]) *Versioned[T] {
+	return &Versioned[T]{list: list, tags: map[string]int{}, mux: &sync.Mutex{}}
+}
+
+// Commit captures the current contents of the list as a new version. The tag
+// is optional, but must be unique.
+func (versioned *Versioned[T]) Commit(tag string) (Version, error) {
+	if versioned == nil {
+		return Version{}, nilError("versioned")
+	}
+	versioned.mux.Lock()
+	defer versioned.mux.Unlock()
+	if _, ok := versioned.tags[tag]; ok && tag != "" {
+		return Version{}, fmt.Errorf("%w: version already tagged %s", ErrDuplicate, tag)
+	}
+	var previous *PersistentList[T]
+	if last := len(versioned.contents) - 1; last >= 0 {
+		previous = versioned.contents[last]
+	}
+	version := Version{Number: len(versioned.versions) + 1, Tag: tag, Time: time.Now()}
+	versioned.versions = append(versioned.versions, version)
+	versioned.contents = append(versioned.contents, versioned.list.persist(previous))
+	if tag != "" {
+		versioned.tags[tag] = version.Number
+	}
+	return version, nil
+}
+
+// persist captures the contents of the list as a persistent list sharing
+// the longest common suffix of previous, which is returned as is when
+// nothing changed.
+func (list *List[T]) persist(previous *PersistentList[T]) *PersistentList[T] {
+	list.mux.RLock()
+	defer list.mux.RUnlock()
+	head, length := previous.nodes()
+	// Line up the ends of the two lists, then find where the values stop
+	// differing for good.
+	node, old := list.head, head
+	for i := list.length; i > length; i-- {
+		node = node.next
+	}
+	for i := length; i > list.length; i-- {
+		old = old.next
+	}
+	shared, sharedLength := old, min(length, list.length)
+	for remaining := sharedLength; node != nil; remaining-- {
+		if node.value != old.value {
+			shared, sharedLength = old.next, remaining-1
+		}
+		node, old = node.next, old.next
+	}
+	if sharedLength == length && length == list.length {
+		return previous
+	}
+	// Copy the values before the shared suffix.
+	first, last := shared, (*persistentNode[T])(nil)
+	node = list.head
+	for i := list.length - sharedLength; i > 0; i-- {
+		copied := &persistentNode[T]{value: node.value, next: shared}
+		if last == nil {
+			first = copied
+		} else {
+			last.next = copied
+		}
+		last, node = copied, node.next
+	}
+	return &PersistentList[T]{first, list.length}
+}
+
+// At gets the contents of a version by number.
+func (versioned *Versioned[T]) At(number int) (*PersistentList[T], bool) {
+	if versioned == nil {
+		return nil, false
+	}
+	versioned.mux.Lock()
+	defer versioned.mux.Unlock()
+	if number < 1 || number > len(versioned.contents) {
+		return nil, false
+	}
+	return versioned.contents[number-1], true
+}
+
+// Lookup finds a version by tag.
+func (versioned *Versioned[T]) Lookup(tag string) (Version, bool) {
+	if versioned == nil {
+		return Version{}, false
+	}
+	versioned.mux.Lock()
+	defer versioned.mux.Unlock()
+	number, ok := versioned.tags[tag]
+	if !ok || tag == "" {
+		return Version{}, false
+	}
+	return versioned.versions[number-1], true
+}
+
+// Versions lists the committed versions, oldest first.
+func (versioned *Versioned[T]) Versions() []Version {
+	if versioned == nil {
+		return nil
+	}
+	versioned.mux.Lock()
+	defer versioned.mux.Unlock()
+	return append([]Version(nil), versioned.versions...)
+}
+
+// Diff gets the shortest list of changes that turns version from into
+// version to, removals before insertions at each position.
+func (versioned *Versioned[T]) Diff(from, to int) ([]Change[T], error) {
+	if versioned == nil {
+		return nil, nilError("versioned")
+	}
+	older, ok := versioned.At(from)
+	if !ok {
+		return nil, fmt.Errorf("%w: version %d", ErrNotFound, from)
+	}
+	newer, ok := versioned.At(to)
+	if !ok {
+		return nil, fmt.Errorf("%w: version %d", ErrNotFound, to)
+	}
+	if older == newer {
+		return nil, nil
+	}
+	return diffValues(older.Values(), newer.Values()), nil
+}
+
+// diffValues computes the shortest edit script between two slices with
+// Myers's O(ND) algorithm, after trimming their common prefix and suffix, in
+// time proportional to their length times the number of changes D and
+// memory proportional to D squared.
+func diffValues[T ListData](a, b []T) []Change[T] {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// furthest[offset+k] is the furthest x reached on diagonal k = x - y, and
+	// trace keeps the diagonals each step started from, to walk back the
+	// path.
+	offset := len(a) + len(b) + 1
+	furthest := make([]int, 2*offset+1)
+	var trace [][]int
+	// down reports whether the path to diagonal k after d changes comes down
+	// from diagonal k+1, an insertion, rather than right from k-1, a removal.
+	down := func(furthest []int, center, d, k int) bool {
+		return k == -d || (k != d && furthest[center+k-1] < furthest[center+k+1])
+	}
+	for d := 0; ; d++ {
+		trace = append(trace, slices.Clone(furthest[offset-d-1:offset+d+2]))
+		done := false
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if down(furthest, offset, d, k) {
+				x = furthest[offset+k+1]
+			} else {
+				x = furthest[offset+k-1] + 1
+			}
+			for x < len(a) && x-k < len(b) && a[x] == b[x-k] {
+				x++
+			}
+			furthest[offset+k] = x
+			if x >= len(a) && x-k >= len(b) {
+				done = true
+				break
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	// Walk back from the end, one change per step, skipping the values the
+	// path took in common.
+	var changes []Change[T]
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d > 0; d-- {
+		diagonals, k := trace[d], x-y
+		if down(diagonals, d+1, d, k) {
+			x = diagonals[d+1+k+1]
+			y = x - k - 1
+			changes = append(changes, Change[T]{Inserted, prefix + y, b[y]})
+		} else {
+			x = diagonals[d+1+k-1]
+			y = x - k + 1
+			changes = append(changes, Change[T]{Removed, prefix + x, a[x]})
+		}
+	}
+	slices.Reverse(changes)
+	return changes
+}
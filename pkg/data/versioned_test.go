@@ -0,0 +1,158 @@
+package data_test
+
+import (
+	"errors"
+	. "fun/pkg/data"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func Test_Versioned(t *testing.T) {
+	list := NewList[Data]()
+	versioned := NewVersioned(list)
+	list.AppendAll(1, 2, 3, 4)
+	first, err := versioned.Commit("initial")
+	if err != nil || first.Number != 1 {
+		t.Fatal("unexpected commit", first, err)
+	}
+	list.Delete(2)
+	list.Append(5)
+	list.Insert(0)
+	second, _ := versioned.Commit("")
+	if _, err := versioned.Commit("initial"); err == nil {
+		t.Error("expected an error reusing a tag")
+	}
+
+	if view, ok := versioned.At(first.Number); !ok || view.String() != "Length: 4, Data: 1 2 3 4" {
+		t.Error("unexpected first version", view)
+	}
+	if view, ok := versioned.At(second.Number); !ok || view.String() != "Length: 5, Data: 0 1 3 4 5" {
+		t.Error("unexpected second version", view)
+	}
+	if _, ok := versioned.At(3); ok {
+		t.Error("expected no third version")
+	}
+	if version, ok := versioned.Lookup("initial"); !ok || version.Number != 1 {
+		t.Error("unexpected tagged version", version, ok)
+	}
+	if _, ok := versioned.Lookup(""); ok {
+		t.Error("expected no version for the empty tag")
+	}
+	if versions := versioned.Versions(); len(versions) != 2 || versions[1].Number != 2 {
+		t.Error("unexpected versions", versions)
+	}
+
+	changes, err := versioned.Diff(1, 2)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	expected := []string{"+0 0", "-1 2", "+4 5"}
+	if len(changes) != len(expected) {
+		t.Fatal("unexpected changes", changes)
+	}
+	for i, change := range changes {
+		if change.String() != expected[i] {
+			t.Error("expected", expected[i], "got", change)
+		}
+	}
+	if changes, _ := versioned.Diff(2, 2); len(changes) != 0 {
+		t.Error("expected no changes between a version and itself", changes)
+	}
+	if _, err := versioned.Diff(1, 9); !errors.Is(err, ErrNotFound) {
+		t.Error("expected not found error, got", err)
+	}
+
+	var missing *Versioned[Data]
+	if _, err := missing.Commit(""); !errors.Is(err, ErrNilReceiver) {
+		t.Error("expected nil receiver error, got", err)
+	}
+}
+
+func Test_VersionedDiff(t *testing.T) {
+	random := rand.New(rand.NewPCG(1, 2))
+	for round := 0; round < 200; round++ {
+		list := NewList[Data]()
+		versioned := NewVersioned(list)
+		for i := random.IntN(20); i > 0; i-- {
+			list.Append(Data(random.IntN(4)))
+		}
+		versioned.Commit("")
+		for i := random.IntN(10); i > 0; i-- {
+			if random.IntN(2) == 0 {
+				list.InsertAt(random.IntN(list.Length()+1), Data(random.IntN(4)))
+			} else if list.Length() > 0 {
+				list.RemoveAt(random.IntN(list.Length()))
+			}
+		}
+		versioned.Commit("")
+
+		older, _ := versioned.At(1)
+		newer, _ := versioned.At(2)
+		a, b := older.Values(), newer.Values()
+		if !slices.Equal(b, slices.Collect(list.All())) {
+			t.Fatal("expected version 2 to hold the list, got", b)
+		}
+		changes, _ := versioned.Diff(1, 2)
+		// Removals are indexed in a and insertions in b, so apply the
+		// removals from the end, then the insertions from the start.
+		patched := slices.Clone(a)
+		for i := len(changes) - 1; i >= 0; i-- {
+			if changes[i].Kind == Removed {
+				patched = slices.Delete(patched, changes[i].Index, changes[i].Index+1)
+			}
+		}
+		for _, change := range changes {
+			if change.Kind == Inserted {
+				patched = slices.Insert(patched, change.Index, change.Value)
+			}
+		}
+		if !slices.Equal(patched, b) {
+			t.Fatal("diff of", a, "and", b, "does not apply:", changes)
+		}
+		if len(changes) != len(a)+len(b)-2*lcsLength(a, b) {
+			t.Fatal("diff of", a, "and", b, "is not the shortest:", changes)
+		}
+	}
+}
+
+// lcsLength computes the length of the longest common subsequence.
+func lcsLength(a, b []Data) int {
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+	return common[0][0]
+}
+
+func Test_VersionedSharing(t *testing.T) {
+	list := NewListOf[Data](1, 2, 3)
+	versioned := NewVersioned(list)
+	versioned.Commit("")
+	versioned.Commit("")
+	first, _ := versioned.At(1)
+	second, _ := versioned.At(2)
+	if first != second {
+		t.Error("expected an unchanged version to be shared")
+	}
+	list.DeleteHead()
+	versioned.Commit("")
+	list.Insert(0)
+	list.Snapshot()
+	list.Append(4)
+	versioned.Commit("")
+	for number, expected := range map[int][]Data{1: {1, 2, 3}, 3: {2, 3}, 4: {0, 2, 3, 4}} {
+		if version, _ := versioned.At(number); !slices.Equal(version.Values(), expected) {
+			t.Error("expected version", number, "to hold", expected, "got", version)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Package datatest checks data structures against reference models. It
+// generates random operation sequences, runs each against a structure and a
+// model, and shrinks failing sequences to a minimal reproduction. For
+// concurrent structures it checks recorded histories for linearizability.
+package datatest
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// Command is an operation applied to both a system under test and its
+// model. Run applies it with an argument and returns an error if their
+// results differ.
+type Command[S, M any] struct {
+	Name string                                 // Name shown in failing sequences.
+	Run  func(system S, model M, arg int) error // Apply to both and compare.
+}
+
+// Checker is a system that can verify its internal invariants, such as a
+// length matching its nodes. Machines check such systems after each step.
+type Checker interface {
+	CheckInvariants() error
+}
+
+// Machine describes a structure and its reference model. If the system is a
+// Checker, its invariants are checked after each step, before Check.
+type Machine[S, M any] struct {
+	NewSystem func() S                      // Create an empty system under test.
+	NewModel  func() M                      // Create an empty model.
+	Commands  []Command[S, M]               // Operations to generate.
+	Check     func(system S, model M) error // Compare whole states after each step, optional.
+	ArgRange  int                           // Arguments are in [0, ArgRange), 0 for 16.
+}
+
+// Config controls sequence generation.
+type Config struct {
+	Seed   int64 // Seed of the first sequence, 0 for 1.
+	Runs   int   // Number of sequences to try, 0 for 100.
+	Length int   // Maximum steps per sequence, 0 for 50.
+}
+
+// Step is one command of a sequence with its argument.
+type Step struct {
+	Command int // Index into Machine.Commands.
+	Arg     int // Argument passed to the command.
+}
+
+// Failure is a minimal sequence that makes a system disagree with its model.
+type Failure struct {
+	Steps []string // Failing steps, as Name(arg).
+	Err   error    // Disagreement reported by the last step.
+	Seed  int64    // Seed of the sequence the failure was shrunk from.
+}
+
+// Error describes the failing sequence.
+func (failure *Failure) Error() string {
+	return fmt.Sprintf("seed %d: %s: %v", failure.Seed, strings.Join(failure.Steps, "; "), failure.Err)
+}
+
+// Run generates random sequences and returns the first failure, shrunk, or
+// nil if every sequence agrees with the model.
+func (machine Machine[S, M]) Run(config Config) *Failure {
+	if config.Seed == 0 {
+		config.Seed = 1
+	}
+	if config.Runs <= 0 {
+		config.Runs = 100
+	}
+	if config.Length <= 0 {
+		config.Length = 50
+	}
+	argRange := machine.ArgRange
+	if argRange <= 0 {
+		argRange = 16
+	}
+	for run := 0; run < config.Runs; run++ {
+		seed := config.Seed + int64(run)
+		random := rand.New(rand.NewSource(seed))
+		steps := make([]Step, 1+random.Intn(config.Length))
+		for i := range steps {
+			steps[i] = Step{random.Intn(len(machine.Commands)), random.Intn(argRange)}
+		}
+		if _, err := machine.Replay(steps); err != nil {
+			steps, err = machine.shrink(steps, err)
+			failure := &Failure{Err: err, Seed: seed}
+			for _, step := range steps {
+				failure.Steps = append(failure.Steps, fmt.Sprintf("%s(%d)", machine.Commands[step.Command].Name, step.Arg))
+			}
+			return failure
+		}
+	}
+	return nil
+}
+
+// Test runs the machine and fails the test with the shrunk sequence.
+func (machine Machine[S, M]) Test(t testing.TB, config Config) {
+	t.Helper()
+	if failure := machine.Run(config); failure != nil {
+		t.Fatal(failure)
+	}
+}
+
+// Replay runs a sequence against a new system and model, returning how many
+// steps ran and the first disagreement. A panic in a step or a broken
+// invariant is a disagreement.
+func (machine Machine[S, M]) Replay(steps []Step) (ran int, err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = fmt.Errorf("panic: %v", value)
+		}
+	}()
+	system, model := machine.NewSystem(), machine.NewModel()
+	for _, step := range steps {
+		ran++
+		if err := machine.Commands[step.Command].Run(system, model, step.Arg); err != nil {
+			return ran, err
+		}
+		if checker, ok := any(system).(Checker); ok {
+			if err := checker.CheckInvariants(); err != nil {
+				return ran, fmt.Errorf("invariant: %w", err)
+			}
+		}
+		if machine.Check != nil {
+			if err := machine.Check(system, model); err != nil {
+				return ran, err
+			}
+		}
+	}
+	return ran, nil
+}
+
+// shrink reduces a failing sequence by dropping everything after the failing
+// step, removing chunks of steps, and lowering arguments, for as long as it
+// still fails.
+func (machine Machine[S, M]) shrink(steps []Step, err error) ([]Step, error) {
+	n, _ := machine.Replay(steps)
+	steps = steps[:n]
+	fails := func(candidate []Step) bool {
+		n, candidateErr := machine.Replay(candidate)
+		if candidateErr == nil {
+			return false
+		}
+		steps, err = candidate[:n], candidateErr
+		return true
+	}
+	for chunk := len(steps) / 2; chunk >= 1; chunk /= 2 {
+		for start := 0; start+chunk <= len(steps); {
+			candidate := append(append([]Step(nil), steps[:start]...), steps[start+chunk:]...)
+			if !fails(candidate) {
+				start += chunk
+			}
+		}
+	}
+	for i := 0; i < len(steps); i++ {
+		for i < len(steps) && steps[i].Arg > 0 {
+			candidate := append([]Step(nil), steps...)
+			candidate[i].Arg /= 2
+			if !fails(candidate) {
+				candidate[i].Arg = steps[i].Arg - 1
+				if !fails(candidate) {
+					break
+				}
+			}
+		}
+	}
+	return steps, err
+}
@@ -0,0 +1,179 @@
+package datatest_test
+
+import (
+	"fmt"
+	. "fun/pkg/datatest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// stack is a system under test that loses values pushed past its limit.
+type stack struct {
+	values []int
+	limit  int
+}
+
+// stackMachine checks a stack against a slice model.
+func stackMachine(limit int) Machine[*stack, *[]int] {
+	return Machine[*stack, *[]int]{
+		NewSystem: func() *stack { return &stack{limit: limit} },
+		NewModel:  func() *[]int { return &[]int{} },
+		Commands: []Command[*stack, *[]int]{
+			{"Push", func(system *stack, model *[]int, arg int) error {
+				if len(system.values) < system.limit {
+					system.values = append(system.values, arg)
+				}
+				*model = append(*model, arg)
+				return nil
+			}},
+			{"Pop", func(system *stack, model *[]int, arg int) error {
+				if len(*model) == 0 {
+					return nil
+				}
+				expected := (*model)[len(*model)-1]
+				*model = (*model)[:len(*model)-1]
+				if len(system.values) == 0 {
+					return fmt.Errorf("expected %d, got empty", expected)
+				}
+				value := system.values[len(system.values)-1]
+				system.values = system.values[:len(system.values)-1]
+				if value != expected {
+					return fmt.Errorf("expected %d, got %d", expected, value)
+				}
+				return nil
+			}},
+		},
+	}
+}
+
+func Test_Machine(t *testing.T) {
+	stackMachine(1000).Test(t, Config{})
+
+	failure := stackMachine(2).Run(Config{Length: 40})
+	if failure == nil {
+		t.Fatal("expected the lossy stack to fail")
+	}
+	if steps := strings.Join(failure.Steps, "; "); steps != "Push(0); Push(0); Push(1); Pop(0)" {
+		t.Error("expected a minimal sequence, got", steps)
+	}
+	if !strings.HasSuffix(failure.Error(), "expected 1, got 0") {
+		t.Error("unexpected failure", failure)
+	}
+}
+
+func Test_MachinePanics(t *testing.T) {
+	machine := Machine[*int, *int]{
+		NewSystem: func() *int { return new(int) },
+		NewModel:  func() *int { return new(int) },
+		Commands: []Command[*int, *int]{
+			{"Divide", func(system, model *int, arg int) error {
+				*system = 100 / arg
+				return nil
+			}},
+		},
+	}
+	failure := machine.Run(Config{})
+	if failure == nil || len(failure.Steps) != 1 || failure.Steps[0] != "Divide(0)" {
+		t.Error("expected panic to be shrunk to Divide(0), got", failure)
+	}
+}
+
+// tally is a system whose running total must match its values, and which
+// forgets to update the total for values over 5.
+type tally struct {
+	values []int
+	total  int
+}
+
+// CheckInvariants verifies the total.
+func (system *tally) CheckInvariants() error {
+	sum := 0
+	for _, value := range system.values {
+		sum += value
+	}
+	if sum != system.total {
+		return fmt.Errorf("total %d, expected %d", system.total, sum)
+	}
+	return nil
+}
+
+func Test_MachineInvariants(t *testing.T) {
+	machine := Machine[*tally, *int]{
+		NewSystem: func() *tally { return &tally{} },
+		NewModel:  func() *int { return new(int) },
+		Commands: []Command[*tally, *int]{
+			{"Add", func(system *tally, model *int, arg int) error {
+				system.values = append(system.values, arg)
+				if arg <= 5 {
+					system.total += arg
+				}
+				return nil
+			}},
+		},
+	}
+	failure := machine.Run(Config{})
+	if failure == nil || len(failure.Steps) != 1 || failure.Steps[0] != "Add(6)" {
+		t.Fatal("expected a broken invariant to be shrunk to Add(6), got", failure)
+	}
+	if !strings.Contains(failure.Error(), "invariant: total 0, expected 6") {
+		t.Error("unexpected failure", failure)
+	}
+}
+
+// register is a sequential model of a single value register.
+var register = Model[int, *int, int]{
+	Init: func() int { return 0 },
+	Step: func(state int, input *int) (int, int) {
+		if input != nil {
+			return *input, *input
+		}
+		return state, state
+	},
+	Equal: func(a, b int) bool { return a == b },
+}
+
+func Test_Linearizable(t *testing.T) {
+	one := 1
+	history := []Operation[*int, int]{
+		{Client: 0, Input: &one, Output: 1, Call: 1, Return: 4},
+		{Client: 1, Input: nil, Output: 1, Call: 2, Return: 3},
+	}
+	if !Linearizable(register, history) {
+		t.Error("expected overlapping write and read to linearize")
+	}
+	history[1] = Operation[*int, int]{Client: 1, Input: nil, Output: 1, Call: 0, Return: 1}
+	if Linearizable(register, history) {
+		t.Error("expected a read of a future write not to linearize")
+	}
+
+	var mux sync.Mutex
+	value := 0
+	recorder := NewRecorder[*int, int]()
+	var wg sync.WaitGroup
+	for client := 0; client < 4; client++ {
+		wg.Add(1)
+		go func(client int) {
+			defer wg.Done()
+			for i := 0; i < 5; i++ {
+				input := &client
+				if i%2 == 1 {
+					input = nil
+				}
+				recorder.Record(client, input, func(input *int) int {
+					mux.Lock()
+					defer mux.Unlock()
+					if input != nil {
+						value = *input
+					}
+					return value
+				})
+			}
+		}(client)
+	}
+	wg.Wait()
+	history = recorder.History()
+	if len(history) != 20 || !Linearizable(register, history) {
+		t.Error("expected a locked register to be linearizable")
+	}
+}
@@ -0,0 +1,95 @@
+package datatest
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Operation is one call recorded in a concurrent history.
+type Operation[In, Out any] struct {
+	Client int   // Goroutine that made the call.
+	Input  In    // Arguments of the call.
+	Output Out   // Result of the call.
+	Call   int64 // Logical time the call started.
+	Return int64 // Logical time the call returned.
+}
+
+// Recorder records a concurrent history of operations.
+type Recorder[In, Out any] struct {
+	clock      atomic.Int64         // Logical time of calls and returns.
+	operations []Operation[In, Out] // Completed operations.
+	mux        *sync.Mutex          // Lock the operations.
+}
+
+// Create a new recorder.
+func NewRecorder[In, Out any]() *Recorder[In, Out] {
+	return &Recorder[In, Out]{mux: &sync.Mutex{}}
+}
+
+// Record runs an operation of a client and records its call and return
+// times. It can be called from many goroutines.
+func (recorder *Recorder[In, Out]) Record(client int, input In, run func(In) Out) Out {
+	call := recorder.clock.Add(1)
+	output := run(input)
+	returned := recorder.clock.Add(1)
+	recorder.mux.Lock()
+	defer recorder.mux.Unlock()
+	recorder.operations = append(recorder.operations, Operation[In, Out]{client, input, output, call, returned})
+	return output
+}
+
+// History gets the recorded operations ordered by call time.
+func (recorder *Recorder[In, Out]) History() []Operation[In, Out] {
+	recorder.mux.Lock()
+	defer recorder.mux.Unlock()
+	history := append([]Operation[In, Out](nil), recorder.operations...)
+	sort.Slice(history, func(i, j int) bool { return history[i].Call < history[j].Call })
+	return history
+}
+
+// Model is a sequential specification of a structure. Step must not modify
+// state, but return the state after applying input, and the expected output.
+type Model[State, In, Out any] struct {
+	Init  func() State                             // Initial state.
+	Step  func(state State, input In) (State, Out) // Apply an operation.
+	Equal func(a, b Out) bool                      // Compare outputs.
+}
+
+// Linearizable reports whether a history could have been produced by
+// applying its operations one at a time, each at some instant between its
+// call and return, to the model. The search is exponential in the worst
+// case, so histories should be kept to a few hundred operations.
+func Linearizable[State, In, Out any](model Model[State, In, Out], history []Operation[In, Out]) bool {
+	done := make([]bool, len(history))
+	var search func(state State, remaining int) bool
+	search = func(state State, remaining int) bool {
+		if remaining == 0 {
+			return true
+		}
+		// Only operations called before every pending operation returned
+		// can be linearized next.
+		deadline := int64(-1)
+		for i, operation := range history {
+			if !done[i] && (deadline < 0 || operation.Return < deadline) {
+				deadline = operation.Return
+			}
+		}
+		for i, operation := range history {
+			if done[i] || operation.Call >= deadline {
+				continue
+			}
+			next, output := model.Step(state, operation.Input)
+			if !model.Equal(output, operation.Output) {
+				continue
+			}
+			done[i] = true
+			if search(next, remaining-1) {
+				return true
+			}
+			done[i] = false
+		}
+		return false
+	}
+	return search(model.Init(), len(history))
+}
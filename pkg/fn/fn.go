@@ -0,0 +1,167 @@
+// Package fn implements lazy operations over data.Iterator. Each operation
+// wraps its source without reading from it, so values are only computed as
+// the final iterator is consumed, and no intermediate slices are built:
+//
+//	squares := fn.Map(fn.Filter(list.Iterator(), isEven), square)
+//	total := fn.Reduce(fn.Take(squares, 10), 0, add)
+package fn
+
+import "fun/pkg/data"
+
+// iterator produces values from a next function.
+type iterator[T any] struct {
+	next  func() (T, bool) // Produces the next value, false when done.
+	value T                // Current value.
+	done  bool             // Whether next reported the end.
+}
+
+// fromNext creates an iterator that calls next until it reports false.
+func fromNext[T any](next func() (T, bool)) data.Iterator[T] {
+	return &iterator[T]{next: next}
+}
+
+func (it *iterator[T]) Next() bool {
+	var unset T
+	if it.done {
+		return false
+	}
+	value, ok := it.next()
+	if !ok {
+		it.done = true
+		it.value = unset
+		return false
+	}
+	it.value = value
+	return true
+}
+
+func (it *iterator[T]) Value() T {
+	return it.value
+}
+
+// Map converts each value with f.
+func Map[T, U any](it data.Iterator[T], f func(T) U) data.Iterator[U] {
+	return fromNext(func() (U, bool) {
+		var unset U
+		if !it.Next() {
+			return unset, false
+		}
+		return f(it.Value()), true
+	})
+}
+
+// Filter keeps the values for which keep returns true.
+func Filter[T any](it data.Iterator[T], keep func(T) bool) data.Iterator[T] {
+	return fromNext(func() (T, bool) {
+		for it.Next() {
+			if value := it.Value(); keep(value) {
+				return value, true
+			}
+		}
+		var unset T
+		return unset, false
+	})
+}
+
+// Take keeps the first n values, and stops reading from its source after
+// them.
+func Take[T any](it data.Iterator[T], n int) data.Iterator[T] {
+	return fromNext(func() (T, bool) {
+		var unset T
+		if n <= 0 || !it.Next() {
+			return unset, false
+		}
+		n--
+		return it.Value(), true
+	})
+}
+
+// Skip drops the first n values.
+func Skip[T any](it data.Iterator[T], n int) data.Iterator[T] {
+	return fromNext(func() (T, bool) {
+		var unset T
+		for ; n > 0; n-- {
+			if !it.Next() {
+				return unset, false
+			}
+		}
+		if !it.Next() {
+			return unset, false
+		}
+		return it.Value(), true
+	})
+}
+
+// Distinct keeps the first occurrence of each value. It remembers every
+// value it has returned.
+func Distinct[T comparable](it data.Iterator[T]) data.Iterator[T] {
+	seen := map[T]struct{}{}
+	return Filter(it, func(value T) bool {
+		if _, ok := seen[value]; ok {
+			return false
+		}
+		seen[value] = struct{}{}
+		return true
+	})
+}
+
+// FlatMap replaces each value with the values of the iterator f makes from
+// it.
+func FlatMap[T, U any](it data.Iterator[T], f func(T) data.Iterator[U]) data.Iterator[U] {
+	var inner data.Iterator[U]
+	return fromNext(func() (U, bool) {
+		for {
+			if inner != nil && inner.Next() {
+				return inner.Value(), true
+			}
+			if !it.Next() {
+				var unset U
+				return unset, false
+			}
+			inner = f(it.Value())
+		}
+	})
+}
+
+// Window groups values into slices of size values, starting a new window
+// every step values. A step equal to size makes adjacent chunks, and a step
+// of 1 makes sliding windows. A final window shorter than size is dropped.
+// Each window is a new slice. Window panics if size or step is not positive.
+func Window[T any](it data.Iterator[T], size int, step int) data.Iterator[[]T] {
+	if size <= 0 || step <= 0 {
+		panic("fn: window size and step must be positive")
+	}
+	var window []T
+	return fromNext(func() ([]T, bool) {
+		if len(window) > 0 {
+			if step < len(window) {
+				window = append([]T(nil), window[step:]...)
+			} else {
+				// Skip the values between windows.
+				for skip := step - len(window); skip > 0; skip-- {
+					if !it.Next() {
+						return nil, false
+					}
+				}
+				window = nil
+			}
+		}
+		for len(window) < size {
+			if !it.Next() {
+				return nil, false
+			}
+			window = append(window, it.Value())
+		}
+		return append([]T(nil), window...), true
+	})
+}
+
+// Reduce combines the values, in order, into an accumulator starting at
+// initial. It consumes the iterator.
+func Reduce[T, A any](it data.Iterator[T], initial A, f func(A, T) A) A {
+	accumulator := initial
+	for it.Next() {
+		accumulator = f(accumulator, it.Value())
+	}
+	return accumulator
+}
@@ -0,0 +1,119 @@
+package fn_test
+
+import (
+	"fun/pkg/data"
+	. "fun/pkg/fn"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func Test_MapFilter(t *testing.T) {
+	square := func(n int) int { return n * n }
+	even := func(n int) bool { return n%2 == 0 }
+	it := Map(Filter(data.FromSlice([]int{1, 2, 3, 4}), even), square)
+	if values := data.Collect(it); !reflect.DeepEqual(values, []int{4, 16}) {
+		t.Error("unexpected values", values)
+	}
+	if it.Next() {
+		t.Error("expected exhausted iterator")
+	}
+}
+
+func Test_Lazy(t *testing.T) {
+	calls := 0
+	it := Map(data.FromSlice([]int{1, 2, 3, 4}), func(n int) int {
+		calls++
+		return n
+	})
+	it = Take(it, 2)
+	if calls != 0 {
+		t.Error("expected no work before consumption, got", calls)
+	}
+	if values := data.Collect(it); !reflect.DeepEqual(values, []int{1, 2}) || calls != 2 {
+		t.Error("expected only taken values to be mapped, got", values, calls)
+	}
+}
+
+func Test_TakeSkip(t *testing.T) {
+	values := data.Collect(Take(Skip(data.FromSlice([]int{1, 2, 3, 4, 5}), 1), 3))
+	if !reflect.DeepEqual(values, []int{2, 3, 4}) {
+		t.Error("unexpected values", values)
+	}
+	if values := data.Collect(Skip(data.FromSlice([]int{1}), 3)); values != nil {
+		t.Error("expected no values, got", values)
+	}
+	if values := data.Collect(Take(data.FromSlice([]int{1}), 0)); values != nil {
+		t.Error("expected no values, got", values)
+	}
+}
+
+func Test_Distinct(t *testing.T) {
+	values := data.Collect(Distinct(data.FromSlice([]string{"a", "b", "a", "c", "b"})))
+	if !reflect.DeepEqual(values, []string{"a", "b", "c"}) {
+		t.Error("unexpected values", values)
+	}
+}
+
+func Test_FlatMap(t *testing.T) {
+	repeat := func(n int) data.Iterator[int] {
+		values := make([]int, n)
+		for i := range values {
+			values[i] = n
+		}
+		return data.FromSlice(values)
+	}
+	values := data.Collect(FlatMap(data.FromSlice([]int{1, 0, 2}), repeat))
+	if !reflect.DeepEqual(values, []int{1, 2, 2}) {
+		t.Error("unexpected values", values)
+	}
+}
+
+func Test_Window(t *testing.T) {
+	source := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		size, step int
+		expected   [][]int
+	}{
+		{2, 1, [][]int{{1, 2}, {2, 3}, {3, 4}, {4, 5}}},
+		{2, 2, [][]int{{1, 2}, {3, 4}}},
+		{2, 3, [][]int{{1, 2}, {4, 5}}},
+		{6, 1, nil},
+	}
+	for _, test := range tests {
+		windows := data.Collect(Window(data.FromSlice(source), test.size, test.step))
+		if !reflect.DeepEqual(windows, test.expected) {
+			t.Errorf("size %d step %d: expected %v, got %v", test.size, test.step, test.expected, windows)
+		}
+	}
+
+	windows := data.Collect(Window(data.FromSlice(source), 3, 1))
+	windows[0][0] = 9
+	if windows[1][0] != 2 {
+		t.Error("expected windows not to share storage")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for a zero window size")
+		}
+	}()
+	Window(data.FromSlice(source), 0, 1)
+}
+
+func Test_Reduce(t *testing.T) {
+	list := data.NewList[number]()
+	list.AppendAll(1, 2, 3)
+	total := Reduce(list.Iterator(), 0, func(total int, n number) int { return total + int(n) })
+	if total != 6 {
+		t.Error("expected 6, got", total)
+	}
+}
+
+// number is a list value.
+type number int
+
+// String converts a number to a string.
+func (n number) String() string {
+	return strconv.Itoa(int(n))
+}
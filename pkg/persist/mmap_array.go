@@ -0,0 +1,316 @@
+package persist
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"fun/pkg/data"
+	"iter"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// mmapMagic identifies memory-mapped array files.
+var mmapMagic = [4]byte{'F', 'U', 'N', 'A'}
+
+// mmapHeaderSize is the size of the array file header: magic, version,
+// element size, and length, padded so elements are aligned.
+const mmapHeaderSize = 64
+
+// mmapMinCapacity is the number of elements the file first grows to.
+const mmapMinCapacity = 64
+
+// MMapArray is an append-only array of fixed-size values stored in a
+// memory-mapped file, so it can be larger than memory and shared with other
+// processes mapping the same file. Values are stored in native byte order.
+// Only one process should append at a time.
+type MMapArray[T any] struct {
+	file     *os.File      // Backing file.
+	mapping  []byte        // Mapped file contents.
+	values   []T           // Elements of the mapping, length is the capacity.
+	elemSize int           // Bytes per element.
+	closed   bool          // Whether Close has been called.
+	mux      *sync.RWMutex // Lock read and write operations.
+}
+
+// OpenMMapArray opens or creates the array stored at path. T must have a
+// fixed size: numbers, booleans, and arrays or structs of them, but no
+// pointers, slices, maps, strings, or interfaces.
+func OpenMMapArray[T any](path string) (*MMapArray[T], error) {
+	var unset T
+	elemType := reflect.TypeOf(&unset).Elem()
+	if !fixedSize(elemType) || elemType.Size() == 0 {
+		return nil, fmt.Errorf("persist: %s is not a fixed-size type", elemType)
+	}
+	if elemType.Align() > mmapHeaderSize {
+		return nil, fmt.Errorf("persist: %s alignment is too large", elemType)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	array := &MMapArray[T]{file: file, elemSize: int(elemType.Size()), mux: &sync.RWMutex{}}
+	if err := array.open(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return array, nil
+}
+
+// open maps an existing file, or initializes an empty one.
+func (array *MMapArray[T]) open() error {
+	info, err := array.file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		header := make([]byte, mmapHeaderSize)
+		copy(header, mmapMagic[:])
+		binary.LittleEndian.PutUint16(header[4:], Version)
+		binary.LittleEndian.PutUint32(header[8:], uint32(array.elemSize))
+		if _, err := array.file.WriteAt(header, 0); err != nil {
+			return err
+		}
+		return array.remap(mmapMinCapacity)
+	}
+	header := make([]byte, mmapHeaderSize)
+	if _, err := array.file.ReadAt(header, 0); err != nil {
+		return fmt.Errorf("%w: short header", ErrCorrupt)
+	}
+	if [4]byte(header[:4]) != mmapMagic {
+		return fmt.Errorf("%w: bad magic", ErrCorrupt)
+	}
+	if version := binary.LittleEndian.Uint16(header[4:]); version > Version {
+		return fmt.Errorf("%w: %d", ErrVersion, version)
+	}
+	if size := binary.LittleEndian.Uint32(header[8:]); int(size) != array.elemSize {
+		return fmt.Errorf("persist: file holds %d-byte elements, not %d", size, array.elemSize)
+	}
+	// A file holding only the header has no room for elements yet, so grow it
+	// like a new one.
+	capacity := max(int((info.Size()-mmapHeaderSize)/int64(array.elemSize)), mmapMinCapacity)
+	if err := array.remap(capacity); err != nil {
+		return err
+	}
+	if array.length() > len(array.values) {
+		return fmt.Errorf("%w: length exceeds file size", ErrCorrupt)
+	}
+	return nil
+}
+
+// remap grows the file to hold at least capacity elements and maps all of it,
+// lock must be held for writing. The file is never shrunk, since another
+// mapping may have grown it further.
+func (array *MMapArray[T]) remap(capacity int) error {
+	info, err := array.file.Stat()
+	if err != nil {
+		return err
+	}
+	size := mmapHeaderSize + capacity*array.elemSize
+	if int64(size) > info.Size() {
+		if err := array.file.Truncate(int64(size)); err != nil {
+			return err
+		}
+	} else {
+		capacity = int((info.Size() - mmapHeaderSize) / int64(array.elemSize))
+		size = mmapHeaderSize + capacity*array.elemSize
+	}
+	if array.mapping != nil {
+		if err := munmap(array.mapping); err != nil {
+			return err
+		}
+		array.mapping, array.values = nil, nil
+	}
+	mapping, err := mmap(array.file, size)
+	if err != nil {
+		return err
+	}
+	array.mapping = mapping
+	array.values = unsafe.Slice((*T)(unsafe.Pointer(&mapping[mmapHeaderSize])), capacity)
+	return nil
+}
+
+// cover remaps the file if another mapping has appended past the end of this
+// one, so index i can be read, lock must be held for writing.
+func (array *MMapArray[T]) cover(i int) error {
+	if i < len(array.values) {
+		return nil
+	}
+	return array.remap(i + 1)
+}
+
+// lengthWord gets the length field of the mapped header.
+func (array *MMapArray[T]) lengthWord() *uint64 {
+	return (*uint64)(unsafe.Pointer(&array.mapping[16]))
+}
+
+// length reads the number of elements from the mapped header, so appends by
+// other processes are visible.
+func (array *MMapArray[T]) length() int {
+	return int(atomic.LoadUint64(array.lengthWord()))
+}
+
+// Length reports the number of elements.
+func (array *MMapArray[T]) Length() int {
+	array.mux.RLock()
+	defer array.mux.RUnlock()
+	if array.closed {
+		return 0
+	}
+	return array.length()
+}
+
+// Append adds values at the end of the array, growing the file as needed.
+func (array *MMapArray[T]) Append(values ...T) error {
+	array.mux.Lock()
+	defer array.mux.Unlock()
+	if array.closed {
+		return data.ErrClosed
+	}
+	length := array.length()
+	if need := length + len(values); need > len(array.values) {
+		capacity := 2 * len(array.values)
+		if capacity < need {
+			capacity = need
+		}
+		if err := array.remap(capacity); err != nil {
+			return err
+		}
+	}
+	copy(array.values[length:], values)
+	// Publish the length after the values, so readers never see unwritten
+	// elements.
+	atomic.StoreUint64(array.lengthWord(), uint64(length+len(values)))
+	return nil
+}
+
+// At gets the value at an index.
+func (array *MMapArray[T]) At(i int) (T, bool) {
+	var unset T
+	array.mux.RLock()
+	defer array.mux.RUnlock()
+	if array.closed || i < 0 || i >= array.length() {
+		return unset, false
+	}
+	if i >= len(array.values) {
+		array.mux.RUnlock()
+		array.mux.Lock()
+		err := error(data.ErrClosed)
+		if !array.closed {
+			err = array.cover(i)
+		}
+		array.mux.Unlock()
+		array.mux.RLock()
+		if err != nil || array.closed || i >= len(array.values) {
+			return unset, false
+		}
+	}
+	return array.values[i], true
+}
+
+// Set replaces the value at an index.
+func (array *MMapArray[T]) Set(i int, value T) error {
+	array.mux.Lock()
+	defer array.mux.Unlock()
+	if array.closed {
+		return data.ErrClosed
+	}
+	if length := array.length(); i < 0 || i >= length {
+		return data.RangeError{Index: i, Length: length}
+	}
+	if err := array.cover(i); err != nil {
+		return err
+	}
+	array.values[i] = value
+	return nil
+}
+
+// Iterator creates an iterator over the values, including values appended
+// during iteration.
+func (array *MMapArray[T]) Iterator() data.Iterator[T] {
+	return &mmapIterator[T]{array: array, index: -1}
+}
+
+// All gets a sequence of the values, with the consistency of Iterator.
+func (array *MMapArray[T]) All() iter.Seq[T] {
+	return data.Seq(array.Iterator())
+}
+
+// Sync flushes the mapped file to disk.
+func (array *MMapArray[T]) Sync() error {
+	array.mux.RLock()
+	defer array.mux.RUnlock()
+	if array.closed {
+		return data.ErrClosed
+	}
+	return msync(array.mapping)
+}
+
+// Close syncs and unmaps the file. The array must not be used afterwards.
+func (array *MMapArray[T]) Close() error {
+	array.mux.Lock()
+	defer array.mux.Unlock()
+	if array.closed {
+		return nil
+	}
+	array.closed = true
+	err := msync(array.mapping)
+	if unmapErr := munmap(array.mapping); err == nil {
+		err = unmapErr
+	}
+	array.mapping, array.values = nil, nil
+	if closeErr := array.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// mmapIterator steps through the indexes of an array.
+type mmapIterator[T any] struct {
+	array *MMapArray[T] // Array being iterated.
+	index int           // Index of the current value.
+	value T             // Current value.
+}
+
+func (it *mmapIterator[T]) Next() bool {
+	value, ok := it.array.At(it.index + 1)
+	if !ok {
+		var unset T
+		it.value = unset
+		return false
+	}
+	it.index++
+	it.value = value
+	return true
+}
+
+func (it *mmapIterator[T]) Value() T {
+	return it.value
+}
+
+// fixedSize reports whether values of a type hold no references, so they can
+// be stored as raw bytes.
+func fixedSize(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return fixedSize(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !fixedSize(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// errMMapUnsupported is returned on platforms without memory mapping.
+var errMMapUnsupported = errors.New("persist: memory mapping is not supported on this platform")
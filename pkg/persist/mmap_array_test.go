@@ -0,0 +1,182 @@
+package persist_test
+
+import (
+	"errors"
+	"fun/pkg/data"
+	. "fun/pkg/persist"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+// point is a fixed-size array value.
+type point struct {
+	X, Y int32
+	Z    float64
+}
+
+func Test_MMapArray(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("memory mapping is not supported on", runtime.GOOS)
+	}
+	path := filepath.Join(t.TempDir(), "points.mmap")
+	array, err := OpenMMapArray[point](path)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	var expected []point
+	for i := 0; i < 1000; i++ {
+		value := point{int32(i), int32(-i), float64(i) / 2}
+		expected = append(expected, value)
+		if err := array.Append(value); err != nil {
+			t.Fatal("unexpected error", err)
+		}
+	}
+	if array.Length() != 1000 {
+		t.Error("expected 1000 values, got", array.Length())
+	}
+	if value, ok := array.At(999); !ok || value != expected[999] {
+		t.Error("unexpected last value", value, ok)
+	}
+	if _, ok := array.At(1000); ok {
+		t.Error("expected At past the end to fail")
+	}
+	if err := array.Set(1000, point{}); !errors.Is(err, data.ErrOutOfRange) {
+		t.Error("expected out of range error, got", err)
+	}
+	expected[10] = point{Z: 1}
+	if err := array.Set(10, expected[10]); err != nil {
+		t.Error("unexpected error", err)
+	}
+
+	// A second mapping of the file sees the same values.
+	shared, err := OpenMMapArray[point](path)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if value, _ := shared.At(10); value != expected[10] {
+		t.Error("expected shared mapping to see Set, got", value)
+	}
+	if err := shared.Close(); err != nil {
+		t.Error("unexpected error", err)
+	}
+
+	if err := array.Close(); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if err := array.Append(point{}); !errors.Is(err, data.ErrClosed) {
+		t.Error("expected closed error, got", err)
+	}
+
+	array, err = OpenMMapArray[point](path)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	defer array.Close()
+	if values := slices.Collect(array.All()); !slices.Equal(values, expected) {
+		t.Error("expected values to survive reopening")
+	}
+}
+
+func Test_MMapArrayErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := OpenMMapArray[string](filepath.Join(dir, "strings.mmap")); err == nil {
+		t.Error("expected an error for a type with pointers")
+	}
+	if _, err := OpenMMapArray[struct{ Values []int }](filepath.Join(dir, "slices.mmap")); err == nil {
+		t.Error("expected an error for a struct with a slice")
+	}
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		return
+	}
+
+	path := filepath.Join(dir, "ints.mmap")
+	array, err := OpenMMapArray[int64](path)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	array.Append(1, 2, 3)
+	array.Close()
+	if _, err := OpenMMapArray[int32](path); err == nil {
+		t.Error("expected an error for a different element size")
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.mmap")
+	os.WriteFile(corrupt, []byte("not an array file"), 0o644)
+	if _, err := OpenMMapArray[int64](corrupt); !errors.Is(err, ErrCorrupt) {
+		t.Error("expected corrupt error, got", err)
+	}
+}
+
+func Test_MMapArrayGrownElsewhere(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("memory mapping is not supported on", runtime.GOOS)
+	}
+	path := filepath.Join(t.TempDir(), "ints.mmap")
+	writer, err := OpenMMapArray[int64](path)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	defer writer.Close()
+	reader, err := OpenMMapArray[int64](path)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	defer reader.Close()
+
+	// The writer grows the file past the capacity the reader mapped.
+	for i := int64(0); i < 200; i++ {
+		writer.Append(i)
+	}
+	if value, ok := reader.At(150); !ok || value != 150 {
+		t.Error("expected reader to see value 150, got", value, ok)
+	}
+	if err := reader.Set(199, -1); err != nil {
+		t.Error("unexpected error", err)
+	}
+	if value, _ := writer.At(199); value != -1 {
+		t.Error("expected writer to see Set, got", value)
+	}
+	if count := len(slices.Collect(reader.All())); count != 200 {
+		t.Error("expected 200 values, got", count)
+	}
+
+	// Appending through the reader keeps what the writer added.
+	if err := reader.Append(200); err != nil {
+		t.Error("unexpected error", err)
+	}
+	if value, ok := writer.At(200); !ok || value != 200 {
+		t.Error("expected writer to see value 200, got", value, ok)
+	}
+}
+
+func Test_MMapArrayHeaderOnly(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("memory mapping is not supported on", runtime.GOOS)
+	}
+	path := filepath.Join(t.TempDir(), "ints.mmap")
+	array, err := OpenMMapArray[int64](path)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	array.Close()
+	if err := os.Truncate(path, 64); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	array, err = OpenMMapArray[int64](path)
+	if err != nil {
+		t.Fatal("expected header-only file to open, got", err)
+	}
+	defer array.Close()
+	if array.Length() != 0 {
+		t.Error("expected no values, got", array.Length())
+	}
+	if err := array.Append(1); err != nil {
+		t.Error("unexpected error", err)
+	}
+	if value, ok := array.At(0); !ok || value != 1 {
+		t.Error("unexpected value", value, ok)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !(linux || darwin)
+
+package persist
+
+import "os"
+
+func mmap(file *os.File, size int) ([]byte, error) {
+	return nil, errMMapUnsupported
+}
+
+func munmap(mapping []byte) error {
+	return errMMapUnsupported
+}
+
+func msync(mapping []byte) error {
+	return errMMapUnsupported
+}
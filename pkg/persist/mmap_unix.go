@@ -0,0 +1,29 @@
+//go:build linux || darwin
+
+package persist
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// mmap maps size bytes of a file for reading and writing, shared with other
+// processes mapping it.
+func mmap(file *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(file.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+}
+
+// munmap releases a mapping.
+func munmap(mapping []byte) error {
+	return syscall.Munmap(mapping)
+}
+
+// msync writes a mapping back to its file and waits for the write.
+func msync(mapping []byte) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_MSYNC, uintptr(unsafe.Pointer(&mapping[0])), uintptr(len(mapping)), syscall.MS_SYNC)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+// Package persist checkpoints structures to files so they survive restarts.
+// A file holds a versioned header, the length and checksum of the payload,
+// and the payload written by the structure. Files are replaced atomically by
+// writing a temporary file and renaming it over the old one, so a crash
+// leaves either the old or the new checkpoint, never a partial one.
+//
+// WALQueue is a durable queue that logs every change instead of
+// checkpointing.
+package persist
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"fun/pkg/codec"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Version is the file format written by Save.
+const Version = 1
+
+// magic identifies checkpoint files.
+var magic = [4]byte{'F', 'U', 'N', 'P'}
+
+// castagnoli is the CRC-32C table used for payload checksums.
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// headerSize is the size of the file header: magic, version, flags, payload
+// length, and payload checksum.
+const headerSize = 4 + 2 + 2 + 8 + 4
+
+// ErrCorrupt is returned by Load when a file is not a checkpoint, is
+// truncated, or fails its checksum.
+var ErrCorrupt = errors.New("persist: corrupt checkpoint")
+
+// ErrVersion is returned by Load when a file was written by a newer format.
+var ErrVersion = errors.New("persist: unsupported checkpoint version")
+
+// Structure is a structure that can be checkpointed.
+type Structure interface {
+	Encode(w io.Writer) error // Write the contents of the structure.
+	Decode(r io.Reader) error // Replace the contents of the structure.
+}
+
+// Container is a structure encoded value by value with a codec, such as a
+// data.List.
+type Container[T any] interface {
+	EncodeTo(w io.Writer, c codec.Codec[T]) error
+	DecodeFrom(r io.Reader, c codec.Codec[T]) error
+}
+
+// containerStructure adapts a Container to a Structure.
+type containerStructure[T any] struct {
+	container Container[T]   // Container to checkpoint.
+	codec     codec.Codec[T] // Encodes each value.
+}
+
+// WithCodec adapts a container to a Structure that encodes its values with c.
+func WithCodec[T any](container Container[T], c codec.Codec[T]) Structure {
+	return containerStructure[T]{container, c}
+}
+
+func (structure containerStructure[T]) Encode(w io.Writer) error {
+	return structure.container.EncodeTo(w, structure.codec)
+}
+
+func (structure containerStructure[T]) Decode(r io.Reader) error {
+	return structure.container.DecodeFrom(r, structure.codec)
+}
+
+// Save atomically replaces the file at path with a checkpoint of structure.
+func Save(path string, structure Structure) (err error) {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(file.Name())
+		}
+	}()
+
+	if _, err := file.Write(make([]byte, headerSize)); err != nil {
+		return err
+	}
+	payload := &checksumWriter{writer: file, hash: crc32.New(castagnoli)}
+	if err := structure.Encode(payload); err != nil {
+		return err
+	}
+	header := make([]byte, headerSize)
+	copy(header, magic[:])
+	binary.BigEndian.PutUint16(header[4:], Version)
+	binary.BigEndian.PutUint64(header[8:], uint64(payload.size))
+	binary.BigEndian.PutUint32(header[16:], payload.hash.Sum32())
+	if _, err := file.WriteAt(header, 0); err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return err
+	}
+	syncDir(filepath.Dir(path))
+	return nil
+}
+
+// Load replaces the contents of structure with the checkpoint at path. The
+// checksum is verified before the structure is touched.
+func Load(path string, structure Structure) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return fmt.Errorf("%w: short header", ErrCorrupt)
+	}
+	if [4]byte(header[:4]) != magic {
+		return fmt.Errorf("%w: bad magic", ErrCorrupt)
+	}
+	version := binary.BigEndian.Uint16(header[4:])
+	if version == 0 {
+		return fmt.Errorf("%w: bad version", ErrCorrupt)
+	}
+	if version > Version {
+		return fmt.Errorf("%w: %d", ErrVersion, version)
+	}
+	size := int64(binary.BigEndian.Uint64(header[8:]))
+	checksum := binary.BigEndian.Uint32(header[16:])
+
+	// Verify the whole payload first, so a corrupt file never reaches the
+	// structure.
+	hash := crc32.New(castagnoli)
+	if n, err := io.Copy(hash, io.NewSectionReader(file, headerSize, size)); err != nil || n != size {
+		return fmt.Errorf("%w: truncated payload", ErrCorrupt)
+	}
+	if hash.Sum32() != checksum {
+		return fmt.Errorf("%w: checksum mismatch", ErrCorrupt)
+	}
+	if info, err := file.Stat(); err == nil && info.Size() != headerSize+size {
+		return fmt.Errorf("%w: trailing data", ErrCorrupt)
+	}
+	return structure.Decode(io.NewSectionReader(file, headerSize, size))
+}
+
+// checksumWriter counts and hashes what is written through it.
+type checksumWriter struct {
+	writer io.Writer   // Destination.
+	hash   hash.Hash32 // Checksum of the bytes written.
+	size   int64       // Number of bytes written.
+}
+
+func (w *checksumWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.hash.Write(p[:n])
+	w.size += int64(n)
+	return n, err
+}
+
+// syncDir flushes a directory so a rename in it is durable. Errors are
+// ignored because not every platform can sync directories.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
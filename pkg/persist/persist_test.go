@@ -0,0 +1,99 @@
+package persist_test
+
+import (
+	"errors"
+	"fun/pkg/codec"
+	"fun/pkg/data"
+	. "fun/pkg/persist"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// item is a list value.
+type item int
+
+// String converts an item to a string.
+func (i item) String() string {
+	return strconv.Itoa(int(i))
+}
+
+// failing is a structure whose encoding fails partway.
+type failing struct{}
+
+func (failing) Encode(w io.Writer) error {
+	w.Write([]byte("partial"))
+	return errors.New("encode failed")
+}
+
+func (failing) Decode(r io.Reader) error {
+	return nil
+}
+
+func Test_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "list.ckpt")
+	list := data.NewList[item]()
+	list.AppendAll(1, 2, 3)
+	if err := Save(path, WithCodec[item](list, codec.JSON[item]())); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	loaded := data.NewList[item]()
+	if err := Load(path, WithCodec[item](loaded, codec.JSON[item]())); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if loaded.String() != list.String() {
+		t.Errorf("expected %s, got %s", list, loaded)
+	}
+
+	if err := Save(path, failing{}); err == nil {
+		t.Error("expected encode error")
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Error("expected failed save to leave only the old checkpoint, got", len(entries), "files")
+	}
+	if err := Load(path, WithCodec[item](loaded, codec.JSON[item]())); err != nil {
+		t.Error("expected old checkpoint to survive a failed save, got", err)
+	}
+}
+
+func Test_LoadCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "list.ckpt")
+	list := data.NewList[item]()
+	list.AppendAll(1, 2, 3)
+	Save(path, WithCodec[item](list, codec.MsgPack[item]()))
+	original, _ := os.ReadFile(path)
+
+	tests := map[string]struct {
+		change   func([]byte) []byte
+		expected error
+	}{
+		"flipped payload": {func(b []byte) []byte { b[len(b)-1] ^= 1; return b }, ErrCorrupt},
+		"truncated":       {func(b []byte) []byte { return b[:len(b)-1] }, ErrCorrupt},
+		"trailing":        {func(b []byte) []byte { return append(b, 0) }, ErrCorrupt},
+		"bad magic":       {func(b []byte) []byte { b[0] = 'X'; return b }, ErrCorrupt},
+		"short header":    {func(b []byte) []byte { return b[:3] }, ErrCorrupt},
+		"newer version":   {func(b []byte) []byte { b[5] = 9; return b }, ErrVersion},
+	}
+	for name, test := range tests {
+		corrupt := filepath.Join(dir, name)
+		os.WriteFile(corrupt, test.change(append([]byte(nil), original...)), 0o600)
+		loaded := data.NewList[item]()
+		loaded.Append(7)
+		err := Load(corrupt, WithCodec[item](loaded, codec.MsgPack[item]()))
+		if !errors.Is(err, test.expected) {
+			t.Errorf("%s: expected %v, got %v", name, test.expected, err)
+		}
+		if loaded.Length() != 1 {
+			t.Errorf("%s: expected structure to be untouched", name)
+		}
+	}
+
+	if err := Load(filepath.Join(dir, "missing"), failing{}); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected missing file error, got", err)
+	}
+}
@@ -0,0 +1,362 @@
+package persist
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"fun/pkg/codec"
+	"fun/pkg/data"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultSegmentSize is the size at which a WALQueue starts a new segment
+// when WALOptions.SegmentSize is 0.
+const DefaultSegmentSize = 4 << 20
+
+// Record kinds of the write-ahead log.
+const (
+	walPut byte = iota + 1 // An enqueued value.
+	walAck                 // A consumed value.
+)
+
+// walSuffix is the file name suffix of log segments.
+const walSuffix = ".wal"
+
+// WALOptions configures a WALQueue.
+type WALOptions struct {
+	SegmentSize int64 // Bytes after which a new segment is started, 0 for DefaultSegmentSize.
+	NoSync      bool  // Skip syncing to disk before acknowledging, trading durability for speed.
+}
+
+// WALEntry is a value taken from a WALQueue. Its Seq acknowledges it.
+type WALEntry[T any] struct {
+	Seq   uint64 // Sequence number of the value in the log.
+	Value T      // Enqueued value.
+}
+
+// walSegment is one file of the log.
+type walSegment struct {
+	first   uint64 // Sequence number the segment was started at.
+	path    string // Location of the segment.
+	pending int    // Values put in the segment and not acknowledged.
+}
+
+// WALQueue is a durable FIFO queue. Put returns once the value is appended
+// to a write-ahead log, and values taken but not acknowledged with Ack are
+// delivered again when the queue is reopened. Segments whose values are all
+// acknowledged are deleted.
+type WALQueue[T any] struct {
+	dir      string                           // Directory of the segments.
+	codec    codec.Codec[T]                   // Encodes values.
+	options  WALOptions                       // Queue configuration.
+	queue    *data.BlockingQueue[WALEntry[T]] // Values ready to be taken.
+	segments []*walSegment                    // Live segments, oldest first.
+	unacked  map[uint64]*walSegment           // Segment of each unacknowledged value.
+	file     *os.File                         // Current segment, opened for append.
+	size     int64                            // Bytes in the current segment.
+	next     uint64                           // Sequence number of the next value.
+	closed   bool                             // Whether Close has been called.
+	mux      *sync.Mutex                      // Lock the log.
+}
+
+// OpenWALQueue opens the queue logged in dir, creating dir if needed, and
+// replays the values that were not acknowledged. A torn record at the end
+// of the log, left by a crash during a write, is discarded.
+func OpenWALQueue[T any](dir string, c codec.Codec[T], options WALOptions) (*WALQueue[T], error) {
+	if options.SegmentSize <= 0 {
+		options.SegmentSize = DefaultSegmentSize
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	queue := &WALQueue[T]{
+		dir:     dir,
+		codec:   c,
+		options: options,
+		queue:   data.NewBlockingQueue[WALEntry[T]](0),
+		unacked: map[uint64]*walSegment{},
+		next:    1,
+		mux:     &sync.Mutex{},
+	}
+	if err := queue.replay(); err != nil {
+		return nil, err
+	}
+	if err := queue.compact(); err != nil {
+		queue.file.Close()
+		return nil, err
+	}
+	return queue, nil
+}
+
+// Length reports the number of values waiting to be taken.
+func (queue *WALQueue[T]) Length() int {
+	return queue.queue.Length()
+}
+
+// Pending reports the number of values put and not acknowledged.
+func (queue *WALQueue[T]) Pending() int {
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	return len(queue.unacked)
+}
+
+// Put appends a value to the log and enqueues it. The value is durable once
+// Put returns, unless WALOptions.NoSync is set.
+func (queue *WALQueue[T]) Put(value T) error {
+	payload, err := queue.codec.Encode(value)
+	if err != nil {
+		return err
+	}
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	if queue.closed {
+		return data.ErrClosed
+	}
+	if queue.size >= queue.options.SegmentSize {
+		if err := queue.rotate(); err != nil {
+			return err
+		}
+	}
+	seq := queue.next
+	if err := queue.write(walPut, seq, payload); err != nil {
+		return err
+	}
+	queue.next++
+	segment := queue.segments[len(queue.segments)-1]
+	segment.pending++
+	queue.unacked[seq] = segment
+	queue.queue.TryPut(WALEntry[T]{seq, value})
+	return nil
+}
+
+// Take removes the oldest value, blocking until one is put, ctx is done, or
+// the queue is closed. The value is delivered again after a restart unless
+// it is acknowledged.
+func (queue *WALQueue[T]) Take(ctx context.Context) (WALEntry[T], error) {
+	return queue.queue.Take(ctx)
+}
+
+// Ack records that a taken value has been consumed, deleting segments that
+// no longer hold unacknowledged values.
+func (queue *WALQueue[T]) Ack(seq uint64) error {
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	if queue.closed {
+		return data.ErrClosed
+	}
+	segment, ok := queue.unacked[seq]
+	if !ok {
+		return fmt.Errorf("%w: sequence %d", data.ErrNotFound, seq)
+	}
+	if err := queue.write(walAck, seq, nil); err != nil {
+		return err
+	}
+	delete(queue.unacked, seq)
+	segment.pending--
+	return queue.compact()
+}
+
+// Close closes the log. Values not taken stay in the log for the next open.
+func (queue *WALQueue[T]) Close() error {
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+	if queue.closed {
+		return nil
+	}
+	queue.closed = true
+	queue.queue.Close()
+	return queue.file.Close()
+}
+
+// write appends a record to the current segment, lock must be held. A record
+// is a length, a body of kind, sequence number, and payload, and a checksum
+// of the body.
+func (queue *WALQueue[T]) write(kind byte, seq uint64, payload []byte) error {
+	body := append([]byte{kind}, binary.AppendUvarint(nil, seq)...)
+	body = append(body, payload...)
+	record := binary.AppendUvarint(nil, uint64(len(body)))
+	record = append(record, body...)
+	record = binary.BigEndian.AppendUint32(record, crc32.Checksum(body, castagnoli))
+	if _, err := queue.file.Write(record); err != nil {
+		return err
+	}
+	queue.size += int64(len(record))
+	if !queue.options.NoSync {
+		return queue.file.Sync()
+	}
+	return nil
+}
+
+// rotate starts a new segment at the next sequence number, lock must be held.
+func (queue *WALQueue[T]) rotate() error {
+	path := filepath.Join(queue.dir, fmt.Sprintf("%020d%s", queue.next, walSuffix))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if queue.file != nil {
+		queue.file.Close()
+	}
+	queue.file, queue.size = file, 0
+	queue.segments = append(queue.segments, &walSegment{first: queue.next, path: path})
+	syncDir(queue.dir)
+	return nil
+}
+
+// compact deletes the oldest segments while every value put in them is
+// acknowledged, lock must be held. The current segment is kept. Deleting
+// only from the oldest end keeps every surviving acknowledgement after the
+// put it refers to.
+func (queue *WALQueue[T]) compact() error {
+	for len(queue.segments) > 1 && queue.segments[0].pending == 0 {
+		if err := os.Remove(queue.segments[0].path); err != nil {
+			return err
+		}
+		queue.segments = queue.segments[1:]
+	}
+	return nil
+}
+
+// replay reads the segments in order, rebuilding the unacknowledged values,
+// and opens the last segment for append.
+func (queue *WALQueue[T]) replay() error {
+	entries, err := os.ReadDir(queue.dir)
+	if err != nil {
+		return err
+	}
+	var firsts []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, walSuffix) {
+			continue
+		}
+		first, err := strconv.ParseUint(strings.TrimSuffix(name, walSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		firsts = append(firsts, first)
+	}
+	sort.Slice(firsts, func(i, j int) bool { return firsts[i] < firsts[j] })
+
+	values := map[uint64]T{}
+	for i, first := range firsts {
+		segment := &walSegment{first: first, path: filepath.Join(queue.dir, fmt.Sprintf("%020d%s", first, walSuffix))}
+		queue.segments = append(queue.segments, segment)
+		last := i == len(firsts)-1
+		if err := queue.replaySegment(segment, values, last); err != nil {
+			return err
+		}
+	}
+
+	seqs := make([]uint64, 0, len(values))
+	for seq := range values {
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	for _, seq := range seqs {
+		queue.queue.TryPut(WALEntry[T]{seq, values[seq]})
+	}
+
+	if len(queue.segments) == 0 {
+		return queue.rotate()
+	}
+	current := queue.segments[len(queue.segments)-1]
+	file, err := os.OpenFile(current.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	queue.file, queue.size = file, info.Size()
+	return nil
+}
+
+// replaySegment applies the records of a segment. A torn or corrupt record
+// ends the log if it is in the last segment, and the segment is truncated
+// before it; elsewhere it is an error.
+func (queue *WALQueue[T]) replaySegment(segment *walSegment, values map[uint64]T, last bool) error {
+	file, err := os.Open(segment.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	var offset int64
+	for {
+		body, size, err := readWALRecord(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if !last {
+				return fmt.Errorf("%w: %s at offset %d: %v", ErrCorrupt, segment.path, offset, err)
+			}
+			return os.Truncate(segment.path, offset)
+		}
+		offset += size
+
+		kind := body[0]
+		seq, n := binary.Uvarint(body[1:])
+		if n <= 0 {
+			return fmt.Errorf("%w: %s at offset %d: bad sequence number", ErrCorrupt, segment.path, offset)
+		}
+		switch kind {
+		case walPut:
+			value, err := queue.codec.Decode(body[1+n:])
+			if err != nil {
+				return fmt.Errorf("%w: %s at offset %d: %v", ErrCorrupt, segment.path, offset, err)
+			}
+			values[seq] = value
+			queue.unacked[seq] = segment
+			segment.pending++
+			if seq >= queue.next {
+				queue.next = seq + 1
+			}
+		case walAck:
+			if owner, ok := queue.unacked[seq]; ok {
+				owner.pending--
+				delete(queue.unacked, seq)
+				delete(values, seq)
+			}
+		default:
+			return fmt.Errorf("%w: %s at offset %d: unknown record kind %d", ErrCorrupt, segment.path, offset, kind)
+		}
+	}
+}
+
+// readWALRecord reads a record and verifies its checksum, returning its body
+// and size on disk.
+func readWALRecord(reader *bufio.Reader) ([]byte, int64, error) {
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		if err == io.EOF {
+			return nil, 0, io.EOF
+		}
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	if length == 0 || length > codec.MaxRecordSize {
+		return nil, 0, errors.New("bad record length")
+	}
+	record := make([]byte, length+4)
+	if _, err := io.ReadFull(reader, record); err != nil {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	body := record[:length]
+	if binary.BigEndian.Uint32(record[length:]) != crc32.Checksum(body, castagnoli) {
+		return nil, 0, errors.New("checksum mismatch")
+	}
+	size := int64(len(binary.AppendUvarint(nil, length))) + int64(len(record))
+	return body, size, nil
+}
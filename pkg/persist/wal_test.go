@@ -0,0 +1,132 @@
+package persist_test
+
+import (
+	"context"
+	"errors"
+	"fun/pkg/codec"
+	"fun/pkg/data"
+	. "fun/pkg/persist"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// walFiles counts the segments in a directory.
+func walFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.wal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// takeAll takes n values from a queue.
+func takeAll(t *testing.T, queue *WALQueue[string], n int) []WALEntry[string] {
+	t.Helper()
+	entries := make([]WALEntry[string], n)
+	for i := range entries {
+		entry, err := queue.Take(context.Background())
+		if err != nil {
+			t.Fatal("unexpected error", err)
+		}
+		entries[i] = entry
+	}
+	return entries
+}
+
+func Test_WALQueue(t *testing.T) {
+	dir := t.TempDir()
+	queue, err := OpenWALQueue(dir, codec.JSON[string](), WALOptions{SegmentSize: 64})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	for _, value := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		if err := queue.Put(value); err != nil {
+			t.Fatal("unexpected error", err)
+		}
+	}
+	if len(walFiles(t, dir)) < 2 {
+		t.Fatal("expected puts to span several segments")
+	}
+	entries := takeAll(t, queue, 3)
+	if entries[0].Value != "a" || entries[2].Value != "c" {
+		t.Error("unexpected entries", entries)
+	}
+	queue.Ack(entries[0].Seq)
+	queue.Ack(entries[2].Seq)
+	if err := queue.Ack(entries[2].Seq); !errors.Is(err, data.ErrNotFound) {
+		t.Error("expected acknowledging twice to fail, got", err)
+	}
+	if queue.Pending() != 6 || queue.Length() != 5 {
+		t.Error("unexpected counts", queue.Pending(), queue.Length())
+	}
+	queue.Close()
+	if err := queue.Put("i"); !errors.Is(err, data.ErrClosed) {
+		t.Error("expected put after close to fail, got", err)
+	}
+
+	// b was taken but not acknowledged, so it is delivered again.
+	queue, err = OpenWALQueue(dir, codec.JSON[string](), WALOptions{SegmentSize: 64})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	entries = takeAll(t, queue, 6)
+	if entries[0].Value != "b" || entries[1].Value != "d" || entries[5].Value != "h" {
+		t.Error("unexpected replay", entries)
+	}
+	queue.Put("i")
+	for _, entry := range entries {
+		queue.Ack(entry.Seq)
+	}
+	if files := walFiles(t, dir); len(files) != 1 {
+		t.Error("expected acknowledged segments to be deleted, got", files)
+	}
+	entry := takeAll(t, queue, 1)[0]
+	if entry.Value != "i" || entry.Seq != 9 {
+		t.Error("expected sequence numbers to continue after replay, got", entry)
+	}
+	queue.Close()
+}
+
+func Test_WALQueueTornWrite(t *testing.T) {
+	dir := t.TempDir()
+	queue, _ := OpenWALQueue(dir, codec.JSON[string](), WALOptions{NoSync: true})
+	queue.Put("a")
+	queue.Put("b")
+	queue.Close()
+
+	files := walFiles(t, dir)
+	contents, _ := os.ReadFile(files[0])
+	os.WriteFile(files[0], contents[:len(contents)-2], 0o644)
+
+	queue, err := OpenWALQueue(dir, codec.JSON[string](), WALOptions{NoSync: true})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if queue.Length() != 1 {
+		t.Fatal("expected the torn record to be dropped, got", queue.Length())
+	}
+	queue.Put("c")
+	entries := takeAll(t, queue, 2)
+	if entries[0].Value != "a" || entries[1].Value != "c" {
+		t.Error("unexpected entries", entries)
+	}
+	queue.Close()
+}
+
+func Test_WALQueueCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	queue, _ := OpenWALQueue(dir, codec.JSON[string](), WALOptions{SegmentSize: 1, NoSync: true})
+	queue.Put("a")
+	queue.Put("b")
+	queue.Close()
+
+	files := walFiles(t, dir)
+	contents, _ := os.ReadFile(files[0])
+	contents[len(contents)-1] ^= 1
+	os.WriteFile(files[0], contents, 0o644)
+	if _, err := OpenWALQueue(dir, codec.JSON[string](), WALOptions{}); !errors.Is(err, ErrCorrupt) {
+		t.Error("expected a corrupt segment before the last to fail, got", err)
+	}
+}
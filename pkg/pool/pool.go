@@ -0,0 +1,162 @@
+// Package pool implements a worker pool built on the queues in package data.
+package pool
+
+import (
+	"context"
+	"fmt"
+	"fun/pkg/data"
+	"runtime/debug"
+	"sync"
+)
+
+// Handler processes a job. The context is canceled if the pool is shut down
+// before running jobs complete.
+type Handler[T, R any] func(ctx context.Context, job T) (R, error)
+
+// Result of processing a job.
+type Result[T, R any] struct {
+	Job   T     // Job that was processed.
+	Value R     // Value returned by the handler.
+	Err   error // Error returned by the handler, or a *PanicError.
+}
+
+// PanicError is the error of a job whose handler panicked.
+type PanicError = data.PanicError
+
+// WorkerPool runs a resizable set of workers that take jobs from a blocking
+// queue and collect their results.
+type WorkerPool[T, R any] struct {
+	handler Handler[T, R]                     // Processes each job.
+	jobs    *data.BlockingQueue[T]            // Submitted jobs.
+	results *data.BlockingQueue[Result[T, R]] // Results of processed jobs.
+	ctx     context.Context                   // Context passed to handlers.
+	cancel  context.CancelFunc                // Cancels running handlers.
+	workers []context.CancelFunc              // Stops each worker.
+	closed  bool                              // Whether Shutdown was called.
+	wg      *sync.WaitGroup                   // Tracks running workers.
+	mux     *sync.Mutex                       // Lock worker management.
+}
+
+// Create a new worker pool with a number of workers and a job queue holding
+// at most capacity jobs, or unbounded if capacity is 0.
+func New[T, R any](workers int, capacity int, handler Handler[T, R]) *WorkerPool[T, R] {
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := &WorkerPool[T, R]{
+		handler: handler,
+		jobs:    data.NewBlockingQueue[T](capacity),
+		results: data.NewBlockingQueue[Result[T, R]](0),
+		ctx:     ctx,
+		cancel:  cancel,
+		wg:      &sync.WaitGroup{},
+		mux:     &sync.Mutex{},
+	}
+	pool.Resize(workers)
+	return pool
+}
+
+// Size reports the number of workers.
+func (pool *WorkerPool[T, R]) Size() int {
+	pool.mux.Lock()
+	defer pool.mux.Unlock()
+	return len(pool.workers)
+}
+
+// Pending reports the number of submitted jobs not yet taken by a worker.
+func (pool *WorkerPool[T, R]) Pending() int {
+	return pool.jobs.Length()
+}
+
+// Resize starts or stops workers until there are n. Stopped workers finish
+// the job they are running first.
+func (pool *WorkerPool[T, R]) Resize(n int) error {
+	if n < 0 {
+		return fmt.Errorf("%w: worker count %d is negative", data.ErrOutOfRange, n)
+	}
+	pool.mux.Lock()
+	defer pool.mux.Unlock()
+	if pool.closed {
+		return data.ErrClosed
+	}
+	for len(pool.workers) < n {
+		ctx, cancel := context.WithCancel(pool.ctx)
+		pool.workers = append(pool.workers, cancel)
+		pool.wg.Add(1)
+		go pool.work(ctx)
+	}
+	for len(pool.workers) > n {
+		last := len(pool.workers) - 1
+		pool.workers[last]()
+		pool.workers = pool.workers[:last]
+	}
+	return nil
+}
+
+// Submit queues a job, blocking while the job queue is full.
+func (pool *WorkerPool[T, R]) Submit(ctx context.Context, job T) error {
+	return pool.jobs.Put(ctx, job)
+}
+
+// Next gets the next result, blocking until one is available. After Shutdown
+// and once all results are collected, it returns data.ErrClosed.
+func (pool *WorkerPool[T, R]) Next(ctx context.Context) (Result[T, R], error) {
+	return pool.results.Take(ctx)
+}
+
+// Shutdown stops accepting jobs and waits for the workers to process the jobs
+// already queued, starting a worker to drain them if the pool was resized to
+// none. If ctx is done first, running handlers are canceled and an error
+// wrapping both data.ErrCanceled and the context error is returned.
+func (pool *WorkerPool[T, R]) Shutdown(ctx context.Context) error {
+	pool.mux.Lock()
+	if !pool.closed {
+		pool.closed = true
+		pool.jobs.Close()
+		if len(pool.workers) == 0 {
+			pool.wg.Add(1)
+			go pool.work(pool.ctx)
+		}
+		go func() {
+			pool.wg.Wait()
+			pool.results.Close()
+		}()
+	}
+	pool.mux.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		pool.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		pool.cancel()
+		return nil
+	case <-ctx.Done():
+		pool.cancel()
+		return fmt.Errorf("%w: %w", data.ErrCanceled, ctx.Err())
+	}
+}
+
+// work takes and processes jobs until stopped or the job queue is drained.
+func (pool *WorkerPool[T, R]) work(ctx context.Context) {
+	defer pool.wg.Done()
+	for {
+		job, err := pool.jobs.Take(ctx)
+		if err != nil {
+			return
+		}
+		pool.results.Put(context.Background(), pool.run(job))
+	}
+}
+
+// run processes a job, recovering from a panic in the handler.
+func (pool *WorkerPool[T, R]) run(job T) (result Result[T, R]) {
+	result.Job = job
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result.Err = &PanicError{Value: recovered, Stack: debug.Stack()}
+		}
+	}()
+	result.Value, result.Err = pool.handler(pool.ctx, job)
+	return result
+}
@@ -0,0 +1,153 @@
+package pool_test
+
+import (
+	"context"
+	"errors"
+	"fun/pkg/data"
+	. "fun/pkg/pool"
+	"testing"
+	"time"
+)
+
+// square is a handler that squares its job.
+func square(ctx context.Context, job int) (int, error) {
+	return job * job, nil
+}
+
+// collect gets results until the pool is drained.
+func collect[T, R any](t *testing.T, pool *WorkerPool[T, R]) []Result[T, R] {
+	var results []Result[T, R]
+	for {
+		result, err := pool.Next(context.Background())
+		if errors.Is(err, data.ErrClosed) {
+			return results
+		}
+		if err != nil {
+			t.Fatal("unexpected error", err)
+		}
+		results = append(results, result)
+	}
+}
+
+func Test_WorkerPool(t *testing.T) {
+	ctx := context.Background()
+	pool := New(4, 0, square)
+	if pool.Size() != 4 {
+		t.Error("expected 4 workers, got", pool.Size())
+	}
+	for i := 1; i <= 100; i++ {
+		if err := pool.Submit(ctx, i); err != nil {
+			t.Fatal("unexpected error", err)
+		}
+	}
+	if err := pool.Shutdown(ctx); err != nil {
+		t.Error("unexpected error", err)
+	}
+	if err := pool.Submit(ctx, 1); !errors.Is(err, data.ErrClosed) {
+		t.Error("expected submit after shutdown to fail, got", err)
+	}
+
+	results := collect(t, pool)
+	if len(results) != 100 {
+		t.Fatal("expected 100 results, got", len(results))
+	}
+	for _, result := range results {
+		if result.Err != nil || result.Value != result.Job*result.Job {
+			t.Errorf("unexpected result %+v", result)
+		}
+	}
+}
+
+func Test_WorkerPoolPanic(t *testing.T) {
+	ctx := context.Background()
+	pool := New(1, 0, func(ctx context.Context, job int) (int, error) {
+		if job == 0 {
+			panic("zero")
+		}
+		return job, nil
+	})
+	pool.Submit(ctx, 0)
+	pool.Submit(ctx, 1)
+	pool.Shutdown(ctx)
+
+	results := collect(t, pool)
+	if len(results) != 2 {
+		t.Fatal("expected 2 results, got", len(results))
+	}
+	var panicErr *PanicError
+	if !errors.As(results[0].Err, &panicErr) || panicErr.Value != "zero" || len(panicErr.Stack) == 0 {
+		t.Error("expected panic error, got", results[0].Err)
+	}
+	if results[1].Err != nil || results[1].Value != 1 {
+		t.Errorf("expected worker to survive the panic, got %+v", results[1])
+	}
+}
+
+func Test_WorkerPoolResize(t *testing.T) {
+	ctx := context.Background()
+	pool := New(2, 0, square)
+	if err := pool.Resize(-1); err == nil {
+		t.Error("expected error resizing to a negative count")
+	}
+	pool.Resize(5)
+	if pool.Size() != 5 {
+		t.Error("expected 5 workers, got", pool.Size())
+	}
+	pool.Resize(0)
+	if pool.Size() != 0 {
+		t.Error("expected 0 workers, got", pool.Size())
+	}
+
+	pool.Submit(ctx, 3)
+	time.Sleep(10 * time.Millisecond)
+	if pool.Pending() != 1 {
+		t.Error("expected job to wait without workers, pending", pool.Pending())
+	}
+	pool.Resize(1)
+	result, err := pool.Next(ctx)
+	if err != nil || result.Value != 9 {
+		t.Error("expected result 9, got", result, err)
+	}
+	pool.Shutdown(ctx)
+	if err := pool.Resize(2); !errors.Is(err, data.ErrClosed) {
+		t.Error("expected resize after shutdown to fail, got", err)
+	}
+}
+
+func Test_WorkerPoolShutdownTimeout(t *testing.T) {
+	started := make(chan struct{})
+	pool := New(1, 0, func(ctx context.Context, job int) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	pool.Submit(context.Background(), 1)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := pool.Shutdown(ctx); !errors.Is(err, data.ErrCanceled) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("expected shutdown to time out, got", err)
+	}
+	results := collect(t, pool)
+	if len(results) != 1 || !errors.Is(results[0].Err, context.Canceled) {
+		t.Error("expected running job to be canceled, got", results)
+	}
+}
+
+func Test_WorkerPoolShutdownWithoutWorkers(t *testing.T) {
+	ctx := context.Background()
+	pool := New(0, 0, square)
+	pool.Submit(ctx, 2)
+	pool.Submit(ctx, 3)
+	if err := pool.Shutdown(ctx); err != nil {
+		t.Error("unexpected error", err)
+	}
+	results := collect(t, pool)
+	if len(results) != 2 || results[0].Value != 4 || results[1].Value != 9 {
+		t.Error("expected queued jobs to be drained, got", results)
+	}
+	if pool.Pending() != 0 {
+		t.Error("expected no pending jobs, got", pool.Pending())
+	}
+}